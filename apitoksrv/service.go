@@ -0,0 +1,297 @@
+//Package apitoksrv ...
+package apitoksrv
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	lg "github.com/Ulbora/Level_Logger"
+	ss "github.com/Ulbora/Six910-ui/sessrv"
+	ds "github.com/Ulbora/json-datastore"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+const (
+	//Prefix Prefix starts every token so a leaked one is easy to spot
+	Prefix = "six910_"
+
+	idLen     = 8
+	secretLen = 32
+
+	//touchInterval keeps Check from writing LastUsed on every request
+	touchInterval = time.Minute
+)
+
+var (
+	//ErrInvalidToken ErrInvalidToken is returned for a token that is
+	//malformed, unknown or revoked
+	ErrInvalidToken = errors.New("invalid API token")
+
+	//ErrExpiredToken ErrExpiredToken
+	ErrExpiredToken = errors.New("API token expired")
+)
+
+//Token Token lets scripts call the admin API as the admin who made it.
+//Session holds that admin's backend credential. It is only filled in by
+//Check and is saved as Sealed, encrypted with a key made from the
+//token's secret, of which only a hash is kept. The store alone can not
+//be used to call the backend; the token itself can.
+type Token struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Username string    `json:"username"`
+	Hash     string    `json:"hash"`
+	Sealed   string    `json:"sealed"`
+	Created  time.Time `json:"created"`
+	LastUsed time.Time `json:"lastUsed"`
+	Expires  time.Time `json:"expires"`
+
+	Session ss.AdminSession `json:"-"`
+	key     []byte
+}
+
+//Expired Expired is false for a token with no expiry
+func (t *Token) Expired(now time.Time) bool {
+	return !t.Expires.IsZero() && now.After(t.Expires)
+}
+
+//Service Service
+type Service interface {
+	Create(t *Token) (string, error)
+	Check(token string) (*Token, error)
+	Update(t *Token) bool
+	List(username string) *[]Token
+	Delete(id string) bool
+}
+
+//Six910TokenService Six910TokenService
+type Six910TokenService struct {
+	Store ds.JSONDatastore
+	Log   *lg.Logger
+	mu    sync.Mutex
+}
+
+//GetNew GetNew
+func (s *Six910TokenService) GetNew() Service {
+	return s
+}
+
+//Create Create saves t with a new ID and secret and returns the token,
+//which can not be read back later
+func (s *Six910TokenService) Create(t *Token) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	secret := randomHex(secretLen)
+	t.ID = randomHex(idLen)
+	t.Hash = hashSecret(secret)
+	t.key = sealKey(secret)
+	t.Created = time.Now()
+	t.LastUsed = time.Time{}
+	if err := t.seal(); err != nil {
+		return "", err
+	}
+	if !s.Store.Save(t.ID, t) {
+		return "", errors.New("API token not saved for: " + t.Username)
+	}
+	s.Log.Info("API token ", t.ID, " created for: ", t.Username)
+	return Prefix + t.ID + "." + secret, nil
+}
+
+//Check Check returns the saved token for a token string
+func (s *Six910TokenService) Check(token string) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !strings.HasPrefix(token, Prefix) {
+		return nil, ErrInvalidToken
+	}
+	parts := strings.Split(strings.TrimPrefix(token, Prefix), ".")
+	if len(parts) != 2 {
+		return nil, ErrInvalidToken
+	}
+	t := s.read(parts[0])
+	if t == nil || subtle.ConstantTimeCompare([]byte(t.Hash), []byte(hashSecret(parts[1]))) != 1 {
+		return nil, ErrInvalidToken
+	}
+	t.key = sealKey(parts[1])
+	if err := t.open(); err != nil {
+		s.Log.Error("API token ", t.ID, " credential can not be read: ", err)
+		return nil, ErrInvalidToken
+	}
+	now := time.Now()
+	if t.Expired(now) {
+		return nil, ErrExpiredToken
+	}
+	if now.Sub(t.LastUsed) > touchInterval {
+		t.LastUsed = now
+		s.Store.Save(t.ID, t)
+	}
+	return t, nil
+}
+
+//Update Update saves t, for example after its backend token was
+//refreshed. Only a token from Check or Create can be saved, since the
+//credential is sealed with its secret. A revoked token is not brought
+//back.
+func (s *Six910TokenService) Update(t *Token) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t.key == nil || s.read(t.ID) == nil {
+		return false
+	}
+	if err := t.seal(); err != nil {
+		s.Log.Error("API token ", t.ID, " not sealed: ", err)
+		return false
+	}
+	return s.Store.Save(t.ID, t)
+}
+
+//List List returns the tokens made by username, newest first
+func (s *Six910TokenService) List(username string) *[]Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var rtn = []Token{}
+	all := s.Store.ReadAll()
+	if all != nil {
+		for _, b := range *all {
+			var t Token
+			if err := json.Unmarshal(b, &t); err != nil || t.ID == "" {
+				continue
+			}
+			if t.Username == username {
+				rtn = append(rtn, t)
+			}
+		}
+	}
+	sort.Slice(rtn, func(i, j int) bool {
+		return rtn[i].Created.After(rtn[j].Created)
+	})
+	return &rtn
+}
+
+//Delete Delete revokes a token
+func (s *Six910TokenService) Delete(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.read(id) == nil {
+		return false
+	}
+	s.Log.Info("API token ", id, " deleted")
+	return s.Store.Delete(id)
+}
+
+func (s *Six910TokenService) read(id string) *Token {
+	if !isHex(id, idLen) {
+		return nil
+	}
+	b := s.Store.Read(id)
+	if b == nil || len(*b) == 0 {
+		return nil
+	}
+	var t Token
+	if err := json.Unmarshal(*b, &t); err != nil {
+		s.Log.Error("bad API token record: ", id, err)
+		return nil
+	}
+	return &t
+}
+
+//isHex keeps ids from the outside safe to use as file names
+func isHex(s string, n int) bool {
+	if len(s) != n*2 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic("apitoksrv: cannot read random bytes: " + err.Error())
+	}
+	return hex.EncodeToString(b)
+}
+
+//sealKey is the credential key for secret; it is not the stored hash
+func sealKey(secret string) []byte {
+	sum := sha256.Sum256([]byte("six910 api token credential:" + secret))
+	return sum[:]
+}
+
+//seal encrypts Session with AES-GCM into Sealed, bound to the token ID
+func (t *Token) seal() error {
+	gcm, err := newGCM(t.key)
+	if err != nil {
+		return err
+	}
+	pt, err := json.Marshal(&t.Session)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	t.Sealed = base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, pt, []byte(t.ID)))
+	return nil
+}
+
+func (t *Token) open() error {
+	gcm, err := newGCM(t.key)
+	if err != nil {
+		return err
+	}
+	b, err := base64.StdEncoding.DecodeString(t.Sealed)
+	if err != nil {
+		return err
+	}
+	if len(b) < gcm.NonceSize() {
+		return errors.New("sealed credential too short")
+	}
+	pt, err := gcm.Open(nil, b[:gcm.NonceSize()], b[gcm.NonceSize():], []byte(t.ID))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(pt, &t.Session)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	blk, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(blk)
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,119 @@
+package apitoksrv
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	lg "github.com/Ulbora/Level_Logger"
+	ds "github.com/Ulbora/json-datastore"
+)
+
+func testService(t *testing.T) (Service, string) {
+	dir, err := ioutil.TempDir("", "six910token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var l lg.Logger
+	var tds ds.DataStore
+	tds.Path = dir
+	var ts Six910TokenService
+	ts.Store = tds.GetNew()
+	ts.Log = &l
+	return ts.GetNew(), dir
+}
+
+func TestSix910TokenService_CreateCheck(t *testing.T) {
+	s, dir := testService(t)
+	defer os.RemoveAll(dir)
+	var tk Token
+	tk.Name = "script"
+	tk.Username = "admin"
+	tk.Session.Username = "admin"
+	tk.Session.Password = "secret"
+	str, err := s.Create(&tk)
+	if err != nil || !strings.HasPrefix(str, Prefix+tk.ID+".") || tk.Hash == "" || strings.Contains(str, tk.Hash) {
+		t.Fail()
+	}
+	ct, err := s.Check(str)
+	if err != nil || ct.Name != "script" || ct.Session.Password != "secret" || ct.LastUsed.IsZero() {
+		t.Fail()
+	}
+	if _, err := s.Check(str + "0"); err != ErrInvalidToken {
+		t.Fail()
+	}
+	if _, err := s.Check(Prefix + "../../etc.x"); err != ErrInvalidToken {
+		t.Fail()
+	}
+	if _, err := s.Check(tk.ID); err != ErrInvalidToken {
+		t.Fail()
+	}
+
+	ct.Session.Password = "changed"
+	if !s.Update(ct) {
+		t.Fail()
+	}
+	if ct2, _ := s.Check(str); ct2.Session.Password != "changed" {
+		t.Fail()
+	}
+
+	var old Token
+	old.Username = "admin"
+	old.Expires = time.Now().Add(-time.Minute)
+	ostr, _ := s.Create(&old)
+	if _, err := s.Check(ostr); err != ErrExpiredToken {
+		t.Fail()
+	}
+	if l := *s.List("admin"); len(l) != 2 || l[0].ID != old.ID {
+		t.Fail()
+	}
+	if l := *s.List("jane"); len(l) != 0 {
+		t.Fail()
+	}
+
+	if !s.Delete(tk.ID) || s.Delete(tk.ID) || s.Update(ct) {
+		t.Fail()
+	}
+	if _, err := s.Check(str); err != ErrInvalidToken {
+		t.Fail()
+	}
+}
+
+func TestSix910TokenService_Sealed(t *testing.T) {
+	s, dir := testService(t)
+	defer os.RemoveAll(dir)
+	var tk Token
+	tk.Username = "admin"
+	tk.Session.Username = "admin"
+	tk.Session.Password = "hunter22"
+	tk.Session.RefreshToken = "refresh77"
+	str, err := s.Create(&tk)
+	if err != nil || tk.Sealed == "" {
+		t.Fail()
+	}
+	files, _ := ioutil.ReadDir(dir)
+	for _, f := range files {
+		b, _ := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		if strings.Contains(string(b), "hunter22") || strings.Contains(string(b), "refresh77") {
+			fmt.Println("credential in clear: ", string(b))
+			t.Fail()
+		}
+	}
+	l := *s.List("admin")
+	if len(l) != 1 || l[0].Session.Password != "" || s.Update(&l[0]) {
+		t.Fail()
+	}
+
+	//a record whose seal was changed is refused
+	ct, _ := s.Check(str)
+	ct.Sealed = ct.Sealed[:len(ct.Sealed)-4] + "AAAA"
+	ts := s.(*Six910TokenService)
+	ts.Store.Save(ct.ID, ct)
+	if _, err := s.Check(str); err != ErrInvalidToken {
+		t.Fail()
+	}
+}
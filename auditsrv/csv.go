@@ -0,0 +1,45 @@
+package auditsrv
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+//WriteCSV WriteCSV writes one row per changed field, or one row for an
+//entry with no changes
+func WriteCSV(w io.Writer, entries *[]Entry) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"seq", "time", "username", "action", "entity", "entityId", "requestId", "field", "before", "after"})
+	for _, e := range *entries {
+		row := []string{strconv.FormatInt(e.Seq, 10), e.Time.Format(time.RFC3339), e.Username, e.Action,
+			e.Entity, e.EntityID, e.RequestID}
+		if len(e.Changes) == 0 {
+			cw.Write(append(row, "", "", ""))
+		}
+		for _, c := range e.Changes {
+			cw.Write(append(append([]string{}, row...), c.Field, c.Before, c.After))
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,123 @@
+package auditsrv
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+//Masked Masked replaces the values of secret fields
+const Masked = "********"
+
+//secretWords mark a field as secret when the last part of its name ends
+//with one of them, so tokenSecret is masked but rekeyTryCount is not
+var secretWords = []string{"password", "secret", "apikey", "clientkey", "privatekey", "token"}
+
+//Diff Diff compares the JSON fields of before and after, either of
+//which can be nil. Nested fields are named with dots and a field missing
+//on one side counts as blank. Secret values are masked but a change to
+//them is still listed.
+func Diff(before interface{}, after interface{}) []Change {
+	bf := flatten(before)
+	af := flatten(after)
+	var names []string
+	for n := range bf {
+		names = append(names, n)
+	}
+	for n := range af {
+		if _, ok := bf[n]; !ok {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	var rtn []Change
+	for _, n := range names {
+		b, a := bf[n], af[n]
+		if a == b {
+			continue
+		}
+		var c Change
+		c.Field = n
+		c.Before = mask(n, b)
+		c.After = mask(n, a)
+		rtn = append(rtn, c)
+	}
+	return rtn
+}
+
+func isSecret(field string) bool {
+	lf := strings.ToLower(field[strings.LastIndex(field, ".")+1:])
+	for _, sw := range secretWords {
+		if strings.HasSuffix(lf, sw) {
+			return true
+		}
+	}
+	return false
+}
+
+func mask(field string, val string) string {
+	if val != "" && isSecret(field) {
+		return Masked
+	}
+	return val
+}
+
+func flatten(v interface{}) map[string]string {
+	var rtn = make(map[string]string)
+	if v == nil {
+		return rtn
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return rtn
+	}
+	var m interface{}
+	if json.Unmarshal(b, &m) != nil {
+		return rtn
+	}
+	flattenInto(rtn, "", m)
+	return rtn
+}
+
+func flattenInto(rtn map[string]string, prefix string, v interface{}) {
+	switch tv := v.(type) {
+	case map[string]interface{}:
+		for k, cv := range tv {
+			n := k
+			if prefix != "" {
+				n = prefix + "." + k
+			}
+			flattenInto(rtn, n, cv)
+		}
+	case nil:
+		if prefix != "" {
+			rtn[prefix] = ""
+		}
+	case string:
+		rtn[prefix] = tv
+	case []interface{}:
+		b, _ := json.Marshal(tv)
+		rtn[prefix] = string(b)
+	default:
+		rtn[prefix] = fmt.Sprint(tv)
+	}
+}
@@ -0,0 +1,90 @@
+package auditsrv
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type testGateway struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	ClientKey   string `json:"clientKey"`
+	TokenSecret string `json:"tokenSecret"`
+	Info        struct {
+		Password      string `json:"password"`
+		Enabled       bool   `json:"enabled"`
+		RekeyTryCount int    `json:"rekeyTryCount"`
+		TokenURL      string `json:"tokenUrl"`
+	} `json:"info"`
+}
+
+func TestDiff(t *testing.T) {
+	var b testGateway
+	b.ID = 2
+	b.Name = "paypal"
+	b.ClientKey = "abc"
+	b.TokenSecret = "same"
+	b.Info.Password = "old"
+	a := b
+	a.Name = "PayPal"
+	a.ClientKey = "def"
+	a.Info.Password = "new"
+	a.Info.Enabled = true
+	a.Info.RekeyTryCount = 3
+	a.Info.TokenURL = "https://pay.test.com/token"
+	cs := Diff(&b, &a)
+	fmt.Println("changes: ", cs)
+	if len(cs) != 6 {
+		t.FailNow()
+	}
+	if cs[0].Field != "clientKey" || cs[0].Before != Masked || cs[0].After != Masked {
+		t.Fail()
+	}
+	if cs[1].Field != "info.enabled" || cs[1].Before != "false" || cs[1].After != "true" {
+		t.Fail()
+	}
+	if cs[2].Field != "info.password" || cs[2].After != Masked {
+		t.Fail()
+	}
+	if cs[3].Field != "info.rekeyTryCount" || cs[3].After != "3" {
+		t.Fail()
+	}
+	if cs[4].Field != "info.tokenUrl" || cs[4].After != "https://pay.test.com/token" {
+		t.Fail()
+	}
+	if cs[5].Field != "name" || cs[5].Before != "paypal" || cs[5].After != "PayPal" {
+		t.Fail()
+	}
+}
+
+func TestDiff_CreateDelete(t *testing.T) {
+	var g testGateway
+	g.ID = 7
+	g.TokenSecret = "s"
+	cs := Diff(nil, &g)
+	if len(cs) != 4 || cs[0].Field != "id" || cs[0].Before != "" || cs[0].After != "7" || cs[3].After != Masked {
+		fmt.Println("create: ", cs)
+		t.Fail()
+	}
+	var ng *testGateway
+	if cs := Diff(&g, ng); len(cs) != 4 || cs[0].After != "" {
+		t.Fail()
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var es = []Entry{{Seq: 2, Username: "admin", Action: Update, Entity: "product", EntityID: "4",
+		Changes: []Change{{Field: "name", Before: "a", After: "b, c"}, {Field: "price", Before: "1", After: "2"}}},
+		{Seq: 1, Username: "admin", Action: Create, Entity: "productUpload", EntityID: "p.csv"}}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, &es); err != nil {
+		t.Fail()
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	fmt.Println("csv: ", buf.String())
+	if len(lines) != 4 || !strings.HasSuffix(lines[1], `name,a,"b, c"`) || !strings.HasSuffix(lines[3], "p.csv,,,,") {
+		t.Fail()
+	}
+}
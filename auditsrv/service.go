@@ -0,0 +1,187 @@
+//Package auditsrv ...
+package auditsrv
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	lg "github.com/Ulbora/Level_Logger"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+//Actions
+const (
+	Create = "create"
+	Update = "update"
+	Delete = "delete"
+)
+
+//logFile is the append-only file in Path
+const logFile = "audit.log"
+
+//Change Change is one field that differs between before and after
+type Change struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+//Entry Entry is one admin action
+type Entry struct {
+	Seq       int64     `json:"seq"`
+	Time      time.Time `json:"time"`
+	Username  string    `json:"username"`
+	Action    string    `json:"action"`
+	Entity    string    `json:"entity"`
+	EntityID  string    `json:"entityId"`
+	RequestID string    `json:"requestId"`
+	Changes   []Change  `json:"changes"`
+}
+
+//Filter Filter selects entries; blank and zero fields match everything.
+//To is not included.
+type Filter struct {
+	Username string
+	Entity   string
+	From     time.Time
+	To       time.Time
+	Limit    int
+}
+
+//Service Service
+type Service interface {
+	Record(e *Entry) bool
+	Search(f *Filter) *[]Entry
+}
+
+//Six910AuditService Six910AuditService appends one JSON line per entry
+//to audit.log in Path. Lines are never changed or removed.
+type Six910AuditService struct {
+	Path    string
+	Log     *lg.Logger
+	lastSeq int64
+	loaded  bool
+	mu      sync.Mutex
+}
+
+//GetNew GetNew
+func (s *Six910AuditService) GetNew() Service {
+	return s
+}
+
+//Record Record sets Seq and Time and appends the entry
+func (s *Six910AuditService) Record(e *Entry) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.loaded {
+		s.scan(func(le *Entry) bool {
+			s.lastSeq = le.Seq
+			return true
+		})
+		s.loaded = true
+	}
+	e.Seq = s.lastSeq + 1
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		s.Log.Error("audit entry not encoded: ", err)
+		return false
+	}
+	f, err := os.OpenFile(filepath.Join(s.Path, logFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		s.Log.Error("audit log not opened: ", err)
+		return false
+	}
+	defer f.Close()
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		s.Log.Error("audit entry not written: ", err)
+		return false
+	}
+	s.lastSeq = e.Seq
+	return true
+}
+
+//Search Search returns matching entries, newest first
+func (s *Six910AuditService) Search(f *Filter) *[]Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var found []Entry
+	s.scan(func(e *Entry) bool {
+		if f.matches(e) {
+			found = append(found, *e)
+		}
+		return true
+	})
+	var rtn = make([]Entry, 0, len(found))
+	for i := len(found) - 1; i >= 0; i-- {
+		if f.Limit > 0 && len(rtn) == f.Limit {
+			break
+		}
+		rtn = append(rtn, found[i])
+	}
+	return &rtn
+}
+
+func (f *Filter) matches(e *Entry) bool {
+	if f.Username != "" && !strings.EqualFold(f.Username, e.Username) {
+		return false
+	}
+	if f.Entity != "" && !strings.EqualFold(f.Entity, e.Entity) {
+		return false
+	}
+	if !f.From.IsZero() && e.Time.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !e.Time.Before(f.To) {
+		return false
+	}
+	return true
+}
+
+//scan calls fn for each entry in file order until fn returns false
+func (s *Six910AuditService) scan(fn func(e *Entry) bool) {
+	f, err := os.Open(filepath.Join(s.Path, logFile))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			s.Log.Error("audit log not read: ", err)
+		}
+		return
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			s.Log.Error("bad audit log line: ", err)
+			continue
+		}
+		if !fn(&e) {
+			break
+		}
+	}
+}
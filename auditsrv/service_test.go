@@ -0,0 +1,82 @@
+package auditsrv
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	lg "github.com/Ulbora/Level_Logger"
+)
+
+func testService(t *testing.T) (*Six910AuditService, string) {
+	dir, err := ioutil.TempDir("", "six910audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var l lg.Logger
+	var as Six910AuditService
+	as.Path = dir
+	as.Log = &l
+	return &as, dir
+}
+
+func TestSix910AuditService_RecordSearch(t *testing.T) {
+	as, dir := testService(t)
+	defer os.RemoveAll(dir)
+	s := as.GetNew()
+	day := time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC)
+	s.Record(&Entry{Time: day, Username: "admin", Action: Create, Entity: "product", EntityID: "1"})
+	s.Record(&Entry{Time: day.Add(24 * time.Hour), Username: "jane", Action: Update, Entity: "product", EntityID: "1"})
+	s.Record(&Entry{Time: day.Add(48 * time.Hour), Username: "admin", Action: Delete, Entity: "category", EntityID: "3"})
+
+	all := *s.Search(&Filter{})
+	if len(all) != 3 || all[0].Seq != 3 || all[2].Seq != 1 {
+		t.Fail()
+	}
+	if lst := *s.Search(&Filter{Username: "ADMIN"}); len(lst) != 2 {
+		t.Fail()
+	}
+	if lst := *s.Search(&Filter{Entity: "product", From: day.Add(time.Hour)}); len(lst) != 1 || lst[0].Username != "jane" {
+		t.Fail()
+	}
+	if lst := *s.Search(&Filter{To: day.Add(24 * time.Hour)}); len(lst) != 1 || lst[0].EntityID != "1" {
+		t.Fail()
+	}
+	if lst := *s.Search(&Filter{Limit: 2}); len(lst) != 2 || lst[0].Seq != 3 {
+		t.Fail()
+	}
+}
+
+func TestSix910AuditService_Reopen(t *testing.T) {
+	as, dir := testService(t)
+	defer os.RemoveAll(dir)
+	as.Record(&Entry{Username: "admin", Entity: "region"})
+	as.Record(&Entry{Username: "admin", Entity: "region"})
+
+	var l lg.Logger
+	var as2 Six910AuditService
+	as2.Path = dir
+	as2.Log = &l
+	e := Entry{Username: "admin", Entity: "region"}
+	if !as2.Record(&e) || e.Seq != 3 || e.Time.IsZero() {
+		t.Fail()
+	}
+	if len(*as2.Search(&Filter{})) != 3 {
+		t.Fail()
+	}
+	fi, _ := os.Stat(dir + "/" + logFile)
+	if fi.Mode().Perm() != 0600 {
+		t.Fail()
+	}
+}
+
+func TestSix910AuditService_BadPath(t *testing.T) {
+	var l lg.Logger
+	var as Six910AuditService
+	as.Path = "/nodir/six910audit"
+	as.Log = &l
+	if as.Record(&Entry{Username: "admin"}) || len(*as.Search(&Filter{})) != 0 {
+		t.Fail()
+	}
+}
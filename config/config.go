@@ -0,0 +1,374 @@
+//Package config ...
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	perms "github.com/Ulbora/Six910-ui/permsrv"
+	yaml "gopkg.in/yaml.v2"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+//Config Config holds the settings for Six910Handler and all services.
+//Values are read from a JSON or YAML file and each field can be
+//overridden by the environment variable named in its env tag.
+type Config struct {
+	Port            string `json:"port" yaml:"port" env:"PORT"`
+	ShutdownTimeout int    `json:"shutdownTimeout" yaml:"shutdownTimeout" env:"SIX910_SHUTDOWN_TIMEOUT"`
+
+	BackendURL    string `json:"backendUrl" yaml:"backendUrl" env:"SIX910_API_URL"`
+	StoreName     string `json:"storeName" yaml:"storeName" env:"SIX910_STORE_NAME"`
+	LocalDomain   string `json:"localDomain" yaml:"localDomain" env:"SIX910_LOCAL_DOMAIN"`
+	APIKey        string `json:"apiKey" yaml:"apiKey" env:"SIX910_API_KEY"`
+	OAuth2Enabled bool   `json:"oauth2Enabled" yaml:"oauth2Enabled" env:"SIX910_OAUTH2_ENABLED"`
+
+	OauthHost      string `json:"oauthHost" yaml:"oauthHost" env:"SIX910_OAUTH_HOST"`
+	UserHost       string `json:"userHost" yaml:"userHost" env:"SIX910_USER_HOST"`
+	SchemeDefault  string `json:"schemeDefault" yaml:"schemeDefault" env:"SIX910_SCHEME_DEFAULT"`
+	AuthCodeClient string `json:"authCodeClient" yaml:"authCodeClient" env:"SIX910_AUTH_CODE_CLIENT"`
+	AuthCodeSecret string `json:"authCodeSecret" yaml:"authCodeSecret" env:"SIX910_AUTH_CODE_SECRET"`
+	AuthCodeState  string `json:"authCodeState" yaml:"authCodeState" env:"SIX910_AUTH_CODE_STATE"`
+
+	SessionKey    string `json:"sessionKey" yaml:"sessionKey" env:"SIX910_SESSION_KEY"`
+	SessionSecure bool   `json:"sessionSecure" yaml:"sessionSecure" env:"SIX910_SESSION_SECURE"`
+
+	AdminSessionStorePath   string `json:"adminSessionStorePath" yaml:"adminSessionStorePath" env:"SIX910_ADMIN_SESSION_STORE_PATH"`
+	AdminSessionIdleTimeout int    `json:"adminSessionIdleTimeout" yaml:"adminSessionIdleTimeout" env:"SIX910_ADMIN_SESSION_IDLE_TIMEOUT"`
+	AdminSessionMaxAge      int    `json:"adminSessionMaxAge" yaml:"adminSessionMaxAge" env:"SIX910_ADMIN_SESSION_MAX_AGE"`
+	TwoFactorStorePath      string `json:"twoFactorStorePath" yaml:"twoFactorStorePath" env:"SIX910_TWO_FACTOR_STORE_PATH"`
+	SuperAdmins             string `json:"superAdmins" yaml:"superAdmins" env:"SIX910_SUPER_ADMINS"`
+	AdminPermissions        string `json:"adminPermissions" yaml:"adminPermissions" env:"SIX910_ADMIN_PERMISSIONS"`
+	DefaultPermissions      string `json:"defaultPermissions" yaml:"defaultPermissions" env:"SIX910_DEFAULT_PERMISSIONS"`
+	LoginLockoutAfter       int    `json:"loginLockoutAfter" yaml:"loginLockoutAfter" env:"SIX910_LOGIN_LOCKOUT_AFTER"`
+	LoginLockoutTime        int    `json:"loginLockoutTime" yaml:"loginLockoutTime" env:"SIX910_LOGIN_LOCKOUT_TIME"`
+
+	PasswordResetStorePath string `json:"passwordResetStorePath" yaml:"passwordResetStorePath" env:"SIX910_PASSWORD_RESET_STORE_PATH"`
+	PasswordResetKey       string `json:"passwordResetKey" yaml:"passwordResetKey" env:"SIX910_PASSWORD_RESET_KEY"`
+	PasswordResetTimeout   int    `json:"passwordResetTimeout" yaml:"passwordResetTimeout" env:"SIX910_PASSWORD_RESET_TIMEOUT"`
+	PasswordResetUser      string `json:"passwordResetUser" yaml:"passwordResetUser" env:"SIX910_PASSWORD_RESET_USER"`
+	PasswordResetPassword  string `json:"passwordResetPassword" yaml:"passwordResetPassword" env:"SIX910_PASSWORD_RESET_PASSWORD"`
+	PublicURL              string `json:"publicUrl" yaml:"publicUrl" env:"SIX910_PUBLIC_URL"`
+
+	AuditStorePath string `json:"auditStorePath" yaml:"auditStorePath" env:"SIX910_AUDIT_STORE_PATH"`
+
+	APITokenStorePath string `json:"apiTokenStorePath" yaml:"apiTokenStorePath" env:"SIX910_API_TOKEN_STORE_PATH"`
+
+	OrderStatuses string `json:"orderStatuses" yaml:"orderStatuses" env:"SIX910_ORDER_STATUSES"`
+
+	StockAlertStorePath string `json:"stockAlertStorePath" yaml:"stockAlertStorePath" env:"SIX910_STOCK_ALERT_STORE_PATH"`
+	StockAlertInterval  int    `json:"stockAlertInterval" yaml:"stockAlertInterval" env:"SIX910_STOCK_ALERT_INTERVAL"`
+	StockAlertEmail     string `json:"stockAlertEmail" yaml:"stockAlertEmail" env:"SIX910_STOCK_ALERT_EMAIL"`
+	StockAlertUser      string `json:"stockAlertUser" yaml:"stockAlertUser" env:"SIX910_STOCK_ALERT_USER"`
+	StockAlertPassword  string `json:"stockAlertPassword" yaml:"stockAlertPassword" env:"SIX910_STOCK_ALERT_PASSWORD"`
+
+	ContentStorePath  string `json:"contentStorePath" yaml:"contentStorePath" env:"SIX910_CONTENT_STORE_PATH"`
+	TemplateStorePath string `json:"templateStorePath" yaml:"templateStorePath" env:"SIX910_TEMPLATE_STORE_PATH"`
+	TemplateFilePath  string `json:"templateFilePath" yaml:"templateFilePath" env:"SIX910_TEMPLATE_FILE_PATH"`
+	ImagePath         string `json:"imagePath" yaml:"imagePath" env:"SIX910_IMAGE_PATH"`
+	AdminTemplates    string `json:"adminTemplates" yaml:"adminTemplates" env:"SIX910_ADMIN_TEMPLATES"`
+
+	CaptchaHost string `json:"captchaHost" yaml:"captchaHost" env:"SIX910_CAPTCHA_HOST"`
+	HitLimit    int    `json:"hitLimit" yaml:"hitLimit" env:"SIX910_HIT_LIMIT"`
+
+	MailHost     string `json:"mailHost" yaml:"mailHost" env:"SIX910_MAIL_HOST"`
+	MailPort     string `json:"mailPort" yaml:"mailPort" env:"SIX910_MAIL_PORT"`
+	MailUser     string `json:"mailUser" yaml:"mailUser" env:"SIX910_MAIL_USER"`
+	MailPassword string `json:"mailPassword" yaml:"mailPassword" env:"SIX910_MAIL_PASSWORD"`
+	MailFrom     string `json:"mailFrom" yaml:"mailFrom" env:"SIX910_MAIL_FROM"`
+
+	LogLevel int `json:"logLevel" yaml:"logLevel" env:"SIX910_LOG_LEVEL"`
+}
+
+//Errors Errors lists every problem found while loading or validating a Config
+type Errors []string
+
+//Error Error
+func (e Errors) Error() string {
+	return "invalid configuration: " + strings.Join(e, "; ")
+}
+
+//Default Default returns a Config with the settings used when nothing is configured
+func Default() *Config {
+	var c Config
+	c.Port = "3001"
+	c.ShutdownTimeout = 30
+	c.BackendURL = "http://localhost:3002"
+	c.SchemeDefault = "http://"
+	c.AdminSessionIdleTimeout = 1800
+	c.AdminSessionMaxAge = 43200
+	c.DefaultPermissions = perms.FullSet
+	c.LoginLockoutAfter = 10
+	c.LoginLockoutTime = 900
+	c.PasswordResetTimeout = 3600
+	c.OrderStatuses = "processing,shipped,delivered,cancelled"
+	c.StockAlertInterval = 86400
+	c.ContentStorePath = "./data/contentStore"
+	c.TemplateStorePath = "./data/templateStore"
+	c.TemplateFilePath = "./static/templates"
+	c.ImagePath = "./static/images"
+	c.AdminTemplates = "./static/admin/*.html"
+	c.HitLimit = 100
+	c.LogLevel = 3
+	return &c
+}
+
+//Load Load reads the file at path (if not blank) over the defaults,
+//applies environment overrides and validates the result
+func Load(path string) (*Config, error) {
+	var rtn = Default()
+	var errs Errors
+	if path != "" {
+		if err := rtn.readFile(path); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	errs = append(errs, rtn.applyEnv(os.LookupEnv)...)
+	if verr := rtn.Validate(); verr != nil {
+		errs = append(errs, verr.(Errors)...)
+	}
+	if len(errs) > 0 {
+		return rtn, errs
+	}
+	return rtn, nil
+}
+
+func (c *Config) readFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.New("cannot read config file " + path + ": " + err.Error())
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.UnmarshalStrict(data, c)
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(c)
+	default:
+		err = errors.New("unsupported file type, use .json, .yaml or .yml")
+	}
+	if err != nil {
+		return errors.New("cannot parse config file " + path + ": " + err.Error())
+	}
+	return nil
+}
+
+func (c *Config) applyEnv(lookup func(string) (string, bool)) Errors {
+	var errs Errors
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		key := t.Field(i).Tag.Get("env")
+		val, found := lookup(key)
+		if key == "" || !found {
+			continue
+		}
+		f := v.Field(i)
+		switch f.Kind() {
+		case reflect.String:
+			f.SetString(val)
+		case reflect.Bool:
+			b, err := strconv.ParseBool(val)
+			if err != nil {
+				errs = append(errs, key+" must be true or false, got "+strconv.Quote(val))
+			} else {
+				f.SetBool(b)
+			}
+		case reflect.Int:
+			n, err := strconv.Atoi(val)
+			if err != nil {
+				errs = append(errs, key+" must be a whole number, got "+strconv.Quote(val))
+			} else {
+				f.SetInt(int64(n))
+			}
+		}
+	}
+	return errs
+}
+
+//Validate Validate checks required values and local paths and
+//returns Errors listing every problem found
+func (c *Config) Validate() error {
+	var errs Errors
+	required := func(name string, val string) {
+		if strings.TrimSpace(val) == "" {
+			errs = append(errs, name+" is required")
+		}
+	}
+	required("port", c.Port)
+	if _, err := strconv.Atoi(c.Port); c.Port != "" && err != nil {
+		errs = append(errs, "port must be numeric, got "+strconv.Quote(c.Port))
+	}
+	if c.ShutdownTimeout < 1 {
+		errs = append(errs, "shutdownTimeout must be at least 1 second")
+	}
+	required("backendUrl", c.BackendURL)
+	if c.BackendURL != "" && !strings.HasPrefix(c.BackendURL, "http://") && !strings.HasPrefix(c.BackendURL, "https://") {
+		errs = append(errs, "backendUrl must start with http:// or https://")
+	}
+	required("storeName", c.StoreName)
+	required("localDomain", c.LocalDomain)
+	required("sessionKey", c.SessionKey)
+	if c.OAuth2Enabled {
+		required("oauthHost", c.OauthHost)
+		required("userHost", c.UserHost)
+		required("authCodeClient", c.AuthCodeClient)
+		required("authCodeSecret", c.AuthCodeSecret)
+		required("authCodeState", c.AuthCodeState)
+	}
+	if c.AdminSessionIdleTimeout < 60 {
+		errs = append(errs, "adminSessionIdleTimeout must be at least 60 seconds")
+	}
+	if c.AdminSessionMaxAge < c.AdminSessionIdleTimeout {
+		errs = append(errs, "adminSessionMaxAge must not be less than adminSessionIdleTimeout")
+	}
+	if _, bad := perms.ParseUsers(c.AdminPermissions); len(bad) > 0 {
+		errs = append(errs, "adminPermissions has unknown entries "+strings.Join(bad, ", ")+", use "+strings.Join(perms.SetNames(), ", "))
+	}
+	for _, sn := range c.DefaultPermissionList() {
+		if !perms.ValidSet(sn) {
+			errs = append(errs, "defaultPermissions has unknown set "+sn)
+		}
+	}
+	if c.LoginLockoutAfter < 1 {
+		errs = append(errs, "loginLockoutAfter must be greater than 0")
+	}
+	if c.LoginLockoutTime < 60 {
+		errs = append(errs, "loginLockoutTime must be at least 60 seconds")
+	}
+	if c.AdminSessionStorePath != "" {
+		errs = append(errs, checkDir("adminSessionStorePath", c.AdminSessionStorePath)...)
+	}
+	if c.TwoFactorStorePath != "" {
+		errs = append(errs, checkDir("twoFactorStorePath", c.TwoFactorStorePath)...)
+	}
+	if c.AuditStorePath != "" {
+		errs = append(errs, checkDir("auditStorePath", c.AuditStorePath)...)
+	}
+	if c.APITokenStorePath != "" {
+		errs = append(errs, checkDir("apiTokenStorePath", c.APITokenStorePath)...)
+	}
+	if c.PasswordResetStorePath != "" {
+		errs = append(errs, checkDir("passwordResetStorePath", c.PasswordResetStorePath)...)
+		if len(c.PasswordResetKey) < 16 {
+			errs = append(errs, "passwordResetKey must be at least 16 characters")
+		}
+		if c.PasswordResetTimeout < 60 {
+			errs = append(errs, "passwordResetTimeout must be at least 60 seconds")
+		}
+		if !strings.HasPrefix(c.PublicURL, "http://") && !strings.HasPrefix(c.PublicURL, "https://") {
+			errs = append(errs, "publicUrl must start with http:// or https:// when passwordResetStorePath is set")
+		}
+		if !c.OAuth2Enabled {
+			required("passwordResetUser", c.PasswordResetUser)
+			required("passwordResetPassword", c.PasswordResetPassword)
+		}
+	}
+	if c.StockAlertStorePath != "" {
+		errs = append(errs, checkDir("stockAlertStorePath", c.StockAlertStorePath)...)
+		if c.StockAlertInterval < 60 {
+			errs = append(errs, "stockAlertInterval must be at least 60 seconds")
+		}
+		required("stockAlertEmail", c.StockAlertEmail)
+		if !c.OAuth2Enabled {
+			required("stockAlertUser", c.StockAlertUser)
+			required("stockAlertPassword", c.StockAlertPassword)
+		}
+	}
+	if c.PasswordResetStorePath != "" || c.StockAlertStorePath != "" {
+		required("mailFrom", c.MailFrom)
+	}
+	if c.HitLimit < 1 {
+		errs = append(errs, "hitLimit must be greater than 0")
+	}
+	if c.LogLevel < 0 || c.LogLevel > 3 {
+		errs = append(errs, "logLevel must be between 0 and 3")
+	}
+	errs = append(errs, checkDir("contentStorePath", c.ContentStorePath)...)
+	errs = append(errs, checkDir("templateStorePath", c.TemplateStorePath)...)
+	errs = append(errs, checkDir("templateFilePath", c.TemplateFilePath)...)
+	errs = append(errs, checkDir("imagePath", c.ImagePath)...)
+	required("adminTemplates", c.AdminTemplates)
+	if c.AdminTemplates != "" {
+		mt, err := filepath.Glob(c.AdminTemplates)
+		if err != nil || len(mt) == 0 {
+			errs = append(errs, "adminTemplates "+c.AdminTemplates+" matches no files")
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+//SuperAdminList SuperAdminList returns the comma separated superAdmins
+func (c *Config) SuperAdminList() []string {
+	return splitList(c.SuperAdmins)
+}
+
+//DefaultPermissionList DefaultPermissionList returns the comma separated
+//defaultPermissions
+func (c *Config) DefaultPermissionList() []string {
+	return splitList(c.DefaultPermissions)
+}
+
+//OrderStatusList OrderStatusList returns the comma separated
+//orderStatuses counted on the dashboard
+func (c *Config) OrderStatusList() []string {
+	return splitList(c.OrderStatuses)
+}
+
+//StockAlertEmailList StockAlertEmailList returns the comma separated
+//stockAlertEmail addresses
+func (c *Config) StockAlertEmailList() []string {
+	return splitList(c.StockAlertEmail)
+}
+
+func splitList(list string) []string {
+	var rtn []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			rtn = append(rtn, item)
+		}
+	}
+	return rtn
+}
+
+func checkDir(name string, path string) Errors {
+	var errs Errors
+	if path == "" {
+		return append(errs, name+" is required")
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		errs = append(errs, name+" "+path+" does not exist")
+	} else if !fi.IsDir() {
+		errs = append(errs, name+" "+path+" is not a directory")
+	}
+	return errs
+}
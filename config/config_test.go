@@ -0,0 +1,185 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setupDirs(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "six910cfg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []string{"content", "templates", "tfiles", "images", "admin"} {
+		os.Mkdir(filepath.Join(dir, d), 0755)
+	}
+	ioutil.WriteFile(filepath.Join(dir, "admin", "index.html"), []byte("test"), 0644)
+	return dir
+}
+
+func writeConfig(t *testing.T, dir string, name string, data string) string {
+	p := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(p, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestConfig_LoadJSON(t *testing.T) {
+	dir := setupDirs(t)
+	defer os.RemoveAll(dir)
+	p := writeConfig(t, dir, "cfg.json", `{
+		"port": "8080",
+		"backendUrl": "http://api.test.com",
+		"storeName": "teststore",
+		"localDomain": "test.com",
+		"sessionKey": "abc123",
+		"contentStorePath": "`+filepath.Join(dir, "content")+`",
+		"templateStorePath": "`+filepath.Join(dir, "templates")+`",
+		"templateFilePath": "`+filepath.Join(dir, "tfiles")+`",
+		"imagePath": "`+filepath.Join(dir, "images")+`",
+		"adminTemplates": "`+filepath.Join(dir, "admin", "*.html")+`",
+		"hitLimit": 50
+	}`)
+	c, err := Load(p)
+	fmt.Println("err: ", err)
+	if err != nil || c.Port != "8080" || c.StoreName != "teststore" || c.HitLimit != 50 {
+		t.Fail()
+	}
+	if c.SchemeDefault != "http://" || c.AdminSessionStorePath != "" || c.AdminSessionMaxAge != 43200 || c.LoginLockoutAfter != 10 {
+		t.Fail()
+	}
+	if dl := c.DefaultPermissionList(); len(dl) != 1 || dl[0] != "full" {
+		t.Fail()
+	}
+	if sl := c.OrderStatusList(); len(sl) != 4 || sl[0] != "processing" {
+		t.Fail()
+	}
+}
+
+func TestConfig_LoadYAMLEnvOverride(t *testing.T) {
+	dir := setupDirs(t)
+	defer os.RemoveAll(dir)
+	p := writeConfig(t, dir, "cfg.yaml", "backendUrl: http://api.test.com\n"+
+		"storeName: teststore\n"+
+		"localDomain: test.com\n"+
+		"sessionKey: abc123\n"+
+		"contentStorePath: "+filepath.Join(dir, "content")+"\n"+
+		"templateStorePath: "+filepath.Join(dir, "templates")+"\n"+
+		"templateFilePath: "+filepath.Join(dir, "tfiles")+"\n"+
+		"imagePath: "+filepath.Join(dir, "images")+"\n"+
+		"adminTemplates: "+filepath.Join(dir, "admin", "*.html")+"\n")
+	os.Setenv("SIX910_STORE_NAME", "prodstore")
+	os.Setenv("SIX910_HIT_LIMIT", "25")
+	defer os.Unsetenv("SIX910_STORE_NAME")
+	defer os.Unsetenv("SIX910_HIT_LIMIT")
+	c, err := Load(p)
+	fmt.Println("err: ", err)
+	if err != nil || c.StoreName != "prodstore" || c.HitLimit != 25 || c.Port != "3001" {
+		t.Fail()
+	}
+}
+
+func TestConfig_LoadAllErrors(t *testing.T) {
+	dir := setupDirs(t)
+	defer os.RemoveAll(dir)
+	p := writeConfig(t, dir, "cfg.json", `{
+		"oauth2Enabled": true,
+		"imagePath": "`+filepath.Join(dir, "missing")+`",
+		"adminSessionStorePath": "`+filepath.Join(dir, "nosessions")+`",
+		"adminSessionIdleTimeout": 10,
+		"twoFactorStorePath": "`+filepath.Join(dir, "no2fa")+`",
+		"loginLockoutTime": 5,
+		"passwordResetStorePath": "`+filepath.Join(dir, "content")+`",
+		"passwordResetKey": "short",
+		"auditStorePath": "`+filepath.Join(dir, "noaudit")+`",
+		"apiTokenStorePath": "`+filepath.Join(dir, "notokens")+`",
+		"stockAlertStorePath": "`+filepath.Join(dir, "nostock")+`",
+		"stockAlertInterval": 5,
+		"adminPermissions": "packer:fulfillment,cashier",
+		"defaultPermissions": "readonly,boss",
+		"hitLimit": 0
+	}`)
+	os.Setenv("SIX910_LOG_LEVEL", "loud")
+	defer os.Unsetenv("SIX910_LOG_LEVEL")
+	_, err := Load(p)
+	fmt.Println("err: ", err)
+	errs, ok := err.(Errors)
+	if !ok {
+		t.FailNow()
+	}
+	var want = []string{"SIX910_LOG_LEVEL", "storeName is required", "localDomain is required",
+		"sessionKey is required", "oauthHost is required", "authCodeSecret is required",
+		"hitLimit must be greater than 0", "imagePath " + filepath.Join(dir, "missing") + " does not exist",
+		"adminSessionIdleTimeout must be at least 60 seconds", "adminSessionStorePath " + filepath.Join(dir, "nosessions") + " does not exist",
+		"twoFactorStorePath " + filepath.Join(dir, "no2fa") + " does not exist",
+		"auditStorePath " + filepath.Join(dir, "noaudit") + " does not exist",
+		"apiTokenStorePath " + filepath.Join(dir, "notokens") + " does not exist",
+		"stockAlertStorePath " + filepath.Join(dir, "nostock") + " does not exist",
+		"stockAlertInterval must be at least 60 seconds", "stockAlertEmail is required",
+		"loginLockoutTime must be at least 60 seconds", "passwordResetKey must be at least 16 characters",
+		"publicUrl must start with http:// or https://", "mailFrom is required",
+		"adminPermissions has unknown entries cashier", "defaultPermissions has unknown set boss"}
+	for _, w := range want {
+		if !strings.Contains(err.Error(), w) {
+			fmt.Println("missing: ", w)
+			t.Fail()
+		}
+	}
+	if len(errs) < len(want) {
+		t.Fail()
+	}
+}
+
+func TestConfig_LoadBasicServiceUsers(t *testing.T) {
+	dir := setupDirs(t)
+	defer os.RemoveAll(dir)
+	p := writeConfig(t, dir, "cfg.json", `{
+		"passwordResetStorePath": "`+filepath.Join(dir, "content")+`",
+		"stockAlertStorePath": "`+filepath.Join(dir, "content")+`"
+	}`)
+	_, err := Load(p)
+	fmt.Println("err: ", err)
+	for _, w := range []string{"passwordResetUser is required", "passwordResetPassword is required",
+		"stockAlertUser is required", "stockAlertPassword is required"} {
+		if err == nil || !strings.Contains(err.Error(), w) {
+			fmt.Println("missing: ", w)
+			t.Fail()
+		}
+	}
+}
+
+func TestConfig_LoadBadFile(t *testing.T) {
+	dir := setupDirs(t)
+	defer os.RemoveAll(dir)
+	p := writeConfig(t, dir, "cfg.json", `{"storeName": "test", "unknownField": 1}`)
+	_, err := Load(p)
+	if err == nil || !strings.Contains(err.Error(), "cannot parse config file") {
+		t.Fail()
+	}
+	p2 := writeConfig(t, dir, "cfg.ini", `storeName=test`)
+	_, err2 := Load(p2)
+	if err2 == nil || !strings.Contains(err2.Error(), "unsupported file type") {
+		t.Fail()
+	}
+	_, err3 := Load(filepath.Join(dir, "nofile.json"))
+	if err3 == nil || !strings.Contains(err3.Error(), "cannot read config file") {
+		t.Fail()
+	}
+}
+
+func TestConfig_SuperAdminList(t *testing.T) {
+	c := Default()
+	if len(c.SuperAdminList()) != 0 {
+		t.Fail()
+	}
+	c.SuperAdmins = " admin, ,ops@test.com"
+	lst := c.SuperAdminList()
+	if len(lst) != 2 || lst[0] != "admin" || lst[1] != "ops@test.com" {
+		t.Fail()
+	}
+}
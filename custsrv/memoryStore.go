@@ -0,0 +1,156 @@
+//Package custsrv ...
+package custsrv
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+const (
+	//DefaultIdleTimeout DefaultIdleTimeout
+	DefaultIdleTimeout = 30 * time.Minute
+
+	//DefaultMaxAge DefaultMaxAge
+	DefaultMaxAge = 24 * time.Hour
+
+	tokenLen = 32
+)
+
+//Session Session is a storefront customer logged in through the JSON
+//API. Only Token is given to the client; the backend credential stays
+//here.
+type Session struct {
+	Token      string
+	Username   string
+	Password   string
+	CustomerID int64
+	Created    time.Time
+	LastUsed   time.Time
+}
+
+//Store Store keeps customer sessions by token. Get never returns a
+//session that is past its idle or absolute expiry.
+type Store interface {
+	Create(s *Session) *Session
+	Get(token string) *Session
+	Delete(token string) bool
+	DeleteUser(username string) int
+}
+
+//MemoryStore MemoryStore keeps sessions for one UI instance
+type MemoryStore struct {
+	IdleTimeout time.Duration
+	MaxAge      time.Duration
+	sessions    map[string]Session
+	mu          sync.Mutex
+}
+
+//GetNew GetNew
+func (m *MemoryStore) GetNew() Store {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.sessions == nil {
+		m.sessions = make(map[string]Session)
+	}
+	return m
+}
+
+//Create Create stores a copy of s under a new token and returns it.
+//Expired sessions are dropped first so abandoned logins do not pile up.
+func (m *MemoryStore) Create(s *Session) *Session {
+	var ns = *s
+	ns.Token = newToken()
+	ns.Created = time.Now()
+	ns.LastUsed = ns.Created
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for t, es := range m.sessions {
+		if m.expired(&es, ns.Created) {
+			delete(m.sessions, t)
+		}
+	}
+	m.sessions[ns.Token] = ns
+	return &ns
+}
+
+//Get Get returns the session for token and marks it used
+func (m *MemoryStore) Get(token string) *Session {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[token]
+	if !ok {
+		return nil
+	}
+	now := time.Now()
+	if m.expired(&s, now) {
+		delete(m.sessions, token)
+		return nil
+	}
+	s.LastUsed = now
+	m.sessions[token] = s
+	return &s
+}
+
+//Delete Delete
+func (m *MemoryStore) Delete(token string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.sessions[token]
+	delete(m.sessions, token)
+	return ok
+}
+
+//DeleteUser DeleteUser ends every session for username, such as after a
+//password reset, and returns how many there were
+func (m *MemoryStore) DeleteUser(username string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var cnt int
+	for t, s := range m.sessions {
+		if s.Username == username {
+			delete(m.sessions, t)
+			cnt++
+		}
+	}
+	return cnt
+}
+
+func (m *MemoryStore) expired(s *Session, now time.Time) bool {
+	idle := m.IdleTimeout
+	if idle <= 0 {
+		idle = DefaultIdleTimeout
+	}
+	max := m.MaxAge
+	if max <= 0 {
+		max = DefaultMaxAge
+	}
+	return now.Sub(s.LastUsed) > idle || now.Sub(s.Created) > max
+}
+
+func newToken() string {
+	b := make([]byte, tokenLen)
+	if _, err := rand.Read(b); err != nil {
+		panic("custsrv: cannot read random bytes: " + err.Error())
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,62 @@
+package custsrv
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryStore_CreateGet(t *testing.T) {
+	var ms MemoryStore
+	s := ms.GetNew()
+	var cs Session
+	cs.Username = "bob@test.com"
+	cs.Password = "secret"
+	cs.CustomerID = 4
+	ns := s.Create(&cs)
+	if len(ns.Token) != tokenLen*2 || cs.Token != "" {
+		t.Fail()
+	}
+	gs := s.Get(ns.Token)
+	if gs == nil || gs.CustomerID != 4 || gs.Password != "secret" {
+		t.Fail()
+	}
+	if s.Get("nope") != nil {
+		t.Fail()
+	}
+	if !s.Delete(ns.Token) || s.Get(ns.Token) != nil || s.Delete(ns.Token) {
+		t.Fail()
+	}
+}
+
+func TestMemoryStore_Expiry(t *testing.T) {
+	var ms MemoryStore
+	ms.IdleTimeout = 20 * time.Millisecond
+	s := ms.GetNew()
+	var cs Session
+	cs.Username = "bob@test.com"
+	ns := s.Create(&cs)
+	time.Sleep(40 * time.Millisecond)
+	if s.Get(ns.Token) != nil {
+		t.Fail()
+	}
+	s.Create(&cs)
+	time.Sleep(40 * time.Millisecond)
+	s.Create(&cs)
+	if len(ms.sessions) != 1 {
+		t.Fail()
+	}
+}
+
+func TestMemoryStore_DeleteUser(t *testing.T) {
+	var ms MemoryStore
+	s := ms.GetNew()
+	var cs Session
+	cs.Username = "bob@test.com"
+	a := s.Create(&cs)
+	s.Create(&cs)
+	cs.Username = "amy@test.com"
+	c := s.Create(&cs)
+	if s.DeleteUser("bob@test.com") != 2 || s.Get(a.Token) != nil || s.Get(c.Token) == nil {
+		t.Fail()
+	}
+}
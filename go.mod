@@ -14,4 +14,5 @@ require (
 	github.com/Ulbora/six910-database-interface v1.0.23
 	github.com/gorilla/mux v1.7.4
 	github.com/gorilla/sessions v1.2.0
+	gopkg.in/yaml.v2 v2.4.0
 )
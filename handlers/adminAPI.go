@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Ulbora/Six910-ui/logging"
+	ss "github.com/Ulbora/Six910-ui/sessrv"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/sessions"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+const (
+	apiDefaultPageSize = 50
+	apiMaxPageSize     = 200
+	apiMaxBody         = 1 << 20
+)
+
+//APIError APIError is the body of every admin API error. Fields names
+//the fields that failed validation.
+type APIError struct {
+	Status int         `json:"status"`
+	Error  string      `json:"error"`
+	Fields FieldErrors `json:"fields,omitempty"`
+}
+
+//APIList APIList is one page of a list. Total is -1 for lists the backend
+//pages itself, where only HasMore is known.
+type APIList struct {
+	Data     interface{} `json:"data"`
+	Page     int         `json:"page"`
+	PageSize int         `json:"pageSize"`
+	Total    int         `json:"total"`
+	HasMore  bool        `json:"hasMore"`
+}
+
+//APIResult APIResult
+type APIResult struct {
+	ID      int64 `json:"id,omitempty"`
+	Success bool  `json:"success"`
+}
+
+//AdminAPIAuth AdminAPIAuth lets a request through with an API token in
+//the Authorization header, or with a logged in admin session, which must
+//also send its CSRF token for anything but a read. It does the work of
+//AdminAuth and AdminCSRF for the JSON API.
+func (h *Six910Handler) AdminAPIAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var as *ss.AdminSession
+		var s *sessions.Session
+		if bt := bearerToken(r); bt != "" {
+			as = h.apiTokenSession(bt)
+			if as == nil {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				h.writeAPIError(w, http.StatusUnauthorized, "invalid or expired API token")
+				return
+			}
+		} else {
+			var suc bool
+			s, suc = h.getSession(r)
+			if suc {
+				as = h.getStoreAdmin(s)
+			}
+			if as == nil {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				h.writeAPIError(w, http.StatusUnauthorized, "API token or admin login required")
+				return
+			}
+			token, _ := s.Values[csrfTokenKey].(string)
+			if !csrfValid(r, token) {
+				h.Log.Error("api csrf token missing or invalid for ", r.Method, " ", r.URL.Path)
+				h.writeAPIError(w, http.StatusForbidden, "invalid csrf token")
+				return
+			}
+		}
+		logging.SetUser(r, as.Username)
+		ctx := r.Context()
+		if s != nil {
+			ctx = context.WithValue(ctx, adminSessionKey, s)
+		}
+		ctx = context.WithValue(ctx, adminUserKey, as)
+		ctx = context.WithValue(ctx, adminHeaderKey, h.getHeader(as, r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+//AdminAPINotFound AdminAPINotFound answers paths under the API that
+//match no route
+func (h *Six910Handler) AdminAPINotFound(w http.ResponseWriter, r *http.Request) {
+	h.writeAPIError(w, http.StatusNotFound, "not found")
+}
+
+//apiTokenSession returns the admin an API token acts as. An OAuth2
+//access token that is refreshed is saved back to the API token.
+func (h *Six910Handler) apiTokenSession(bt string) *ss.AdminSession {
+	if h.APITokenService == nil {
+		return nil
+	}
+	tok, err := h.APITokenService.Check(bt)
+	if err != nil {
+		h.Log.Info("api token rejected: ", err)
+		return nil
+	}
+	as := tok.Session
+	if h.OAuth2Enabled {
+		at := as.AccessToken
+		if !h.refreshAdminToken(&as) {
+			h.Log.Info("api token ", tok.ID, " has no valid access token")
+			return nil
+		}
+		if as.AccessToken != at {
+			tok.Session = as
+			h.APITokenService.Update(tok)
+		}
+	}
+	return &as
+}
+
+func bearerToken(r *http.Request) string {
+	ah := r.Header.Get("Authorization")
+	if len(ah) > 7 && strings.EqualFold(ah[:7], "bearer ") {
+		return strings.TrimSpace(ah[7:])
+	}
+	return ""
+}
+
+func (h *Six910Handler) writeAPI(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.Log.Error("api response encode err: ", err)
+	}
+}
+
+func (h *Six910Handler) writeAPIError(w http.ResponseWriter, status int, msg string) {
+	var e APIError
+	e.Status = status
+	e.Error = msg
+	h.writeAPI(w, status, &e)
+}
+
+func (h *Six910Handler) writeAPIFields(w http.ResponseWriter, fe FieldErrors) {
+	var e APIError
+	e.Status = http.StatusUnprocessableEntity
+	e.Error = "validation failed"
+	e.Fields = fe
+	h.writeAPI(w, e.Status, &e)
+}
+
+//readAPI decodes a JSON body into v, refusing fields v does not have so
+//a misspelled field is not silently dropped
+func readAPI(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, apiMaxBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.Decode(&struct{}{}) != io.EOF {
+		return errors.New("body must hold one JSON object")
+	}
+	return nil
+}
+
+//apiID reads the id path variable
+func apiID(r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	return id, err == nil && id > 0
+}
+
+//apiQueryID reads an optional ID from the query string. A missing value
+//is zero.
+func apiQueryID(r *http.Request, name string) (int64, bool) {
+	val := r.URL.Query().Get(name)
+	if val == "" {
+		return 0, true
+	}
+	id, err := strconv.ParseInt(val, 10, 64)
+	return id, err == nil && id > 0
+}
+
+//apiPage apiPage is the page asked for with page (from 1) and pageSize
+type apiPage struct {
+	page int
+	size int
+}
+
+func apiPaging(r *http.Request) (*apiPage, error) {
+	var p = apiPage{page: 1, size: apiDefaultPageSize}
+	q := r.URL.Query()
+	if v := q.Get("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, errors.New("page must be a number from 1")
+		}
+		p.page = n
+	}
+	if v := q.Get("pageSize"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > apiMaxPageSize {
+			return nil, errors.New("pageSize must be a number from 1 to " + strconv.Itoa(apiMaxPageSize))
+		}
+		p.size = n
+	}
+	return &p, nil
+}
+
+func (p *apiPage) start() int {
+	return (p.page - 1) * p.size
+}
+
+//slice returns the page of list, a pointer to a slice that may be nil
+func (p *apiPage) slice(list interface{}) *APIList {
+	var rtn = APIList{Page: p.page, PageSize: p.size}
+	lv := reflect.ValueOf(list)
+	items := reflect.MakeSlice(lv.Type().Elem(), 0, 0)
+	if !lv.IsNil() {
+		items = lv.Elem()
+	}
+	rtn.Total = items.Len()
+	st := p.start()
+	if st > rtn.Total {
+		st = rtn.Total
+	}
+	end := st + p.size
+	if end > rtn.Total {
+		end = rtn.Total
+	}
+	rtn.Data = items.Slice(st, end).Interface()
+	rtn.HasMore = end < rtn.Total
+	return &rtn
+}
+
+//fetch returns a page of a list the backend pages from start up to end.
+//One extra item is asked for to learn if there is another page.
+func (p *apiPage) fetch(get func(start int64, end int64) interface{}) *APIList {
+	st := int64(p.start())
+	var more = apiPage{page: 1, size: p.size + 1}
+	rtn := more.slice(get(st, st+int64(more.size)))
+	rtn.Page = p.page
+	rtn.PageSize = p.size
+	rtn.Total = -1
+	if items := reflect.ValueOf(rtn.Data); items.Len() > p.size {
+		rtn.Data = items.Slice(0, p.size).Interface()
+		rtn.HasMore = true
+	}
+	return rtn
+}
@@ -0,0 +1,513 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+
+	perms "github.com/Ulbora/Six910-ui/permsrv"
+	api "github.com/Ulbora/Six910API-Go"
+	sdbi "github.com/Ulbora/six910-database-interface"
+	"github.com/gorilla/mux"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+const adminAPIPrefix = "/admin/api/v1"
+
+//apiResource apiResource is one kind of entity in the admin API, such as
+//products. It uses the same backend calls, permission area and audit
+//entity as the admin pages. A nil function is an operation the resource
+//does not have.
+type apiResource struct {
+	area   string
+	entity string
+	item   func() interface{}
+	list   func(a api.API, r *http.Request, p *apiPage, hd *api.Headers) (*APIList, error)
+	get    func(a api.API, id int64, hd *api.Headers) interface{}
+	add    func(a api.API, v interface{}, hd *api.Headers) *api.ResponseID
+	update func(a api.API, v interface{}, hd *api.Headers) *api.Response
+	del    func(a api.API, id int64, hd *api.Headers) *api.Response
+	valid  func(v interface{}) FieldErrors
+}
+
+var errQueryID = errors.New("query IDs must be numbers from 1")
+
+var apiResources = map[string]*apiResource{
+	"products": {
+		area:   perms.Products,
+		entity: auditProduct,
+		item:   func() interface{} { return new(sdbi.Product) },
+		list: func(a api.API, r *http.Request, p *apiPage, hd *api.Headers) (*APIList, error) {
+			name := r.URL.Query().Get("name")
+			cid, ok := apiQueryID(r, "categoryId")
+			if !ok {
+				return nil, errQueryID
+			}
+			if name != "" && cid != 0 {
+				return nil, errors.New("use name or categoryId, not both")
+			}
+			return p.fetch(func(st int64, end int64) interface{} {
+				if name != "" {
+					return a.GetProductsByName(url.PathEscape(name), st, end, hd)
+				} else if cid != 0 {
+					return a.GetProductsByCaterory(cid, st, end, hd)
+				}
+				return a.GetProductList(st, end, hd)
+			}), nil
+		},
+		get: func(a api.API, id int64, hd *api.Headers) interface{} { return a.GetProductByID(id, hd) },
+		add: func(a api.API, v interface{}, hd *api.Headers) *api.ResponseID {
+			return a.AddProduct(v.(*sdbi.Product), hd)
+		},
+		update: func(a api.API, v interface{}, hd *api.Headers) *api.Response {
+			return a.UpdateProduct(v.(*sdbi.Product), hd)
+		},
+		del:   func(a api.API, id int64, hd *api.Headers) *api.Response { return a.DeleteProduct(id, hd) },
+		valid: func(v interface{}) FieldErrors { return validateProduct(v.(*sdbi.Product)) },
+	},
+	"categories": {
+		area:   perms.Products,
+		entity: auditCategory,
+		item:   func() interface{} { return new(sdbi.Category) },
+		list: func(a api.API, r *http.Request, p *apiPage, hd *api.Headers) (*APIList, error) {
+			return p.slice(a.GetCategoryList(hd)), nil
+		},
+		get: func(a api.API, id int64, hd *api.Headers) interface{} { return a.GetCategory(id, hd) },
+		add: func(a api.API, v interface{}, hd *api.Headers) *api.ResponseID {
+			return a.AddCategory(v.(*sdbi.Category), hd)
+		},
+		update: func(a api.API, v interface{}, hd *api.Headers) *api.Response {
+			return a.UpdateCategory(v.(*sdbi.Category), hd)
+		},
+		del:   func(a api.API, id int64, hd *api.Headers) *api.Response { return a.DeleteCategory(id, hd) },
+		valid: func(v interface{}) FieldErrors { return validateCategory(v.(*sdbi.Category)) },
+	},
+	"distributors": {
+		area:   perms.Products,
+		entity: auditDistributor,
+		item:   func() interface{} { return new(sdbi.Distributor) },
+		list: func(a api.API, r *http.Request, p *apiPage, hd *api.Headers) (*APIList, error) {
+			return p.slice(a.GetDistributorList(hd)), nil
+		},
+		get: func(a api.API, id int64, hd *api.Headers) interface{} { return a.GetDistributor(id, hd) },
+		add: func(a api.API, v interface{}, hd *api.Headers) *api.ResponseID {
+			return a.AddDistributor(v.(*sdbi.Distributor), hd)
+		},
+		update: func(a api.API, v interface{}, hd *api.Headers) *api.Response {
+			return a.UpdateDistributor(v.(*sdbi.Distributor), hd)
+		},
+		del:   func(a api.API, id int64, hd *api.Headers) *api.Response { return a.DeleteDistributor(id, hd) },
+		valid: func(v interface{}) FieldErrors { return validateDistributor(v.(*sdbi.Distributor)) },
+	},
+	"orders": {
+		area:   perms.Orders,
+		entity: auditOrder,
+		item:   func() interface{} { return new(sdbi.Order) },
+		list: func(a api.API, r *http.Request, p *apiPage, hd *api.Headers) (*APIList, error) {
+			status := r.URL.Query().Get("status")
+			cid, ok := apiQueryID(r, "customerId")
+			if !ok {
+				return nil, errQueryID
+			}
+			if status != "" && cid != 0 {
+				return nil, errors.New("use status or customerId, not both")
+			}
+			if status != "" {
+				return p.slice(a.GetStoreOrderListByStatus(status, hd)), nil
+			} else if cid != 0 {
+				return p.slice(a.GetOrderList(cid, hd)), nil
+			}
+			return p.slice(a.GetStoreOrderList(hd)), nil
+		},
+		get: func(a api.API, id int64, hd *api.Headers) interface{} { return a.GetOrder(id, hd) },
+		update: func(a api.API, v interface{}, hd *api.Headers) *api.Response {
+			return a.UpdateOrder(v.(*sdbi.Order), hd)
+		},
+		valid: func(v interface{}) FieldErrors { return validateOrder(v.(*sdbi.Order)) },
+	},
+	"shipments": {
+		area:   perms.Shipments,
+		entity: auditShipment,
+		item:   func() interface{} { return new(sdbi.Shipment) },
+		list: func(a api.API, r *http.Request, p *apiPage, hd *api.Headers) (*APIList, error) {
+			oid, ok := apiQueryID(r, "orderId")
+			if !ok || oid == 0 {
+				return nil, errors.New("orderId is required")
+			}
+			return p.slice(a.GetShipmentList(oid, hd)), nil
+		},
+		get: func(a api.API, id int64, hd *api.Headers) interface{} { return a.GetShipment(id, hd) },
+		add: func(a api.API, v interface{}, hd *api.Headers) *api.ResponseID {
+			return a.AddShipment(v.(*sdbi.Shipment), hd)
+		},
+		update: func(a api.API, v interface{}, hd *api.Headers) *api.Response {
+			return a.UpdateShipment(v.(*sdbi.Shipment), hd)
+		},
+		del:   func(a api.API, id int64, hd *api.Headers) *api.Response { return a.DeleteShipment(id, hd) },
+		valid: func(v interface{}) FieldErrors { return validateShipment(v.(*sdbi.Shipment)) },
+	},
+	"customers": {
+		area:   perms.Customers,
+		entity: auditCustomer,
+		item:   func() interface{} { return new(sdbi.Customer) },
+		list: func(a api.API, r *http.Request, p *apiPage, hd *api.Headers) (*APIList, error) {
+			return p.slice(a.GetCustomerList(hd)), nil
+		},
+		get: func(a api.API, id int64, hd *api.Headers) interface{} { return a.GetCustomerID(id, hd) },
+		update: func(a api.API, v interface{}, hd *api.Headers) *api.Response {
+			return a.UpdateCustomer(v.(*sdbi.Customer), hd)
+		},
+		valid: func(v interface{}) FieldErrors { return validateCustomer(v.(*sdbi.Customer)) },
+	},
+	"insurance": {
+		area:   perms.Payments,
+		entity: auditInsurance,
+		item:   func() interface{} { return new(sdbi.Insurance) },
+		list: func(a api.API, r *http.Request, p *apiPage, hd *api.Headers) (*APIList, error) {
+			return p.slice(a.GetInsuranceList(hd)), nil
+		},
+		get: func(a api.API, id int64, hd *api.Headers) interface{} { return a.GetInsurance(id, hd) },
+		add: func(a api.API, v interface{}, hd *api.Headers) *api.ResponseID {
+			return a.AddInsurance(v.(*sdbi.Insurance), hd)
+		},
+		update: func(a api.API, v interface{}, hd *api.Headers) *api.Response {
+			return a.UpdateInsurance(v.(*sdbi.Insurance), hd)
+		},
+		del:   func(a api.API, id int64, hd *api.Headers) *api.Response { return a.DeleteInsurance(id, hd) },
+		valid: func(v interface{}) FieldErrors { return validateInsurance(v.(*sdbi.Insurance)) },
+	},
+	"paymentGateways": {
+		area:   perms.Payments,
+		entity: auditPaymentGateway,
+		item:   func() interface{} { return new(sdbi.PaymentGateway) },
+		list: func(a api.API, r *http.Request, p *apiPage, hd *api.Headers) (*APIList, error) {
+			return p.slice(a.GetPaymentGateways(hd)), nil
+		},
+		get: func(a api.API, id int64, hd *api.Headers) interface{} { return a.GetPaymentGateway(id, hd) },
+		add: func(a api.API, v interface{}, hd *api.Headers) *api.ResponseID {
+			return a.AddPaymentGateway(v.(*sdbi.PaymentGateway), hd)
+		},
+		update: func(a api.API, v interface{}, hd *api.Headers) *api.Response {
+			return a.UpdatePaymentGateway(v.(*sdbi.PaymentGateway), hd)
+		},
+		del:   func(a api.API, id int64, hd *api.Headers) *api.Response { return a.DeletePaymentGateway(id, hd) },
+		valid: func(v interface{}) FieldErrors { return validatePaymentGateway(v.(*sdbi.PaymentGateway)) },
+	},
+	"plugins": {
+		area:   perms.Plugins,
+		entity: auditPlugin,
+		item:   func() interface{} { return new(sdbi.Plugins) },
+		list: func(a api.API, r *http.Request, p *apiPage, hd *api.Headers) (*APIList, error) {
+			return p.fetch(func(st int64, end int64) interface{} {
+				return a.GetPluginList(st, end, hd)
+			}), nil
+		},
+		get: func(a api.API, id int64, hd *api.Headers) interface{} { return a.GetPlugin(id, hd) },
+		add: func(a api.API, v interface{}, hd *api.Headers) *api.ResponseID {
+			return a.AddPlugin(v.(*sdbi.Plugins), hd)
+		},
+		update: func(a api.API, v interface{}, hd *api.Headers) *api.Response {
+			return a.UpdatePlugin(v.(*sdbi.Plugins), hd)
+		},
+		del:   func(a api.API, id int64, hd *api.Headers) *api.Response { return a.DeletePlugin(id, hd) },
+		valid: func(v interface{}) FieldErrors { return validatePlugin(v.(*sdbi.Plugins)) },
+	},
+	"shippingCarriers": {
+		area:   perms.Shipping,
+		entity: auditShippingCarrier,
+		item:   func() interface{} { return new(sdbi.ShippingCarrier) },
+		list: func(a api.API, r *http.Request, p *apiPage, hd *api.Headers) (*APIList, error) {
+			return p.slice(a.GetShippingCarrierList(hd)), nil
+		},
+		get: func(a api.API, id int64, hd *api.Headers) interface{} { return a.GetShippingCarrier(id, hd) },
+		add: func(a api.API, v interface{}, hd *api.Headers) *api.ResponseID {
+			return a.AddShippingCarrier(v.(*sdbi.ShippingCarrier), hd)
+		},
+		update: func(a api.API, v interface{}, hd *api.Headers) *api.Response {
+			return a.UpdateShippingCarrier(v.(*sdbi.ShippingCarrier), hd)
+		},
+		del:   func(a api.API, id int64, hd *api.Headers) *api.Response { return a.DeleteShippingCarrier(id, hd) },
+		valid: func(v interface{}) FieldErrors { return validateShippingCarrier(v.(*sdbi.ShippingCarrier)) },
+	},
+	"shippingMethods": {
+		area:   perms.Shipping,
+		entity: auditShippingMethod,
+		item:   func() interface{} { return new(sdbi.ShippingMethod) },
+		list: func(a api.API, r *http.Request, p *apiPage, hd *api.Headers) (*APIList, error) {
+			return p.slice(a.GetShippingMethodList(hd)), nil
+		},
+		get: func(a api.API, id int64, hd *api.Headers) interface{} { return a.GetShippingMethod(id, hd) },
+		add: func(a api.API, v interface{}, hd *api.Headers) *api.ResponseID {
+			return a.AddShippingMethod(v.(*sdbi.ShippingMethod), hd)
+		},
+		update: func(a api.API, v interface{}, hd *api.Headers) *api.Response {
+			return a.UpdateShippingMethod(v.(*sdbi.ShippingMethod), hd)
+		},
+		del:   func(a api.API, id int64, hd *api.Headers) *api.Response { return a.DeleteShippingMethod(id, hd) },
+		valid: func(v interface{}) FieldErrors { return validateShippingMethod(v.(*sdbi.ShippingMethod)) },
+	},
+	"regions": {
+		area:   perms.Regions,
+		entity: auditRegion,
+		item:   func() interface{} { return new(sdbi.Region) },
+		list: func(a api.API, r *http.Request, p *apiPage, hd *api.Headers) (*APIList, error) {
+			return p.slice(a.GetRegionList(hd)), nil
+		},
+		get: func(a api.API, id int64, hd *api.Headers) interface{} { return a.GetRegion(id, hd) },
+		add: func(a api.API, v interface{}, hd *api.Headers) *api.ResponseID {
+			return a.AddRegion(v.(*sdbi.Region), hd)
+		},
+		update: func(a api.API, v interface{}, hd *api.Headers) *api.Response {
+			return a.UpdateRegion(v.(*sdbi.Region), hd)
+		},
+		del:   func(a api.API, id int64, hd *api.Headers) *api.Response { return a.DeleteRegion(id, hd) },
+		valid: func(v interface{}) FieldErrors { return validateRegion(v.(*sdbi.Region)) },
+	},
+	"subRegions": {
+		area:   perms.Regions,
+		entity: auditSubRegion,
+		item:   func() interface{} { return new(sdbi.SubRegion) },
+		list: func(a api.API, r *http.Request, p *apiPage, hd *api.Headers) (*APIList, error) {
+			rid, ok := apiQueryID(r, "regionId")
+			if !ok || rid == 0 {
+				return nil, errors.New("regionId is required")
+			}
+			return p.slice(a.GetSubRegionList(rid, hd)), nil
+		},
+		get: func(a api.API, id int64, hd *api.Headers) interface{} { return a.GetSubRegion(id, hd) },
+		add: func(a api.API, v interface{}, hd *api.Headers) *api.ResponseID {
+			return a.AddSubRegion(v.(*sdbi.SubRegion), hd)
+		},
+		update: func(a api.API, v interface{}, hd *api.Headers) *api.Response {
+			return a.UpdateSubRegion(v.(*sdbi.SubRegion), hd)
+		},
+		del:   func(a api.API, id int64, hd *api.Headers) *api.Response { return a.DeleteSubRegion(id, hd) },
+		valid: func(v interface{}) FieldErrors { return validateSubRegion(v.(*sdbi.SubRegion)) },
+	},
+}
+
+//AdminAPIList AdminAPIList returns a page of a resource
+func (h *Six910Handler) AdminAPIList(w http.ResponseWriter, r *http.Request) {
+	res := h.apiResource(w, r, false)
+	if res == nil {
+		return
+	}
+	if res.list == nil {
+		h.writeAPIError(w, http.StatusMethodNotAllowed, "list is not supported")
+		return
+	}
+	p, err := apiPaging(r)
+	if err == nil {
+		var lst *APIList
+		lst, err = res.list(h.API, r, p, h.getAdminHeader(r))
+		if err == nil {
+			h.writeAPI(w, http.StatusOK, lst)
+			return
+		}
+	}
+	h.writeAPIError(w, http.StatusBadRequest, err.Error())
+}
+
+//AdminAPIGet AdminAPIGet
+func (h *Six910Handler) AdminAPIGet(w http.ResponseWriter, r *http.Request) {
+	res := h.apiResource(w, r, false)
+	if res == nil {
+		return
+	}
+	if cur := h.apiCurrent(w, r, res); cur != nil {
+		h.writeAPI(w, http.StatusOK, cur)
+	}
+}
+
+//AdminAPIAdd AdminAPIAdd creates an entity and answers 201 with its
+//Location. The ID in the body is ignored.
+func (h *Six910Handler) AdminAPIAdd(w http.ResponseWriter, r *http.Request) {
+	res := h.apiResource(w, r, true)
+	if res == nil {
+		return
+	}
+	if res.add == nil {
+		h.writeAPIError(w, http.StatusMethodNotAllowed, "add is not supported")
+		return
+	}
+	v := h.apiBody(w, r, res)
+	if v == nil {
+		return
+	}
+	apiSetID(v, 0)
+	rid := res.add(h.API, v, h.getAdminHeader(r))
+	if rid == nil {
+		rid = new(api.ResponseID)
+	}
+	if !rid.Success || rid.ID == 0 {
+		h.writeAPIBackend(w, rid.Code, rid.Message)
+		return
+	}
+	apiSetID(v, rid.ID)
+	h.auditCreate(r, res.entity, rid.ID, v)
+	w.Header().Set("Location", adminAPIPrefix+"/"+mux.Vars(r)["resource"]+"/"+strconv.FormatInt(rid.ID, 10))
+	h.writeAPI(w, http.StatusCreated, v)
+}
+
+//AdminAPIUpdate AdminAPIUpdate replaces an entity with the body, which
+//must hold the whole entity
+func (h *Six910Handler) AdminAPIUpdate(w http.ResponseWriter, r *http.Request) {
+	res := h.apiResource(w, r, true)
+	if res == nil {
+		return
+	}
+	if res.update == nil {
+		h.writeAPIError(w, http.StatusMethodNotAllowed, "update is not supported")
+		return
+	}
+	cur := h.apiCurrent(w, r, res)
+	if cur == nil {
+		return
+	}
+	v := h.apiBody(w, r, res)
+	if v == nil {
+		return
+	}
+	id := apiIDOf(cur)
+	if bid := apiIDOf(v); bid != 0 && bid != id {
+		h.writeAPIError(w, http.StatusBadRequest, "id in the body does not match the URL")
+		return
+	}
+	apiSetID(v, id)
+	ae := h.auditBefore(r, res.entity, id, func() interface{} { return cur })
+	ur := res.update(h.API, v, h.getAdminHeader(r))
+	if ur == nil {
+		ur = new(api.Response)
+	}
+	if !ur.Success {
+		h.writeAPIBackend(w, ur.Code, ur.Message)
+		return
+	}
+	ae.updated(v)
+	h.writeAPI(w, http.StatusOK, v)
+}
+
+//AdminAPIDelete AdminAPIDelete
+func (h *Six910Handler) AdminAPIDelete(w http.ResponseWriter, r *http.Request) {
+	res := h.apiResource(w, r, true)
+	if res == nil {
+		return
+	}
+	if res.del == nil {
+		h.writeAPIError(w, http.StatusMethodNotAllowed, "delete is not supported")
+		return
+	}
+	cur := h.apiCurrent(w, r, res)
+	if cur == nil {
+		return
+	}
+	id := apiIDOf(cur)
+	ae := h.auditBefore(r, res.entity, id, func() interface{} { return cur })
+	dr := res.del(h.API, id, h.getAdminHeader(r))
+	if dr == nil {
+		dr = new(api.Response)
+	}
+	if !dr.Success {
+		h.writeAPIBackend(w, dr.Code, dr.Message)
+		return
+	}
+	ae.deleted()
+	h.writeAPI(w, http.StatusOK, &APIResult{ID: id, Success: true})
+}
+
+//apiResource finds the resource named in the path and checks the
+//admin's permission for it
+func (h *Six910Handler) apiResource(w http.ResponseWriter, r *http.Request, write bool) *apiResource {
+	res, ok := apiResources[mux.Vars(r)["resource"]]
+	if !ok {
+		h.writeAPIError(w, http.StatusNotFound, "unknown resource")
+		return nil
+	}
+	if !h.adminAllowed(r, res.area, write) {
+		var username string
+		if as := h.getAdminUser(r); as != nil {
+			username = as.Username
+		}
+		h.Log.Info("admin api permission denied for: ", username, " area: ", res.area, " ", r.Method, " ", r.URL.Path)
+		h.writeAPIError(w, http.StatusForbidden, "you do not have permission for "+res.area)
+		return nil
+	}
+	return res
+}
+
+//apiCurrent reads the entity named in the path, writing a 404 when there
+//is none
+func (h *Six910Handler) apiCurrent(w http.ResponseWriter, r *http.Request, res *apiResource) interface{} {
+	if res.get == nil {
+		h.writeAPIError(w, http.StatusMethodNotAllowed, "get is not supported")
+		return nil
+	}
+	id, ok := apiID(r)
+	if ok {
+		cur := res.get(h.API, id, h.getAdminHeader(r))
+		if apiIDOf(cur) == id {
+			return cur
+		}
+	}
+	h.writeAPIError(w, http.StatusNotFound, "not found")
+	return nil
+}
+
+//apiBody decodes and validates the request body for res
+func (h *Six910Handler) apiBody(w http.ResponseWriter, r *http.Request, res *apiResource) interface{} {
+	v := res.item()
+	if err := readAPI(w, r, v); err != nil {
+		h.writeAPIError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return nil
+	}
+	if fe := res.valid(v); len(fe) > 0 {
+		h.writeAPIFields(w, fe)
+		return nil
+	}
+	return v
+}
+
+//writeAPIBackend passes on a client error from the backend; anything
+//else, including no answer, is a bad gateway
+func (h *Six910Handler) writeAPIBackend(w http.ResponseWriter, code int64, msg string) {
+	status := int(code)
+	if status < 400 || status > 499 {
+		status = http.StatusBadGateway
+	}
+	if msg == "" {
+		msg = "the store backend did not accept the change"
+	}
+	h.writeAPIError(w, status, msg)
+}
+
+//apiIDOf returns the ID field of a pointer to an entity, or zero for nil
+func apiIDOf(v interface{}) int64 {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || rv.IsNil() {
+		return 0
+	}
+	return rv.Elem().FieldByName("ID").Int()
+}
+
+func apiSetID(v interface{}, id int64) {
+	reflect.ValueOf(v).Elem().FieldByName("ID").SetInt(id)
+}
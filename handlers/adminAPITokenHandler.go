@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	apitok "github.com/Ulbora/Six910-ui/apitoksrv"
+	ss "github.com/Ulbora/Six910-ui/sessrv"
+	"github.com/gorilla/mux"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+const (
+	apiTokenDefaultDays = 90
+	apiTokenMaxDays     = 365
+)
+
+//APITokenRow APITokenRow is an API token on the token page, without its
+//hash or backend credential
+type APITokenRow struct {
+	ID       string
+	Name     string
+	Created  time.Time
+	LastUsed time.Time
+	Expires  time.Time
+	Expired  bool
+}
+
+//APITokenPage APITokenPage lists the admin's API tokens. NewToken is
+//only set on the page that made it; it can not be shown again.
+type APITokenPage struct {
+	Error    string
+	NewToken string
+	Tokens   []APITokenRow
+}
+
+//StoreAdminViewAPITokenList StoreAdminViewAPITokenList
+func (h *Six910Handler) StoreAdminViewAPITokenList(w http.ResponseWriter, r *http.Request) {
+	if h.APITokenService == nil {
+		http.NotFound(w, r)
+		return
+	}
+	var tp APITokenPage
+	tp.Error = r.URL.Query().Get("error")
+	h.showAPITokens(w, r, &tp)
+}
+
+//StoreAdminAddAPIToken StoreAdminAddAPIToken makes a token that acts as
+//the logged in admin for days (90 when blank)
+func (h *Six910Handler) StoreAdminAddAPIToken(w http.ResponseWriter, r *http.Request) {
+	if h.APITokenService == nil {
+		http.NotFound(w, r)
+		return
+	}
+	var tp APITokenPage
+	as := h.getAdminUser(r)
+	name := strings.TrimSpace(r.FormValue("name"))
+	days, err := apiTokenDays(r.FormValue("days"))
+	if name == "" {
+		tp.Error = "Name is required"
+	} else if err != nil {
+		tp.Error = err.Error()
+	} else {
+		var t apitok.Token
+		t.Name = name
+		t.Username = as.Username
+		t.Session = apiTokenSessionOf(as)
+		t.Expires = time.Now().AddDate(0, 0, days)
+		tok, cerr := h.APITokenService.Create(&t)
+		if cerr != nil {
+			h.Log.Error("api token create err: ", cerr)
+			tp.Error = "Token not created"
+		} else {
+			tp.NewToken = tok
+			h.auditCreateKey(r, auditAPIToken, t.ID, apiTokenRowOf(&t))
+		}
+	}
+	h.showAPITokens(w, r, &tp)
+}
+
+//StoreAdminDeleteAPIToken StoreAdminDeleteAPIToken revokes one of the
+//logged in admin's tokens
+func (h *Six910Handler) StoreAdminDeleteAPIToken(w http.ResponseWriter, r *http.Request) {
+	if h.APITokenService == nil {
+		http.NotFound(w, r)
+		return
+	}
+	id := mux.Vars(r)["id"]
+	as := h.getAdminUser(r)
+	var found bool
+	for _, t := range *h.APITokenService.List(as.Username) {
+		if t.ID == id {
+			row := apiTokenRowOf(&t)
+			ae := h.auditBeforeKey(r, auditAPIToken, id, func() interface{} { return row })
+			found = h.APITokenService.Delete(id)
+			if found {
+				ae.deleted()
+			}
+			break
+		}
+	}
+	if found {
+		http.Redirect(w, r, adminAPITokenListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminAPITokenListViewFail, http.StatusFound)
+	}
+}
+
+func (h *Six910Handler) showAPITokens(w http.ResponseWriter, r *http.Request, tp *APITokenPage) {
+	for _, t := range *h.APITokenService.List(h.getAdminUser(r).Username) {
+		tp.Tokens = append(tp.Tokens, apiTokenRowOf(&t))
+	}
+	h.executeAdminTemplate(w, r, adminAPITokenPage, tp)
+}
+
+//apiTokenSessionOf copies the backend credential of as without the
+//session ID, so a refresh for the token never touches the admin's login
+func apiTokenSessionOf(as *ss.AdminSession) ss.AdminSession {
+	var rtn ss.AdminSession
+	rtn.Username = as.Username
+	rtn.Password = as.Password
+	rtn.AccessToken = as.AccessToken
+	rtn.RefreshToken = as.RefreshToken
+	rtn.TokenExpires = as.TokenExpires
+	return rtn
+}
+
+func apiTokenRowOf(t *apitok.Token) APITokenRow {
+	var row APITokenRow
+	row.ID = t.ID
+	row.Name = t.Name
+	row.Created = t.Created
+	row.LastUsed = t.LastUsed
+	row.Expires = t.Expires
+	row.Expired = t.Expired(time.Now())
+	return row
+}
+
+func apiTokenDays(val string) (int, error) {
+	if strings.TrimSpace(val) == "" {
+		return apiTokenDefaultDays, nil
+	}
+	days, err := strconv.Atoi(strings.TrimSpace(val))
+	if err != nil || days < 1 || days > apiTokenMaxDays {
+		return 0, errors.New("Days must be from 1 to " + strconv.Itoa(apiTokenMaxDays))
+	}
+	return days, nil
+}
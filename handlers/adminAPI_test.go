@@ -0,0 +1,290 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	apitok "github.com/Ulbora/Six910-ui/apitoksrv"
+	audit "github.com/Ulbora/Six910-ui/auditsrv"
+	perms "github.com/Ulbora/Six910-ui/permsrv"
+	api "github.com/Ulbora/Six910API-Go"
+	sdbi "github.com/Ulbora/six910-database-interface"
+	"github.com/gorilla/mux"
+)
+
+func apiTestRouter(sh *Six910Handler) *mux.Router {
+	router := mux.NewRouter()
+	adminAPI := router.PathPrefix("/admin/api/v1").Subrouter()
+	adminAPI.Use(sh.AdminAPIAuth)
+	adminAPI.HandleFunc("/{resource}", sh.AdminAPIList).Methods("GET")
+	adminAPI.HandleFunc("/{resource}", sh.AdminAPIAdd).Methods("POST")
+	adminAPI.HandleFunc("/{resource}/{id:[0-9]+}", sh.AdminAPIGet).Methods("GET")
+	adminAPI.HandleFunc("/{resource}/{id:[0-9]+}", sh.AdminAPIUpdate).Methods("PUT")
+	adminAPI.HandleFunc("/{resource}/{id:[0-9]+}", sh.AdminAPIDelete).Methods("DELETE")
+	return router
+}
+
+func apiTestToken(t *testing.T, sh *Six910Handler, username string) string {
+	var tk apitok.Token
+	tk.Name = "script"
+	tk.Username = username
+	tk.Session.Username = username
+	tk.Session.Password = "tester"
+	str, err := sh.APITokenService.Create(&tk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return str
+}
+
+func serveAPI(sh *Six910Handler, token string, method string, url string, body string) *httptest.ResponseRecorder {
+	r, _ := http.NewRequest(method, url, strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	apiTestRouter(sh).ServeHTTP(w, r)
+	return w
+}
+
+func TestSix910Handler_AdminAPIListToken(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	useAPITokens(sh, dir)
+	defer os.RemoveAll(dir)
+	sapi.MockProductList = &[]sdbi.Product{{ID: 1}, {ID: 2}, {ID: 3}}
+	tok := apiTestToken(t, sh, "tester")
+
+	w := serveAPI(sh, tok, "GET", "/admin/api/v1/products?page=2&pageSize=2", "")
+	var lst struct {
+		Data     []sdbi.Product `json:"data"`
+		Page     int            `json:"page"`
+		PageSize int            `json:"pageSize"`
+		Total    int            `json:"total"`
+		HasMore  bool           `json:"hasMore"`
+	}
+	json.NewDecoder(w.Body).Decode(&lst)
+	fmt.Println("list: ", w.Code, lst)
+	if w.Code != 200 || len(lst.Data) != 2 || lst.Page != 2 || lst.Total != -1 || !lst.HasMore {
+		t.Fail()
+	}
+
+	w = serveAPI(sh, "six910_bad.token", "GET", "/admin/api/v1/products", "")
+	if w.Code != 401 || !strings.Contains(w.Body.String(), `"status":401`) {
+		fmt.Println("bad token: ", w.Code, w.Body.String())
+		t.Fail()
+	}
+	w = serveAPI(sh, tok, "GET", "/admin/api/v1/products?pageSize=500", "")
+	if w.Code != 400 {
+		t.Fail()
+	}
+	w = serveAPI(sh, tok, "GET", "/admin/api/v1/widgets", "")
+	if w.Code != 404 {
+		t.Fail()
+	}
+	w = serveAPI(sh, tok, "GET", "/admin/api/v1/subRegions", "")
+	if w.Code != 400 || !strings.Contains(w.Body.String(), "regionId is required") {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_AdminAPIListSlice(t *testing.T) {
+	sh, _, dir := testHandler(t)
+	useAPITokens(sh, dir)
+	defer os.RemoveAll(dir)
+	tok := apiTestToken(t, sh, "tester")
+
+	w := serveAPI(sh, tok, "GET", "/admin/api/v1/categories?page=3&pageSize=2", "")
+	fmt.Println("nil list: ", w.Body.String())
+	if w.Code != 200 || !strings.Contains(w.Body.String(), `"data":[]`) || !strings.Contains(w.Body.String(), `"total":0`) {
+		t.Fail()
+	}
+
+	var p = apiPage{page: 2, size: 2}
+	lst := p.slice(&[]sdbi.Category{{ID: 1}, {ID: 2}, {ID: 3}})
+	if lst.Total != 3 || lst.HasMore || len(lst.Data.([]sdbi.Category)) != 1 || lst.Data.([]sdbi.Category)[0].ID != 3 {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_AdminAPIAdd(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	useAPITokens(sh, dir)
+	defer os.RemoveAll(dir)
+	var rid api.ResponseID
+	rid.ID = 12
+	rid.Success = true
+	sapi.MockAddDistributorResp = &rid
+	tok := apiTestToken(t, sh, "tester")
+
+	w := serveAPI(sh, tok, "POST", "/admin/api/v1/distributors", `{"id":99,"company":"acme"}`)
+	fmt.Println("add: ", w.Code, w.Body.String(), w.Header().Get("Location"))
+	if w.Code != 201 || w.Header().Get("Location") != "/admin/api/v1/distributors/12" || !strings.Contains(w.Body.String(), `"id":12`) {
+		t.Fail()
+	}
+	es := *sh.AuditService.Search(&audit.Filter{})
+	if len(es) != 1 || es[0].Action != audit.Create || es[0].EntityID != "12" || es[0].Username != "tester" {
+		fmt.Println("entries: ", es)
+		t.Fail()
+	}
+
+	w = serveAPI(sh, tok, "POST", "/admin/api/v1/distributors", `{"company":""}`)
+	if w.Code != 422 || !strings.Contains(w.Body.String(), `"company":"is required"`) {
+		fmt.Println("invalid: ", w.Code, w.Body.String())
+		t.Fail()
+	}
+	w = serveAPI(sh, tok, "POST", "/admin/api/v1/distributors", `{"compnay":"acme"}`)
+	if w.Code != 400 {
+		t.Fail()
+	}
+	w = serveAPI(sh, tok, "POST", "/admin/api/v1/orders", `{"status":"new"}`)
+	if w.Code != 405 {
+		t.Fail()
+	}
+	var bad api.ResponseID
+	bad.Code = 409
+	bad.Message = "duplicate"
+	sapi.MockAddDistributorResp = &bad
+	w = serveAPI(sh, tok, "POST", "/admin/api/v1/distributors", `{"company":"acme"}`)
+	if w.Code != 409 || !strings.Contains(w.Body.String(), "duplicate") {
+		t.Fail()
+	}
+	sapi.MockAddDistributorResp = nil
+	w = serveAPI(sh, tok, "POST", "/admin/api/v1/distributors", `{"company":"acme"}`)
+	if w.Code != 502 {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_AdminAPIUpdateDelete(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	useAPITokens(sh, dir)
+	defer os.RemoveAll(dir)
+	sapi.MockRegion = &sdbi.Region{ID: 4, RegionCode: "US", Name: "old"}
+	var ok api.Response
+	ok.Success = true
+	sapi.MockUpdateRegionResp = &ok
+	sapi.MockDeleteRegionResp = &ok
+	tok := apiTestToken(t, sh, "tester")
+
+	w := serveAPI(sh, tok, "PUT", "/admin/api/v1/regions/4", `{"regionCode":"US","name":"new"}`)
+	fmt.Println("update: ", w.Code, w.Body.String())
+	if w.Code != 200 || !strings.Contains(w.Body.String(), `"id":4`) {
+		t.Fail()
+	}
+	w = serveAPI(sh, tok, "PUT", "/admin/api/v1/regions/4", `{"id":5,"regionCode":"US","name":"new"}`)
+	if w.Code != 400 {
+		t.Fail()
+	}
+	w = serveAPI(sh, tok, "GET", "/admin/api/v1/regions/7", "")
+	if w.Code != 404 {
+		t.Fail()
+	}
+	w = serveAPI(sh, tok, "DELETE", "/admin/api/v1/regions/4", "")
+	if w.Code != 200 || !strings.Contains(w.Body.String(), `"success":true`) {
+		t.Fail()
+	}
+	es := *sh.AuditService.Search(&audit.Filter{})
+	fmt.Println("entries: ", es)
+	if len(es) != 2 {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_AdminAPIPermission(t *testing.T) {
+	sh, _, dir := testHandler(t)
+	useAPITokens(sh, dir)
+	defer os.RemoveAll(dir)
+	var ps perms.Six910PermissionService
+	ps.Users = map[string][]string{"tester": {"fulfillment"}}
+	sh.PermissionService = ps.GetNew()
+	tok := apiTestToken(t, sh, "tester")
+
+	w := serveAPI(sh, tok, "DELETE", "/admin/api/v1/products/3", "")
+	if w.Code != 403 || !strings.Contains(w.Body.String(), "products") {
+		fmt.Println("perm: ", w.Code, w.Body.String())
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_AdminAPISession(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	useAPITokens(sh, dir)
+	defer os.RemoveAll(dir)
+	sapi.MockCategory = &sdbi.Category{ID: 3, Name: "hats"}
+
+	r, _ := http.NewRequest("DELETE", "/admin/api/v1/categories/3", nil)
+	s, _ := sh.getSession(r)
+	loginTestAdmin(sh, s)
+	s.Values["storeAdminUser"] = true
+	s.Values[csrfTokenKey] = "csrf123"
+	w := httptest.NewRecorder()
+	apiTestRouter(sh).ServeHTTP(w, r)
+	if w.Code != 403 || !strings.Contains(w.Body.String(), "csrf") {
+		fmt.Println("no csrf: ", w.Code, w.Body.String())
+		t.Fail()
+	}
+
+	r.Header.Set(csrfTokenHeader, "csrf123")
+	w = httptest.NewRecorder()
+	apiTestRouter(sh).ServeHTTP(w, r)
+	fmt.Println("csrf: ", w.Code, w.Body.String())
+	if w.Code != 502 {
+		t.Fail()
+	}
+
+	r, _ = http.NewRequest("GET", "/admin/api/v1/categories/3", nil)
+	w = httptest.NewRecorder()
+	apiTestRouter(sh).ServeHTTP(w, r)
+	if w.Code != 401 || w.Header().Get("WWW-Authenticate") == "" {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_APITokenPages(t *testing.T) {
+	sh, _, dir := testHandler(t)
+	useAPITokens(sh, dir)
+	defer os.RemoveAll(dir)
+	sh.AdminTemplates = template.Must(template.New("admin").Parse(`{{define "apiTokens.html"}}{{.NewToken}}{{end}}`))
+	other := apiTestToken(t, sh, "other")
+	otk, _ := sh.APITokenService.Check(other)
+
+	r, _ := http.NewRequest("POST", "/admin/addApiToken", strings.NewReader("name=deploy&days=30"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := serveAsAdmin(sh, sh.StoreAdminAddAPIToken, r)
+	toks := *sh.APITokenService.List("tester")
+	ntk, err := sh.APITokenService.Check(w.Body.String())
+	if w.Code != 200 || len(toks) != 1 || toks[0].Name != "deploy" || toks[0].Session.Password != "" ||
+		err != nil || ntk.Session.ID != "" || ntk.Session.Password != "tester" {
+		fmt.Println("add token: ", w.Code, toks)
+		t.Fail()
+	}
+
+	r, _ = http.NewRequest("POST", "/admin/deleteApiToken/"+otk.ID, nil)
+	r = mux.SetURLVars(r, map[string]string{"id": otk.ID})
+	w = serveAsAdmin(sh, sh.StoreAdminDeleteAPIToken, r)
+	if w.Header().Get("Location") != adminAPITokenListViewFail {
+		t.Fail()
+	}
+
+	r, _ = http.NewRequest("POST", "/admin/deleteApiToken/"+toks[0].ID, nil)
+	r = mux.SetURLVars(r, map[string]string{"id": toks[0].ID})
+	w = serveAsAdmin(sh, sh.StoreAdminDeleteAPIToken, r)
+	if w.Header().Get("Location") != adminAPITokenListView || len(*sh.APITokenService.List("tester")) != 0 {
+		t.Fail()
+	}
+
+	sh.endUserSessions("other")
+	if len(*sh.APITokenService.List("other")) != 0 {
+		t.Fail()
+	}
+	if _, err := apiTokenDays("400"); err == nil {
+		t.Fail()
+	}
+}
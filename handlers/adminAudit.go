@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	audit "github.com/Ulbora/Six910-ui/auditsrv"
+	"github.com/Ulbora/Six910-ui/logging"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+//audited entity types
+const (
+	auditAdminUser         = "adminUser"
+	auditAPIToken          = "apiToken"
+	auditCategory          = "category"
+	auditCustomer          = "customer"
+	auditCustomerUser      = "customerUser"
+	auditDistributor       = "distributor"
+	auditExcludedSubRegion = "excludedSubRegion"
+	auditIncludedSubRegion = "includedSubRegion"
+	auditInsurance         = "insurance"
+	auditOrder             = "order"
+	auditOrderComment      = "orderComment"
+	auditPaymentGateway    = "paymentGateway"
+	auditPlugin            = "plugin"
+	auditProduct           = "product"
+	auditProductCategory   = "productCategory"
+	auditProductUpload     = "productUpload"
+	auditRegion            = "region"
+	auditShipment          = "shipment"
+	auditShipmentItem      = "shipmentItem"
+	auditShippingCarrier   = "shippingCarrier"
+	auditShippingMethod    = "shippingMethod"
+	auditStorePlugin       = "storePlugin"
+	auditSubRegion         = "subRegion"
+)
+
+//auditPageLimit is the most entries shown on the audit log page; the
+//CSV export has no limit
+const auditPageLimit = 500
+
+//auditDateFormat is the format of the from and to filters
+const auditDateFormat = "2006-01-02"
+
+//AuditPage AuditPage
+type AuditPage struct {
+	Error    string
+	Username string
+	Entity   string
+	From     string
+	To       string
+	Entries  *[]audit.Entry
+	Limited  bool
+}
+
+//auditEdit holds an entity as it was before an admin update or delete.
+//A nil auditEdit records nothing.
+type auditEdit struct {
+	h      *Six910Handler
+	r      *http.Request
+	entity string
+	id     string
+	before interface{}
+}
+
+//auditBefore reads the entity with get before it is changed. get is not
+//called when there is no AuditService.
+func (h *Six910Handler) auditBefore(r *http.Request, entity string, id int64, get func() interface{}) *auditEdit {
+	return h.auditBeforeKey(r, entity, strconv.FormatInt(id, 10), get)
+}
+
+//auditBeforeKey is auditBefore for entities keyed by name, such as users
+func (h *Six910Handler) auditBeforeKey(r *http.Request, entity string, key string, get func() interface{}) *auditEdit {
+	if h.AuditService == nil {
+		return nil
+	}
+	var ae auditEdit
+	ae.h = h
+	ae.r = r
+	ae.entity = entity
+	ae.id = key
+	if get != nil {
+		ae.before = get()
+	}
+	return &ae
+}
+
+//updated records the change from before to after
+func (ae *auditEdit) updated(after interface{}) {
+	if ae != nil {
+		ae.h.audit(ae.r, audit.Update, ae.entity, ae.id, audit.Diff(ae.before, after))
+	}
+}
+
+//deleted records the entity as removed
+func (ae *auditEdit) deleted() {
+	if ae != nil {
+		ae.h.audit(ae.r, audit.Delete, ae.entity, ae.id, audit.Diff(ae.before, nil))
+	}
+}
+
+//auditPassword records a password change without either password
+func (h *Six910Handler) auditPassword(r *http.Request, entity string, username string) {
+	if h.AuditService != nil {
+		var c audit.Change
+		c.Field = "password"
+		c.Before = audit.Masked
+		c.After = audit.Masked
+		h.audit(r, audit.Update, entity, username, []audit.Change{c})
+	}
+}
+
+//auditCreate records a new entity with the ID the backend gave it
+func (h *Six910Handler) auditCreate(r *http.Request, entity string, id int64, after interface{}) {
+	h.auditCreateKey(r, entity, strconv.FormatInt(id, 10), after)
+}
+
+//auditCreateKey is auditCreate for entities keyed by name
+func (h *Six910Handler) auditCreateKey(r *http.Request, entity string, key string, after interface{}) {
+	if h.AuditService != nil {
+		h.audit(r, audit.Create, entity, key, audit.Diff(nil, after))
+	}
+}
+
+//auditUpload records a product file upload. The products in the file
+//are not listed one by one.
+func (h *Six910Handler) auditUpload(r *http.Request, filename string, notImported int) {
+	if h.AuditService != nil {
+		var up struct {
+			File        string `json:"file"`
+			NotImported int    `json:"notImported"`
+		}
+		up.File = filename
+		up.NotImported = notImported
+		h.audit(r, audit.Create, auditProductUpload, filename, audit.Diff(nil, &up))
+	}
+}
+
+func (h *Six910Handler) audit(r *http.Request, action string, entity string, id string, changes []audit.Change) {
+	var e audit.Entry
+	e.Username = h.auditUsername(r)
+	e.Action = action
+	e.Entity = entity
+	e.EntityID = id
+	e.RequestID = logging.RequestID(r)
+	e.Changes = changes
+	if !h.AuditService.Record(&e) {
+		h.Log.Error("audit entry lost for ", action, " ", entity, " ", id, " by ", e.Username)
+	}
+}
+
+//auditUsername is the admin username, or the session ref for an OAuth2
+//session whose access token named no user
+func (h *Six910Handler) auditUsername(r *http.Request) string {
+	var rtn string
+	as := h.getAdminUser(r)
+	if as != nil {
+		rtn = as.Username
+		if rtn == "" {
+			rtn = "session " + as.Ref()
+		}
+	}
+	return rtn
+}
+
+//auditFilter reads the filter from the query. The to date is included.
+//A bad date sets the page error.
+func auditFilter(r *http.Request) (*audit.Filter, *AuditPage) {
+	var f audit.Filter
+	var ap AuditPage
+	q := r.URL.Query()
+	ap.Username = q.Get("user")
+	ap.Entity = q.Get("entity")
+	ap.From = q.Get("from")
+	ap.To = q.Get("to")
+	f.Username = ap.Username
+	f.Entity = ap.Entity
+	if ap.From != "" {
+		from, err := time.ParseInLocation(auditDateFormat, ap.From, time.Local)
+		if err != nil {
+			ap.Error = "Bad from date"
+		}
+		f.From = from
+	}
+	if ap.To != "" {
+		to, err := time.ParseInLocation(auditDateFormat, ap.To, time.Local)
+		if err != nil {
+			ap.Error = "Bad to date"
+		} else {
+			f.To = to.AddDate(0, 0, 1)
+		}
+	}
+	return &f, &ap
+}
+
+//StoreAdminViewAuditLog StoreAdminViewAuditLog lists audit entries
+//newest first, filtered by user, entity and date range
+func (h *Six910Handler) StoreAdminViewAuditLog(w http.ResponseWriter, r *http.Request) {
+	if h.AuditService == nil {
+		http.NotFound(w, r)
+		return
+	}
+	f, ap := auditFilter(r)
+	f.Limit = auditPageLimit + 1
+	ap.Entries = h.AuditService.Search(f)
+	if len(*ap.Entries) > auditPageLimit {
+		lst := (*ap.Entries)[:auditPageLimit]
+		ap.Entries = &lst
+		ap.Limited = true
+	}
+	h.Log.Debug("audit entries in list: ", len(*ap.Entries))
+	h.executeAdminTemplate(w, r, adminAuditLogPage, ap)
+}
+
+//StoreAdminExportAuditLog StoreAdminExportAuditLog sends the entries
+//matching the list page filters as CSV
+func (h *Six910Handler) StoreAdminExportAuditLog(w http.ResponseWriter, r *http.Request) {
+	if h.AuditService == nil {
+		http.NotFound(w, r)
+		return
+	}
+	f, ap := auditFilter(r)
+	if ap.Error != "" {
+		http.Error(w, ap.Error, http.StatusBadRequest)
+		return
+	}
+	entries := h.AuditService.Search(f)
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", "attachment; filename=\"audit-"+time.Now().Format(auditDateFormat)+".csv\"")
+	if err := audit.WriteCSV(w, entries); err != nil {
+		h.Log.Error("audit csv export failed: ", err)
+	}
+}
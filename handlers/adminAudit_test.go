@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	lg "github.com/Ulbora/Level_Logger"
+	audit "github.com/Ulbora/Six910-ui/auditsrv"
+	api "github.com/Ulbora/Six910API-Go"
+	sdbi "github.com/Ulbora/six910-database-interface"
+	"github.com/gorilla/mux"
+)
+
+func TestSix910Handler_AuditEditCategory(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	defer os.RemoveAll(dir)
+	var c sdbi.Category
+	c.ID = 3
+	c.Name = "old"
+	sapi.MockCategory = &c
+	var pr api.Response
+	pr.Success = true
+	sapi.MockUpdateCategoryResp = &pr
+
+	r, _ := http.NewRequest("POST", "/admin/editCategory", strings.NewReader("id=3&name=tester"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := serveAsAdmin(sh, sh.StoreAdminEditCategory, r)
+	es := *sh.AuditService.Search(&audit.Filter{})
+	fmt.Println("entries: ", es)
+	if w.Code != 302 || len(es) != 1 {
+		t.FailNow()
+	}
+	e := es[0]
+	if e.Username != "tester" || e.Action != audit.Update || e.Entity != auditCategory || e.EntityID != "3" {
+		t.Fail()
+	}
+	if len(e.Changes) != 1 || e.Changes[0].Field != "name" || e.Changes[0].Before != "old" || e.Changes[0].After != "tester" {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_AuditDeleteFailed(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	defer os.RemoveAll(dir)
+	var pr api.Response
+	sapi.MockDeleteCategoryResp = &pr
+
+	r, _ := http.NewRequest("POST", "/admin/deleteCategory/3", nil)
+	r = mux.SetURLVars(r, map[string]string{"id": "3"})
+	serveAsAdmin(sh, sh.StoreAdminDeleteCategory, r)
+	if len(*sh.AuditService.Search(&audit.Filter{})) != 0 {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_AuditPassword(t *testing.T) {
+	sh, _, dir := testHandler(t)
+	defer os.RemoveAll(dir)
+	r, _ := http.NewRequest("POST", "/admin/changePassword", nil)
+	serveAsAdmin(sh, func(w http.ResponseWriter, r *http.Request) {
+		sh.auditPassword(r, auditAdminUser, "tester")
+	}, r)
+	es := *sh.AuditService.Search(&audit.Filter{Entity: auditAdminUser})
+	if len(es) != 1 || es[0].Changes[0].After != audit.Masked {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAdminViewAuditLog(t *testing.T) {
+	sh, _, dir := testHandler(t)
+	defer os.RemoveAll(dir)
+	sh.AdminTemplates = template.Must(template.New("admin").Parse(
+		`{{define "auditLog.html"}}{{.Error}}{{range .Entries}}[{{.Username}} {{.Entity}}]{{end}}{{end}}`))
+	now := time.Now()
+	sh.AuditService.Record(&audit.Entry{Time: now.AddDate(0, 0, -3), Username: "jane", Entity: "product"})
+	sh.AuditService.Record(&audit.Entry{Time: now, Username: "jane", Entity: "region"})
+	sh.AuditService.Record(&audit.Entry{Time: now, Username: "bob", Entity: "product"})
+
+	today := now.Format(auditDateFormat)
+	r, _ := http.NewRequest("GET", "/admin/auditLogView?user=jane&from="+today+"&to="+today, nil)
+	w := serveAsAdmin(sh, sh.StoreAdminViewAuditLog, r)
+	fmt.Println("body: ", w.Body.String())
+	if w.Code != 200 || w.Body.String() != "[jane region]" {
+		t.Fail()
+	}
+
+	r2, _ := http.NewRequest("GET", "/admin/auditLogView?from=june", nil)
+	w2 := serveAsAdmin(sh, sh.StoreAdminViewAuditLog, r2)
+	if !strings.HasPrefix(w2.Body.String(), "Bad from date") {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAdminExportAuditLog(t *testing.T) {
+	sh, _, dir := testHandler(t)
+	defer os.RemoveAll(dir)
+	sh.AuditService.Record(&audit.Entry{Username: "jane", Action: audit.Delete, Entity: "product", EntityID: "9"})
+	sh.AuditService.Record(&audit.Entry{Username: "bob", Action: audit.Delete, Entity: "region", EntityID: "2"})
+
+	r, _ := http.NewRequest("GET", "/admin/auditLogExport?entity=product", nil)
+	w := serveAsAdmin(sh, sh.StoreAdminExportAuditLog, r)
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	fmt.Println("csv: ", w.Body.String())
+	if w.Code != 200 || w.Header().Get("Content-Type") != "text/csv" || len(lines) != 2 || !strings.Contains(lines[1], "jane,delete,product,9") {
+		t.Fail()
+	}
+
+	r2, _ := http.NewRequest("GET", "/admin/auditLogExport?to=2020-13-01", nil)
+	w2 := serveAsAdmin(sh, sh.StoreAdminExportAuditLog, r2)
+	if w2.Code != 400 {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_AuditLogOff(t *testing.T) {
+	var sh Six910Handler
+	var l lg.Logger
+	sh.Log = &l
+	r, _ := http.NewRequest("GET", "/admin/auditLogView", nil)
+	w := serveAsAdmin(&sh, sh.StoreAdminViewAuditLog, r)
+	if w.Code != 404 || sh.auditBefore(r, auditProduct, 1, nil) != nil {
+		t.Fail()
+	}
+	sh.auditBefore(r, auditProduct, 1, nil).updated(nil)
+}
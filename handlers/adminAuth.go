@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Ulbora/Six910-ui/logging"
+	ss "github.com/Ulbora/Six910-ui/sessrv"
+	api "github.com/Ulbora/Six910API-Go"
+	"github.com/gorilla/sessions"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+//adminSessionIDKey is the cookie value naming the server side session
+const adminSessionIDKey = "adminSessionId"
+
+type adminCtxKey int
+
+const (
+	adminSessionKey adminCtxKey = iota
+	adminHeaderKey
+	adminUserKey
+)
+
+//AdminAuth AdminAuth lets only logged in store admins through to next.
+//Others are sent to the login page, or to the OAuth2 server when OAuth2
+//is enabled. The cookie session, the server side admin session and the
+//backend headers for the admin are put in the request context for the
+//handlers.
+func (h *Six910Handler) AdminAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var as *ss.AdminSession
+		s, suc := h.getSession(r)
+		if suc {
+			as = h.getStoreAdmin(s)
+		}
+		if as == nil {
+			h.Log.Debug("store admin not logged in: ", r.URL.Path)
+			h.authorize(w, r)
+			return
+		}
+		logging.SetUser(r, as.Username)
+		ctx := context.WithValue(r.Context(), adminSessionKey, s)
+		ctx = context.WithValue(ctx, adminUserKey, as)
+		ctx = context.WithValue(ctx, adminHeaderKey, h.getHeader(as, r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (h *Six910Handler) getAdminSession(r *http.Request) *sessions.Session {
+	s, _ := r.Context().Value(adminSessionKey).(*sessions.Session)
+	return s
+}
+
+func (h *Six910Handler) getAdminUser(r *http.Request) *ss.AdminSession {
+	as, _ := r.Context().Value(adminUserKey).(*ss.AdminSession)
+	return as
+}
+
+func (h *Six910Handler) getAdminHeader(r *http.Request) *api.Headers {
+	hd, ok := r.Context().Value(adminHeaderKey).(*api.Headers)
+	if !ok {
+		hd = new(api.Headers)
+	}
+	return hd
+}
+
+//startAdminSession replaces any server side session named by the cookie
+//with a new one for as, so a session ID is never reused across logins.
+//A session waiting for a two factor code is not a store admin yet.
+func (h *Six910Handler) startAdminSession(w http.ResponseWriter, r *http.Request, s *sessions.Session, as *ss.AdminSession) error {
+	if oid, ok := s.Values[adminSessionIDKey].(string); ok {
+		h.AdminSessions.Delete(oid)
+	}
+	as.RemoteAddr = r.RemoteAddr
+	as.UserAgent = r.UserAgent()
+	nas := h.AdminSessions.Create(as)
+	s.Values[adminSessionIDKey] = nas.ID
+	if as.TwoFactorPending {
+		delete(s.Values, "storeAdminUser")
+	} else {
+		s.Values["storeAdminUser"] = true
+	}
+	delete(s.Values, "username")
+	delete(s.Values, "password")
+	delete(s.Values, "loggedIn")
+	delete(s.Values, csrfTokenKey)
+	return s.Save(r, w)
+}
+
+//endAdminSession revokes the server side session named by the cookie
+func (h *Six910Handler) endAdminSession(r *http.Request) {
+	s, suc := h.getSession(r)
+	if suc {
+		if id, ok := s.Values[adminSessionIDKey].(string); ok {
+			h.AdminSessions.Delete(id)
+		}
+	}
+}
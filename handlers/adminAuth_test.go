@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	lg "github.com/Ulbora/Level_Logger"
+	m "github.com/Ulbora/Six910-ui/managers"
+	mapi "github.com/Ulbora/Six910-ui/mockapi"
+	ss "github.com/Ulbora/Six910-ui/sessrv"
+	api "github.com/Ulbora/Six910API-Go"
+	oauth2 "github.com/Ulbora/go-oauth2-client"
+	"github.com/gorilla/sessions"
+)
+
+func loginTestAdmin(sh *Six910Handler, s *sessions.Session) *ss.AdminSession {
+	sh.GetNew()
+	var as ss.AdminSession
+	as.Username = "tester"
+	as.Password = "tester"
+	nas := sh.AdminSessions.Create(&as)
+	s.Values[adminSessionIDKey] = nas.ID
+	return nas
+}
+
+func loginTestOAuthAdmin(sh *Six910Handler, s *sessions.Session, t *oauth2.Token) *ss.AdminSession {
+	as := loginTestAdmin(sh, s)
+	setAdminToken(as, t)
+	sh.AdminSessions.Update(as)
+	return as
+}
+
+func TestSix910Handler_AdminAuth(t *testing.T) {
+	var sh Six910Handler
+	var l lg.Logger
+	l.LogLevel = lg.AllLevel
+	sh.Log = &l
+	var called bool
+	var hdOk, sessOk bool
+	var user string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		_, hdOk = r.Context().Value(adminHeaderKey).(*api.Headers)
+		sessOk = sh.getAdminSession(r) != nil
+		user = sh.getAdminUser(r).Username
+	})
+
+	r, _ := http.NewRequest("GET", "/admin/productListView", nil)
+	w := httptest.NewRecorder()
+	s, _ := sh.getSession(r)
+	loginTestAdmin(&sh, s)
+	s.Values["storeAdminUser"] = true
+	h := sh.GetNew()
+	h.AdminAuth(next).ServeHTTP(w, r)
+	fmt.Println("code: ", w.Code)
+	if !called || !hdOk || !sessOk || user != "tester" || w.Code != 200 {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_AdminAuthRevoked(t *testing.T) {
+	var sh Six910Handler
+	var l lg.Logger
+	sh.Log = &l
+	var called bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	r, _ := http.NewRequest("GET", "/admin/productListView", nil)
+	w := httptest.NewRecorder()
+	s, _ := sh.getSession(r)
+	as := loginTestAdmin(&sh, s)
+	s.Values["storeAdminUser"] = true
+	sh.AdminSessions.Delete(as.ID)
+	h := sh.GetNew()
+	h.AdminAuth(next).ServeHTTP(w, r)
+	if called || w.Code != 302 || w.Header().Get("Location") != adminLogin {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_AdminAuthNotLoggedIn(t *testing.T) {
+	var sh Six910Handler
+	var l lg.Logger
+	sh.Log = &l
+	var called bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	r, _ := http.NewRequest("GET", "/admin/productListView", nil)
+	w := httptest.NewRecorder()
+	s, _ := sh.getSession(r)
+	s.Values["storeAdminUser"] = true
+	h := sh.GetNew()
+	h.AdminAuth(next).ServeHTTP(w, r)
+	fmt.Println("location: ", w.Header().Get("Location"))
+	if called || w.Code != 302 || w.Header().Get("Location") != adminLogin {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_AdminAuthOAuthNoToken(t *testing.T) {
+	var sh Six910Handler
+	var l lg.Logger
+	sh.Log = &l
+	sh.OAuth2Enabled = true
+	var cc ClientCreds
+	cc.AuthCodeState = "123"
+	cc.AuthCodeClient = "1"
+	sh.ClientCreds = &cc
+	sh.OauthHost = "test.com"
+	var called bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	r, _ := http.NewRequest("GET", "/admin/productListView", nil)
+	w := httptest.NewRecorder()
+	s, _ := sh.getSession(r)
+	s.Values["userLoggenIn"] = true
+	s.Values["storeAdminUser"] = true
+	as := loginTestAdmin(&sh, s)
+	h := sh.GetNew()
+	h.AdminAuth(next).ServeHTTP(w, r)
+	if called || w.Code != 302 {
+		t.Fail()
+	}
+
+	var mTkn oauth2.Token
+	mTkn.AccessToken = "45ffffff"
+	setAdminToken(as, &mTkn)
+	sh.AdminSessions.Update(as)
+	w2 := httptest.NewRecorder()
+	h.AdminAuth(next).ServeHTTP(w2, r)
+	if !called {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAdminChangeUserPasswordBasic(t *testing.T) {
+	var sh Six910Handler
+	var l lg.Logger
+	l.LogLevel = lg.AllLevel
+	sh.Log = &l
+
+	var sapi mapi.MockAPI
+	var usr api.UserResponse
+	usr.Username = "tester"
+	usr.Enabled = true
+	usr.Role = "StoreAdmin"
+	sapi.MockUser = &usr
+	var ures api.Response
+	ures.Success = true
+	sapi.MockUpdateUserResp = &ures
+
+	var man m.Six910Manager
+	man.API = &sapi
+	man.Log = &l
+	sh.Manager = man.GetNew()
+	sh.AdminTemplates = template.Must(template.ParseFiles("testHtmls/test.html"))
+
+	r, _ := http.NewRequest("POST", "/admin/changePassword", nil)
+	r.ParseForm()
+	r.Form.Set("oldPassword", "tester")
+	r.Form.Set("password", "newpass")
+	w := httptest.NewRecorder()
+	s, _ := sh.getSession(r)
+	as := loginTestAdmin(&sh, s)
+	s.Values["storeAdminUser"] = true
+	h := sh.GetNew()
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminChangeUserPassword)).ServeHTTP(w, r)
+	fmt.Println("location: ", w.Header().Get("Location"))
+	if w.Code != 302 || w.Header().Get("Location") != adminIndex || sh.AdminSessions.Get(as.ID).Password != "newpass" {
+		t.Fail()
+	}
+}
@@ -33,158 +33,132 @@ type CatPage struct {
 	Error        string
 	CategoryList *[]sdbi.Category
 	Category     *sdbi.Category
+	FormErrors
 }
 
 //StoreAdminAddCategoryPage StoreAdminAddCategoryPage
 func (h *Six910Handler) StoreAdminAddCategoryPage(w http.ResponseWriter, r *http.Request) {
-	acs, suc := h.getSession(r)
-	h.Log.Debug("session suc in cat add view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(acs) {
-			hd := h.getHeader(acs)
-			acpErr := r.URL.Query().Get("error")
-			var cgp CatPage
-			cgp.Error = acpErr
-			cgp.CategoryList = h.API.GetCategoryList(hd)
-			h.AdminTemplates.ExecuteTemplate(w, adminAddCategoryPage, &cgp)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	hd := h.getAdminHeader(r)
+	acpErr := r.URL.Query().Get("error")
+	var cgp CatPage
+	cgp.Error = acpErr
+	cgp.CategoryList = h.API.GetCategoryList(hd)
+	h.executeAdminTemplate(w, r, adminAddCategoryPage, &cgp)
 }
 
 //StoreAdminAddCategory  StoreAdminAddCategory
 func (h *Six910Handler) StoreAdminAddCategory(w http.ResponseWriter, r *http.Request) {
-	accs, suc := h.getSession(r)
-	h.Log.Debug("session suc in category add", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(accs) {
-			c := h.processCategory(r)
-			h.Log.Debug("Cat add", *c)
-			hd := h.getHeader(accs)
-			prres := h.API.AddCategory(c, hd)
-			h.Log.Debug("Category add resp", *prres)
-			if prres.Success {
-				http.Redirect(w, r, adminCategoryListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminAddCategoryViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	c, fe := h.processCategory(r)
+	if len(fe) > 0 {
+		var fp CatPage
+		fp.Error = invalidForm
+		fp.Category = c
+		fp.FormErrors = formErrors(r, fe)
+		fp.CategoryList = h.API.GetCategoryList(h.getAdminHeader(r))
+		h.executeAdminTemplate(w, r, adminAddCategoryPage, &fp)
+		return
+	}
+	h.Log.Debug("Cat add", *c)
+	hd := h.getAdminHeader(r)
+	prres := h.API.AddCategory(c, hd)
+	h.Log.Debug("Category add resp", *prres)
+	if prres.Success {
+		h.auditCreate(r, auditCategory, prres.ID, c)
+		http.Redirect(w, r, adminCategoryListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminAddCategoryViewFail, http.StatusFound)
 	}
 }
 
 //StoreAdminEditCategoryPage StoreAdminEditCategoryPage
 func (h *Six910Handler) StoreAdminEditCategoryPage(w http.ResponseWriter, r *http.Request) {
-	acs, suc := h.getSession(r)
-	h.Log.Debug("session suc in cat edit view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(acs) {
-			hd := h.getHeader(acs)
-			acpErr := r.URL.Query().Get("error")
-			ecvars := mux.Vars(r)
-			idstr := ecvars["id"]
-			cID, _ := strconv.ParseInt(idstr, 10, 64)
-			h.Log.Debug("prod id in edit", cID)
-			var cgp CatPage
-			cgp.Error = acpErr
-
-			var wg sync.WaitGroup
-			wg.Add(1)
-			go func(header *six910api.Headers) {
-				defer wg.Done()
-				cgp.CategoryList = h.API.GetCategoryList(header)
-			}(hd)
-
-			wg.Add(1)
-			go func(catID int64, header *six910api.Headers) {
-				defer wg.Done()
-				cgp.Category = h.API.GetCategory(catID, header)
-			}(cID, hd)
-
-			wg.Wait()
-
-			h.AdminTemplates.ExecuteTemplate(w, adminEditCategoryPage, &cgp)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	hd := h.getAdminHeader(r)
+	acpErr := r.URL.Query().Get("error")
+	ecvars := mux.Vars(r)
+	idstr := ecvars["id"]
+	cID, _ := strconv.ParseInt(idstr, 10, 64)
+	h.Log.Debug("prod id in edit", cID)
+	var cgp CatPage
+	cgp.Error = acpErr
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func(header *six910api.Headers) {
+		defer wg.Done()
+		cgp.CategoryList = h.API.GetCategoryList(header)
+	}(hd)
+
+	wg.Add(1)
+	go func(catID int64, header *six910api.Headers) {
+		defer wg.Done()
+		cgp.Category = h.API.GetCategory(catID, header)
+	}(cID, hd)
+
+	wg.Wait()
+
+	h.executeAdminTemplate(w, r, adminEditCategoryPage, &cgp)
 }
 
 //StoreAdminEditCategory StoreAdminEditCategory
 func (h *Six910Handler) StoreAdminEditCategory(w http.ResponseWriter, r *http.Request) {
-	eccs, suc := h.getSession(r)
-	h.Log.Debug("session suc in cat edit", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(eccs) {
-			ecc := h.processCategory(r)
-			h.Log.Debug("Cat update", *ecc)
-			hd := h.getHeader(eccs)
-			res := h.API.UpdateCategory(ecc, hd)
-			h.Log.Debug("Cat update resp", *res)
-			if res.Success {
-				http.Redirect(w, r, adminCategoryListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminEditCategoryViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	ecc, fe := h.processCategory(r)
+	if len(fe) > 0 {
+		var fp CatPage
+		fp.Error = invalidForm
+		fp.Category = ecc
+		fp.FormErrors = formErrors(r, fe)
+		fp.CategoryList = h.API.GetCategoryList(h.getAdminHeader(r))
+		h.executeAdminTemplate(w, r, adminEditCategoryPage, &fp)
+		return
+	}
+	h.Log.Debug("Cat update", *ecc)
+	hd := h.getAdminHeader(r)
+	ae := h.auditBefore(r, auditCategory, ecc.ID, func() interface{} { return h.API.GetCategory(ecc.ID, hd) })
+	res := h.API.UpdateCategory(ecc, hd)
+	h.Log.Debug("Cat update resp", *res)
+	if res.Success {
+		ae.updated(ecc)
+		http.Redirect(w, r, adminCategoryListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminEditCategoryViewFail, http.StatusFound)
 	}
 }
 
 //StoreAdminViewCategoryList StoreAdminViewCategoryList
 func (h *Six910Handler) StoreAdminViewCategoryList(w http.ResponseWriter, r *http.Request) {
-	gcls, suc := h.getSession(r)
-	h.Log.Debug("session suc in cats view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(gcls) {
-			hd := h.getHeader(gcls)
-			cats := h.API.GetCategoryList(hd)
-			h.Log.Debug("prods  in edit", cats)
-			h.AdminTemplates.ExecuteTemplate(w, adminCategoryListPage, &cats)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	hd := h.getAdminHeader(r)
+	cats := h.API.GetCategoryList(hd)
+	h.Log.Debug("prods  in edit", cats)
+	h.executeAdminTemplate(w, r, adminCategoryListPage, &cats)
 }
 
 //StoreAdminDeleteCategory StoreAdminDeleteCategory
 func (h *Six910Handler) StoreAdminDeleteCategory(w http.ResponseWriter, r *http.Request) {
-	dcs, suc := h.getSession(r)
-	h.Log.Debug("session suc in cat list delete", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(dcs) {
-			hd := h.getHeader(dcs)
-			dcvars := mux.Vars(r)
-			idstrd := dcvars["id"]
-			idddc, _ := strconv.ParseInt(idstrd, 10, 64)
-			res := h.API.DeleteCategory(idddc, hd)
-			h.Log.Debug("cat delete resp", *res)
-			if res.Success {
-				http.Redirect(w, r, adminCategoryListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminCategoryListViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	hd := h.getAdminHeader(r)
+	dcvars := mux.Vars(r)
+	idstrd := dcvars["id"]
+	idddc, _ := strconv.ParseInt(idstrd, 10, 64)
+	ae := h.auditBefore(r, auditCategory, idddc, func() interface{} { return h.API.GetCategory(idddc, hd) })
+	res := h.API.DeleteCategory(idddc, hd)
+	h.Log.Debug("cat delete resp", *res)
+	if res.Success {
+		ae.deleted()
+		http.Redirect(w, r, adminCategoryListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminCategoryListViewFail, http.StatusFound)
 	}
 }
 
-func (h *Six910Handler) processCategory(r *http.Request) *sdbi.Category {
+func (h *Six910Handler) processCategory(r *http.Request) (*sdbi.Category, FieldErrors) {
 	var c sdbi.Category
-	id := r.FormValue("id")
-	c.ID, _ = strconv.ParseInt(id, 10, 64)
+	f := newFormReader(r)
+	c.ID = f.int("id")
 	c.Name = r.FormValue("name")
 	c.Description = r.FormValue("desc")
 	c.Image = r.FormValue("image")
 	c.Thumbnail = r.FormValue("thumbnail")
-	storeID := r.FormValue("storeId")
-	c.StoreID, _ = strconv.ParseInt(storeID, 10, 64)
-	pID := r.FormValue("parentId")
-	c.ParentCategoryID, _ = strconv.ParseInt(pID, 10, 64)
+	c.StoreID = f.int("storeId")
+	c.ParentCategoryID = f.int("parentId")
 
-	return &c
+	return &c, f.check(validateCategory(&c))
 }
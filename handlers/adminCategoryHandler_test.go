@@ -56,13 +56,11 @@ func TestSix910Handler_StoreAdminAddCategoryPage(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddCategoryPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddCategoryPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -88,11 +86,9 @@ func TestSix910Handler_StoreAdminAddCategoryPageLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddCategoryPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddCategoryPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -134,13 +130,11 @@ func TestSix910Handler_StoreAdminAddCategory(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddCategory(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddCategory)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -184,11 +178,9 @@ func TestSix910Handler_StoreAdminAddCategoryLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddCategory(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddCategory)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -230,13 +222,11 @@ func TestSix910Handler_StoreAdminAddCategoryFail(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddCategory(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddCategory)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -286,13 +276,11 @@ func TestSix910Handler_StoreAdminEditCategoryPage(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditCategoryPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditCategoryPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -344,11 +332,9 @@ func TestSix910Handler_StoreAdminEditCategoryPageLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditCategoryPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditCategoryPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -389,13 +375,11 @@ func TestSix910Handler_StoreAdminEditCategory(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditCategory(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditCategory)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -438,11 +422,9 @@ func TestSix910Handler_StoreAdminEditCategoryLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditCategory(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditCategory)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -483,13 +465,11 @@ func TestSix910Handler_StoreAdminEditCategoryFail(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditCategory(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditCategory)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -538,13 +518,11 @@ func TestSix910Handler_StoreAdminViewCategoryList(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminViewCategoryList(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminViewCategoryList)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -595,11 +573,9 @@ func TestSix910Handler_StoreAdminViewCategoryListLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminViewCategoryList(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminViewCategoryList)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -643,13 +619,11 @@ func TestSix910Handler_StoreAdminDeleteCategory(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminDeleteCategory(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminDeleteCategory)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -695,11 +669,9 @@ func TestSix910Handler_StoreAdminDeleteCategoryLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminDeleteCategory(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminDeleteCategory)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -743,13 +715,11 @@ func TestSix910Handler_StoreAdminDeleteCategoryFail(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminDeleteCategory(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminDeleteCategory)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	rsts "github.com/Ulbora/Six910-ui/resetsrv"
 	api "github.com/Ulbora/Six910API-Go"
 	sdbi "github.com/Ulbora/six910-database-interface"
 	"github.com/gorilla/mux"
@@ -32,130 +33,120 @@ type CusPage struct {
 	Error    string
 	Customer *sdbi.Customer
 	User     *api.UserResponse
+	FormErrors
 }
 
 //StoreAdminEditCustomerPage StoreAdminEditCustomerPage
 func (h *Six910Handler) StoreAdminEditCustomerPage(w http.ResponseWriter, r *http.Request) {
-	ecps, suc := h.getSession(r)
-	h.Log.Debug("session suc in customer edit view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(ecps) {
-			hd := h.getHeader(ecps)
-			epvars := mux.Vars(r)
-			cidstr := epvars["id"]
-			cID, _ := strconv.ParseInt(cidstr, 10, 64)
-			h.Log.Debug("customer id in edit", cID)
-			cust := h.API.GetCustomerID(cID, hd)
-			h.Log.Debug("customer  in edit", cust)
-			edErr := r.URL.Query().Get("error")
-			var ceparm CusPage
-			ceparm.Error = edErr
-			ceparm.Customer = cust
-			h.AdminTemplates.ExecuteTemplate(w, adminEditCustomerPage, &ceparm)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	hd := h.getAdminHeader(r)
+	epvars := mux.Vars(r)
+	cidstr := epvars["id"]
+	cID, _ := strconv.ParseInt(cidstr, 10, 64)
+	h.Log.Debug("customer id in edit", cID)
+	cust := h.API.GetCustomerID(cID, hd)
+	h.Log.Debug("customer  in edit", cust)
+	edErr := r.URL.Query().Get("error")
+	var ceparm CusPage
+	ceparm.Error = edErr
+	ceparm.Customer = cust
+	h.executeAdminTemplate(w, r, adminEditCustomerPage, &ceparm)
 }
 
 //StoreAdminEditCustomer StoreAdminEditCustomer
 func (h *Six910Handler) StoreAdminEditCustomer(w http.ResponseWriter, r *http.Request) {
-	ecs, suc := h.getSession(r)
-	h.Log.Debug("session suc in customer edit", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(ecs) {
-			c := h.processCustomer(r)
-			h.Log.Debug("customer edit", *c)
-			hd := h.getHeader(ecs)
-			ecres := h.API.UpdateCustomer(c, hd)
-			h.Log.Debug("customer edit resp", *ecres)
-			if ecres.Success {
-				http.Redirect(w, r, adminCustomerListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminEditCustomerViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	c, fe := h.processCustomer(r)
+	if len(fe) > 0 {
+		var fp CusPage
+		fp.Error = invalidForm
+		fp.Customer = c
+		fp.FormErrors = formErrors(r, fe)
+		h.executeAdminTemplate(w, r, adminEditCustomerPage, &fp)
+		return
+	}
+	h.Log.Debug("customer edit", *c)
+	hd := h.getAdminHeader(r)
+	ae := h.auditBefore(r, auditCustomer, c.ID, func() interface{} { return h.API.GetCustomerID(c.ID, hd) })
+	ecres := h.API.UpdateCustomer(c, hd)
+	h.Log.Debug("customer edit resp", *ecres)
+	if ecres.Success {
+		ae.updated(c)
+		http.Redirect(w, r, adminCustomerListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminEditCustomerViewFail, http.StatusFound)
 	}
 }
 
 //StoreAdminEditCustomerUserPage StoreAdminEditCustomerUserPage
 func (h *Six910Handler) StoreAdminEditCustomerUserPage(w http.ResponseWriter, r *http.Request) {
-	ecups, suc := h.getSession(r)
-	h.Log.Debug("session suc in customer edit view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(ecups) {
-			hd := h.getHeader(ecups)
-			ecuvars := mux.Vars(r)
-			unstr := ecuvars["username"]
-			eucid := ecuvars["cid"]
-			cID, _ := strconv.ParseInt(eucid, 10, 64)
-			h.Log.Debug("customer username in edit", unstr)
-			var u api.User
-			u.Username = unstr
-			cusr := h.API.GetUser(&u, hd)
-			if cusr.CustomerID == cID {
-				h.Log.Debug("customer user in edit", cusr)
-				edErr := r.URL.Query().Get("error")
-				var ceparm CusPage
-				ceparm.Error = edErr
-				ceparm.User = cusr
-				h.AdminTemplates.ExecuteTemplate(w, adminEditCustomerUserPage, &ceparm)
-			} else {
-				http.Redirect(w, r, adminCustomerListView, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	hd := h.getAdminHeader(r)
+	ecuvars := mux.Vars(r)
+	unstr := ecuvars["username"]
+	eucid := ecuvars["cid"]
+	cID, _ := strconv.ParseInt(eucid, 10, 64)
+	h.Log.Debug("customer username in edit", unstr)
+	var u api.User
+	u.Username = unstr
+	cusr := h.API.GetUser(&u, hd)
+	if cusr.CustomerID == cID {
+		h.Log.Debug("customer user in edit", cusr)
+		edErr := r.URL.Query().Get("error")
+		var ceparm CusPage
+		ceparm.Error = edErr
+		ceparm.User = cusr
+		h.executeAdminTemplate(w, r, adminEditCustomerUserPage, &ceparm)
+	} else {
+		http.Redirect(w, r, adminCustomerListView, http.StatusFound)
 	}
 }
 
 //StoreAdminEditCustomerUser StoreAdminEditCustomerUser
 func (h *Six910Handler) StoreAdminEditCustomerUser(w http.ResponseWriter, r *http.Request) {
-	ecus, suc := h.getSession(r)
-	h.Log.Debug("session suc in customer edit", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(ecus) {
-			cu := h.processCustomerUser(r)
-			h.Log.Debug("customer user edit", *cu)
-			hd := h.getHeader(ecus)
-			ecures := h.API.UpdateUser(cu, hd)
-			h.Log.Debug("customer user edit resp", *ecures)
-			if ecures.Success {
-				http.Redirect(w, r, adminCustomerListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminEditCustomerUserViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	cu, fe := h.processCustomerUser(r)
+	if len(fe) > 0 {
+		var fp CusPage
+		fp.Error = invalidForm
+		fp.User = &api.UserResponse{Username: cu.Username, Role: cu.Role, CustomerID: cu.CustomerID,
+			StoreID: cu.StoreID, Enabled: cu.Enabled}
+		fp.FormErrors = formErrors(r, fe)
+		h.executeAdminTemplate(w, r, adminEditCustomerUserPage, &fp)
+		return
+	}
+	h.Log.Debug("customer user edit", *cu)
+	hd := h.getAdminHeader(r)
+	ae := h.auditBeforeKey(r, auditCustomerUser, cu.Username, func() interface{} {
+		var u api.User
+		u.Username = cu.Username
+		return h.API.GetUser(&u, hd)
+	})
+	ecures := h.API.UpdateUser(cu, hd)
+	h.Log.Debug("customer user edit resp", *ecures)
+	if ecures.Success && cu.Password != "" {
+		h.passwordChanged(rsts.ScopeCustomer, cu.Username)
+	}
+	if ecures.Success {
+		ae.updated(cu)
+	}
+	if ecures.Success {
+		http.Redirect(w, r, adminCustomerListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminEditCustomerUserViewFail, http.StatusFound)
 	}
 }
 
 //StoreAdminViewCustomerList StoreAdminViewCustomerList
 func (h *Six910Handler) StoreAdminViewCustomerList(w http.ResponseWriter, r *http.Request) {
-	culs, suc := h.getSession(r)
-	h.Log.Debug("session suc in customer user list view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(culs) {
-			hd := h.getHeader(culs)
-			cul := h.API.GetCustomerList(hd)
-			h.Log.Debug("customer  in list", cul)
-			h.AdminTemplates.ExecuteTemplate(w, adminCustomerListPage, &cul)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	hd := h.getAdminHeader(r)
+	cul := h.API.GetCustomerList(hd)
+	h.Log.Debug("customer  in list", cul)
+	h.executeAdminTemplate(w, r, adminCustomerListPage, &cul)
 }
 
-func (h *Six910Handler) processCustomer(r *http.Request) *sdbi.Customer {
+func (h *Six910Handler) processCustomer(r *http.Request) (*sdbi.Customer, FieldErrors) {
 	var c sdbi.Customer
-	id := r.FormValue("id")
-	c.ID, _ = strconv.ParseInt(id, 10, 64)
+	f := newFormReader(r)
+	c.ID = f.int("id")
 	c.Email = r.FormValue("email")
-	resetPw := r.FormValue("resetPassword")
-	c.ResetPassword, _ = strconv.ParseBool(resetPw)
+	c.ResetPassword = f.bool("resetPassword")
 	c.FirstName = r.FormValue("firstName")
 	c.LastName = r.FormValue("lastName")
 	c.Company = r.FormValue("company")
@@ -163,22 +154,19 @@ func (h *Six910Handler) processCustomer(r *http.Request) *sdbi.Customer {
 	c.State = r.FormValue("state")
 	c.Zip = r.FormValue("zip")
 	c.Phone = r.FormValue("phone")
-	storeID := r.FormValue("storeId")
-	c.StoreID, _ = strconv.ParseInt(storeID, 10, 64)
-	return &c
+	c.StoreID = f.int("storeId")
+	return &c, f.check(validateCustomer(&c))
 }
 
-func (h *Six910Handler) processCustomerUser(r *http.Request) *api.User {
+func (h *Six910Handler) processCustomerUser(r *http.Request) (*api.User, FieldErrors) {
 	var c api.User
+	f := newFormReader(r)
 	c.Username = r.FormValue("username")
 	c.Password = r.FormValue("password")
 	c.OldPassword = r.FormValue("oldPassword")
 	c.Role = r.FormValue("role")
-	cID := r.FormValue("cid")
-	c.CustomerID, _ = strconv.ParseInt(cID, 10, 64)
-	storeID := r.FormValue("storeId")
-	c.StoreID, _ = strconv.ParseInt(storeID, 10, 64)
-	enabled := r.FormValue("enabled")
-	c.Enabled, _ = strconv.ParseBool(enabled)
-	return &c
+	c.CustomerID = f.int("cid")
+	c.StoreID = f.int("storeId")
+	c.Enabled = f.bool("enabled")
+	return &c, f.fe
 }
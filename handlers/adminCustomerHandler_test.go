@@ -54,13 +54,11 @@ func TestSix910Handler_StoreAdminEditCustomerPage(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditCustomerPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditCustomerPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -108,11 +106,9 @@ func TestSix910Handler_StoreAdminEditCustomerPageLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditCustomerPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditCustomerPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -148,18 +144,16 @@ func TestSix910Handler_StoreAdminEditCustomer(t *testing.T) {
 
 	//-----------end mocking --------
 
-	r, _ := http.NewRequest("PUT", "https://test.com", strings.NewReader("id=3&firstName=tester123&resetPassword=true"))
+	r, _ := http.NewRequest("PUT", "https://test.com", strings.NewReader("id=3&email=tester@test.com&firstName=tester123&resetPassword=true"))
 	r.Header.Set("Content-Type", "application/x-www-form-urlencoded; param=value")
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditCustomer(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditCustomer)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -202,11 +196,9 @@ func TestSix910Handler_StoreAdminEditCustomerLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditCustomer(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditCustomer)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -242,18 +234,16 @@ func TestSix910Handler_StoreAdminEditCustomerFail(t *testing.T) {
 
 	//-----------end mocking --------
 
-	r, _ := http.NewRequest("PUT", "https://test.com", strings.NewReader("id=3&firstName=tester123&resetPassword=true"))
+	r, _ := http.NewRequest("PUT", "https://test.com", strings.NewReader("id=3&email=tester@test.com&firstName=tester123&resetPassword=true"))
 	r.Header.Set("Content-Type", "application/x-www-form-urlencoded; param=value")
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditCustomer(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditCustomer)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -300,13 +290,11 @@ func TestSix910Handler_StoreAdminEditCustomerUserPage(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditCustomerUserPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditCustomerUserPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -355,11 +343,9 @@ func TestSix910Handler_StoreAdminEditCustomerUserPageLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditCustomerUserPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditCustomerUserPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -406,13 +392,11 @@ func TestSix910Handler_StoreAdminEditCustomerUserPageWrongCid(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditCustomerUserPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditCustomerUserPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -453,13 +437,11 @@ func TestSix910Handler_StoreAdminEditCustomerUser(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditCustomerUser(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditCustomerUser)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -502,11 +484,9 @@ func TestSix910Handler_StoreAdminEditCustomerUserLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditCustomerUser(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditCustomerUser)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -547,13 +527,11 @@ func TestSix910Handler_StoreAdminEditCustomerUserFail(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditCustomerUser(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditCustomerUser)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -602,13 +580,11 @@ func TestSix910Handler_StoreAdminViewCustomerList(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminViewCustomerList(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminViewCustomerList)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -659,11 +635,9 @@ func TestSix910Handler_StoreAdminViewCustomerListLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminViewCustomerList(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminViewCustomerList)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
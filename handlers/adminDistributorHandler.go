@@ -30,138 +30,111 @@ import (
 type DistPage struct {
 	Error       string
 	Distributor *sdbi.Distributor
+	FormErrors
 }
 
 //StoreAdminAddDistributorPage StoreAdminAddDistributorPage
 func (h *Six910Handler) StoreAdminAddDistributorPage(w http.ResponseWriter, r *http.Request) {
-	ads, suc := h.getSession(r)
-	h.Log.Debug("session suc in dist add view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(ads) {
-			adErr := r.URL.Query().Get("error")
-			var adpg DistPage
-			adpg.Error = adErr
-			h.AdminTemplates.ExecuteTemplate(w, adminAddDistributorPage, &adpg)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	adErr := r.URL.Query().Get("error")
+	var adpg DistPage
+	adpg.Error = adErr
+	h.executeAdminTemplate(w, r, adminAddDistributorPage, &adpg)
 }
 
 //StoreAdminAddDistributor StoreAdminAddDistributor
 func (h *Six910Handler) StoreAdminAddDistributor(w http.ResponseWriter, r *http.Request) {
-	adds, suc := h.getSession(r)
-	h.Log.Debug("session suc in dist add", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(adds) {
-			d := h.processDistributor(r)
-			h.Log.Debug("Dist add", *d)
-			hd := h.getHeader(adds)
-			prres := h.API.AddDistributor(d, hd)
-			h.Log.Debug("Dist add resp", *prres)
-			if prres.Success {
-				http.Redirect(w, r, adminDistributorListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminAddDistributorViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	d, fe := h.processDistributor(r)
+	if len(fe) > 0 {
+		var fp DistPage
+		fp.Error = invalidForm
+		fp.Distributor = d
+		fp.FormErrors = formErrors(r, fe)
+		h.executeAdminTemplate(w, r, adminAddDistributorPage, &fp)
+		return
+	}
+	h.Log.Debug("Dist add", *d)
+	hd := h.getAdminHeader(r)
+	prres := h.API.AddDistributor(d, hd)
+	h.Log.Debug("Dist add resp", *prres)
+	if prres.Success {
+		h.auditCreate(r, auditDistributor, prres.ID, d)
+		http.Redirect(w, r, adminDistributorListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminAddDistributorViewFail, http.StatusFound)
 	}
 }
 
 //StoreAdminEditDistributorPage StoreAdminEditDistributorPage
 func (h *Six910Handler) StoreAdminEditDistributorPage(w http.ResponseWriter, r *http.Request) {
-	eds, suc := h.getSession(r)
-	h.Log.Debug("session suc in dist edit view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(eds) {
-			hd := h.getHeader(eds)
-			edpErr := r.URL.Query().Get("error")
-			edvars := mux.Vars(r)
-			idstr := edvars["id"]
-			dID, _ := strconv.ParseInt(idstr, 10, 64)
-			h.Log.Debug("dist id in edit", dID)
-			var dgp DistPage
-			dgp.Error = edpErr
-			dgp.Distributor = h.API.GetDistributor(dID, hd)
-			h.AdminTemplates.ExecuteTemplate(w, adminEditDistributorPage, &dgp)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	hd := h.getAdminHeader(r)
+	edpErr := r.URL.Query().Get("error")
+	edvars := mux.Vars(r)
+	idstr := edvars["id"]
+	dID, _ := strconv.ParseInt(idstr, 10, 64)
+	h.Log.Debug("dist id in edit", dID)
+	var dgp DistPage
+	dgp.Error = edpErr
+	dgp.Distributor = h.API.GetDistributor(dID, hd)
+	h.executeAdminTemplate(w, r, adminEditDistributorPage, &dgp)
 }
 
 //StoreAdminEditDistributor StoreAdminEditDistributor
 func (h *Six910Handler) StoreAdminEditDistributor(w http.ResponseWriter, r *http.Request) {
-	edds, suc := h.getSession(r)
-	h.Log.Debug("session suc in dist edit", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(edds) {
-			edd := h.processDistributor(r)
-			h.Log.Debug("Dist update", *edd)
-			hd := h.getHeader(edds)
-			res := h.API.UpdateDistributor(edd, hd)
-			h.Log.Debug("Dist update resp", *res)
-			if res.Success {
-				http.Redirect(w, r, adminDistributorListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminEditDistributorViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	edd, fe := h.processDistributor(r)
+	if len(fe) > 0 {
+		var fp DistPage
+		fp.Error = invalidForm
+		fp.Distributor = edd
+		fp.FormErrors = formErrors(r, fe)
+		h.executeAdminTemplate(w, r, adminEditDistributorPage, &fp)
+		return
+	}
+	h.Log.Debug("Dist update", *edd)
+	hd := h.getAdminHeader(r)
+	ae := h.auditBefore(r, auditDistributor, edd.ID, func() interface{} { return h.API.GetDistributor(edd.ID, hd) })
+	res := h.API.UpdateDistributor(edd, hd)
+	h.Log.Debug("Dist update resp", *res)
+	if res.Success {
+		ae.updated(edd)
+		http.Redirect(w, r, adminDistributorListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminEditDistributorViewFail, http.StatusFound)
 	}
 }
 
 //StoreAdminViewDistributorList StoreAdminViewDistributorList
 func (h *Six910Handler) StoreAdminViewDistributorList(w http.ResponseWriter, r *http.Request) {
-	gdls, suc := h.getSession(r)
-	h.Log.Debug("session suc in dist view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(gdls) {
-			hd := h.getHeader(gdls)
-			dsl := h.API.GetDistributorList(hd)
-			h.Log.Debug("Dist  in list", dsl)
-			h.AdminTemplates.ExecuteTemplate(w, adminDistributorListPage, &dsl)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	hd := h.getAdminHeader(r)
+	dsl := h.API.GetDistributorList(hd)
+	h.Log.Debug("Dist  in list", dsl)
+	h.executeAdminTemplate(w, r, adminDistributorListPage, &dsl)
 }
 
 //StoreAdminDeleteDistributor StoreAdminDeleteDistributor
 func (h *Six910Handler) StoreAdminDeleteDistributor(w http.ResponseWriter, r *http.Request) {
-	dds, suc := h.getSession(r)
-	h.Log.Debug("session suc in cat list delete", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(dds) {
-			hd := h.getHeader(dds)
-			ddvars := mux.Vars(r)
-			idstrd := ddvars["id"]
-			idddd, _ := strconv.ParseInt(idstrd, 10, 64)
-			res := h.API.DeleteDistributor(idddd, hd)
-			h.Log.Debug("dist delete resp", *res)
-			if res.Success {
-				http.Redirect(w, r, adminDistributorListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminDistributorListViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	hd := h.getAdminHeader(r)
+	ddvars := mux.Vars(r)
+	idstrd := ddvars["id"]
+	idddd, _ := strconv.ParseInt(idstrd, 10, 64)
+	ae := h.auditBefore(r, auditDistributor, idddd, func() interface{} { return h.API.GetDistributor(idddd, hd) })
+	res := h.API.DeleteDistributor(idddd, hd)
+	h.Log.Debug("dist delete resp", *res)
+	if res.Success {
+		ae.deleted()
+		http.Redirect(w, r, adminDistributorListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminDistributorListViewFail, http.StatusFound)
 	}
 }
 
-func (h *Six910Handler) processDistributor(r *http.Request) *sdbi.Distributor {
+func (h *Six910Handler) processDistributor(r *http.Request) (*sdbi.Distributor, FieldErrors) {
 	var d sdbi.Distributor
-	id := r.FormValue("id")
-	d.ID, _ = strconv.ParseInt(id, 10, 64)
+	f := newFormReader(r)
+	d.ID = f.int("id")
 	d.Company = r.FormValue("company")
 	d.ContactName = r.FormValue("contactName")
 	d.Phone = r.FormValue("phone")
-	storeID := r.FormValue("storeId")
-	d.StoreID, _ = strconv.ParseInt(storeID, 10, 64)
+	d.StoreID = f.int("storeId")
 
-	return &d
+	return &d, f.check(validateDistributor(&d))
 }
@@ -56,13 +56,11 @@ func TestSix910Handler_StoreAdminAddDistributorPage(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddDistributorPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddDistributorPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -112,11 +110,9 @@ func TestSix910Handler_StoreAdminAddDistributorPageLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddDistributorPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddDistributorPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -158,13 +154,11 @@ func TestSix910Handler_StoreAdminAddDistributor(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddDistributor(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddDistributor)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -208,11 +202,9 @@ func TestSix910Handler_StoreAdminAddDistributorLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddDistributor(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddDistributor)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -254,13 +246,11 @@ func TestSix910Handler_StoreAdminAddDistributorFail(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddDistributor(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddDistributor)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -310,13 +300,11 @@ func TestSix910Handler_StoreAdminEditDistributorPage(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditDistributorPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditDistributorPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -368,11 +356,9 @@ func TestSix910Handler_StoreAdminEditDistributorPageLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditDistributorPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditDistributorPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -413,13 +399,11 @@ func TestSix910Handler_StoreAdminEditDistributor(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditDistributor(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditDistributor)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -462,11 +446,9 @@ func TestSix910Handler_StoreAdminEditDistributorLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditDistributor(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditDistributor)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -507,13 +489,11 @@ func TestSix910Handler_StoreAdminEditDistributorFail(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditDistributor(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditDistributor)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -562,13 +542,11 @@ func TestSix910Handler_StoreAdminViewDistributorList(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminViewDistributorList(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminViewDistributorList)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -619,11 +597,9 @@ func TestSix910Handler_StoreAdminViewDistributorListLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminViewDistributorList(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminViewDistributorList)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -667,13 +643,11 @@ func TestSix910Handler_StoreAdminDeleteDistributor(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminDeleteDistributor(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminDeleteDistributor)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -719,11 +693,9 @@ func TestSix910Handler_StoreAdminDeleteDistributorLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminDeleteDistributor(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminDeleteDistributor)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -767,13 +739,11 @@ func TestSix910Handler_StoreAdminDeleteDistributorFail(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminDeleteDistributor(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminDeleteDistributor)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
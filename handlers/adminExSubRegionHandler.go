@@ -34,126 +34,113 @@ type ExSubRegionPage struct {
 	ExcludedSubRegion *sdbi.ExcludedSubRegion
 	Region            *sdbi.Region
 	SubRegion         *sdbi.SubRegion
+	FormErrors
 }
 
 //StoreAdminAddExcludedSubRegionPage StoreAdminAddExcludedSubRegionPage
 func (h *Six910Handler) StoreAdminAddExcludedSubRegionPage(w http.ResponseWriter, r *http.Request) {
-	aessrs, suc := h.getSession(r)
-	h.Log.Debug("session suc in ex sub region add view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(aessrs) {
-			essrvars := mux.Vars(r)
-			ridssrstr := essrvars["regionId"]
-			sridssrstr := essrvars["subRegionId"]
-			riID, _ := strconv.ParseInt(ridssrstr, 10, 64)
-			sriID, _ := strconv.ParseInt(sridssrstr, 10, 64)
-			h.Log.Debug("Region id in edit", riID)
-			h.Log.Debug("Sub Region id in edit", sriID)
-			aesrErr := r.URL.Query().Get("error")
-			var assrpg ExSubRegionPage
-			assrpg.Error = aesrErr
+	essrvars := mux.Vars(r)
+	ridssrstr := essrvars["regionId"]
+	sridssrstr := essrvars["subRegionId"]
+	riID, _ := strconv.ParseInt(ridssrstr, 10, 64)
+	sriID, _ := strconv.ParseInt(sridssrstr, 10, 64)
+	h.Log.Debug("Region id in edit", riID)
+	h.Log.Debug("Sub Region id in edit", sriID)
+	aesrErr := r.URL.Query().Get("error")
+	var assrpg ExSubRegionPage
+	assrpg.Error = aesrErr
 
-			hd := h.getHeader(aessrs)
-			var wg sync.WaitGroup
+	hd := h.getAdminHeader(r)
+	var wg sync.WaitGroup
 
-			wg.Add(1)
-			go func(regionID int64, header *six910api.Headers) {
-				defer wg.Done()
-				assrpg.Region = h.API.GetRegion(regionID, header)
-			}(riID, hd)
+	wg.Add(1)
+	go func(regionID int64, header *six910api.Headers) {
+		defer wg.Done()
+		assrpg.Region = h.API.GetRegion(regionID, header)
+	}(riID, hd)
 
-			wg.Add(1)
-			go func(subRegionID int64, header *six910api.Headers) {
-				defer wg.Done()
-				assrpg.SubRegion = h.API.GetSubRegion(subRegionID, header)
-			}(sriID, hd)
+	wg.Add(1)
+	go func(subRegionID int64, header *six910api.Headers) {
+		defer wg.Done()
+		assrpg.SubRegion = h.API.GetSubRegion(subRegionID, header)
+	}(sriID, hd)
 
-			wg.Wait()
+	wg.Wait()
 
-			h.AdminTemplates.ExecuteTemplate(w, adminAddExSubRegionPage, &assrpg)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	h.executeAdminTemplate(w, r, adminAddExSubRegionPage, &assrpg)
 }
 
 //StoreAdminAddExcludedSubRegion StoreAdminAddExcludedSubRegion
 func (h *Six910Handler) StoreAdminAddExcludedSubRegion(w http.ResponseWriter, r *http.Request) {
-	adessrs, suc := h.getSession(r)
-	h.Log.Debug("session suc in ex Sub Region add", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(adessrs) {
-			aessr := h.processExSubRegion(r)
-			h.Log.Debug("Ex Sub Region add", *aessr)
-			hd := h.getHeader(adessrs)
-			esrres := h.API.AddExcludedSubRegion(aessr, hd)
-			h.Log.Debug("Ex Sub Region add resp", *esrres)
-			if esrres.Success {
-				http.Redirect(w, r, adminExSubRegionListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminExSubRegionListViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	aessr, fe := h.processExSubRegion(r)
+	if len(fe) > 0 {
+		var fp ExSubRegionPage
+		fp.Error = invalidForm
+		fp.ExcludedSubRegion = aessr
+		fp.FormErrors = formErrors(r, fe)
+		hd := h.getAdminHeader(r)
+		fp.Region = h.API.GetRegion(aessr.RegionID, hd)
+		fp.SubRegion = h.API.GetSubRegion(aessr.SubRegionID, hd)
+		h.executeAdminTemplate(w, r, adminAddExSubRegionPage, &fp)
+		return
+	}
+	h.Log.Debug("Ex Sub Region add", *aessr)
+	hd := h.getAdminHeader(r)
+	esrres := h.API.AddExcludedSubRegion(aessr, hd)
+	h.Log.Debug("Ex Sub Region add resp", *esrres)
+	if esrres.Success {
+		h.auditCreate(r, auditExcludedSubRegion, esrres.ID, aessr)
+		http.Redirect(w, r, adminExSubRegionListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminExSubRegionListViewFail, http.StatusFound)
 	}
 }
 
 //StoreAdminViewExcludedSubRegionList StoreAdminViewExcludedSubRegionList
 func (h *Six910Handler) StoreAdminViewExcludedSubRegionList(w http.ResponseWriter, r *http.Request) {
-	gessrls, suc := h.getSession(r)
-	h.Log.Debug("session suc in Ex Sub Region view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(gessrls) {
-			resssrvars := mux.Vars(r)
-			reidssrstr := resssrvars["regionId"]
-			esriID, _ := strconv.ParseInt(reidssrstr, 10, 64)
-			h.Log.Debug(" Ex Sub Region id in edit", esriID)
-			hd := h.getHeader(gessrls)
-			essrsl := h.API.GetExcludedSubRegionList(esriID, hd)
-			h.Log.Debug("Ex Sub Region  in list", *essrsl)
-			h.AdminTemplates.ExecuteTemplate(w, adminExSubRegionListPage, &essrsl)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	resssrvars := mux.Vars(r)
+	reidssrstr := resssrvars["regionId"]
+	esriID, _ := strconv.ParseInt(reidssrstr, 10, 64)
+	h.Log.Debug(" Ex Sub Region id in edit", esriID)
+	hd := h.getAdminHeader(r)
+	essrsl := h.API.GetExcludedSubRegionList(esriID, hd)
+	h.Log.Debug("Ex Sub Region  in list", *essrsl)
+	h.executeAdminTemplate(w, r, adminExSubRegionListPage, &essrsl)
 }
 
 //StoreAdminDeleteExcludedSubRegion StoreAdminDeleteExcludedSubRegion
 func (h *Six910Handler) StoreAdminDeleteExcludedSubRegion(w http.ResponseWriter, r *http.Request) {
-	dessrs, suc := h.getSession(r)
-	h.Log.Debug("session suc in Ex Sub Region list delete", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(dessrs) {
-			hd := h.getHeader(dessrs)
-			dssrvars := mux.Vars(r)
-			idessrstrd := dssrvars["id"]
-			idddessr, _ := strconv.ParseInt(idessrstrd, 10, 64)
-			ridessrstrd := dssrvars["regionId"]
-			ridddessr, _ := strconv.ParseInt(ridessrstrd, 10, 64)
-			res := h.API.DeleteExcludedSubRegion(idddessr, ridddessr, hd)
-			h.Log.Debug("Ex Sub Region delete resp", *res)
-			if res.Success {
-				http.Redirect(w, r, adminExSubRegionListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminExSubRegionListViewFail, http.StatusFound)
+	hd := h.getAdminHeader(r)
+	dssrvars := mux.Vars(r)
+	idessrstrd := dssrvars["id"]
+	idddessr, _ := strconv.ParseInt(idessrstrd, 10, 64)
+	ridessrstrd := dssrvars["regionId"]
+	ridddessr, _ := strconv.ParseInt(ridessrstrd, 10, 64)
+	ae := h.auditBefore(r, auditExcludedSubRegion, idddessr, func() interface{} {
+		for _, sr := range *h.API.GetExcludedSubRegionList(ridddessr, hd) {
+			if sr.ID == idddessr {
+				return sr
 			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
 		}
+		return nil
+	})
+	res := h.API.DeleteExcludedSubRegion(idddessr, ridddessr, hd)
+	h.Log.Debug("Ex Sub Region delete resp", *res)
+	if res.Success {
+		ae.deleted()
+		http.Redirect(w, r, adminExSubRegionListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminExSubRegionListViewFail, http.StatusFound)
 	}
 }
 
-func (h *Six910Handler) processExSubRegion(r *http.Request) *sdbi.ExcludedSubRegion {
+func (h *Six910Handler) processExSubRegion(r *http.Request) (*sdbi.ExcludedSubRegion, FieldErrors) {
 	var i sdbi.ExcludedSubRegion
-	id := r.FormValue("id")
-	i.ID, _ = strconv.ParseInt(id, 10, 64)
-	regionID := r.FormValue("regionId")
-	i.RegionID, _ = strconv.ParseInt(regionID, 10, 64)
-	shippingMethodID := r.FormValue("shippingMethodId")
-	i.ShippingMethodID, _ = strconv.ParseInt(shippingMethodID, 10, 64)
-	subRegionID := r.FormValue("subRegionId")
-	i.SubRegionID, _ = strconv.ParseInt(subRegionID, 10, 64)
+	f := newFormReader(r)
+	i.ID = f.int("id")
+	i.RegionID = f.int("regionId")
+	i.ShippingMethodID = f.int("shippingMethodId")
+	i.SubRegionID = f.int("subRegionId")
 
-	return &i
+	return &i, f.check(validateExSubRegion(&i))
 }
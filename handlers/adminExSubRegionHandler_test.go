@@ -61,13 +61,11 @@ func TestSix910Handler_StoreAdminAddExcludedSubRegionPage(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddExcludedSubRegionPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddExcludedSubRegionPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -117,11 +115,9 @@ func TestSix910Handler_StoreAdminAddExcludedSubRegionPageLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddExcludedSubRegionPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddExcludedSubRegionPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -158,18 +154,16 @@ func TestSix910Handler_StoreAdminAddExcludedSubRegion(t *testing.T) {
 
 	//-----------end mocking --------
 
-	r, _ := http.NewRequest("POST", "https://test.com", strings.NewReader("regionId=48&shippingMethodId=25"))
+	r, _ := http.NewRequest("POST", "https://test.com", strings.NewReader("regionId=48&subRegionId=12&shippingMethodId=25"))
 	r.Header.Set("Content-Type", "application/x-www-form-urlencoded; param=value")
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddExcludedSubRegion(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddExcludedSubRegion)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -213,11 +207,9 @@ func TestSix910Handler_StoreAdminAddExcludedSubRegionLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddExcludedSubRegion(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddExcludedSubRegion)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -254,18 +246,16 @@ func TestSix910Handler_StoreAdminAddExcludedSubRegionFail(t *testing.T) {
 
 	//-----------end mocking --------
 
-	r, _ := http.NewRequest("POST", "https://test.com", strings.NewReader("regionId=48&shippingMethodId=25"))
+	r, _ := http.NewRequest("POST", "https://test.com", strings.NewReader("regionId=48&subRegionId=12&shippingMethodId=25"))
 	r.Header.Set("Content-Type", "application/x-www-form-urlencoded; param=value")
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddExcludedSubRegion(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddExcludedSubRegion)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -313,13 +303,11 @@ func TestSix910Handler_StoreAdminViewExcludedSubRegionList(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminViewExcludedSubRegionList(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminViewExcludedSubRegionList)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -369,11 +357,9 @@ func TestSix910Handler_StoreAdminViewExcludedSubRegionListLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminViewExcludedSubRegionList(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminViewExcludedSubRegionList)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -418,13 +404,11 @@ func TestSix910Handler_StoreAdminDeleteExcludedSubRegion(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminDeleteExcludedSubRegion(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminDeleteExcludedSubRegion)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -471,11 +455,9 @@ func TestSix910Handler_StoreAdminDeleteExcludedSubRegionLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminDeleteExcludedSubRegion(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminDeleteExcludedSubRegion)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -520,13 +502,11 @@ func TestSix910Handler_StoreAdminDeleteExcludedSubRegionFail(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminDeleteExcludedSubRegion(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminDeleteExcludedSubRegion)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
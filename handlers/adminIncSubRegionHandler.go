@@ -34,126 +34,113 @@ type IncSubRegionPage struct {
 	InccludedSubRegion *sdbi.IncludedSubRegion
 	Region             *sdbi.Region
 	SubRegion          *sdbi.SubRegion
+	FormErrors
 }
 
 //StoreAdminAddIncludedSubRegionPage StoreAdminAddIncludedSubRegionPage
 func (h *Six910Handler) StoreAdminAddIncludedSubRegionPage(w http.ResponseWriter, r *http.Request) {
-	ainssrs, suc := h.getSession(r)
-	h.Log.Debug("session suc in Inc sub region add view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(ainssrs) {
-			inssrvars := mux.Vars(r)
-			ridinrstr := inssrvars["regionId"]
-			sridinrstr := inssrvars["subRegionId"]
-			inriID, _ := strconv.ParseInt(ridinrstr, 10, 64)
-			insriID, _ := strconv.ParseInt(sridinrstr, 10, 64)
-			h.Log.Debug("Region id in edit", inriID)
-			h.Log.Debug("Sub Region id in edit", insriID)
-			aesrErr := r.URL.Query().Get("error")
-			var ainssrpg ExSubRegionPage
-			ainssrpg.Error = aesrErr
+	inssrvars := mux.Vars(r)
+	ridinrstr := inssrvars["regionId"]
+	sridinrstr := inssrvars["subRegionId"]
+	inriID, _ := strconv.ParseInt(ridinrstr, 10, 64)
+	insriID, _ := strconv.ParseInt(sridinrstr, 10, 64)
+	h.Log.Debug("Region id in edit", inriID)
+	h.Log.Debug("Sub Region id in edit", insriID)
+	aesrErr := r.URL.Query().Get("error")
+	var ainssrpg ExSubRegionPage
+	ainssrpg.Error = aesrErr
 
-			hd := h.getHeader(ainssrs)
-			var wg sync.WaitGroup
+	hd := h.getAdminHeader(r)
+	var wg sync.WaitGroup
 
-			wg.Add(1)
-			go func(regionID int64, header *six910api.Headers) {
-				defer wg.Done()
-				ainssrpg.Region = h.API.GetRegion(regionID, header)
-			}(inriID, hd)
+	wg.Add(1)
+	go func(regionID int64, header *six910api.Headers) {
+		defer wg.Done()
+		ainssrpg.Region = h.API.GetRegion(regionID, header)
+	}(inriID, hd)
 
-			wg.Add(1)
-			go func(subRegionID int64, header *six910api.Headers) {
-				defer wg.Done()
-				ainssrpg.SubRegion = h.API.GetSubRegion(subRegionID, header)
-			}(insriID, hd)
+	wg.Add(1)
+	go func(subRegionID int64, header *six910api.Headers) {
+		defer wg.Done()
+		ainssrpg.SubRegion = h.API.GetSubRegion(subRegionID, header)
+	}(insriID, hd)
 
-			wg.Wait()
+	wg.Wait()
 
-			h.AdminTemplates.ExecuteTemplate(w, adminAddIncSubRegionPage, &ainssrpg)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	h.executeAdminTemplate(w, r, adminAddIncSubRegionPage, &ainssrpg)
 }
 
 //StoreAdminAddIncludedSubRegion StoreAdminAddIncludedSubRegion
 func (h *Six910Handler) StoreAdminAddIncludedSubRegion(w http.ResponseWriter, r *http.Request) {
-	adinssrs, suc := h.getSession(r)
-	h.Log.Debug("session suc in Inc Sub Region add", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(adinssrs) {
-			ainssr := h.processIncSubRegion(r)
-			h.Log.Debug("Inc Sub Region add", *ainssr)
-			hd := h.getHeader(adinssrs)
-			insrres := h.API.AddIncludedSubRegion(ainssr, hd)
-			h.Log.Debug("Inc Sub Region add resp", *insrres)
-			if insrres.Success {
-				http.Redirect(w, r, adminIncSubRegionListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminIncSubRegionListViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	ainssr, fe := h.processIncSubRegion(r)
+	if len(fe) > 0 {
+		var fp IncSubRegionPage
+		fp.Error = invalidForm
+		fp.InccludedSubRegion = ainssr
+		fp.FormErrors = formErrors(r, fe)
+		hd := h.getAdminHeader(r)
+		fp.Region = h.API.GetRegion(ainssr.RegionID, hd)
+		fp.SubRegion = h.API.GetSubRegion(ainssr.SubRegionID, hd)
+		h.executeAdminTemplate(w, r, adminAddIncSubRegionPage, &fp)
+		return
+	}
+	h.Log.Debug("Inc Sub Region add", *ainssr)
+	hd := h.getAdminHeader(r)
+	insrres := h.API.AddIncludedSubRegion(ainssr, hd)
+	h.Log.Debug("Inc Sub Region add resp", *insrres)
+	if insrres.Success {
+		h.auditCreate(r, auditIncludedSubRegion, insrres.ID, ainssr)
+		http.Redirect(w, r, adminIncSubRegionListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminIncSubRegionListViewFail, http.StatusFound)
 	}
 }
 
 //StoreAdminViewIncludedSubRegionList StoreAdminViewIncludedSubRegionList
 func (h *Six910Handler) StoreAdminViewIncludedSubRegionList(w http.ResponseWriter, r *http.Request) {
-	ginssrls, suc := h.getSession(r)
-	h.Log.Debug("session suc in In Sub Region view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(ginssrls) {
-			rinsssrvars := mux.Vars(r)
-			rinidssrstr := rinsssrvars["regionId"]
-			insriID, _ := strconv.ParseInt(rinidssrstr, 10, 64)
-			h.Log.Debug(" In Sub Region id in edit", insriID)
-			hd := h.getHeader(ginssrls)
-			inssrsl := h.API.GetIncludedSubRegionList(insriID, hd)
-			h.Log.Debug("In Sub Region  in list", *inssrsl)
-			h.AdminTemplates.ExecuteTemplate(w, adminIncSubRegionListPage, &inssrsl)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	rinsssrvars := mux.Vars(r)
+	rinidssrstr := rinsssrvars["regionId"]
+	insriID, _ := strconv.ParseInt(rinidssrstr, 10, 64)
+	h.Log.Debug(" In Sub Region id in edit", insriID)
+	hd := h.getAdminHeader(r)
+	inssrsl := h.API.GetIncludedSubRegionList(insriID, hd)
+	h.Log.Debug("In Sub Region  in list", *inssrsl)
+	h.executeAdminTemplate(w, r, adminIncSubRegionListPage, &inssrsl)
 }
 
 //StoreAdminDeleteIncludedSubRegion StoreAdminDeleteIncludedSubRegion
 func (h *Six910Handler) StoreAdminDeleteIncludedSubRegion(w http.ResponseWriter, r *http.Request) {
-	dinssrs, suc := h.getSession(r)
-	h.Log.Debug("session suc in Inc Sub Region list delete", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(dinssrs) {
-			hd := h.getHeader(dinssrs)
-			dssrvars := mux.Vars(r)
-			idinssrstrd := dssrvars["id"]
-			idddessr, _ := strconv.ParseInt(idinssrstrd, 10, 64)
-			ridinssrstrd := dssrvars["regionId"]
-			ridddessr, _ := strconv.ParseInt(ridinssrstrd, 10, 64)
-			res := h.API.DeleteIncludedSubRegion(idddessr, ridddessr, hd)
-			h.Log.Debug("Inc Sub Region delete resp", *res)
-			if res.Success {
-				http.Redirect(w, r, adminIncSubRegionListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminIncSubRegionListViewFail, http.StatusFound)
+	hd := h.getAdminHeader(r)
+	dssrvars := mux.Vars(r)
+	idinssrstrd := dssrvars["id"]
+	idddessr, _ := strconv.ParseInt(idinssrstrd, 10, 64)
+	ridinssrstrd := dssrvars["regionId"]
+	ridddessr, _ := strconv.ParseInt(ridinssrstrd, 10, 64)
+	ae := h.auditBefore(r, auditIncludedSubRegion, idddessr, func() interface{} {
+		for _, sr := range *h.API.GetIncludedSubRegionList(ridddessr, hd) {
+			if sr.ID == idddessr {
+				return sr
 			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
 		}
+		return nil
+	})
+	res := h.API.DeleteIncludedSubRegion(idddessr, ridddessr, hd)
+	h.Log.Debug("Inc Sub Region delete resp", *res)
+	if res.Success {
+		ae.deleted()
+		http.Redirect(w, r, adminIncSubRegionListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminIncSubRegionListViewFail, http.StatusFound)
 	}
 }
 
-func (h *Six910Handler) processIncSubRegion(r *http.Request) *sdbi.IncludedSubRegion {
+func (h *Six910Handler) processIncSubRegion(r *http.Request) (*sdbi.IncludedSubRegion, FieldErrors) {
 	var in sdbi.IncludedSubRegion
-	id := r.FormValue("id")
-	in.ID, _ = strconv.ParseInt(id, 10, 64)
-	regionID := r.FormValue("regionId")
-	in.RegionID, _ = strconv.ParseInt(regionID, 10, 64)
-	shippingMethodID := r.FormValue("shippingMethodId")
-	in.ShippingMethodID, _ = strconv.ParseInt(shippingMethodID, 10, 64)
-	subRegionID := r.FormValue("subRegionId")
-	in.SubRegionID, _ = strconv.ParseInt(subRegionID, 10, 64)
+	f := newFormReader(r)
+	in.ID = f.int("id")
+	in.RegionID = f.int("regionId")
+	in.ShippingMethodID = f.int("shippingMethodId")
+	in.SubRegionID = f.int("subRegionId")
 
-	return &in
+	return &in, f.check(validateIncSubRegion(&in))
 }
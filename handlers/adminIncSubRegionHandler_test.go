@@ -61,13 +61,11 @@ func TestSix910Handler_StoreAdminAddIncludedSubRegionPage(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddIncludedSubRegionPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddIncludedSubRegionPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -122,11 +120,9 @@ func TestSix910Handler_StoreAdminAddIncludedSubRegionPageLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddIncludedSubRegionPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddIncludedSubRegionPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -163,18 +159,16 @@ func TestSix910Handler_StoreAdminAddIncludedSubRegion(t *testing.T) {
 
 	//-----------end mocking --------
 
-	r, _ := http.NewRequest("POST", "https://test.com", strings.NewReader("regionId=48&shippingMethodId=25"))
+	r, _ := http.NewRequest("POST", "https://test.com", strings.NewReader("regionId=48&subRegionId=12&shippingMethodId=25"))
 	r.Header.Set("Content-Type", "application/x-www-form-urlencoded; param=value")
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddIncludedSubRegion(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddIncludedSubRegion)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -218,11 +212,9 @@ func TestSix910Handler_StoreAdminAddIncludedSubRegionLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddIncludedSubRegion(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddIncludedSubRegion)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -259,18 +251,16 @@ func TestSix910Handler_StoreAdminAddIncludedSubRegionFail(t *testing.T) {
 
 	//-----------end mocking --------
 
-	r, _ := http.NewRequest("POST", "https://test.com", strings.NewReader("regionId=48&shippingMethodId=25"))
+	r, _ := http.NewRequest("POST", "https://test.com", strings.NewReader("regionId=48&subRegionId=12&shippingMethodId=25"))
 	r.Header.Set("Content-Type", "application/x-www-form-urlencoded; param=value")
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddIncludedSubRegion(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddIncludedSubRegion)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -318,13 +308,11 @@ func TestSix910Handler_StoreAdminViewIncludedSubRegionList(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminViewIncludedSubRegionList(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminViewIncludedSubRegionList)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -374,11 +362,9 @@ func TestSix910Handler_StoreAdminViewIncludedSubRegionListLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminViewIncludedSubRegionList(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminViewIncludedSubRegionList)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -423,13 +409,11 @@ func TestSix910Handler_StoreAdminDeleteIncludedSubRegion(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminDeleteIncludedSubRegion(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminDeleteIncludedSubRegion)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -476,11 +460,9 @@ func TestSix910Handler_StoreAdminDeleteIncludedSubRegionLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminDeleteIncludedSubRegion(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminDeleteIncludedSubRegion)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -525,13 +507,11 @@ func TestSix910Handler_StoreAdminDeleteIncludedSubRegionFail(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminDeleteIncludedSubRegion(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminDeleteIncludedSubRegion)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
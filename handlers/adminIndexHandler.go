@@ -2,6 +2,14 @@ package handlers
 
 import (
 	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	conts "github.com/Ulbora/Six910-ui/contsrv"
+	perms "github.com/Ulbora/Six910-ui/permsrv"
+	api "github.com/Ulbora/Six910API-Go"
+	sdbi "github.com/Ulbora/six910-database-interface"
 )
 
 /*
@@ -22,19 +30,259 @@ import (
  along with this program.  If not, see <http://www.gnu.org/licenses/>.
 */
 
-//StoreAdminIndex StoreAdminIndex
+const (
+	dashboardTimeout      = 3 * time.Second
+	dashboardPageSize     = 100
+	dashboardMaxProducts  = 2000
+	dashboardMaxOpen      = 50
+	dashboardListSize     = 10
+	dashboardLowStockSize = 20
+	dashboardOpenStatus   = "processing"
+
+	widgetRevenue         = "revenue"
+	widgetOrdersByStatus  = "ordersByStatus"
+	widgetLowStock        = "lowStock"
+	widgetRecentCustomers = "recentCustomers"
+	widgetOpenShipments   = "openShipments"
+	widgetContentLeaders  = "contentLeaders"
+)
+
+//StatusCount StatusCount
+type StatusCount struct {
+	Status string
+	Count  int
+}
+
+//DashboardPage DashboardPage
+type DashboardPage struct {
+	TodayRevenue    float64
+	TodayOrders     int
+	MonthRevenue    float64
+	MonthOrders     int
+	OrdersByStatus  []StatusCount
+	LowStock        []sdbi.Product
+	RecentCustomers []sdbi.Customer
+	OpenShipments   []sdbi.Shipment
+	ContentLeaders  []conts.Content
+	Failed          []string
+}
+
+//dashboardWidget loads one widget and returns the func that copies its
+//results into the page
+type dashboardWidget func(hd *api.Headers) func(*DashboardPage)
+
+type widgetResult struct {
+	name  string
+	apply func(*DashboardPage)
+}
+
+//StoreAdminIndex StoreAdminIndex only loads the widgets of areas the
+//admin can view. Revenue needs write access to orders so admins who only
+//look orders up for shipping do not see it.
 func (h *Six910Handler) StoreAdminIndex(w http.ResponseWriter, r *http.Request) {
-	s, suc := h.getSession(r)
-	h.Log.Debug("session suc", suc)
-	if suc {
-		// loggedInAuth := s.Values["loggedIn"]
-		// storeAdminUser := s.Values["storeAdminUser"]
-		// h.Log.Debug("loggedIn in backups: ", loggedInAuth)
-		// if loggedInAuth == true && storeAdminUser == true {
-		if h.isStoreAdminLoggedIn(s) {
-			h.AdminTemplates.ExecuteTemplate(w, adminIndexPage, nil)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
+	hd := h.getAdminHeader(r)
+	var widgets = map[string]dashboardWidget{}
+	if h.adminAllowed(r, perms.Orders, true) {
+		widgets[widgetRevenue] = h.dashboardRevenue
+	}
+	if h.adminCanRead(r, perms.Orders) {
+		widgets[widgetOrdersByStatus] = h.dashboardOrdersByStatus
+	}
+	if h.adminCanRead(r, perms.Products) {
+		widgets[widgetLowStock] = h.dashboardLowStock
+	}
+	if h.adminCanRead(r, perms.Customers) {
+		widgets[widgetRecentCustomers] = h.dashboardRecentCustomers
+	}
+	if h.adminCanRead(r, perms.Shipments) {
+		widgets[widgetOpenShipments] = h.dashboardOpenShipments
+	}
+	if h.ContentService != nil {
+		widgets[widgetContentLeaders] = h.dashboardContentLeaders
+	}
+	dp := h.loadDashboard(widgets, hd)
+	h.executeAdminTemplate(w, r, adminIndexPage, dp)
+}
+
+//loadDashboard runs every widget at once and waits no longer than the
+//dashboard timeout. A widget that panics or is still running when time is
+//up is listed in Failed and whatever it returns later is dropped.
+func (h *Six910Handler) loadDashboard(widgets map[string]dashboardWidget, hd *api.Headers) *DashboardPage {
+	var dp DashboardPage
+	timeout := h.DashboardTimeout
+	if timeout <= 0 {
+		timeout = dashboardTimeout
+	}
+	res := make(chan widgetResult, len(widgets))
+	for name, wg := range widgets {
+		go func(name string, wg dashboardWidget) {
+			var apply func(*DashboardPage)
+			defer func() {
+				if rec := recover(); rec != nil {
+					h.Log.Error("dashboard widget ", name, " failed: ", rec)
+				}
+				res <- widgetResult{name, apply}
+			}()
+			apply = wg(hd)
+		}(name, wg)
+	}
+	done := make(map[string]bool)
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+wait:
+	for len(done) < len(widgets) {
+		select {
+		case wr := <-res:
+			done[wr.name] = true
+			if wr.apply != nil {
+				wr.apply(&dp)
+			} else {
+				dp.Failed = append(dp.Failed, wr.name)
+			}
+		case <-timer.C:
+			break wait
+		}
+	}
+	for name := range widgets {
+		if !done[name] {
+			h.Log.Info("dashboard widget timed out: ", name)
+			dp.Failed = append(dp.Failed, name)
+		}
+	}
+	sort.Strings(dp.Failed)
+	return &dp
+}
+
+func (h *Six910Handler) dashboardRevenue(hd *api.Headers) func(*DashboardPage) {
+	ol := h.API.GetStoreOrderList(hd)
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	var tr, mr float64
+	var tc, mc int
+	if ol != nil {
+		for _, o := range *ol {
+			if strings.Contains(strings.ToLower(o.Status), "cancel") || o.OrderDate.Before(month) {
+				continue
+			}
+			mr += o.Total
+			mc++
+			if !o.OrderDate.Before(today) {
+				tr += o.Total
+				tc++
+			}
+		}
+	}
+	return func(dp *DashboardPage) {
+		dp.TodayRevenue = tr
+		dp.TodayOrders = tc
+		dp.MonthRevenue = mr
+		dp.MonthOrders = mc
+	}
+}
+
+func (h *Six910Handler) dashboardOrdersByStatus(hd *api.Headers) func(*DashboardPage) {
+	var sl []StatusCount
+	for _, st := range h.OrderStatuses {
+		var sc StatusCount
+		sc.Status = st
+		if ol := h.API.GetStoreOrderListByStatus(st, hd); ol != nil {
+			sc.Count = len(*ol)
+		}
+		sl = append(sl, sc)
+	}
+	return func(dp *DashboardPage) {
+		dp.OrdersByStatus = sl
+	}
+}
+
+//dashboardLowStock pages through the products, so a large catalog stops
+//after dashboardMaxProducts
+func (h *Six910Handler) dashboardLowStock(hd *api.Headers) func(*DashboardPage) {
+	var ls []sdbi.Product
+	for st := int64(0); st < dashboardMaxProducts; st += dashboardPageSize {
+		pl := h.API.GetProductList(st, st+dashboardPageSize, hd)
+		if pl == nil {
+			break
+		}
+		for _, p := range *pl {
+			if p.Stock <= p.StockAlert {
+				ls = append(ls, p)
+			}
+		}
+		if len(*pl) < dashboardPageSize {
+			break
+		}
+	}
+	sort.SliceStable(ls, func(i, j int) bool {
+		return ls[i].Stock-ls[i].StockAlert < ls[j].Stock-ls[j].StockAlert
+	})
+	if len(ls) > dashboardLowStockSize {
+		ls = ls[:dashboardLowStockSize]
+	}
+	return func(dp *DashboardPage) {
+		dp.LowStock = ls
+	}
+}
+
+func (h *Six910Handler) dashboardRecentCustomers(hd *api.Headers) func(*DashboardPage) {
+	var cl []sdbi.Customer
+	if cs := h.API.GetCustomerList(hd); cs != nil {
+		cl = append(cl, *cs...)
+	}
+	sort.SliceStable(cl, func(i, j int) bool {
+		return cl[i].DateEntered.After(cl[j].DateEntered)
+	})
+	if len(cl) > dashboardListSize {
+		cl = cl[:dashboardListSize]
+	}
+	return func(dp *DashboardPage) {
+		dp.RecentCustomers = cl
+	}
+}
+
+//dashboardOpenShipments lists shipments not yet shipped or delivered for
+//orders still processing
+func (h *Six910Handler) dashboardOpenShipments(hd *api.Headers) func(*DashboardPage) {
+	var sl []sdbi.Shipment
+	ol := h.API.GetStoreOrderListByStatus(dashboardOpenStatus, hd)
+	if ol != nil {
+		for i, o := range *ol {
+			if i >= dashboardMaxOpen {
+				break
+			}
+			shl := h.API.GetShipmentList(o.ID, hd)
+			if shl == nil {
+				continue
+			}
+			for _, s := range *shl {
+				st := strings.ToLower(s.Status)
+				if st != "shipped" && st != "delivered" {
+					sl = append(sl, s)
+				}
+			}
 		}
 	}
+	sort.SliceStable(sl, func(i, j int) bool {
+		return sl[i].CreateDate.Before(sl[j].CreateDate)
+	})
+	return func(dp *DashboardPage) {
+		dp.OpenShipments = sl
+	}
+}
+
+func (h *Six910Handler) dashboardContentLeaders(hd *api.Headers) func(*DashboardPage) {
+	var cl []conts.Content
+	if cs := h.ContentService.GetContentList(false); cs != nil {
+		cl = append(cl, *cs...)
+	}
+	sort.SliceStable(cl, func(i, j int) bool {
+		return cl[i].Hits > cl[j].Hits
+	})
+	if len(cl) > dashboardListSize {
+		cl = cl[:dashboardListSize]
+	}
+	return func(dp *DashboardPage) {
+		dp.ContentLeaders = cl
+	}
 }
@@ -6,8 +6,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	lg "github.com/Ulbora/Level_Logger"
+	mapi "github.com/Ulbora/Six910-ui/mockapi"
+	perms "github.com/Ulbora/Six910-ui/permsrv"
+	api "github.com/Ulbora/Six910API-Go"
+	sdbi "github.com/Ulbora/six910-database-interface"
 )
 
 func TestSix910Handler_StoreAdminIndex(t *testing.T) {
@@ -29,7 +34,7 @@ func TestSix910Handler_StoreAdminIndex(t *testing.T) {
 	//s.Values["loggedIn"] = true
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminIndex(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminIndex)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -53,14 +58,179 @@ func TestSix910Handler_StoreAdminIndexLoggedIn(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminIndex(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminIndex)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
 		t.Fail()
 	}
 }
+
+func TestSix910Handler_StoreAdminIndexDashboard(t *testing.T) {
+	var sh Six910Handler
+	var l lg.Logger
+	l.LogLevel = lg.AllLevel
+	sh.Log = &l
+	sh.OrderStatuses = []string{"processing", "shipped"}
+
+	var sapi mapi.MockAPI
+	now := time.Now()
+	var o1 sdbi.Order
+	o1.ID = 1
+	o1.OrderDate = now
+	o1.Total = 10.5
+	o1.Status = "processing"
+	var o2 sdbi.Order
+	o2.ID = 2
+	o2.OrderDate = now
+	o2.Total = 20
+	o2.Status = "Cancelled"
+	var o3 sdbi.Order
+	o3.ID = 3
+	o3.OrderDate = now.AddDate(-1, 0, 0)
+	o3.Total = 30
+	ol := []sdbi.Order{o1, o2, o3}
+	sapi.MockOrderList = &ol
+
+	var p1 sdbi.Product
+	p1.ID = 1
+	p1.Stock = 2
+	p1.StockAlert = 5
+	var p2 sdbi.Product
+	p2.ID = 2
+	p2.Stock = 50
+	p2.StockAlert = 5
+	pl := []sdbi.Product{p1, p2}
+	sapi.MockProductList = &pl
+
+	var c1 sdbi.Customer
+	c1.ID = 1
+	c1.DateEntered = now.AddDate(0, 0, -2)
+	var c2 sdbi.Customer
+	c2.ID = 2
+	c2.DateEntered = now
+	cl := []sdbi.Customer{c1, c2}
+	sapi.MockCustomerList = &cl
+
+	var s1 sdbi.Shipment
+	s1.ID = 1
+	s1.Status = "Shipped"
+	var s2 sdbi.Shipment
+	s2.ID = 2
+	s2.Status = "packing"
+	shl := []sdbi.Shipment{s1, s2}
+	sapi.MockShipmentList = &shl
+	sh.API = sapi.GetNew()
+
+	var hd api.Headers
+	widgets := map[string]dashboardWidget{
+		widgetRevenue:         sh.dashboardRevenue,
+		widgetOrdersByStatus:  sh.dashboardOrdersByStatus,
+		widgetLowStock:        sh.dashboardLowStock,
+		widgetRecentCustomers: sh.dashboardRecentCustomers,
+		widgetOpenShipments:   sh.dashboardOpenShipments,
+	}
+	dp := sh.loadDashboard(widgets, &hd)
+	fmt.Println("dashboard: ", *dp)
+	if len(dp.Failed) != 0 || dp.MonthOrders != 1 || dp.MonthRevenue != 10.5 {
+		t.Fail()
+	}
+	if dp.TodayOrders != 1 || dp.TodayRevenue != 10.5 {
+		t.Fail()
+	}
+	if len(dp.OrdersByStatus) != 2 || dp.OrdersByStatus[1].Status != "shipped" || dp.OrdersByStatus[1].Count != 3 {
+		t.Fail()
+	}
+	if len(dp.LowStock) != 1 || dp.LowStock[0].ID != 1 {
+		t.Fail()
+	}
+	if len(dp.RecentCustomers) != 2 || dp.RecentCustomers[0].ID != 2 {
+		t.Fail()
+	}
+	if len(dp.OpenShipments) != 3 || dp.OpenShipments[0].ID != 2 {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAdminIndexDashboardSlow(t *testing.T) {
+	var sh Six910Handler
+	var l lg.Logger
+	l.LogLevel = lg.AllLevel
+	sh.Log = &l
+	sh.DashboardTimeout = 50 * time.Millisecond
+
+	var hd api.Headers
+	widgets := map[string]dashboardWidget{
+		"fast": func(hd *api.Headers) func(*DashboardPage) {
+			return func(dp *DashboardPage) {
+				dp.TodayOrders = 4
+			}
+		},
+		"slow": func(hd *api.Headers) func(*DashboardPage) {
+			time.Sleep(time.Second)
+			return func(dp *DashboardPage) {
+				dp.MonthOrders = 9
+			}
+		},
+		"broken": func(hd *api.Headers) func(*DashboardPage) {
+			panic("backend down")
+		},
+	}
+	st := time.Now()
+	dp := sh.loadDashboard(widgets, &hd)
+	fmt.Println("dashboard: ", *dp)
+	if time.Since(st) > 500*time.Millisecond {
+		t.Fail()
+	}
+	if dp.TodayOrders != 4 || dp.MonthOrders != 0 {
+		t.Fail()
+	}
+	if len(dp.Failed) != 2 || dp.Failed[0] != "broken" || dp.Failed[1] != "slow" {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAdminIndexPermissions(t *testing.T) {
+	sh := permissionTestHandler()
+	sh.OrderStatuses = []string{"processing"}
+	var sapi mapi.MockAPI
+	ol := []sdbi.Order{{ID: 1, OrderDate: time.Now(), Total: 10.5, Status: "processing"}}
+	sapi.MockOrderList = &ol
+	cl := []sdbi.Customer{{ID: 1, DateEntered: time.Now()}}
+	sapi.MockCustomerList = &cl
+	shl := []sdbi.Shipment{{ID: 2, Status: "packing"}}
+	sapi.MockShipmentList = &shl
+	sh.API = sapi.GetNew()
+	sh.AdminTemplates = template.Must(template.New("admin").Parse(`{{define "index.html"}}` +
+		`{{.MonthRevenue}}|{{len .OrdersByStatus}}|{{len .LowStock}}|{{len .RecentCustomers}}|{{len .OpenShipments}}{{end}}`))
+	pl := []sdbi.Product{{ID: 1, Stock: 1, StockAlert: 5}}
+	sapi.MockProductList = &pl
+
+	// tester only has the fulfillment set
+	r, _ := http.NewRequest("GET", "/admin/index", nil)
+	w := serveAsAdmin(sh, sh.StoreAdminIndex, r)
+	fmt.Println("body: ", w.Body.String())
+	if w.Code != 200 || w.Body.String() != "0|1|1|0|1" {
+		t.Fail()
+	}
+
+	var ps perms.Six910PermissionService
+	ps.Users = map[string][]string{"tester": {"catalog"}}
+	sh.PermissionService = ps.GetNew()
+	w = serveAsAdmin(sh, sh.StoreAdminIndex, r)
+	fmt.Println("body: ", w.Body.String())
+	if w.Code != 200 || w.Body.String() != "0|0|1|0|0" {
+		t.Fail()
+	}
+
+	ps.Users = map[string][]string{"tester": {"orders"}}
+	w = serveAsAdmin(sh, sh.StoreAdminIndex, r)
+	fmt.Println("body: ", w.Body.String())
+	if w.Body.String() != "10.5|1|1|1|1" {
+		t.Fail()
+	}
+}
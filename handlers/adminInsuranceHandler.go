@@ -30,141 +30,111 @@ import (
 type InsPage struct {
 	Error     string
 	Insurance *sdbi.Insurance
+	FormErrors
 }
 
 //StoreAdminAddInsurancePage StoreAdminAddInsurancePage
 func (h *Six910Handler) StoreAdminAddInsurancePage(w http.ResponseWriter, r *http.Request) {
-	ads, suc := h.getSession(r)
-	h.Log.Debug("session suc in insc add view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(ads) {
-			aiErr := r.URL.Query().Get("error")
-			var aipg InsPage
-			aipg.Error = aiErr
-			h.AdminTemplates.ExecuteTemplate(w, adminAddInsurancePage, &aipg)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	aiErr := r.URL.Query().Get("error")
+	var aipg InsPage
+	aipg.Error = aiErr
+	h.executeAdminTemplate(w, r, adminAddInsurancePage, &aipg)
 }
 
 //StoreAdminAddInsurance StoreAdminAddInsurance
 func (h *Six910Handler) StoreAdminAddInsurance(w http.ResponseWriter, r *http.Request) {
-	adds, suc := h.getSession(r)
-	h.Log.Debug("session suc in dist add", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(adds) {
-			ai := h.processInsurance(r)
-			h.Log.Debug("Ins add", *ai)
-			hd := h.getHeader(adds)
-			prres := h.API.AddInsurance(ai, hd)
-			h.Log.Debug("Ins add resp", *prres)
-			if prres.Success {
-				http.Redirect(w, r, adminInsuranceListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminAddInsuranceViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	ai, fe := h.processInsurance(r)
+	if len(fe) > 0 {
+		var fp InsPage
+		fp.Error = invalidForm
+		fp.Insurance = ai
+		fp.FormErrors = formErrors(r, fe)
+		h.executeAdminTemplate(w, r, adminAddInsurancePage, &fp)
+		return
+	}
+	h.Log.Debug("Ins add", *ai)
+	hd := h.getAdminHeader(r)
+	prres := h.API.AddInsurance(ai, hd)
+	h.Log.Debug("Ins add resp", *prres)
+	if prres.Success {
+		h.auditCreate(r, auditInsurance, prres.ID, ai)
+		http.Redirect(w, r, adminInsuranceListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminAddInsuranceViewFail, http.StatusFound)
 	}
 }
 
 //StoreAdminEditInsurancePage StoreAdminEditInsurancePage
 func (h *Six910Handler) StoreAdminEditInsurancePage(w http.ResponseWriter, r *http.Request) {
-	eis, suc := h.getSession(r)
-	h.Log.Debug("session suc in ins edit view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(eis) {
-			hd := h.getHeader(eis)
-			eipErr := r.URL.Query().Get("error")
-			eivars := mux.Vars(r)
-			idstr := eivars["id"]
-			iID, _ := strconv.ParseInt(idstr, 10, 64)
-			h.Log.Debug("ins id in edit", iID)
-			var dgp InsPage
-			dgp.Error = eipErr
-			dgp.Insurance = h.API.GetInsurance(iID, hd)
-			h.AdminTemplates.ExecuteTemplate(w, adminEditInsurancePage, &dgp)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	hd := h.getAdminHeader(r)
+	eipErr := r.URL.Query().Get("error")
+	eivars := mux.Vars(r)
+	idstr := eivars["id"]
+	iID, _ := strconv.ParseInt(idstr, 10, 64)
+	h.Log.Debug("ins id in edit", iID)
+	var dgp InsPage
+	dgp.Error = eipErr
+	dgp.Insurance = h.API.GetInsurance(iID, hd)
+	h.executeAdminTemplate(w, r, adminEditInsurancePage, &dgp)
 }
 
 //StoreAdminEditInsurance StoreAdminEditInsurance
 func (h *Six910Handler) StoreAdminEditInsurance(w http.ResponseWriter, r *http.Request) {
-	eiis, suc := h.getSession(r)
-	h.Log.Debug("session suc in ins edit", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(eiis) {
-			eii := h.processInsurance(r)
-			h.Log.Debug("ins update", *eii)
-			hd := h.getHeader(eiis)
-			res := h.API.UpdateInsurance(eii, hd)
-			h.Log.Debug("Ins update resp", *res)
-			if res.Success {
-				http.Redirect(w, r, adminInsuranceListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminEditInsuranceViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	eii, fe := h.processInsurance(r)
+	if len(fe) > 0 {
+		var fp InsPage
+		fp.Error = invalidForm
+		fp.Insurance = eii
+		fp.FormErrors = formErrors(r, fe)
+		h.executeAdminTemplate(w, r, adminEditInsurancePage, &fp)
+		return
+	}
+	h.Log.Debug("ins update", *eii)
+	hd := h.getAdminHeader(r)
+	ae := h.auditBefore(r, auditInsurance, eii.ID, func() interface{} { return h.API.GetInsurance(eii.ID, hd) })
+	res := h.API.UpdateInsurance(eii, hd)
+	h.Log.Debug("Ins update resp", *res)
+	if res.Success {
+		ae.updated(eii)
+		http.Redirect(w, r, adminInsuranceListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminEditInsuranceViewFail, http.StatusFound)
 	}
 }
 
 //StoreAdminViewInsuranceList StoreAdminViewInsuranceList
 func (h *Six910Handler) StoreAdminViewInsuranceList(w http.ResponseWriter, r *http.Request) {
-	gils, suc := h.getSession(r)
-	h.Log.Debug("session suc in ins view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(gils) {
-			hd := h.getHeader(gils)
-			isl := h.API.GetInsuranceList(hd)
-			h.Log.Debug("Ins  in list", isl)
-			h.AdminTemplates.ExecuteTemplate(w, adminInsuranceListPage, &isl)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	hd := h.getAdminHeader(r)
+	isl := h.API.GetInsuranceList(hd)
+	h.Log.Debug("Ins  in list", isl)
+	h.executeAdminTemplate(w, r, adminInsuranceListPage, &isl)
 }
 
 //StoreAdminDeleteInsurance StoreAdminDeleteInsurance
 func (h *Six910Handler) StoreAdminDeleteInsurance(w http.ResponseWriter, r *http.Request) {
-	dis, suc := h.getSession(r)
-	h.Log.Debug("session suc in ins list delete", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(dis) {
-			hd := h.getHeader(dis)
-			divars := mux.Vars(r)
-			idstrd := divars["id"]
-			idddi, _ := strconv.ParseInt(idstrd, 10, 64)
-			res := h.API.DeleteInsurance(idddi, hd)
-			h.Log.Debug("ins delete resp", *res)
-			if res.Success {
-				http.Redirect(w, r, adminInsuranceListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminInsuranceListViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	hd := h.getAdminHeader(r)
+	divars := mux.Vars(r)
+	idstrd := divars["id"]
+	idddi, _ := strconv.ParseInt(idstrd, 10, 64)
+	ae := h.auditBefore(r, auditInsurance, idddi, func() interface{} { return h.API.GetInsurance(idddi, hd) })
+	res := h.API.DeleteInsurance(idddi, hd)
+	h.Log.Debug("ins delete resp", *res)
+	if res.Success {
+		ae.deleted()
+		http.Redirect(w, r, adminInsuranceListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminInsuranceListViewFail, http.StatusFound)
 	}
 }
 
-func (h *Six910Handler) processInsurance(r *http.Request) *sdbi.Insurance {
+func (h *Six910Handler) processInsurance(r *http.Request) (*sdbi.Insurance, FieldErrors) {
 	var i sdbi.Insurance
-	id := r.FormValue("id")
-	i.ID, _ = strconv.ParseInt(id, 10, 64)
-	cost := r.FormValue("cost")
-	i.Cost, _ = strconv.ParseFloat(cost, 64)
-	max := r.FormValue("maxOrderAmount")
-	i.MaxOrderAmount, _ = strconv.ParseFloat(max, 64)
-	min := r.FormValue("minOrderAmount")
-	i.MinOrderAmount, _ = strconv.ParseFloat(min, 64)
-	storeID := r.FormValue("storeId")
-	i.StoreID, _ = strconv.ParseInt(storeID, 10, 64)
+	f := newFormReader(r)
+	i.ID = f.int("id")
+	i.Cost = f.float("cost")
+	i.MaxOrderAmount = f.float("maxOrderAmount")
+	i.MinOrderAmount = f.float("minOrderAmount")
+	i.StoreID = f.int("storeId")
 
-	return &i
+	return &i, f.check(validateInsurance(&i))
 }
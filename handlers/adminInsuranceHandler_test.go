@@ -5,10 +5,12 @@ import (
 	"html/template"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
 
 	lg "github.com/Ulbora/Level_Logger"
+	audit "github.com/Ulbora/Six910-ui/auditsrv"
 	m "github.com/Ulbora/Six910-ui/managers"
 	mapi "github.com/Ulbora/Six910-ui/mockapi"
 	api "github.com/Ulbora/Six910API-Go"
@@ -56,13 +58,11 @@ func TestSix910Handler_StoreAdminAddInsurancePage(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddInsurancePage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddInsurancePage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -112,11 +112,9 @@ func TestSix910Handler_StoreAdminAddInsurancePageLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddInsurancePage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddInsurancePage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -158,13 +156,11 @@ func TestSix910Handler_StoreAdminAddInsurance(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddInsurance(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddInsurance)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -208,11 +204,9 @@ func TestSix910Handler_StoreAdminAddInsuranceLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddInsurance(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddInsurance)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -254,13 +248,11 @@ func TestSix910Handler_StoreAdminAddInsuranceFail(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddInsurance(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddInsurance)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -310,13 +302,11 @@ func TestSix910Handler_StoreAdminEditInsurancePage(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditInsurancePage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditInsurancePage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -368,11 +358,9 @@ func TestSix910Handler_StoreAdminEditInsurancePageLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditInsurancePage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditInsurancePage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -413,13 +401,11 @@ func TestSix910Handler_StoreAdminEditInsurance(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditInsurance(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditInsurance)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -462,11 +448,9 @@ func TestSix910Handler_StoreAdminEditInsuranceLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditInsurance(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditInsurance)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -507,13 +491,11 @@ func TestSix910Handler_StoreAdminEditInsuranceFail(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditInsurance(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditInsurance)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -562,13 +544,11 @@ func TestSix910Handler_StoreAdminViewInsuranceList(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminViewInsuranceList(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminViewInsuranceList)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -619,11 +599,9 @@ func TestSix910Handler_StoreAdminViewInsuranceListLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminViewInsuranceList(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminViewInsuranceList)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -667,13 +645,11 @@ func TestSix910Handler_StoreAdminDeleteInsurance(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminDeleteInsurance(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminDeleteInsurance)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -719,11 +695,9 @@ func TestSix910Handler_StoreAdminDeleteInsuranceLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminDeleteInsurance(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminDeleteInsurance)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -767,16 +741,34 @@ func TestSix910Handler_StoreAdminDeleteInsurancefail(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminDeleteInsurance(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminDeleteInsurance)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
 		t.Fail()
 	}
 }
+
+func TestSix910Handler_StoreAdminAddInsuranceInvalid(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	defer os.RemoveAll(dir)
+	sh.AdminTemplates = template.Must(template.New(adminAddInsurancePage).Parse(
+		`{{.Error}}|{{.Fields.cost}}|{{.Fields.minOrderAmount}}|{{.Form.Get "cost"}}`))
+	sapi.MockAddInsuranceResp = &api.ResponseID{ID: 5, Success: true}
+
+	r, _ := http.NewRequest("POST", "https://test.com", strings.NewReader("cost=12,99&minOrderAmount=500&maxOrderAmount=300.50"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := serveAsAdmin(sh, sh.StoreAdminAddInsurance, r)
+	fmt.Println("body: ", w.Body.String())
+	if w.Code != 200 || w.Body.String() != invalidForm+"|must be a number like 12.99|must not be more than maxOrderAmount|12,99" {
+		t.Fail()
+	}
+	var f audit.Filter
+	if len(*sh.AuditService.Search(&f)) != 0 {
+		t.Fail()
+	}
+}
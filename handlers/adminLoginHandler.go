@@ -6,6 +6,10 @@ import (
 
 	b64 "encoding/base64"
 
+	"github.com/Ulbora/Six910-ui/logging"
+	rsts "github.com/Ulbora/Six910-ui/resetsrv"
+	ss "github.com/Ulbora/Six910-ui/sessrv"
+	thr "github.com/Ulbora/Six910-ui/throtsrv"
 	userv "github.com/Ulbora/Six910-ui/usersrv"
 	api "github.com/Ulbora/Six910API-Go"
 	oauth2 "github.com/Ulbora/go-oauth2-client"
@@ -36,7 +40,7 @@ func (h *Six910Handler) StoreAdminLogin(w http.ResponseWriter, r *http.Request)
 		var lge ProcError
 		lge.Error = loginErr
 		h.Log.Debug("in login----")
-		h.AdminTemplates.ExecuteTemplate(w, adminloginPage, &lge)
+		h.executeAdminTemplate(w, r, adminloginPage, &lge)
 	} else {
 		h.authorize(w, r)
 	}
@@ -49,15 +53,17 @@ func (h *Six910Handler) StoreAdminLoginNonOAuthUser(w http.ResponseWriter, r *ht
 	if suc {
 		username := r.FormValue("username")
 		password := r.FormValue("password")
+		if h.adminLoginThrottled(w, r, username) {
+			return
+		}
 		sEnccl := b64.StdEncoding.EncodeToString([]byte(username + ":" + password))
-		h.Log.Debug("sEnc: ", sEnccl)
 
 		var hd api.Headers
 		hd.Set("Authorization", "Basic "+sEnccl)
+		hd.Set(logging.RequestIDHeader, logging.RequestID(r))
 		//head.Set("Authorization", "Basic YWRtaW46YWRtaW4=")
 
 		h.Log.Debug("username", username)
-		h.Log.Debug("password", password)
 
 		var u api.User
 		u.Username = username
@@ -68,34 +74,23 @@ func (h *Six910Handler) StoreAdminLoginNonOAuthUser(w http.ResponseWriter, r *ht
 		h.Log.Debug("usr: ", *usrcl)
 		if usrcl.Enabled && usrcl.Username == u.Username && usrcl.Role == storeAdmin {
 			loginSuc = true
-			h.Log.Debug("loginSuc", loginSuc)
-			s.Values["storeAdminUser"] = true
-			s.Values["username"] = username
-			s.Values["password"] = password
-
 		}
 		h.Log.Debug("login suc", loginSuc)
 		if loginSuc {
-			//if lari.ResponseType == codeRespType || lari.ResponseType == tokenRespType {
-			s.Values["loggedIn"] = true
-
-			//s.Values["user"] = username
-			serr := s.Save(r, w)
+			var as ss.AdminSession
+			as.Username = username
+			as.Password = password
+			as.TwoFactorPending = h.TwoFactorService != nil && h.TwoFactorService.Enrolled(username)
+			serr := h.startAdminSession(w, r, s, &as)
 			h.Log.Debug("serr", serr)
-			//session, sserr := store.Get(r, "temp-name")
-			//fmt.Println("sserr", sserr)
-			//session.Store()
-			//session.Options.Path = "/oauth/"
-			//session.Values["loggedIn"] = true
-			//fmt.Println("store", session.Store())
-			//session.Save(r, w)
-
-			//clintStr := strconv.FormatInt(lari.ClientID, 10)
-			http.Redirect(w, r, adminIndex, http.StatusFound)
-			//} else {
-			//http.Redirect(w, r, invalidGrantErrorURL, http.StatusFound)
-			//}
+			if as.TwoFactorPending {
+				http.Redirect(w, r, adminLoginTwoFactor, http.StatusFound)
+			} else {
+				h.LoginThrottle.Reset(thr.UserKey(adminScope, username))
+				http.Redirect(w, r, adminIndex, http.StatusFound)
+			}
 		} else {
+			h.LoginThrottle.Fail(adminLoginKeys(username, r)...)
 			http.Redirect(w, r, adminLoginFailedURL, http.StatusFound)
 		}
 
@@ -106,63 +101,61 @@ func (h *Six910Handler) StoreAdminLoginNonOAuthUser(w http.ResponseWriter, r *ht
 
 //StoreAdminChangePassword StoreAdminChangePassword
 func (h *Six910Handler) StoreAdminChangePassword(w http.ResponseWriter, r *http.Request) {
-	s, suc := h.getSession(r)
-	h.Log.Debug("session suc", suc)
-	if suc {
-		// loggedInAuth := s.Values["loggedIn"]
-		// storeAdminUser := s.Values["storeAdminUser"]
-		// h.Log.Debug("loggedIn in backups: ", loggedInAuth)
-		// if loggedInAuth == true && storeAdminUser == true {
-		if h.isStoreAdminLoggedIn(s) {
-			h.AdminTemplates.ExecuteTemplate(w, adminChangePwPage, nil)
-		} else {
-			h.authorize(w, r)
-		}
-	}
+	h.executeAdminTemplate(w, r, adminChangePwPage, nil)
 }
 
 //StoreAdminChangeUserPassword StoreAdminChangeUserPassword
 func (h *Six910Handler) StoreAdminChangeUserPassword(w http.ResponseWriter, r *http.Request) {
-	s, suc := h.getSession(r)
-	if suc {
-		loggedIn := s.Values["userLoggenIn"]
-		storeAdminUser := s.Values["storeAdminUser"]
-		token := h.token
-		h.Log.Debug("user update pw Logged in: ", loggedIn)
-
-		if loggedIn == nil || loggedIn.(bool) == false || storeAdminUser.(bool) == false || token == nil {
-			h.authorize(w, r)
-		} else {
-			var uu userv.UserPW
-			clientID := r.FormValue("clientId")
-			h.Log.Debug("user update pw client: ", clientID)
-			clientIDD, _ := strconv.ParseInt(clientID, 10, 0)
-			uu.ClientID = clientIDD
+	var suc bool
+	if h.OAuth2Enabled {
+		var uu userv.UserPW
+		clientID := r.FormValue("clientId")
+		h.Log.Debug("user update pw client: ", clientID)
+		clientIDD, _ := strconv.ParseInt(clientID, 10, 0)
+		uu.ClientID = clientIDD
 
-			username := r.FormValue("username")
-			h.Log.Debug("user update pw username: ", username)
-			uu.Username = username
+		username := r.FormValue("username")
+		h.Log.Debug("user update pw username: ", username)
+		uu.Username = username
 
-			password := r.FormValue("password")
-			h.Log.Debug("user update pw password: ", password)
-			uu.Password = password
+		uu.Password = r.FormValue("password")
 
-			h.UserService.SetToken(h.token.AccessToken)
+		us := h.UserService.SetToken(h.getAdminUser(r).AccessToken)
 
-			res := h.UserService.UpdateUser(&uu)
-			h.Log.Debug("user update pw res: ", *res)
-			if res.Success {
-				http.Redirect(w, r, adminIndex, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminChangePassword, http.StatusFound)
-			}
+		res, err := us.UpdateUser(&uu)
+		h.Log.Debug("user update pw res: ", *res, err)
+		suc = err == nil
+		if suc {
+			h.passwordChanged(rsts.ScopeAdmin, username)
+			h.auditPassword(r, auditAdminUser, username)
 		}
+	} else {
+		as := h.getAdminUser(r)
+		var u api.User
+		u.Username = as.Username
+		u.OldPassword = r.FormValue("oldPassword")
+		u.Password = r.FormValue("password")
+		var hd api.Headers
+		hd.Set(logging.RequestIDHeader, logging.RequestID(r))
+		suc, _ = h.Manager.StoreAdminChangePassword(&u, &hd)
+		h.Log.Debug("user update pw suc: ", suc)
+		if suc {
+			as.Password = u.Password
+			h.AdminSessions.Update(as)
+			h.passwordChanged(rsts.ScopeAdmin, as.Username)
+			h.auditPassword(r, auditAdminUser, as.Username)
+		}
+	}
+	if suc {
+		http.Redirect(w, r, adminIndex, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminChangePassword, http.StatusFound)
 	}
 }
 
 //StoreAdminLogout StoreAdminLogout
 func (h *Six910Handler) StoreAdminLogout(w http.ResponseWriter, r *http.Request) {
-	h.token = nil
+	h.endAdminSession(r)
 	cookie := &http.Cookie{
 		Name:   "goauth2-ui",
 		Value:  "",
@@ -189,6 +182,7 @@ func (h *Six910Handler) StoreAdminHandleToken(w http.ResponseWriter, r *http.Req
 	h.Log.Debug("handle token")
 	if state == h.ClientCreds.AuthCodeState {
 
+		h.authMu.Lock()
 		h.Auth.SetOauthHost(h.OauthHost)
 		h.Auth.SetClientID(h.ClientCreds.AuthCodeClient)
 		h.Auth.SetSecret(h.ClientCreds.AuthCodeSecret)
@@ -198,22 +192,21 @@ func (h *Six910Handler) StoreAdminHandleToken(w http.ResponseWriter, r *http.Req
 		h.Log.Debug("getting token")
 
 		resp := h.Auth.AuthCodeToken()
-		h.Log.Debug("token resp: ", *resp)
-
-		h.Log.Debug("token len: ", len(resp.AccessToken))
-
-		h.Log.Debug("token : ", resp.AccessToken)
+		h.authMu.Unlock()
 		if resp != nil && resp.AccessToken != "" {
 
 			s, suc := h.getSession(r)
 			if suc {
 				h.Log.Debug("userLoggenIn : ", true)
 				s.Values["userLoggenIn"] = true
-				s.Values["storeAdminUser"] = true
-
-				h.token = resp
 
-				err := s.Save(r, w)
+				var as ss.AdminSession
+				setAdminToken(&as, resp)
+				as.Username = tokenUsername(resp.AccessToken)
+				if as.Username == "" {
+					h.Log.Info("OAuth2 access token names no user; default permissions apply")
+				}
+				err := h.startAdminSession(w, r, s, &as)
 				h.Log.Debug(err)
 				http.Redirect(w, r, "/clients", http.StatusFound)
 			}
@@ -225,7 +218,7 @@ func (h *Six910Handler) authorize(w http.ResponseWriter, r *http.Request) bool {
 	h.Log.Debug("in authorize")
 	var resp bool
 	if !h.OAuth2Enabled {
-		http.Redirect(w, r, adminloginPage, http.StatusFound)
+		http.Redirect(w, r, adminLogin, http.StatusFound)
 	} else {
 		var a oauth2.AuthCodeAuthorize
 		a.ClientID = h.ClientCreds.AuthCodeClient // h.getAuthCodeClient()
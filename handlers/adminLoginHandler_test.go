@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"encoding/base64"
 	"fmt"
 	"html/template"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 
 	lg "github.com/Ulbora/Level_Logger"
 	mapi "github.com/Ulbora/Six910-ui/mockapi"
+	perms "github.com/Ulbora/Six910-ui/permsrv"
 	userv "github.com/Ulbora/Six910-ui/usersrv"
 	api "github.com/Ulbora/Six910API-Go"
 	oauth2 "github.com/Ulbora/go-oauth2-client"
@@ -129,6 +131,7 @@ func TestSix910Handler_StoreAdminHandleToken(t *testing.T) {
 	cc.AuthCodeSecret = "12345"
 	h.ClientCreds = &cc
 	h.OauthHost = "http://test12.com"
+	h.GetNew()
 	r, _ := http.NewRequest("POST", "https://test.com?code=555&state=123", nil)
 	w := httptest.NewRecorder()
 	h.StoreAdminHandleToken(w, r)
@@ -136,6 +139,57 @@ func TestSix910Handler_StoreAdminHandleToken(t *testing.T) {
 	if w.Code != 302 {
 		t.Fail()
 	}
+	s, _ := h.getSession(r)
+	id, _ := s.Values[adminSessionIDKey].(string)
+	as := h.AdminSessions.Get(id)
+	if as == nil || as.AccessToken != "45ffffff" {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAdminHandleTokenUsername(t *testing.T) {
+	var h Six910Handler
+	var l lg.Logger
+	h.Log = &l
+	var ps perms.Six910PermissionService
+	ps.Users = map[string][]string{"packer": {"fulfillment"}}
+	ps.Default = []string{"readonly"}
+	h.PermissionService = ps.GetNew()
+
+	var mTkn oauth2.Token
+	mTkn.AccessToken = "eyJhbGciOiJIUzI1NiJ9." +
+		base64.RawURLEncoding.EncodeToString([]byte(`{"userId":"packer","clientId":2}`)) + ".c2ln"
+	var mockAcTkn oauth2.MockAuthCodeToken
+	mockAcTkn.MockToken = &mTkn
+	h.Auth = &mockAcTkn
+
+	var cc ClientCreds
+	cc.AuthCodeState = "123"
+	h.ClientCreds = &cc
+	h.GetNew()
+	r, _ := http.NewRequest("GET", "https://test.com?code=555&state=123", nil)
+	w := httptest.NewRecorder()
+	h.StoreAdminHandleToken(w, r)
+	s, _ := h.getSession(r)
+	id, _ := s.Values[adminSessionIDKey].(string)
+	as := h.AdminSessions.Get(id)
+	if as == nil || as.Username != "packer" {
+		fmt.Println("session: ", as)
+		t.Fail()
+	}
+	if !h.PermissionService.Allowed(as.Username, perms.Shipments, true) || h.PermissionService.Allowed(as.Username, perms.Products, true) {
+		t.Fail()
+	}
+}
+
+func TestTokenUsername(t *testing.T) {
+	claims := func(js string) string {
+		return "h." + base64.RawURLEncoding.EncodeToString([]byte(js)) + ".s"
+	}
+	if tokenUsername(claims(`{"userId":"jane"}`)) != "jane" || tokenUsername(claims(`{"sub":"bob"}`)) != "bob" ||
+		tokenUsername(claims(`{"clientId":2}`)) != "" || tokenUsername("45ffffff") != "" || tokenUsername("a.!!.b") != "" {
+		t.Fail()
+	}
 }
 
 func TestSix910Handler_HandleLogout(t *testing.T) {
@@ -158,13 +212,14 @@ func TestSix910Handler_HandleLogout(t *testing.T) {
 	fmt.Println("suc: ", suc)
 
 	s.Values["accessTokenKey"] = "123"
+	as := loginTestAdmin(&sh, s)
 
 	w := httptest.NewRecorder()
 	s.Save(r, w)
 	h := sh.GetNew()
 	h.StoreAdminLogout(w, r)
 	fmt.Println("code: ", w.Code)
-	if w.Code != 302 {
+	if w.Code != 302 || sh.AdminSessions.Get(as.ID) != nil {
 		t.Fail()
 	}
 
@@ -207,7 +262,20 @@ func TestSix910Handler_StoreAdminLoginNonOAuthUser(t *testing.T) {
 	h.StoreAdminLoginNonOAuthUser(w, r)
 	fmt.Println("code: ", w.Code)
 
-	if w.Code != 302 {
+	if w.Code != 302 || w.Header().Get("Location") != adminIndex {
+		t.Fail()
+	}
+	s, _ := sh.getSession(r)
+	id, _ := s.Values[adminSessionIDKey].(string)
+	as := sh.AdminSessions.Get(id)
+	if as == nil || as.Username != "tester123" || as.Password != "tester" || s.Values["password"] != nil {
+		t.Fail()
+	}
+
+	w2 := httptest.NewRecorder()
+	h.StoreAdminLoginNonOAuthUser(w2, r)
+	id2, _ := s.Values[adminSessionIDKey].(string)
+	if id2 == id || sh.AdminSessions.Get(id) != nil || len(*sh.AdminSessions.List()) != 1 {
 		t.Fail()
 	}
 }
@@ -312,11 +380,11 @@ func TestSix910Handler_StoreAdminChangePassword(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminChangePassword(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminChangePassword)).ServeHTTP(w, r)
 
 	if w.Code != 200 {
 		t.Fail()
@@ -342,7 +410,7 @@ func TestSix910Handler_StoreAdminChangePasswordNotLoggedin(t *testing.T) {
 	//s.Values["loggedIn"] = true
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminChangePassword(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminChangePassword)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -369,16 +437,17 @@ func TestSix910Handler_StoreAdminChangeUserPassword(t *testing.T) {
 	var mTkn oauth2.Token
 	mTkn.AccessToken = "45ffffff"
 
-	sh.token = &mTkn
+	sh.OAuth2Enabled = true
 	r, _ := http.NewRequest("POST", "https://test.com", nil)
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
 	s.Values["userLoggenIn"] = true
 	s.Values["storeAdminUser"] = true
+	loginTestOAuthAdmin(&sh, s, &mTkn)
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminChangeUserPassword(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminChangeUserPassword)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -405,7 +474,7 @@ func TestSix910Handler_StoreAdminChangeUserPasswordNotLoggedIn(t *testing.T) {
 	var mTkn oauth2.Token
 	mTkn.AccessToken = "45ffffff"
 
-	sh.token = &mTkn
+	sh.OAuth2Enabled = true
 	r, _ := http.NewRequest("POST", "https://test.com", nil)
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
@@ -413,7 +482,7 @@ func TestSix910Handler_StoreAdminChangeUserPasswordNotLoggedIn(t *testing.T) {
 	//s.Values["userLoggenIn"] = true
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminChangeUserPassword(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminChangeUserPassword)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -440,16 +509,17 @@ func TestSix910Handler_StoreAdminChangeUserPasswordUpdateFail(t *testing.T) {
 	var mTkn oauth2.Token
 	mTkn.AccessToken = "45ffffff"
 
-	sh.token = &mTkn
+	sh.OAuth2Enabled = true
 	r, _ := http.NewRequest("POST", "https://test.com", nil)
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
 	s.Values["userLoggenIn"] = true
 	s.Values["storeAdminUser"] = true
+	loginTestOAuthAdmin(&sh, s, &mTkn)
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminChangeUserPassword(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminChangeUserPassword)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -33,126 +33,108 @@ type OrderPage struct {
 	Notes         *[]sdbi.OrderComment
 	OrderItemList *[]sdbi.OrderItem
 	Orders        *[]sdbi.Order
+	FormErrors
 }
 
 //StoreAdminEditOrderPage StoreAdminEditOrderPage
 func (h *Six910Handler) StoreAdminEditOrderPage(w http.ResponseWriter, r *http.Request) {
-	s, suc := h.getSession(r)
-	h.Log.Debug("session suc in order edit view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(s) {
-			hd := h.getHeader(s)
-			eovars := mux.Vars(r)
-			idstr := eovars["id"]
-			oID, _ := strconv.ParseInt(idstr, 10, 64)
-			h.Log.Debug("order id in edit", oID)
-			odr := h.API.GetOrder(oID, hd)
-			h.Log.Debug("order in edit", odr)
-			oItemList := h.API.GetOrderItemList(oID, hd)
-			notes := h.API.GetOrderCommentList(oID, hd)
-			odErr := r.URL.Query().Get("error")
-			var eoparm OrderPage
-			eoparm.Error = odErr
-			eoparm.Order = odr
-			eoparm.OrderItemList = oItemList
-			eoparm.Notes = notes
-			h.AdminTemplates.ExecuteTemplate(w, adminEditOrderPage, &eoparm)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	hd := h.getAdminHeader(r)
+	eovars := mux.Vars(r)
+	idstr := eovars["id"]
+	oID, _ := strconv.ParseInt(idstr, 10, 64)
+	h.Log.Debug("order id in edit", oID)
+	odr := h.API.GetOrder(oID, hd)
+	h.Log.Debug("order in edit", odr)
+	oItemList := h.API.GetOrderItemList(oID, hd)
+	notes := h.API.GetOrderCommentList(oID, hd)
+	odErr := r.URL.Query().Get("error")
+	var eoparm OrderPage
+	eoparm.Error = odErr
+	eoparm.Order = odr
+	eoparm.OrderItemList = oItemList
+	eoparm.Notes = notes
+	h.executeAdminTemplate(w, r, adminEditOrderPage, &eoparm)
 }
 
 //StoreAdminEditOrder StoreAdminEditOrder
 func (h *Six910Handler) StoreAdminEditOrder(w http.ResponseWriter, r *http.Request) {
-	s, suc := h.getSession(r)
-	h.Log.Debug("session suc in prod edit", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(s) {
-			eop := h.processOrder(r)
-			found, eocom := h.processOrderComment(r)
-			h.Log.Debug("order in update", *eop)
-			hd := h.getHeader(s)
-			res := h.API.UpdateOrder(eop, hd)
-			if found {
-				cres := h.API.AddOrderComments(eocom, hd)
-				h.Log.Debug("order comment add resp", *cres)
-			}
-			h.Log.Debug("order update resp", *res)
-			if res.Success {
-				http.Redirect(w, r, adminOrderListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminProductListViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
+	eop, fe := h.processOrder(r)
+	if len(fe) > 0 {
+		var fp OrderPage
+		fp.Error = invalidForm
+		fp.Order = eop
+		fp.FormErrors = formErrors(r, fe)
+		hd := h.getAdminHeader(r)
+		fp.OrderItemList = h.API.GetOrderItemList(eop.ID, hd)
+		fp.Notes = h.API.GetOrderCommentList(eop.ID, hd)
+		h.executeAdminTemplate(w, r, adminEditOrderPage, &fp)
+		return
+	}
+	found, eocom := h.processOrderComment(r)
+	h.Log.Debug("order in update", *eop)
+	hd := h.getAdminHeader(r)
+	ae := h.auditBefore(r, auditOrder, eop.ID, func() interface{} { return h.API.GetOrder(eop.ID, hd) })
+	res := h.API.UpdateOrder(eop, hd)
+	if found {
+		cres := h.API.AddOrderComments(eocom, hd)
+		h.Log.Debug("order comment add resp", *cres)
+		if cres.Success {
+			h.auditCreate(r, auditOrderComment, cres.ID, eocom)
 		}
 	}
+	h.Log.Debug("order update resp", *res)
+	if res.Success {
+		ae.updated(eop)
+		http.Redirect(w, r, adminOrderListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminProductListViewFail, http.StatusFound)
+	}
 }
 
 //StoreAdminViewOrderList StoreAdminViewOrderList
 func (h *Six910Handler) StoreAdminViewOrderList(w http.ResponseWriter, r *http.Request) {
-	s, suc := h.getSession(r)
-	h.Log.Debug("session suc in prod list view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(s) {
-			hd := h.getHeader(s)
-			volvars := mux.Vars(r)
-			status := volvars["status"]
-			var orders *[]sdbi.Order
-			if status != "" {
-				orders = h.API.GetStoreOrderListByStatus(status, hd)
-			} else {
-				orders = h.API.GetStoreOrderList(hd)
-			}
-			plErr := r.URL.Query().Get("error")
-			var plparm OrderPage
-			plparm.Error = plErr
-			plparm.Orders = orders
-			h.Log.Debug("orders  in list", orders)
-			h.AdminTemplates.ExecuteTemplate(w, adminOrderListPage, &plparm)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	hd := h.getAdminHeader(r)
+	volvars := mux.Vars(r)
+	status := volvars["status"]
+	var orders *[]sdbi.Order
+	if status != "" {
+		orders = h.API.GetStoreOrderListByStatus(status, hd)
+	} else {
+		orders = h.API.GetStoreOrderList(hd)
 	}
+	plErr := r.URL.Query().Get("error")
+	var plparm OrderPage
+	plparm.Error = plErr
+	plparm.Orders = orders
+	h.Log.Debug("orders  in list", orders)
+	h.executeAdminTemplate(w, r, adminOrderListPage, &plparm)
 }
 
-func (h *Six910Handler) processOrder(r *http.Request) *sdbi.Order {
+func (h *Six910Handler) processOrder(r *http.Request) (*sdbi.Order, FieldErrors) {
 	var p sdbi.Order
-	id := r.FormValue("id")
-	p.ID, _ = strconv.ParseInt(id, 10, 64)
+	f := newFormReader(r)
+	p.ID = f.int("id")
 	p.Status = r.FormValue("status")
-	subTotal := r.FormValue("subTotal")
-	p.Subtotal, _ = strconv.ParseFloat(subTotal, 64)
-	shippingHandling := r.FormValue("shippingHandling")
-	p.ShippingHandling, _ = strconv.ParseFloat(shippingHandling, 64)
-	insurance := r.FormValue("insurance")
-	p.Insurance, _ = strconv.ParseFloat(insurance, 64)
-	taxes := r.FormValue("taxes")
-	p.Taxes, _ = strconv.ParseFloat(taxes, 64)
-	total := r.FormValue("total")
-	p.Total, _ = strconv.ParseFloat(total, 64)
+	p.Subtotal = f.float("subTotal")
+	p.ShippingHandling = f.float("shippingHandling")
+	p.Insurance = f.float("insurance")
+	p.Taxes = f.float("taxes")
+	p.Total = f.float("total")
 	p.OrderNumber = r.FormValue("orderNumber")
 	p.OrderType = r.FormValue("orderType")
-	pickup := r.FormValue("pickup")
-	p.Pickup, _ = strconv.ParseBool(pickup)
+	p.Pickup = f.bool("pickup")
 	p.Username = r.FormValue("username")
 	p.CustomerName = r.FormValue("customerName")
-	customerID := r.FormValue("customerId")
-	p.CustomerID, _ = strconv.ParseInt(customerID, 10, 64)
+	p.CustomerID = f.int("customerId")
 	p.BillingAddress = r.FormValue("billingAddress")
-	billingAddressID := r.FormValue("billingAddressId")
-	p.BillingAddressID, _ = strconv.ParseInt(billingAddressID, 10, 64)
+	p.BillingAddressID = f.int("billingAddressId")
 	p.ShippingAddress = r.FormValue("shippingAddress")
-	shippingAddressID := r.FormValue("shippingAddressId")
-	p.ShippingAddressID, _ = strconv.ParseInt(shippingAddressID, 10, 64)
-	shippingMethodID := r.FormValue("shippingMethodId")
-	p.ShippingMethodID, _ = strconv.ParseInt(shippingMethodID, 10, 64)
+	p.ShippingAddressID = f.int("shippingAddressId")
+	p.ShippingMethodID = f.int("shippingMethodId")
 	p.ShippingMethodName = r.FormValue("billingMethodName")
-	storeID := r.FormValue("storeId")
-	p.StoreID, _ = strconv.ParseInt(storeID, 10, 64)
+	p.StoreID = f.int("storeId")
 
-	return &p
+	return &p, f.check(validateOrder(&p))
 }
 
 func (h *Six910Handler) processOrderComment(r *http.Request) (bool, *sdbi.OrderComment) {
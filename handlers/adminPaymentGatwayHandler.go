@@ -30,140 +30,113 @@ import (
 type PgwPage struct {
 	Error         string
 	PaymentGatway *sdbi.PaymentGateway
+	FormErrors
 }
 
 //StoreAdminAddPaymentGatewayPage StoreAdminAddPaymentGatewayPage
 func (h *Six910Handler) StoreAdminAddPaymentGatewayPage(w http.ResponseWriter, r *http.Request) {
-	apgs, suc := h.getSession(r)
-	h.Log.Debug("session suc in PWG add view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(apgs) {
-			aiErr := r.URL.Query().Get("error")
-			var apgpg PgwPage
-			apgpg.Error = aiErr
-			h.AdminTemplates.ExecuteTemplate(w, adminAddPaymentGatwayPage, &apgpg)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	aiErr := r.URL.Query().Get("error")
+	var apgpg PgwPage
+	apgpg.Error = aiErr
+	h.executeAdminTemplate(w, r, adminAddPaymentGatwayPage, &apgpg)
 }
 
 //StoreAdminAddPaymentGateway StoreAdminAddPaymentGateway
 func (h *Six910Handler) StoreAdminAddPaymentGateway(w http.ResponseWriter, r *http.Request) {
-	apgs, suc := h.getSession(r)
-	h.Log.Debug("session suc in pgw add", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(apgs) {
-			apg := h.processPgw(r)
-			h.Log.Debug("pgw add", *apg)
-			hd := h.getHeader(apgs)
-			prres := h.API.AddPaymentGateway(apg, hd)
-			h.Log.Debug("pgw add resp", *prres)
-			if prres.Success {
-				http.Redirect(w, r, adminPaymentGatewayListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminAddPaymentGatewayViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	apg, fe := h.processPgw(r)
+	if len(fe) > 0 {
+		var fp PgwPage
+		fp.Error = invalidForm
+		fp.PaymentGatway = apg
+		fp.FormErrors = formErrors(r, fe)
+		h.executeAdminTemplate(w, r, adminAddPaymentGatwayPage, &fp)
+		return
+	}
+	h.Log.Debug("pgw add", *apg)
+	hd := h.getAdminHeader(r)
+	prres := h.API.AddPaymentGateway(apg, hd)
+	h.Log.Debug("pgw add resp", *prres)
+	if prres.Success {
+		h.auditCreate(r, auditPaymentGateway, prres.ID, apg)
+		http.Redirect(w, r, adminPaymentGatewayListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminAddPaymentGatewayViewFail, http.StatusFound)
 	}
 }
 
 //StoreAdminEditPaymentGatewayPage StoreAdminEditPaymentGatewayPage
 func (h *Six910Handler) StoreAdminEditPaymentGatewayPage(w http.ResponseWriter, r *http.Request) {
-	epgs, suc := h.getSession(r)
-	h.Log.Debug("session suc in pgw edit view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(epgs) {
-			hd := h.getHeader(epgs)
-			epgpErr := r.URL.Query().Get("error")
-			epgvars := mux.Vars(r)
-			idstr := epgvars["id"]
-			pgID, _ := strconv.ParseInt(idstr, 10, 64)
-			h.Log.Debug("pgw id in edit", pgID)
-			var dgp PgwPage
-			dgp.Error = epgpErr
-			dgp.PaymentGatway = h.API.GetPaymentGateway(pgID, hd)
-			h.AdminTemplates.ExecuteTemplate(w, adminEditPaymentGatwayPage, &dgp)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	hd := h.getAdminHeader(r)
+	epgpErr := r.URL.Query().Get("error")
+	epgvars := mux.Vars(r)
+	idstr := epgvars["id"]
+	pgID, _ := strconv.ParseInt(idstr, 10, 64)
+	h.Log.Debug("pgw id in edit", pgID)
+	var dgp PgwPage
+	dgp.Error = epgpErr
+	dgp.PaymentGatway = h.API.GetPaymentGateway(pgID, hd)
+	h.executeAdminTemplate(w, r, adminEditPaymentGatwayPage, &dgp)
 }
 
 //StoreAdminEditPaymentGateway StoreAdminEditPaymentGateway
 func (h *Six910Handler) StoreAdminEditPaymentGateway(w http.ResponseWriter, r *http.Request) {
-	epgs, suc := h.getSession(r)
-	h.Log.Debug("session suc in pgw edit", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(epgs) {
-			epg := h.processPgw(r)
-			h.Log.Debug("pgw update", *epg)
-			hd := h.getHeader(epgs)
-			res := h.API.UpdatePaymentGateway(epg, hd)
-			h.Log.Debug("Pgw update resp", *res)
-			if res.Success {
-				http.Redirect(w, r, adminPaymentGatewayListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminEditPaymentGatewayViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	epg, fe := h.processPgw(r)
+	if len(fe) > 0 {
+		var fp PgwPage
+		fp.Error = invalidForm
+		fp.PaymentGatway = epg
+		fp.FormErrors = formErrors(r, fe)
+		h.executeAdminTemplate(w, r, adminEditPaymentGatwayPage, &fp)
+		return
+	}
+	h.Log.Debug("pgw update", *epg)
+	hd := h.getAdminHeader(r)
+	ae := h.auditBefore(r, auditPaymentGateway, epg.ID, func() interface{} { return h.API.GetPaymentGateway(epg.ID, hd) })
+	res := h.API.UpdatePaymentGateway(epg, hd)
+	h.Log.Debug("Pgw update resp", *res)
+	if res.Success {
+		ae.updated(epg)
+		http.Redirect(w, r, adminPaymentGatewayListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminEditPaymentGatewayViewFail, http.StatusFound)
 	}
 }
 
 //StoreAdminViewPaymentGatewayList StoreAdminViewPaymentGatewayList
 func (h *Six910Handler) StoreAdminViewPaymentGatewayList(w http.ResponseWriter, r *http.Request) {
-	gpgls, suc := h.getSession(r)
-	h.Log.Debug("session suc in pgw view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(gpgls) {
-			hd := h.getHeader(gpgls)
-			pgl := h.API.GetPaymentGateways(hd)
-			h.Log.Debug("pgw  in list", pgl)
-			h.AdminTemplates.ExecuteTemplate(w, adminPaymentGatwayListPage, &pgl)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	hd := h.getAdminHeader(r)
+	pgl := h.API.GetPaymentGateways(hd)
+	h.Log.Debug("pgw  in list", pgl)
+	h.executeAdminTemplate(w, r, adminPaymentGatwayListPage, &pgl)
 }
 
 //StoreAdminDeletePaymentGateway StoreAdminDeletePaymentGateway
 func (h *Six910Handler) StoreAdminDeletePaymentGateway(w http.ResponseWriter, r *http.Request) {
-	dpgs, suc := h.getSession(r)
-	h.Log.Debug("session suc in pgw list delete", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(dpgs) {
-			hd := h.getHeader(dpgs)
-			dpgvars := mux.Vars(r)
-			idstrd := dpgvars["id"]
-			idddpg, _ := strconv.ParseInt(idstrd, 10, 64)
-			res := h.API.DeletePaymentGateway(idddpg, hd)
-			h.Log.Debug("pgw delete resp", *res)
-			if res.Success {
-				http.Redirect(w, r, adminPaymentGatewayListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminPaymentGatewayListViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	hd := h.getAdminHeader(r)
+	dpgvars := mux.Vars(r)
+	idstrd := dpgvars["id"]
+	idddpg, _ := strconv.ParseInt(idstrd, 10, 64)
+	ae := h.auditBefore(r, auditPaymentGateway, idddpg, func() interface{} { return h.API.GetPaymentGateway(idddpg, hd) })
+	res := h.API.DeletePaymentGateway(idddpg, hd)
+	h.Log.Debug("pgw delete resp", *res)
+	if res.Success {
+		ae.deleted()
+		http.Redirect(w, r, adminPaymentGatewayListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminPaymentGatewayListViewFail, http.StatusFound)
 	}
 }
 
-func (h *Six910Handler) processPgw(r *http.Request) *sdbi.PaymentGateway {
+func (h *Six910Handler) processPgw(r *http.Request) (*sdbi.PaymentGateway, FieldErrors) {
 	var p sdbi.PaymentGateway
-	id := r.FormValue("id")
-	p.ID, _ = strconv.ParseInt(id, 10, 64)
+	f := newFormReader(r)
+	p.ID = f.int("id")
 	p.CheckoutURL = r.FormValue("checkoutUrl")
 	p.ClientID = r.FormValue("clientId")
 	p.ClientKey = r.FormValue("clientKey")
 	p.LogoURL = r.FormValue("logoUrl")
 	p.PostOrderURL = r.FormValue("postOrderUrl")
-	storePID := r.FormValue("storePluginId")
-	p.StorePluginsID, _ = strconv.ParseInt(storePID, 10, 64)
+	p.StorePluginsID = f.int("storePluginId")
 
-	return &p
+	return &p, f.check(validatePaymentGateway(&p), "storePluginsId", "storePluginId")
 }
@@ -56,13 +56,11 @@ func TestSix910Handler_StoreAdminAddPaymentGatewayPage(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddPaymentGatewayPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddPaymentGatewayPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -112,11 +110,9 @@ func TestSix910Handler_StoreAdminAddPaymentGatewayPageLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddPaymentGatewayPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddPaymentGatewayPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -153,18 +149,16 @@ func TestSix910Handler_StoreAdminAddPaymentGateway(t *testing.T) {
 
 	//-----------end mocking --------
 
-	r, _ := http.NewRequest("POST", "https://test.com", strings.NewReader("checkoutUrl=tester&clientId=125"))
+	r, _ := http.NewRequest("POST", "https://test.com", strings.NewReader("checkoutUrl=https://pay.test.com/checkout&clientId=125&storePluginId=4"))
 	r.Header.Set("Content-Type", "application/x-www-form-urlencoded; param=value")
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddPaymentGateway(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddPaymentGateway)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -208,11 +202,9 @@ func TestSix910Handler_StoreAdminAddPaymentGatewayLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddPaymentGateway(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddPaymentGateway)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -249,18 +241,16 @@ func TestSix910Handler_StoreAdminAddPaymentGatewayFail(t *testing.T) {
 
 	//-----------end mocking --------
 
-	r, _ := http.NewRequest("POST", "https://test.com", strings.NewReader("cost=11.23&maxOrderAmount=300.50"))
+	r, _ := http.NewRequest("POST", "https://test.com", strings.NewReader("clientId=125&storePluginId=4"))
 	r.Header.Set("Content-Type", "application/x-www-form-urlencoded; param=value")
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddPaymentGateway(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddPaymentGateway)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -310,13 +300,11 @@ func TestSix910Handler_StoreAdminEditPaymentGatewayPage(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditPaymentGatewayPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditPaymentGatewayPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -368,11 +356,9 @@ func TestSix910Handler_StoreAdminEditPaymentGatewayPageLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditPaymentGatewayPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditPaymentGatewayPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -408,18 +394,16 @@ func TestSix910Handler_StoreAdminEditPaymentGateway(t *testing.T) {
 
 	//-----------end mocking --------
 
-	r, _ := http.NewRequest("PUT", "https://test.com", strings.NewReader("id=3&checkoutUrl=tester&clientId=125"))
+	r, _ := http.NewRequest("PUT", "https://test.com", strings.NewReader("id=3&checkoutUrl=https://pay.test.com/checkout&clientId=125&storePluginId=4"))
 	r.Header.Set("Content-Type", "application/x-www-form-urlencoded; param=value")
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditPaymentGateway(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditPaymentGateway)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -462,11 +446,9 @@ func TestSix910Handler_StoreAdminEditPaymentGatewayLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditPaymentGateway(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditPaymentGateway)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -502,18 +484,16 @@ func TestSix910Handler_StoreAdminEditPaymentGatewayFail(t *testing.T) {
 
 	//-----------end mocking --------
 
-	r, _ := http.NewRequest("PUT", "https://test.com", strings.NewReader("id=3&cost=11.23&maxOrderAmount=300.50"))
+	r, _ := http.NewRequest("PUT", "https://test.com", strings.NewReader("id=3&clientId=125&storePluginId=4"))
 	r.Header.Set("Content-Type", "application/x-www-form-urlencoded; param=value")
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditPaymentGateway(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditPaymentGateway)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -562,13 +542,11 @@ func TestSix910Handler_StoreAdminViewPaymentGatewayList(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminViewPaymentGatewayList(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminViewPaymentGatewayList)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -619,11 +597,9 @@ func TestSix910Handler_StoreAdminViewPaymentGatewayListLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminViewPaymentGatewayList(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminViewPaymentGatewayList)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -667,13 +643,11 @@ func TestSix910Handler_StoreAdminDeletePaymentGateway(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminDeletePaymentGateway(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminDeletePaymentGateway)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -719,11 +693,9 @@ func TestSix910Handler_StoreAdminDeletePaymentGatewayLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminDeletePaymentGateway(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminDeletePaymentGateway)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -767,13 +739,11 @@ func TestSix910Handler_StoreAdminDeletePaymentGatewayfail(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminDeletePaymentGateway(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminDeletePaymentGateway)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+//ForbiddenPage ForbiddenPage
+type ForbiddenPage struct {
+	Error    string
+	Area     string
+	Username string
+}
+
+//AdminPermission AdminPermission returns middleware for one admin route
+//group. GET and HEAD need read access to area, everything else needs
+//write access. It runs after AdminAuth; with no PermissionService every
+//admin can do everything.
+func (h *Six910Handler) AdminPermission(area string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if h.PermissionService == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			var username string
+			if as := h.getAdminUser(r); as != nil {
+				username = as.Username
+			}
+			write := r.Method != http.MethodGet && r.Method != http.MethodHead
+			if h.PermissionService.Allowed(username, area, write) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			h.Log.Info("admin permission denied for: ", username, " area: ", area, " ", r.Method, " ", r.URL.Path)
+			h.forbidden(w, r, area, username)
+		})
+	}
+}
+
+//adminCanRead adminCanRead reports whether the admin may view area, for
+//pages that show more than one area
+func (h *Six910Handler) adminCanRead(r *http.Request, area string) bool {
+	return h.adminAllowed(r, area, false)
+}
+
+//adminAllowed adminAllowed reports whether the admin may view, or with
+//write change, area
+func (h *Six910Handler) adminAllowed(r *http.Request, area string, write bool) bool {
+	if h.PermissionService == nil {
+		return true
+	}
+	var username string
+	if as := h.getAdminUser(r); as != nil {
+		username = as.Username
+	}
+	return h.PermissionService.Allowed(username, area, write)
+}
+
+//forbidden forbidden renders forbiddenPage with a 403, or plain text when
+//the admin templates do not have one
+func (h *Six910Handler) forbidden(w http.ResponseWriter, r *http.Request, area string, username string) {
+	if h.AdminTemplates == nil || h.AdminTemplates.Lookup(adminForbiddenPage) == nil {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	var fp ForbiddenPage
+	fp.Error = "You do not have permission for " + area
+	fp.Area = area
+	fp.Username = username
+	w.WriteHeader(http.StatusForbidden)
+	h.executeAdminTemplate(w, r, adminForbiddenPage, &fp)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	lg "github.com/Ulbora/Level_Logger"
+	perms "github.com/Ulbora/Six910-ui/permsrv"
+)
+
+func permissionTestHandler() *Six910Handler {
+	var sh Six910Handler
+	var l lg.Logger
+	l.LogLevel = lg.AllLevel
+	sh.Log = &l
+	var ps perms.Six910PermissionService
+	ps.Users = map[string][]string{"tester": {"fulfillment"}}
+	sh.PermissionService = ps.GetNew()
+	return &sh
+}
+
+func servePermission(sh *Six910Handler, method string, area string) (*httptest.ResponseRecorder, bool) {
+	var called bool
+	r, _ := http.NewRequest(method, "/admin/test", nil)
+	s, _ := sh.getSession(r)
+	loginTestAdmin(sh, s)
+	s.Values["storeAdminUser"] = true
+	w := httptest.NewRecorder()
+	h := sh.GetNew()
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+	h.AdminAuth(h.AdminPermission(area)(next)).ServeHTTP(w, r)
+	return w, called
+}
+
+func TestSix910Handler_AdminPermission(t *testing.T) {
+	sh := permissionTestHandler()
+	if w, called := servePermission(sh, "POST", perms.Shipments); !called || w.Code != 200 {
+		t.Fail()
+	}
+	if w, called := servePermission(sh, "GET", perms.Orders); !called || w.Code != 200 {
+		t.Fail()
+	}
+	w, called := servePermission(sh, "POST", perms.Orders)
+	fmt.Println("body: ", w.Body.String())
+	if called || w.Code != http.StatusForbidden {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_AdminPermissionPage(t *testing.T) {
+	sh := permissionTestHandler()
+	sh.AdminTemplates = template.Must(template.New("admin").Parse(
+		`{{define "forbidden.html"}}{{.Username}} {{.Area}}{{end}}`))
+	w, called := servePermission(sh, "GET", perms.Payments)
+	fmt.Println("body: ", w.Body.String())
+	if called || w.Code != http.StatusForbidden || w.Body.String() != "tester payments" {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_AdminPermissionOff(t *testing.T) {
+	sh := permissionTestHandler()
+	sh.PermissionService = nil
+	if _, called := servePermission(sh, "POST", perms.Payments); !called {
+		t.Fail()
+	}
+}
@@ -30,46 +30,40 @@ import (
 type PluginPage struct {
 	Error  string
 	Plugin *sdbi.Plugins
+	FormErrors
 }
 
 //StoreAdminAddPluginPage StoreAdminAddPluginPage
 func (h *Six910Handler) StoreAdminAddPluginPage(w http.ResponseWriter, r *http.Request) {
 	if !h.OAuth2Enabled {
-		apls, suc := h.getSession(r)
-		h.Log.Debug("session suc in plugin add view", suc)
-		if suc {
-			if h.isStoreAdminLoggedIn(apls) {
-				aplErr := r.URL.Query().Get("error")
-				var aplpg PluginPage
-				aplpg.Error = aplErr
-				h.AdminTemplates.ExecuteTemplate(w, adminAddPluginPage, &aplpg)
-			} else {
-				http.Redirect(w, r, adminloginPage, http.StatusFound)
-			}
-		}
+		aplErr := r.URL.Query().Get("error")
+		var aplpg PluginPage
+		aplpg.Error = aplErr
+		h.executeAdminTemplate(w, r, adminAddPluginPage, &aplpg)
 	}
 }
 
 //StoreAdminAddPlugin StoreAdminAddPlugin
 func (h *Six910Handler) StoreAdminAddPlugin(w http.ResponseWriter, r *http.Request) {
 	if !h.OAuth2Enabled {
-		apiis, suc := h.getSession(r)
-		h.Log.Debug("session suc in plugin add", suc)
-		if suc {
-			if h.isStoreAdminLoggedIn(apiis) {
-				apii := h.processPlugin(r)
-				h.Log.Debug("Plugin add", *apii)
-				hd := h.getHeader(apiis)
-				pires := h.API.AddPlugin(apii, hd)
-				h.Log.Debug("Plugin add resp", *pires)
-				if pires.Success {
-					http.Redirect(w, r, adminAddPluginView, http.StatusFound)
-				} else {
-					http.Redirect(w, r, adminAddPluginViewFail, http.StatusFound)
-				}
-			} else {
-				http.Redirect(w, r, adminloginPage, http.StatusFound)
-			}
+		apii, fe := h.processPlugin(r)
+		if len(fe) > 0 {
+			var fp PluginPage
+			fp.Error = invalidForm
+			fp.Plugin = apii
+			fp.FormErrors = formErrors(r, fe)
+			h.executeAdminTemplate(w, r, adminAddPluginPage, &fp)
+			return
+		}
+		h.Log.Debug("Plugin add", *apii)
+		hd := h.getAdminHeader(r)
+		pires := h.API.AddPlugin(apii, hd)
+		h.Log.Debug("Plugin add resp", *pires)
+		if pires.Success {
+			h.auditCreate(r, auditPlugin, pires.ID, apii)
+			http.Redirect(w, r, adminAddPluginView, http.StatusFound)
+		} else {
+			http.Redirect(w, r, adminAddPluginViewFail, http.StatusFound)
 		}
 	}
 }
@@ -77,114 +71,91 @@ func (h *Six910Handler) StoreAdminAddPlugin(w http.ResponseWriter, r *http.Reque
 //StoreAdminEditPluginPage StoreAdminEditPluginPage
 func (h *Six910Handler) StoreAdminEditPluginPage(w http.ResponseWriter, r *http.Request) {
 	if !h.OAuth2Enabled {
-		epis, suc := h.getSession(r)
-		h.Log.Debug("session suc in plugin edit view", suc)
-		if suc {
-			if h.isStoreAdminLoggedIn(epis) {
-				hd := h.getHeader(epis)
-				eipErr := r.URL.Query().Get("error")
-				epivars := mux.Vars(r)
-				pidstr := epivars["id"]
-				iID, _ := strconv.ParseInt(pidstr, 10, 64)
-				h.Log.Debug("plugin id in edit", iID)
-				var epip PluginPage
-				epip.Error = eipErr
-				epip.Plugin = h.API.GetPlugin(iID, hd)
-				h.AdminTemplates.ExecuteTemplate(w, adminEditPluginPage, &epip)
-			} else {
-				http.Redirect(w, r, adminloginPage, http.StatusFound)
-			}
-		}
+		hd := h.getAdminHeader(r)
+		eipErr := r.URL.Query().Get("error")
+		epivars := mux.Vars(r)
+		pidstr := epivars["id"]
+		iID, _ := strconv.ParseInt(pidstr, 10, 64)
+		h.Log.Debug("plugin id in edit", iID)
+		var epip PluginPage
+		epip.Error = eipErr
+		epip.Plugin = h.API.GetPlugin(iID, hd)
+		h.executeAdminTemplate(w, r, adminEditPluginPage, &epip)
 	}
 }
 
 //StoreAdminEditPlugin StoreAdminEditPlugin
 func (h *Six910Handler) StoreAdminEditPlugin(w http.ResponseWriter, r *http.Request) {
 	if !h.OAuth2Enabled {
-		epiis, suc := h.getSession(r)
-		h.Log.Debug("session suc in plugin edit", suc)
-		if suc {
-			if h.isStoreAdminLoggedIn(epiis) {
-				epii := h.processPlugin(r)
-				h.Log.Debug("Plugin update", *epii)
-				hd := h.getHeader(epiis)
-				res := h.API.UpdatePlugin(epii, hd)
-				h.Log.Debug("Plugin update resp", *res)
-				if res.Success {
-					http.Redirect(w, r, adminPluginListView, http.StatusFound)
-				} else {
-					http.Redirect(w, r, adminPluginListViewFail, http.StatusFound)
-				}
-			} else {
-				http.Redirect(w, r, adminloginPage, http.StatusFound)
-			}
+		epii, fe := h.processPlugin(r)
+		if len(fe) > 0 {
+			var fp PluginPage
+			fp.Error = invalidForm
+			fp.Plugin = epii
+			fp.FormErrors = formErrors(r, fe)
+			h.executeAdminTemplate(w, r, adminEditPluginPage, &fp)
+			return
+		}
+		h.Log.Debug("Plugin update", *epii)
+		hd := h.getAdminHeader(r)
+		ae := h.auditBefore(r, auditPlugin, epii.ID, func() interface{} { return h.API.GetPlugin(epii.ID, hd) })
+		res := h.API.UpdatePlugin(epii, hd)
+		h.Log.Debug("Plugin update resp", *res)
+		if res.Success {
+			ae.updated(epii)
+			http.Redirect(w, r, adminPluginListView, http.StatusFound)
+		} else {
+			http.Redirect(w, r, adminPluginListViewFail, http.StatusFound)
 		}
 	}
 }
 
 //StoreAdminViewPluginList StoreAdminViewPluginList
 func (h *Six910Handler) StoreAdminViewPluginList(w http.ResponseWriter, r *http.Request) {
-	gpils, suc := h.getSession(r)
-	h.Log.Debug("session suc in plugin view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(gpils) {
-			hd := h.getHeader(gpils)
-			vpivars := mux.Vars(r)
-			ststr := vpivars["start"]
-			endstr := vpivars["end"]
-			vpistart, _ := strconv.ParseInt(ststr, 10, 64)
-			vpiend, _ := strconv.ParseInt(endstr, 10, 64)
-			pisl := h.API.GetPluginList(vpistart, vpiend, hd)
-			h.Log.Debug("Plugin  in list", pisl)
-			h.AdminTemplates.ExecuteTemplate(w, adminPluginListPage, &pisl)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	hd := h.getAdminHeader(r)
+	vpivars := mux.Vars(r)
+	ststr := vpivars["start"]
+	endstr := vpivars["end"]
+	vpistart, _ := strconv.ParseInt(ststr, 10, 64)
+	vpiend, _ := strconv.ParseInt(endstr, 10, 64)
+	pisl := h.API.GetPluginList(vpistart, vpiend, hd)
+	h.Log.Debug("Plugin  in list", pisl)
+	h.executeAdminTemplate(w, r, adminPluginListPage, &pisl)
 }
 
 //StoreAdminDeletePlugin StoreAdminDeletePlugin
 func (h *Six910Handler) StoreAdminDeletePlugin(w http.ResponseWriter, r *http.Request) {
 	if !h.OAuth2Enabled {
-		dpis, suc := h.getSession(r)
-		h.Log.Debug("session suc in plugin list delete", suc)
-		if suc {
-			if h.isStoreAdminLoggedIn(dpis) {
-				hd := h.getHeader(dpis)
-				dpivars := mux.Vars(r)
-				idstrd := dpivars["id"]
-				idddpi, _ := strconv.ParseInt(idstrd, 10, 64)
-				res := h.API.DeletePlugin(idddpi, hd)
-				h.Log.Debug("plugin delete resp", *res)
-				if res.Success {
-					http.Redirect(w, r, adminPluginListView, http.StatusFound)
-				} else {
-					http.Redirect(w, r, adminPluginListViewFail, http.StatusFound)
-				}
-			} else {
-				http.Redirect(w, r, adminloginPage, http.StatusFound)
-			}
+		hd := h.getAdminHeader(r)
+		dpivars := mux.Vars(r)
+		idstrd := dpivars["id"]
+		idddpi, _ := strconv.ParseInt(idstrd, 10, 64)
+		ae := h.auditBefore(r, auditPlugin, idddpi, func() interface{} { return h.API.GetPlugin(idddpi, hd) })
+		res := h.API.DeletePlugin(idddpi, hd)
+		h.Log.Debug("plugin delete resp", *res)
+		if res.Success {
+			ae.deleted()
+			http.Redirect(w, r, adminPluginListView, http.StatusFound)
+		} else {
+			http.Redirect(w, r, adminPluginListViewFail, http.StatusFound)
 		}
 	}
 }
 
-func (h *Six910Handler) processPlugin(r *http.Request) *sdbi.Plugins {
+func (h *Six910Handler) processPlugin(r *http.Request) (*sdbi.Plugins, FieldErrors) {
 	var i sdbi.Plugins
-	id := r.FormValue("id")
-	i.ID, _ = strconv.ParseInt(id, 10, 64)
+	f := newFormReader(r)
+	i.ID = f.int("id")
 	i.PluginName = r.FormValue("pluginName")
 	i.Developer = r.FormValue("developer")
 	i.ContactPhone = r.FormValue("contactPhone")
 	i.DeveloperAddress = r.FormValue("developerAddress")
-	fee := r.FormValue("fee")
-	i.Fee, _ = strconv.ParseFloat(fee, 64)
-	enabled := r.FormValue("enabled")
-	i.Enabled, _ = strconv.ParseBool(enabled)
+	i.Fee = f.float("fee")
+	i.Enabled = f.bool("enabled")
 	i.Category = r.FormValue("category")
 	i.ActivateURL = r.FormValue("activateUrl")
 	i.OauthRedirectURL = r.FormValue("oauthRedirectUrl")
-	isPgw := r.FormValue("isPgw")
-	i.IsPGW, _ = strconv.ParseBool(isPgw)
+	i.IsPGW = f.bool("isPgw")
 
-	return &i
+	return &i, f.check(validatePlugin(&i))
 }
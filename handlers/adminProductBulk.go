@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	six910api "github.com/Ulbora/Six910API-Go"
+	sdbi "github.com/Ulbora/six910-database-interface"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+//bulk product actions
+const (
+	bulkShow           = "show"
+	bulkHide           = "hide"
+	bulkSearchable     = "searchable"
+	bulkNotSearchable  = "notSearchable"
+	bulkPromote        = "promote"
+	bulkUnpromote      = "unpromote"
+	bulkAddCategory    = "addCategory"
+	bulkRemoveCategory = "removeCategory"
+	bulkPrice          = "price"
+	bulkSalePrice      = "salePrice"
+	bulkDelete         = "delete"
+)
+
+const (
+	bulkProductWorkers = 8
+	bulkProductMax     = dashboardMaxProducts
+)
+
+//ProductBulkFailure ProductBulkFailure is a product a bulk action could
+//not change
+type ProductBulkFailure struct {
+	ID    int64
+	Name  string
+	Error string
+}
+
+//ProductBulkResult ProductBulkResult is the summary of a bulk action.
+//Skipped products needed no change, such as hiding a hidden product.
+type ProductBulkResult struct {
+	Error    string
+	Action   string
+	Count    int
+	Done     int
+	Skipped  int
+	Failures []ProductBulkFailure
+	ListURL  string
+	FormErrors
+}
+
+//productBulk is a checked bulk action form
+type productBulk struct {
+	action     string
+	ids        []int64
+	categoryID int64
+	amount     float64
+	percent    bool
+}
+
+type bulkOutcome int
+
+const (
+	bulkDone bulkOutcome = iota
+	bulkSkipped
+	bulkFailed
+)
+
+func processProductBulk(r *http.Request) (*productBulk, FieldErrors) {
+	var b productBulk
+	f := newFormReader(r)
+	b.action = f.str("action")
+	b.categoryID = f.int("categoryId")
+	b.amount = f.float("amount")
+	b.percent = f.bool("percent")
+	var seen = map[int64]bool{}
+	for _, v := range r.Form["id"] {
+		id, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		if err != nil || id <= 0 {
+			f.fe.add("id", "must be product IDs")
+		} else if !seen[id] {
+			seen[id] = true
+			b.ids = append(b.ids, id)
+		}
+	}
+	var fe = FieldErrors{}
+	if len(b.ids) == 0 {
+		fe.add("id", "select at least one product")
+	} else if len(b.ids) > bulkProductMax {
+		fe.add("id", "select at most "+strconv.Itoa(bulkProductMax)+" products")
+	}
+	switch b.action {
+	case bulkShow, bulkHide, bulkSearchable, bulkNotSearchable, bulkPromote, bulkUnpromote, bulkDelete:
+	case bulkAddCategory, bulkRemoveCategory:
+		fe.requiredID("categoryId", b.categoryID)
+	case bulkPrice, bulkSalePrice:
+		if b.amount == 0 {
+			fe.add("amount", "is required")
+		} else if b.percent && b.amount < -100 {
+			fe.add("amount", "must not take off more than 100 percent")
+		}
+	default:
+		fe.add("action", "is not a bulk action")
+	}
+	return &b, f.check(fe)
+}
+
+//StoreAdminProductBulk StoreAdminProductBulk runs one action on the
+//products checked on the product list, several at a time, and shows how
+//each went
+func (h *Six910Handler) StoreAdminProductBulk(w http.ResponseWriter, r *http.Request) {
+	b, fe := processProductBulk(r)
+	var res ProductBulkResult
+	res.Action = b.action
+	res.ListURL = adminProductListView
+	if l := r.FormValue("list"); strings.HasPrefix(l, adminProductListView) {
+		res.ListURL = l
+	}
+	if len(fe) > 0 {
+		res.Error = invalidForm
+		res.FormErrors = formErrors(r, fe)
+		h.executeAdminTemplate(w, r, adminProductBulkResultPage, &res)
+		return
+	}
+	defer h.startJob()()
+	hd := h.getAdminHeader(r)
+	res.Count = len(b.ids)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	ids := make(chan int64)
+	for i := 0; i < bulkProductWorkers && i < len(b.ids); i++ {
+		wg.Add(1)
+		go func(header *six910api.Headers) {
+			defer wg.Done()
+			for id := range ids {
+				out, name, msg := h.productBulkOne(r, b, id, header)
+				mu.Lock()
+				switch out {
+				case bulkDone:
+					res.Done++
+				case bulkSkipped:
+					res.Skipped++
+				default:
+					res.Failures = append(res.Failures, ProductBulkFailure{ID: id, Name: name, Error: msg})
+				}
+				mu.Unlock()
+			}
+		}(hd)
+	}
+	for _, id := range b.ids {
+		ids <- id
+	}
+	close(ids)
+	wg.Wait()
+	sort.Slice(res.Failures, func(i, j int) bool {
+		return res.Failures[i].ID < res.Failures[j].ID
+	})
+
+	h.Log.Info("bulk ", b.action, " on ", res.Count, " products: ", res.Done, " done, ",
+		res.Skipped, " skipped, ", len(res.Failures), " failed")
+	h.executeAdminTemplate(w, r, adminProductBulkResultPage, &res)
+}
+
+//productBulkOne runs the action on one product and returns how it went,
+//the product name and why it failed
+func (h *Six910Handler) productBulkOne(r *http.Request, b *productBulk, id int64, hd *six910api.Headers) (bulkOutcome, string, string) {
+	p := h.API.GetProductByID(id, hd)
+	if p == nil || p.ID == 0 {
+		return bulkFailed, "", "product not found"
+	}
+	var res *six910api.Response
+	switch b.action {
+	case bulkDelete:
+		ae := h.auditBefore(r, auditProduct, id, func() interface{} { return p })
+		if res = h.API.DeleteProduct(id, hd); res != nil && res.Success {
+			ae.deleted()
+		}
+	case bulkAddCategory, bulkRemoveCategory:
+		var pc sdbi.ProductCategory
+		pc.ProductID = id
+		pc.CategoryID = b.categoryID
+		if b.action == bulkAddCategory {
+			if res = h.API.AddProductCategory(&pc, hd); res != nil && res.Success {
+				h.auditCreate(r, auditProductCategory, id, &pc)
+			}
+		} else {
+			ae := h.auditBefore(r, auditProductCategory, id, func() interface{} { return &pc })
+			if res = h.API.DeleteProductCategory(&pc, hd); res != nil && res.Success {
+				ae.deleted()
+			}
+		}
+	default:
+		np := *p
+		if msg := b.change(&np); msg != "" {
+			return bulkFailed, p.Name, msg
+		}
+		if np == *p {
+			return bulkSkipped, p.Name, ""
+		}
+		ae := h.auditBefore(r, auditProduct, id, func() interface{} { return p })
+		if res = h.API.UpdateProduct(&np, hd); res != nil && res.Success {
+			ae.updated(&np)
+		}
+	}
+	if res == nil || !res.Success {
+		msg := "backend call failed"
+		if res != nil && res.Message != "" {
+			msg = res.Message
+		}
+		return bulkFailed, p.Name, msg
+	}
+	return bulkDone, p.Name, ""
+}
+
+//change sets the fields of the action on p, or says why it can not.
+//A sale price is only changed on products that have one.
+func (b *productBulk) change(p *sdbi.Product) string {
+	var rtn string
+	switch b.action {
+	case bulkShow, bulkHide:
+		p.Visible = b.action == bulkShow
+	case bulkSearchable, bulkNotSearchable:
+		p.Searchable = b.action == bulkSearchable
+	case bulkPromote, bulkUnpromote:
+		p.Promoted = b.action == bulkPromote
+	case bulkPrice:
+		p.Price = b.newPrice(p.Price)
+		if p.Price < 0 {
+			rtn = "price would be below zero"
+		}
+	case bulkSalePrice:
+		if p.SalePrice > 0 {
+			p.SalePrice = b.newPrice(p.SalePrice)
+			if p.SalePrice < 0 {
+				rtn = "sale price would be below zero"
+			}
+		}
+	}
+	return rtn
+}
+
+func (b *productBulk) newPrice(old float64) float64 {
+	if b.percent {
+		return roundCents(old * (1 + b.amount/100))
+	}
+	return roundCents(old + b.amount)
+}
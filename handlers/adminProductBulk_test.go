@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	audit "github.com/Ulbora/Six910-ui/auditsrv"
+	api "github.com/Ulbora/Six910API-Go"
+	sdbi "github.com/Ulbora/six910-database-interface"
+)
+
+const productBulkTestPage = `{{define "productBulkResults.html"}}{{.Error}}{{range $k, $v := .Fields}}[{{$k}}: {{$v}}]{{end}}` +
+	`|{{.Action}} {{.Count}} {{.Done}} {{.Skipped}}{{range .Failures}}[{{.ID}} {{.Name}} {{.Error}}]{{end}}|{{.ListURL}}{{end}}`
+
+func productBulkTestRequest(form string) *http.Request {
+	r, _ := http.NewRequest("POST", "/admin/productBulk", strings.NewReader(form))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+func TestSix910Handler_StoreAdminProductBulkInvalid(t *testing.T) {
+	sh, _, dir := testHandler(t)
+	defer os.RemoveAll(dir)
+	sh.AdminTemplates = template.Must(template.New("admin").Parse(productBulkTestPage))
+
+	r := productBulkTestRequest("action=paint&id=x&list=https://evil.com")
+	w := serveAsAdmin(sh, sh.StoreAdminProductBulk, r)
+	fmt.Println("body: ", w.Body.String())
+	if w.Code != 200 || w.Body.String() != invalidForm+"[action: is not a bulk action][id: must be product IDs]|paint 0 0 0|/admin/productListView" {
+		t.Fail()
+	}
+
+	r = productBulkTestRequest("action=price&id=4&amount=-120&percent=on")
+	w = serveAsAdmin(sh, sh.StoreAdminProductBulk, r)
+	fmt.Println("body: ", w.Body.String())
+	if w.Body.String() != invalidForm+"[amount: must not take off more than 100 percent]|price 0 0 0|/admin/productListView" {
+		t.Fail()
+	}
+
+	r = productBulkTestRequest("action=addCategory&id=4")
+	w = serveAsAdmin(sh, sh.StoreAdminProductBulk, r)
+	fmt.Println("body: ", w.Body.String())
+	if w.Body.String() != invalidForm+"[categoryId: is required]|addCategory 0 0 0|/admin/productListView" {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAdminProductBulkHide(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	defer os.RemoveAll(dir)
+	sh.AdminTemplates = template.Must(template.New("admin").Parse(productBulkTestPage))
+	sapi.MockProduct = &sdbi.Product{ID: 4, Name: "hat", Visible: true}
+	sapi.MockUpdateProductResp = &api.Response{Success: true}
+
+	r := productBulkTestRequest("action=hide&id=4&id=5&id=6&id=4&list=/admin/productListView?page=2")
+	w := serveAsAdmin(sh, sh.StoreAdminProductBulk, r)
+	fmt.Println("body: ", w.Body.String())
+	if w.Code != 200 || w.Body.String() != "|hide 3 3 0|/admin/productListView?page=2" {
+		t.Fail()
+	}
+	ents := sh.AuditService.Search(&audit.Filter{})
+	if len(*ents) != 3 || (*ents)[0].Changes[0].Field != "visible" {
+		fmt.Println("audit: ", ents)
+		t.Fail()
+	}
+
+	sapi.MockProduct.Visible = false
+	w = serveAsAdmin(sh, sh.StoreAdminProductBulk, productBulkTestRequest("action=hide&id=4&id=5"))
+	fmt.Println("body: ", w.Body.String())
+	if w.Body.String() != "|hide 2 0 2|/admin/productListView" {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAdminProductBulkFailures(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	defer os.RemoveAll(dir)
+	sh.AdminTemplates = template.Must(template.New("admin").Parse(productBulkTestPage))
+	sapi.MockProduct = &sdbi.Product{ID: 4, Name: "hat", Price: 10}
+	sapi.MockUpdateProductResp = &api.Response{Message: "locked"}
+
+	w := serveAsAdmin(sh, sh.StoreAdminProductBulk, productBulkTestRequest("action=price&id=5&id=4&amount=10&percent=on"))
+	fmt.Println("body: ", w.Body.String())
+	if w.Body.String() != "|price 2 0 0[4 hat locked][5 hat locked]|/admin/productListView" {
+		t.Fail()
+	}
+
+	w = serveAsAdmin(sh, sh.StoreAdminProductBulk, productBulkTestRequest("action=price&id=4&amount=-12.5"))
+	fmt.Println("body: ", w.Body.String())
+	if w.Body.String() != "|price 1 0 0[4 hat price would be below zero]|/admin/productListView" {
+		t.Fail()
+	}
+
+	w = serveAsAdmin(sh, sh.StoreAdminProductBulk, productBulkTestRequest("action=salePrice&id=4&amount=-1"))
+	fmt.Println("body: ", w.Body.String())
+	if w.Body.String() != "|salePrice 1 0 1|/admin/productListView" {
+		t.Fail()
+	}
+
+	w = serveAsAdmin(sh, sh.StoreAdminProductBulk, productBulkTestRequest("action=removeCategory&categoryId=3&id=4"))
+	fmt.Println("body: ", w.Body.String())
+	if w.Body.String() != "|removeCategory 1 0 0[4 hat backend call failed]|/admin/productListView" {
+		t.Fail()
+	}
+
+	sapi.MockProduct = nil
+	w = serveAsAdmin(sh, sh.StoreAdminProductBulk, productBulkTestRequest("action=delete&id=4"))
+	fmt.Println("body: ", w.Body.String())
+	if w.Body.String() != "|delete 1 0 0[4  product not found]|/admin/productListView" {
+		t.Fail()
+	}
+	ents := sh.AuditService.Search(&audit.Filter{})
+	if len(*ents) != 0 {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAdminProductBulkCategoryAndDelete(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	defer os.RemoveAll(dir)
+	sh.AdminTemplates = template.Must(template.New("admin").Parse(productBulkTestPage))
+	sapi.MockProduct = &sdbi.Product{ID: 4, Name: "hat"}
+	sapi.MockAddProductCategoryResp = &api.Response{Success: true}
+	sapi.MockDeleteProductResp = &api.Response{Success: true}
+
+	w := serveAsAdmin(sh, sh.StoreAdminProductBulk, productBulkTestRequest("action=addCategory&categoryId=3&id=4&id=5"))
+	fmt.Println("body: ", w.Body.String())
+	if w.Body.String() != "|addCategory 2 2 0|/admin/productListView" {
+		t.Fail()
+	}
+	w = serveAsAdmin(sh, sh.StoreAdminProductBulk, productBulkTestRequest("action=delete&id=4"))
+	fmt.Println("body: ", w.Body.String())
+	if w.Body.String() != "|delete 1 1 0|/admin/productListView" {
+		t.Fail()
+	}
+	ents := sh.AuditService.Search(&audit.Filter{Entity: auditProductCategory})
+	if len(*ents) != 2 {
+		t.Fail()
+	}
+}
+
+func TestProductBulk_change(t *testing.T) {
+	var b productBulk
+	b.action = bulkPrice
+	b.amount = -15
+	b.percent = true
+	p := sdbi.Product{Price: 19.99, SalePrice: 15}
+	if msg := b.change(&p); msg != "" || p.Price != 16.99 || p.SalePrice != 15 {
+		fmt.Println("price: ", p.Price)
+		t.Fail()
+	}
+	b.action = bulkSalePrice
+	b.amount = 2.5
+	b.percent = false
+	if msg := b.change(&p); msg != "" || p.SalePrice != 17.5 || p.Price != 16.99 {
+		t.Fail()
+	}
+	b.action = bulkPromote
+	if b.change(&p); !p.Promoted {
+		t.Fail()
+	}
+}
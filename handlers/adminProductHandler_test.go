@@ -35,7 +35,7 @@ func TestSix910Handler_StoreAdminAddProductPage(t *testing.T) {
 	//s.Values["loggedIn"] = true
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddProductPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddProductPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -59,11 +59,11 @@ func TestSix910Handler_StoreAdminAddProductPageLoggedIn(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddProductPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddProductPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -105,13 +105,11 @@ func TestSix910Handler_StoreAdminAddProduct(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddProduct(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddProduct)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -154,11 +152,9 @@ func TestSix910Handler_StoreAdminAddProductNotLoggedIn(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddProduct(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddProduct)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -195,17 +191,15 @@ func TestSix910Handler_StoreAdminAddProductFail(t *testing.T) {
 
 	//-----------end mocking --------
 
-	r, _ := http.NewRequest("POST", "https://test.com", nil)
+	r, _ := http.NewRequest("POST", "https://test.com?sku=abc123&name=hat", nil)
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddProduct(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddProduct)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -251,13 +245,11 @@ func TestSix910Handler_StoreAdminEditProductPage(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditProductPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditProductPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -304,11 +296,9 @@ func TestSix910Handler_StoreAdminEditProductPageNotLoggedIn(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditProductPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditProductPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -349,13 +339,11 @@ func TestSix910Handler_StoreAdminEditProduct(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditProduct(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditProduct)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -398,11 +386,9 @@ func TestSix910Handler_StoreAdminEditProductNotLoggedIn(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditProduct(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditProduct)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -443,13 +429,11 @@ func TestSix910Handler_StoreAdminEditProductFail(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditProduct(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditProduct)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -498,13 +482,11 @@ func TestSix910Handler_StoreAdminViewProductList(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminViewProductList(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminViewProductList)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -555,11 +537,9 @@ func TestSix910Handler_StoreAdminViewProductListNotLoggedIn(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminViewProductList(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminViewProductList)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -603,13 +583,11 @@ func TestSix910Handler_StoreAdminDeleteProduct(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminDeleteProduct(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminDeleteProduct)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -655,11 +633,9 @@ func TestSix910Handler_StoreAdminDeleteProductLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminDeleteProduct(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminDeleteProduct)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -703,13 +679,11 @@ func TestSix910Handler_StoreAdminDeleteProductFail(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminDeleteProduct(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminDeleteProduct)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	six910api "github.com/Ulbora/Six910API-Go"
+	sdbi "github.com/Ulbora/six910-database-interface"
+	"github.com/gorilla/mux"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+const (
+	productListPageSize    = 25
+	productListMaxPageSize = 100
+)
+
+//ProductListPageSizes ProductListPageSizes are the sizes offered on the
+//product list
+var ProductListPageSizes = []int{10, 25, 50, 100}
+
+//ProductListFilter ProductListFilter is the product list as asked for in
+//the query string. Visible is "yes" or "no", Stock is "out", "low" or
+//"in" and Sort is "name", "price" or "stock"; empty means any.
+type ProductListFilter struct {
+	Name          string
+	CategoryID    int64
+	DistributorID int64
+	Visible       string
+	Stock         string
+	Sort          string
+	Desc          bool
+	Page          int
+	PageSize      int
+}
+
+//productListFilter reads the filter from the query string. Old links with
+//{start} and {end} in the path are turned into a page.
+func productListFilter(r *http.Request) *ProductListFilter {
+	var f ProductListFilter
+	q := r.URL.Query()
+	f.Name = strings.TrimSpace(q.Get("name"))
+	f.CategoryID, _ = strconv.ParseInt(q.Get("categoryId"), 10, 64)
+	f.DistributorID, _ = strconv.ParseInt(q.Get("distributorId"), 10, 64)
+	if v := q.Get("visible"); v == "yes" || v == "no" {
+		f.Visible = v
+	}
+	if v := q.Get("stock"); v == "out" || v == "low" || v == "in" {
+		f.Stock = v
+	}
+	if v := q.Get("sort"); v == "name" || v == "price" || v == "stock" {
+		f.Sort = v
+		f.Desc = q.Get("dir") == "desc"
+	}
+	f.Page, _ = strconv.Atoi(q.Get("page"))
+	f.PageSize, _ = strconv.Atoi(q.Get("pageSize"))
+	vars := mux.Vars(r)
+	if st, err := strconv.Atoi(vars["start"]); err == nil && q.Get("page") == "" {
+		if end, eerr := strconv.Atoi(vars["end"]); eerr == nil && end > st && st >= 0 {
+			f.PageSize = end - st
+			if f.PageSize > productListMaxPageSize {
+				f.PageSize = productListMaxPageSize
+			}
+			f.Page = st/f.PageSize + 1
+		}
+	}
+	if f.PageSize < 1 || f.PageSize > productListMaxPageSize {
+		f.PageSize = productListPageSize
+	}
+	if f.Page < 1 {
+		f.Page = 1
+	}
+	if f.CategoryID < 0 {
+		f.CategoryID = 0
+	}
+	if f.DistributorID < 0 {
+		f.DistributorID = 0
+	}
+	return &f
+}
+
+func (f *ProductListFilter) query() url.Values {
+	var q = url.Values{}
+	if f.Name != "" {
+		q.Set("name", f.Name)
+	}
+	if f.CategoryID != 0 {
+		q.Set("categoryId", strconv.FormatInt(f.CategoryID, 10))
+	}
+	if f.DistributorID != 0 {
+		q.Set("distributorId", strconv.FormatInt(f.DistributorID, 10))
+	}
+	if f.Visible != "" {
+		q.Set("visible", f.Visible)
+	}
+	if f.Stock != "" {
+		q.Set("stock", f.Stock)
+	}
+	if f.Sort != "" {
+		q.Set("sort", f.Sort)
+		if f.Desc {
+			q.Set("dir", "desc")
+		}
+	}
+	if f.PageSize != productListPageSize {
+		q.Set("pageSize", strconv.Itoa(f.PageSize))
+	}
+	if f.Page > 1 {
+		q.Set("page", strconv.Itoa(f.Page))
+	}
+	return q
+}
+
+func (f ProductListFilter) url() string {
+	if q := f.query().Encode(); q != "" {
+		return adminProductListView + "?" + q
+	}
+	return adminProductListView
+}
+
+//PageURL PageURL links to another page of the same list
+func (f ProductListFilter) PageURL(page int) string {
+	f.Page = page
+	return f.url()
+}
+
+//SizeURL SizeURL links to the first page with another page size
+func (f ProductListFilter) SizeURL(size int) string {
+	f.Page = 1
+	f.PageSize = size
+	return f.url()
+}
+
+//SortURL SortURL sorts by field from the first page, turning the order
+//around when the list is already sorted by field
+func (f ProductListFilter) SortURL(field string) string {
+	f.Desc = f.Sort == field && !f.Desc
+	f.Sort = field
+	f.Page = 1
+	return f.url()
+}
+
+//scans tells if the list has to be read and sorted here; the backend can
+//only page all products, or products by name or by category
+func (f *ProductListFilter) scans() bool {
+	return (f.Name != "" && f.CategoryID != 0) || f.DistributorID != 0 || f.Visible != "" ||
+		f.Stock != "" || f.Sort != ""
+}
+
+func (f *ProductListFilter) match(p *sdbi.Product) bool {
+	var rtn = true
+	switch {
+	case f.Name != "" && f.CategoryID != 0 && !strings.Contains(strings.ToLower(p.Name), strings.ToLower(f.Name)):
+		rtn = false
+	case f.DistributorID != 0 && p.DistributorID != f.DistributorID:
+		rtn = false
+	case f.Visible != "" && p.Visible != (f.Visible == "yes"):
+		rtn = false
+	case f.Stock == "out" && p.Stock > 0:
+		rtn = false
+	case f.Stock == "low" && (p.Stock <= 0 || p.Stock > p.StockAlert):
+		rtn = false
+	case f.Stock == "in" && (p.Stock <= 0 || p.Stock <= p.StockAlert):
+		rtn = false
+	}
+	return rtn
+}
+
+func (f *ProductListFilter) less(a *sdbi.Product, b *sdbi.Product) bool {
+	var rtn bool
+	switch f.Sort {
+	case "name":
+		rtn = strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		if f.Desc {
+			rtn = strings.ToLower(a.Name) > strings.ToLower(b.Name)
+		}
+	case "price":
+		rtn = a.Price < b.Price
+		if f.Desc {
+			rtn = a.Price > b.Price
+		}
+	case "stock":
+		rtn = a.Stock < b.Stock
+		if f.Desc {
+			rtn = a.Stock > b.Stock
+		}
+	}
+	return rtn
+}
+
+//productList is one page of products. Total is -1 when the backend pages
+//the list and Truncated is set when a scan stopped at
+//dashboardMaxProducts.
+type productList struct {
+	products  []sdbi.Product
+	more      bool
+	total     int
+	truncated bool
+}
+
+//productListPage reads the page asked for. A list the backend can page
+//is read one item past the page to learn if there is more; a filtered or
+//sorted one is scanned like the dashboard.
+func (h *Six910Handler) productListPage(f *ProductListFilter, hd *six910api.Headers) *productList {
+	get := func(st int64, end int64) *[]sdbi.Product {
+		if f.CategoryID != 0 {
+			return h.API.GetProductsByCaterory(f.CategoryID, st, end, hd)
+		} else if f.Name != "" {
+			return h.API.GetProductsByName(url.PathEscape(f.Name), st, end, hd)
+		}
+		return h.API.GetProductList(st, end, hd)
+	}
+	var rtn productList
+	st := (f.Page - 1) * f.PageSize
+	if !f.scans() {
+		rtn.total = -1
+		if pl := get(int64(st), int64(st+f.PageSize+1)); pl != nil {
+			rtn.products = *pl
+		}
+		if len(rtn.products) > f.PageSize {
+			rtn.products = rtn.products[:f.PageSize]
+			rtn.more = true
+		}
+		return &rtn
+	}
+	var all []sdbi.Product
+	for pos := int64(0); ; pos += dashboardPageSize {
+		if pos >= dashboardMaxProducts {
+			rtn.truncated = true
+			break
+		}
+		pl := get(pos, pos+dashboardPageSize)
+		if pl == nil {
+			break
+		}
+		for i := range *pl {
+			if f.match(&(*pl)[i]) {
+				all = append(all, (*pl)[i])
+			}
+		}
+		if len(*pl) < dashboardPageSize {
+			break
+		}
+	}
+	if f.Sort != "" {
+		sort.SliceStable(all, func(i, j int) bool {
+			return f.less(&all[i], &all[j])
+		})
+	}
+	rtn.total = len(all)
+	if st < len(all) {
+		end := st + f.PageSize
+		if end > len(all) {
+			end = len(all)
+		}
+		rtn.products = all[st:end]
+		rtn.more = end < len(all)
+	}
+	return &rtn
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"testing"
+
+	sdbi "github.com/Ulbora/six910-database-interface"
+	"github.com/gorilla/mux"
+)
+
+const productListTestPage = `{{define "productList.html"}}{{range .Products}}[{{.Name}}]{{end}}` +
+	`|{{.Total}}|{{.PrevURL}}|{{.NextURL}}|{{if .Categories}}{{len .Categories}}{{else}}0{{end}}|{{.Filter.SortURL "price"}}{{end}}`
+
+func productListTestProducts() *[]sdbi.Product {
+	var pl []sdbi.Product
+	pl = append(pl, sdbi.Product{ID: 1, Name: "Boots", Price: 40, Stock: 0, StockAlert: 5, Visible: true, DistributorID: 2})
+	pl = append(pl, sdbi.Product{ID: 2, Name: "apron", Price: 12.5, Stock: 3, StockAlert: 5, Visible: true, DistributorID: 2})
+	pl = append(pl, sdbi.Product{ID: 3, Name: "Cap", Price: 18, Stock: 30, StockAlert: 5, Visible: false, DistributorID: 4})
+	pl = append(pl, sdbi.Product{ID: 4, Name: "belt", Price: 25, Stock: 2, StockAlert: 5, Visible: true, DistributorID: 4})
+	return &pl
+}
+
+func TestProductListFilter(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/admin/productListView?name=red+hat&categoryId=3&visible=maybe&stock=low&sort=price&dir=desc&page=2&pageSize=500", nil)
+	f := productListFilter(r)
+	fmt.Println("filter: ", *f)
+	if f.Name != "red hat" || f.CategoryID != 3 || f.Visible != "" || f.Stock != "low" ||
+		f.Sort != "price" || !f.Desc || f.Page != 2 || f.PageSize != productListPageSize {
+		t.Fail()
+	}
+	if f.PageURL(3) != "/admin/productListView?categoryId=3&dir=desc&name=red+hat&page=3&sort=price&stock=low" {
+		fmt.Println("page url: ", f.PageURL(3))
+		t.Fail()
+	}
+	if f.SortURL("price") != "/admin/productListView?categoryId=3&name=red+hat&sort=price&stock=low" {
+		fmt.Println("sort url: ", f.SortURL("price"))
+		t.Fail()
+	}
+	if f.SortURL("name") != "/admin/productListView?categoryId=3&name=red+hat&sort=name&stock=low" {
+		fmt.Println("sort url: ", f.SortURL("name"))
+		t.Fail()
+	}
+	if f.SizeURL(10) != "/admin/productListView?categoryId=3&dir=desc&name=red+hat&pageSize=10&sort=price&stock=low" {
+		fmt.Println("size url: ", f.SizeURL(10))
+		t.Fail()
+	}
+
+	r, _ = http.NewRequest("GET", "/admin/productListView/100/150", nil)
+	r = mux.SetURLVars(r, map[string]string{"start": "100", "end": "150"})
+	f = productListFilter(r)
+	if f.Page != 3 || f.PageSize != 50 || f.PageURL(1) != "/admin/productListView?pageSize=50" {
+		fmt.Println("legacy filter: ", *f)
+		t.Fail()
+	}
+
+	r, _ = http.NewRequest("GET", "/admin/productListView", nil)
+	f = productListFilter(r)
+	if f.Page != 1 || f.PageSize != productListPageSize || f.PageURL(1) != adminProductListView {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAdminViewProductListPaged(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	defer os.RemoveAll(dir)
+	sh.AdminTemplates = template.Must(template.New("admin").Parse(productListTestPage))
+	sapi.MockProductList = productListTestProducts()
+	var cl []sdbi.Category
+	cl = append(cl, sdbi.Category{ID: 3, Name: "hats"})
+	sapi.MockCategoryList = &cl
+
+	// the mock returns every product, so the page size cuts it and shows there is more
+	r, _ := http.NewRequest("GET", "/admin/productListView?pageSize=3&page=2", nil)
+	w := serveAsAdmin(sh, sh.StoreAdminViewProductList, r)
+	fmt.Println("body: ", w.Body.String())
+	if w.Code != 200 || w.Body.String() != "[Boots][apron][Cap]|-1|/admin/productListView?pageSize=3"+
+		"|/admin/productListView?page=3&amp;pageSize=3|1|/admin/productListView?pageSize=3&amp;sort=price" {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAdminViewProductListFiltered(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	defer os.RemoveAll(dir)
+	sh.AdminTemplates = template.Must(template.New("admin").Parse(productListTestPage))
+	sapi.MockProductList = productListTestProducts()
+
+	r, _ := http.NewRequest("GET", "/admin/productListView?visible=yes&stock=low&sort=price&dir=desc", nil)
+	w := serveAsAdmin(sh, sh.StoreAdminViewProductList, r)
+	fmt.Println("body: ", w.Body.String())
+	if w.Code != 200 || w.Body.String() != "[belt][apron]|2|||0|/admin/productListView?sort=price&amp;stock=low&amp;visible=yes" {
+		t.Fail()
+	}
+
+	r, _ = http.NewRequest("GET", "/admin/productListView?distributorId=4&sort=name&pageSize=1", nil)
+	w = serveAsAdmin(sh, sh.StoreAdminViewProductList, r)
+	fmt.Println("body: ", w.Body.String())
+	if w.Code != 200 || w.Body.String() != "[belt]|2||/admin/productListView?distributorId=4&amp;page=2&amp;pageSize=1&amp;sort=name"+
+		"|0|/admin/productListView?distributorId=4&amp;pageSize=1&amp;sort=price" {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAdminViewProductListCategory(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	defer os.RemoveAll(dir)
+	sh.AdminTemplates = template.Must(template.New("admin").Parse(productListTestPage))
+	sapi.MockProductList = productListTestProducts()
+	sapi.MockProductCategoryList = productListTestProducts()
+	sapi.MockProductNameList = &[]sdbi.Product{}
+
+	r, _ := http.NewRequest("GET", "/admin/productListView?categoryId=7&name=B", nil)
+	w := serveAsAdmin(sh, sh.StoreAdminViewProductList, r)
+	fmt.Println("body: ", w.Body.String())
+	if w.Code != 200 || w.Body.String() != "[Boots][belt]|2|||0|/admin/productListView?categoryId=7&amp;name=B&amp;sort=price" {
+		t.Fail()
+	}
+
+	r, _ = http.NewRequest("GET", "/admin/productListView?name=none", nil)
+	w = serveAsAdmin(sh, sh.StoreAdminViewProductList, r)
+	fmt.Println("body: ", w.Body.String())
+	if w.Code != 200 || w.Body.String() != "|-1|||0|/admin/productListView?name=none&amp;sort=price" {
+		t.Fail()
+	}
+}
@@ -30,137 +30,110 @@ import (
 type RegionPage struct {
 	Error  string
 	Region *sdbi.Region
+	FormErrors
 }
 
 //StoreAdminAddRegionPage StoreAdminAddRegionPage
 func (h *Six910Handler) StoreAdminAddRegionPage(w http.ResponseWriter, r *http.Request) {
-	asrs, suc := h.getSession(r)
-	h.Log.Debug("session suc in region add view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(asrs) {
-			asrErr := r.URL.Query().Get("error")
-			var asrpg RegionPage
-			asrpg.Error = asrErr
-			h.AdminTemplates.ExecuteTemplate(w, adminAddRegionPage, &asrpg)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	asrErr := r.URL.Query().Get("error")
+	var asrpg RegionPage
+	asrpg.Error = asrErr
+	h.executeAdminTemplate(w, r, adminAddRegionPage, &asrpg)
 }
 
 //StoreAdminAddRegion StoreAdminAddRegion
 func (h *Six910Handler) StoreAdminAddRegion(w http.ResponseWriter, r *http.Request) {
-	adsrs, suc := h.getSession(r)
-	h.Log.Debug("session suc in Region add", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(adsrs) {
-			asr := h.processRegion(r)
-			h.Log.Debug("Region add", *asr)
-			hd := h.getHeader(adsrs)
-			srres := h.API.AddRegion(asr, hd)
-			h.Log.Debug("Region add resp", *srres)
-			if srres.Success {
-				http.Redirect(w, r, adminRegionListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminRegionListViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	asr, fe := h.processRegion(r)
+	if len(fe) > 0 {
+		var fp RegionPage
+		fp.Error = invalidForm
+		fp.Region = asr
+		fp.FormErrors = formErrors(r, fe)
+		h.executeAdminTemplate(w, r, adminAddRegionPage, &fp)
+		return
+	}
+	h.Log.Debug("Region add", *asr)
+	hd := h.getAdminHeader(r)
+	srres := h.API.AddRegion(asr, hd)
+	h.Log.Debug("Region add resp", *srres)
+	if srres.Success {
+		h.auditCreate(r, auditRegion, srres.ID, asr)
+		http.Redirect(w, r, adminRegionListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminRegionListViewFail, http.StatusFound)
 	}
 }
 
 //StoreAdminEditRegionPage StoreAdminEditRegionPage
 func (h *Six910Handler) StoreAdminEditRegionPage(w http.ResponseWriter, r *http.Request) {
-	esrs, suc := h.getSession(r)
-	h.Log.Debug("session suc in Region edit view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(esrs) {
-			hd := h.getHeader(esrs)
-			eipErr := r.URL.Query().Get("error")
-			esrvars := mux.Vars(r)
-			idsrstr := esrvars["id"]
-			iID, _ := strconv.ParseInt(idsrstr, 10, 64)
-			h.Log.Debug("Region id in edit", iID)
-			var srp RegionPage
-			srp.Error = eipErr
-			srp.Region = h.API.GetRegion(iID, hd)
-			h.AdminTemplates.ExecuteTemplate(w, adminEditShippingMethodPage, &srp)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	hd := h.getAdminHeader(r)
+	eipErr := r.URL.Query().Get("error")
+	esrvars := mux.Vars(r)
+	idsrstr := esrvars["id"]
+	iID, _ := strconv.ParseInt(idsrstr, 10, 64)
+	h.Log.Debug("Region id in edit", iID)
+	var srp RegionPage
+	srp.Error = eipErr
+	srp.Region = h.API.GetRegion(iID, hd)
+	h.executeAdminTemplate(w, r, adminEditRegionPage, &srp)
 }
 
 //StoreAdminEditRegion StoreAdminEditRegion
 func (h *Six910Handler) StoreAdminEditRegion(w http.ResponseWriter, r *http.Request) {
-	esrs, suc := h.getSession(r)
-	h.Log.Debug("session suc in Region edit", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(esrs) {
-			esr := h.processRegion(r)
-			h.Log.Debug("Region update", *esr)
-			hd := h.getHeader(esrs)
-			res := h.API.UpdateRegion(esr, hd)
-			h.Log.Debug("Region update resp", *res)
-			if res.Success {
-				http.Redirect(w, r, adminRegionListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminEditRegionViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	esr, fe := h.processRegion(r)
+	if len(fe) > 0 {
+		var fp RegionPage
+		fp.Error = invalidForm
+		fp.Region = esr
+		fp.FormErrors = formErrors(r, fe)
+		h.executeAdminTemplate(w, r, adminEditRegionPage, &fp)
+		return
+	}
+	h.Log.Debug("Region update", *esr)
+	hd := h.getAdminHeader(r)
+	ae := h.auditBefore(r, auditRegion, esr.ID, func() interface{} { return h.API.GetRegion(esr.ID, hd) })
+	res := h.API.UpdateRegion(esr, hd)
+	h.Log.Debug("Region update resp", *res)
+	if res.Success {
+		ae.updated(esr)
+		http.Redirect(w, r, adminRegionListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminEditRegionViewFail, http.StatusFound)
 	}
 }
 
 //StoreAdminViewRegionList StoreAdminViewRegionList
 func (h *Six910Handler) StoreAdminViewRegionList(w http.ResponseWriter, r *http.Request) {
-	gsrls, suc := h.getSession(r)
-	h.Log.Debug("session suc in Region view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(gsrls) {
-			hd := h.getHeader(gsrls)
-			srsl := h.API.GetRegionList(hd)
-			h.Log.Debug("Region  in list", srsl)
-			h.AdminTemplates.ExecuteTemplate(w, adminRegionListPage, &srsl)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	hd := h.getAdminHeader(r)
+	srsl := h.API.GetRegionList(hd)
+	h.Log.Debug("Region  in list", srsl)
+	h.executeAdminTemplate(w, r, adminRegionListPage, &srsl)
 }
 
 //StoreAdminDeleteRegion StoreAdminDeleteRegion
 func (h *Six910Handler) StoreAdminDeleteRegion(w http.ResponseWriter, r *http.Request) {
-	dsrs, suc := h.getSession(r)
-	h.Log.Debug("session suc in Region list delete", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(dsrs) {
-			hd := h.getHeader(dsrs)
-			dsrvars := mux.Vars(r)
-			idsrstrd := dsrvars["id"]
-			idddsr, _ := strconv.ParseInt(idsrstrd, 10, 64)
-			res := h.API.DeleteRegion(idddsr, hd)
-			h.Log.Debug("Region delete resp", *res)
-			if res.Success {
-				http.Redirect(w, r, adminRegionListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminRegionListViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	hd := h.getAdminHeader(r)
+	dsrvars := mux.Vars(r)
+	idsrstrd := dsrvars["id"]
+	idddsr, _ := strconv.ParseInt(idsrstrd, 10, 64)
+	ae := h.auditBefore(r, auditRegion, idddsr, func() interface{} { return h.API.GetRegion(idddsr, hd) })
+	res := h.API.DeleteRegion(idddsr, hd)
+	h.Log.Debug("Region delete resp", *res)
+	if res.Success {
+		ae.deleted()
+		http.Redirect(w, r, adminRegionListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminRegionListViewFail, http.StatusFound)
 	}
 }
 
-func (h *Six910Handler) processRegion(r *http.Request) *sdbi.Region {
+func (h *Six910Handler) processRegion(r *http.Request) (*sdbi.Region, FieldErrors) {
 	var i sdbi.Region
-	id := r.FormValue("id")
-	i.ID, _ = strconv.ParseInt(id, 10, 64)
+	f := newFormReader(r)
+	i.ID = f.int("id")
 	i.RegionCode = r.FormValue("regionCode")
 	i.Name = r.FormValue("name")
-	storeID := r.FormValue("storeId")
-	i.StoreID, _ = strconv.ParseInt(storeID, 10, 64)
+	i.StoreID = f.int("storeId")
 
-	return &i
+	return &i, f.check(validateRegion(&i))
 }
@@ -0,0 +1,339 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	perms "github.com/Ulbora/Six910-ui/permsrv"
+	api "github.com/Ulbora/Six910API-Go"
+	sdbi "github.com/Ulbora/six910-database-interface"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+//search kinds
+const (
+	searchOrderNumber = "orderNumber"
+	searchEmail       = "email"
+	searchGtin        = "gtin"
+	searchNumber      = "number"
+	searchText        = "text"
+
+	searchLimit = 50
+)
+
+//SearchHit SearchHit is one result linking to its edit page
+type SearchHit struct {
+	ID     int64
+	Title  string
+	Detail string
+	Link   string
+}
+
+//SearchGroup SearchGroup
+type SearchGroup struct {
+	Name string
+	Hits []SearchHit
+}
+
+//SearchPage SearchPage
+type SearchPage struct {
+	Query  string
+	Kind   string
+	Groups []SearchGroup
+	Count  int
+}
+
+//searchResult is what one lookup found
+type searchResult struct {
+	orders    []sdbi.Order
+	customers []sdbi.Customer
+	products  []sdbi.Product
+}
+
+type searchTask func(hd *api.Headers) *searchResult
+
+//StoreAdminSearch StoreAdminSearch looks q up as an order number, email,
+//order ID, SKU, GTIN or product name depending on what it looks like. The
+//lookups run at once and only areas the admin can view are searched.
+func (h *Six910Handler) StoreAdminSearch(w http.ResponseWriter, r *http.Request) {
+	sp := h.adminSearch(r, strings.TrimSpace(r.URL.Query().Get("q")))
+	h.Log.Debug("admin search: ", sp.Query, " kind: ", sp.Kind, " found: ", sp.Count)
+	h.executeAdminTemplate(w, r, adminSearchPage, sp)
+}
+
+func (h *Six910Handler) adminSearch(r *http.Request, q string) *SearchPage {
+	var sp SearchPage
+	sp.Query = q
+	if sp.Query != "" {
+		sp.Kind = searchKind(sp.Query)
+		canOrders := h.adminCanRead(r, perms.Orders)
+		canCustomers := h.adminCanRead(r, perms.Customers)
+		canProducts := h.adminCanRead(r, perms.Products)
+		var tasks []searchTask
+		switch sp.Kind {
+		case searchOrderNumber:
+			if canOrders {
+				tasks = append(tasks, h.searchOrderNumber(sp.Query))
+			}
+		case searchEmail:
+			if canCustomers {
+				tasks = append(tasks, h.searchCustomer(sp.Query))
+			}
+			if canOrders {
+				tasks = append(tasks, h.searchCustomerOrders(sp.Query))
+			}
+		case searchGtin, searchNumber:
+			if canOrders {
+				tasks = append(tasks, h.searchOrderID(sp.Query))
+			}
+			if canProducts {
+				tasks = append(tasks, h.searchSku(sp.Query))
+			}
+			if canProducts && sp.Kind == searchGtin {
+				tasks = append(tasks, h.searchGtin(sp.Query))
+			}
+		default:
+			if canProducts {
+				tasks = append(tasks, h.searchProductName(sp.Query))
+			}
+			if canProducts && !strings.Contains(sp.Query, " ") {
+				tasks = append(tasks, h.searchSku(sp.Query))
+			}
+		}
+		res := runSearch(tasks, h.getAdminHeader(r))
+		sp.Groups = searchGroups(res)
+		for _, g := range sp.Groups {
+			sp.Count += len(g.Hits)
+		}
+	}
+	return &sp
+}
+
+//searchKind searchKind tells what q looks like. Numbers of GTIN length
+//are also tried as order IDs and SKUs.
+func searchKind(q string) string {
+	var rtn string
+	switch {
+	case strings.HasPrefix(strings.ToUpper(q), "OD-"):
+		rtn = searchOrderNumber
+	case strings.Contains(q, "@"):
+		rtn = searchEmail
+	case isDigits(q):
+		rtn = searchNumber
+		switch len(q) {
+		case 8, 12, 13, 14:
+			rtn = searchGtin
+		}
+	default:
+		rtn = searchText
+	}
+	return rtn
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+//runSearch runs every task at once and merges what they found, dropping
+//duplicates
+func runSearch(tasks []searchTask, hd *api.Headers) *searchResult {
+	var rtn searchResult
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, t := range tasks {
+		wg.Add(1)
+		go func(t searchTask) {
+			defer wg.Done()
+			sr := t(hd)
+			if sr == nil {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			rtn.orders = append(rtn.orders, sr.orders...)
+			rtn.customers = append(rtn.customers, sr.customers...)
+			rtn.products = append(rtn.products, sr.products...)
+		}(t)
+	}
+	wg.Wait()
+	return &rtn
+}
+
+func searchGroups(sr *searchResult) []SearchGroup {
+	var rtn []SearchGroup
+	seen := make(map[string]bool)
+	add := func(g *SearchGroup, key string, hit SearchHit) {
+		if hit.ID == 0 || seen[key] || len(g.Hits) >= searchLimit {
+			return
+		}
+		seen[key] = true
+		g.Hits = append(g.Hits, hit)
+	}
+	sort.SliceStable(sr.orders, func(i, j int) bool {
+		return sr.orders[i].OrderDate.After(sr.orders[j].OrderDate)
+	})
+	var og = SearchGroup{Name: "Orders"}
+	for _, o := range sr.orders {
+		id := strconv.FormatInt(o.ID, 10)
+		add(&og, "o"+id, SearchHit{ID: o.ID, Title: o.OrderNumber, Detail: o.CustomerName + " " + o.Status,
+			Link: adminEditOrderView + "/" + id})
+	}
+	sort.SliceStable(sr.customers, func(i, j int) bool {
+		return sr.customers[i].Email < sr.customers[j].Email
+	})
+	var cg = SearchGroup{Name: "Customers"}
+	for _, c := range sr.customers {
+		id := strconv.FormatInt(c.ID, 10)
+		add(&cg, "c"+id, SearchHit{ID: c.ID, Title: c.Email, Detail: c.FirstName + " " + c.LastName,
+			Link: adminEditCustomerView + "/" + id})
+	}
+	sort.SliceStable(sr.products, func(i, j int) bool {
+		return sr.products[i].Name < sr.products[j].Name
+	})
+	var pg = SearchGroup{Name: "Products"}
+	for _, p := range sr.products {
+		id := strconv.FormatInt(p.ID, 10)
+		add(&pg, "p"+id, SearchHit{ID: p.ID, Title: p.Name, Detail: p.Sku + " " + p.Gtin,
+			Link: adminEditProdView + "/" + id})
+	}
+	for _, g := range []SearchGroup{og, cg, pg} {
+		if len(g.Hits) > 0 {
+			rtn = append(rtn, g)
+		}
+	}
+	return rtn
+}
+
+//searchOrderNumber has to scan the store's orders since the backend has
+//no lookup by order number
+func (h *Six910Handler) searchOrderNumber(q string) searchTask {
+	return func(hd *api.Headers) *searchResult {
+		var rtn searchResult
+		if ol := h.API.GetStoreOrderList(hd); ol != nil {
+			for _, o := range *ol {
+				if strings.EqualFold(o.OrderNumber, q) {
+					rtn.orders = append(rtn.orders, o)
+				}
+			}
+		}
+		return &rtn
+	}
+}
+
+func (h *Six910Handler) searchOrderID(q string) searchTask {
+	return func(hd *api.Headers) *searchResult {
+		var rtn searchResult
+		id, _ := strconv.ParseInt(q, 10, 64)
+		if o := h.API.GetOrder(id, hd); o != nil && o.ID == id {
+			rtn.orders = append(rtn.orders, *o)
+		}
+		return &rtn
+	}
+}
+
+func (h *Six910Handler) searchCustomer(email string) searchTask {
+	return func(hd *api.Headers) *searchResult {
+		var rtn searchResult
+		if c := h.API.GetCustomer(email, hd); c != nil {
+			rtn.customers = append(rtn.customers, *c)
+		}
+		return &rtn
+	}
+}
+
+func (h *Six910Handler) searchCustomerOrders(email string) searchTask {
+	return func(hd *api.Headers) *searchResult {
+		var rtn searchResult
+		if c := h.API.GetCustomer(email, hd); c != nil && c.ID != 0 {
+			if ol := h.API.GetOrderList(c.ID, hd); ol != nil {
+				rtn.orders = append(rtn.orders, *ol...)
+			}
+		}
+		return &rtn
+	}
+}
+
+//searchSku searchSku asks for the SKU from every distributor at once
+//since a SKU is only unique per distributor
+func (h *Six910Handler) searchSku(sku string) searchTask {
+	return func(hd *api.Headers) *searchResult {
+		var tasks []searchTask
+		dids := []int64{0}
+		if dl := h.API.GetDistributorList(hd); dl != nil {
+			for _, d := range *dl {
+				dids = append(dids, d.ID)
+			}
+		}
+		for _, did := range dids {
+			tasks = append(tasks, func(did int64) searchTask {
+				return func(hd *api.Headers) *searchResult {
+					var rtn searchResult
+					if p := h.API.GetProductBySku(url.PathEscape(sku), did, hd); p != nil && strings.EqualFold(p.Sku, sku) {
+						rtn.products = append(rtn.products, *p)
+					}
+					return &rtn
+				}
+			}(did))
+		}
+		return runSearch(tasks, hd)
+	}
+}
+
+//searchGtin pages through the products since the backend has no lookup by
+//GTIN, stopping after dashboardMaxProducts like the dashboard
+func (h *Six910Handler) searchGtin(gtin string) searchTask {
+	return func(hd *api.Headers) *searchResult {
+		var rtn searchResult
+		for st := int64(0); st < dashboardMaxProducts; st += dashboardPageSize {
+			pl := h.API.GetProductList(st, st+dashboardPageSize, hd)
+			if pl == nil {
+				break
+			}
+			for _, p := range *pl {
+				if p.Gtin == gtin {
+					rtn.products = append(rtn.products, p)
+				}
+			}
+			if len(*pl) < dashboardPageSize {
+				break
+			}
+		}
+		return &rtn
+	}
+}
+
+func (h *Six910Handler) searchProductName(name string) searchTask {
+	return func(hd *api.Headers) *searchResult {
+		var rtn searchResult
+		if pl := h.API.GetProductsByName(url.PathEscape(name), 0, searchLimit, hd); pl != nil {
+			rtn.products = append(rtn.products, *pl...)
+		}
+		return &rtn
+	}
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"testing"
+
+	mapi "github.com/Ulbora/Six910-ui/mockapi"
+	perms "github.com/Ulbora/Six910-ui/permsrv"
+	sdbi "github.com/Ulbora/six910-database-interface"
+)
+
+func searchTestMocks(sapi *mapi.MockAPI) {
+	ol := []sdbi.Order{{ID: 4, OrderNumber: "OD-1600000000001", CustomerName: "Jane Doe"}, {ID: 5, OrderNumber: "OD-1600000000002"}}
+	sapi.MockOrderList = &ol
+	var o sdbi.Order
+	o.ID = 12
+	o.OrderNumber = "OD-1600000000012"
+	sapi.MockOrder = &o
+	var c sdbi.Customer
+	c.ID = 3
+	c.Email = "jane@test.com"
+	sapi.MockCustomer = &c
+	var p sdbi.Product
+	p.ID = 7
+	p.Sku = "12"
+	p.Name = "bolt"
+	sapi.MockProduct = &p
+	pl := []sdbi.Product{{ID: 8, Name: "nut", Gtin: "00012345678905"}, {ID: 9, Name: "axle"}}
+	sapi.MockProductList = &pl
+	sapi.MockProductNameList = &pl
+	dl := []sdbi.Distributor{{ID: 1}, {ID: 2}}
+	sapi.MockDistributorList = &dl
+}
+
+func serveSearch(sh *Six910Handler, q string) *SearchPage {
+	var sp *SearchPage
+	r, _ := http.NewRequest("GET", "/admin/search", nil)
+	serveAsAdmin(sh, func(w http.ResponseWriter, r *http.Request) {
+		sp = sh.adminSearch(r, q)
+	}, r)
+	fmt.Println("search: ", q, " ", *sp)
+	return sp
+}
+
+func TestSix910Handler_searchKind(t *testing.T) {
+	var tests = map[string]string{
+		"od-1600000000001":   searchOrderNumber,
+		"jane@test.com":      searchEmail,
+		"12":                 searchNumber,
+		"00012345678905":     searchGtin,
+		"12345670":           searchGtin,
+		"blue widget":        searchText,
+		"SKU-1":              searchText,
+		"123456789012345678": searchNumber,
+	}
+	for q, k := range tests {
+		if searchKind(q) != k {
+			fmt.Println("bad kind for: ", q, " ", searchKind(q))
+			t.Fail()
+		}
+	}
+}
+
+func TestSix910Handler_StoreAdminSearch(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	defer os.RemoveAll(dir)
+	searchTestMocks(sapi)
+	sh.AdminTemplates = template.Must(template.ParseFiles("testHtmls/test.html"))
+	r, _ := http.NewRequest("GET", "/admin/search?q=bolt", nil)
+	w := serveAsAdmin(sh, sh.StoreAdminSearch, r)
+	if w.Code != 200 {
+		t.Fail()
+	}
+
+	if sp := serveSearch(sh, ""); sp.Kind != "" || len(sp.Groups) != 0 {
+		t.Fail()
+	}
+
+	sp := serveSearch(sh, "OD-1600000000001")
+	if sp.Count != 1 || sp.Groups[0].Name != "Orders" || sp.Groups[0].Hits[0].Link != "/admin/editOrderView/4" {
+		t.Fail()
+	}
+
+	sp = serveSearch(sh, "jane@test.com")
+	if len(sp.Groups) != 2 || sp.Groups[1].Name != "Customers" || sp.Groups[1].Hits[0].Link != "/admin/editCustomerView/3" {
+		t.Fail()
+	}
+
+	//order 12 and SKU 12 asked of every distributor but listed once
+	sp = serveSearch(sh, "12")
+	if sp.Count != 2 || sp.Groups[0].Hits[0].ID != 12 || sp.Groups[1].Hits[0].Link != "/admin/editProductView/7" {
+		t.Fail()
+	}
+
+	sp = serveSearch(sh, "00012345678905")
+	if len(sp.Groups) != 1 || len(sp.Groups[0].Hits) != 1 || sp.Groups[0].Hits[0].ID != 8 {
+		t.Fail()
+	}
+
+	sp = serveSearch(sh, "blue widget")
+	if sp.Count != 2 || sp.Groups[0].Name != "Products" || sp.Groups[0].Hits[0].Title != "axle" {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAdminSearchPermission(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	defer os.RemoveAll(dir)
+	searchTestMocks(sapi)
+	sh.AdminTemplates = template.Must(template.ParseFiles("testHtmls/test.html"))
+	var ps perms.Six910PermissionService
+	ps.Users = map[string][]string{"tester": {"catalog"}}
+	sh.PermissionService = ps.GetNew()
+	if sp := serveSearch(sh, "jane@test.com"); sp.Kind != searchEmail || len(sp.Groups) != 0 {
+		t.Fail()
+	}
+	if sp := serveSearch(sh, "12"); len(sp.Groups) != 1 || sp.Groups[0].Name != "Products" {
+		t.Fail()
+	}
+}
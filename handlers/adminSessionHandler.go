@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	thr "github.com/Ulbora/Six910-ui/throtsrv"
+	"github.com/gorilla/mux"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+//AdminSessionRow AdminSessionRow is one active admin session on the
+//session list page. Ref is shown instead of the session ID.
+type AdminSessionRow struct {
+	Ref        string
+	Username   string
+	RemoteAddr string
+	UserAgent  string
+	Created    time.Time
+	LastUsed   time.Time
+	Current    bool
+}
+
+//AdminSessionPage AdminSessionPage also lists recent login lockouts
+type AdminSessionPage struct {
+	Error    string
+	Sessions []AdminSessionRow
+	Lockouts []thr.LockoutEvent
+}
+
+//StoreAdminViewSessionList StoreAdminViewSessionList
+func (h *Six910Handler) StoreAdminViewSessionList(w http.ResponseWriter, r *http.Request) {
+	var sp AdminSessionPage
+	sp.Error = r.URL.Query().Get("error")
+	cur := h.getAdminUser(r)
+	for _, as := range *h.AdminSessions.List() {
+		var row AdminSessionRow
+		row.Ref = as.Ref()
+		row.Username = as.Username
+		row.RemoteAddr = as.RemoteAddr
+		row.UserAgent = as.UserAgent
+		row.Created = as.Created
+		row.LastUsed = as.LastUsed
+		row.Current = as.ID == cur.ID
+		sp.Sessions = append(sp.Sessions, row)
+	}
+	if h.LoginThrottle != nil {
+		sp.Lockouts = *h.LoginThrottle.Events()
+	}
+	h.Log.Debug("admin sessions in list: ", len(sp.Sessions))
+	h.executeAdminTemplate(w, r, adminSessionListPage, &sp)
+}
+
+//StoreAdminRevokeSession StoreAdminRevokeSession ends the session with
+//the ref in the path. Revoking your own session logs you out.
+func (h *Six910Handler) StoreAdminRevokeSession(w http.ResponseWriter, r *http.Request) {
+	ref := mux.Vars(r)["ref"]
+	var found bool
+	for _, as := range *h.AdminSessions.List() {
+		if as.Ref() == ref {
+			found = h.AdminSessions.Delete(as.ID)
+			h.Log.Info("admin session revoked for: ", as.Username)
+			break
+		}
+	}
+	if !found {
+		http.Redirect(w, r, adminSessionListViewFail, http.StatusFound)
+	} else if h.getAdminUser(r).Ref() == ref {
+		http.Redirect(w, r, adminLogin, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminSessionListView, http.StatusFound)
+	}
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	lg "github.com/Ulbora/Level_Logger"
+	"github.com/gorilla/mux"
+)
+
+func TestSix910Handler_StoreAdminViewSessionList(t *testing.T) {
+	var sh Six910Handler
+	var l lg.Logger
+	l.LogLevel = lg.AllLevel
+	sh.Log = &l
+	sh.AdminTemplates = template.Must(template.New("admin").Parse(
+		`{{define "sessionList.html"}}{{range .Sessions}}[{{.Username}} {{.Current}}]{{end}}{{end}}`))
+
+	r, _ := http.NewRequest("GET", "/admin/sessionListView", nil)
+	w := httptest.NewRecorder()
+	s, _ := sh.getSession(r)
+	loginTestAdmin(&sh, s)
+	s.Values["storeAdminUser"] = true
+	r2, _ := http.NewRequest("GET", "/admin/sessionListView", nil)
+	s2, _ := sh.getSession(r2)
+	loginTestAdmin(&sh, s2)
+	h := sh.GetNew()
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminViewSessionList)).ServeHTTP(w, r)
+	fmt.Println("body: ", w.Body.String())
+	if w.Code != 200 || w.Body.String() != "[tester false][tester true]" {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAdminRevokeSession(t *testing.T) {
+	var sh Six910Handler
+	var l lg.Logger
+	l.LogLevel = lg.AllLevel
+	sh.Log = &l
+
+	r0, _ := http.NewRequest("GET", "/admin/index", nil)
+	s0, _ := sh.getSession(r0)
+	other := loginTestAdmin(&sh, s0)
+
+	r, _ := http.NewRequest("POST", "/admin/revokeSession/"+other.Ref(), nil)
+	r = mux.SetURLVars(r, map[string]string{"ref": other.Ref()})
+	w := httptest.NewRecorder()
+	s, _ := sh.getSession(r)
+	cur := loginTestAdmin(&sh, s)
+	s.Values["storeAdminUser"] = true
+	h := sh.GetNew()
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminRevokeSession)).ServeHTTP(w, r)
+	fmt.Println("location: ", w.Header().Get("Location"))
+	if w.Code != 302 || w.Header().Get("Location") != adminSessionListView || sh.AdminSessions.Get(other.ID) != nil {
+		t.Fail()
+	}
+
+	w2 := httptest.NewRecorder()
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminRevokeSession)).ServeHTTP(w2, r)
+	if w2.Header().Get("Location") != adminSessionListViewFail {
+		t.Fail()
+	}
+
+	r3 := mux.SetURLVars(r, map[string]string{"ref": cur.Ref()})
+	w3 := httptest.NewRecorder()
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminRevokeSession)).ServeHTTP(w3, r3)
+	if w3.Header().Get("Location") != adminLogin || sh.AdminSessions.Get(cur.ID) != nil {
+		t.Fail()
+	}
+}
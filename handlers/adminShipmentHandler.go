@@ -39,281 +39,265 @@ type ShipPage struct {
 	Order         *sdbi.Order
 	OrderItems    *[]sdbi.OrderItem
 	OrderComments *[]sdbi.OrderComment
+	FormErrors
 }
 
 //StoreAdminAddShipmentPage StoreAdminAddShipmentPage
 func (h *Six910Handler) StoreAdminAddShipmentPage(w http.ResponseWriter, r *http.Request) {
-	gss, suc := h.getSession(r)
-	h.Log.Debug("session suc in shipment add view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(gss) {
-			asvars := mux.Vars(r)
-			asidstr := asvars["id"]
-			asOIID, _ := strconv.ParseInt(asidstr, 10, 64)
-			aspErr := r.URL.Query().Get("error")
-			var page ShipPage
-			page.Error = aspErr
-			hd := h.getHeader(gss)
-			var wg sync.WaitGroup
-			wg.Add(1)
-			go func(oid int64, header *six910api.Headers) {
-				defer wg.Done()
-				page.Order = h.API.GetOrder(oid, header)
-			}(asOIID, hd)
-
-			wg.Add(1)
-			go func(oid int64, header *six910api.Headers) {
-				defer wg.Done()
-				page.OrderComments = h.API.GetOrderCommentList(oid, header)
-			}(asOIID, hd)
-
-			wg.Add(1)
-			go func(oid int64, header *six910api.Headers) {
-				defer wg.Done()
-				page.OrderItems = h.API.GetOrderItemList(oid, header)
-			}(asOIID, hd)
-
-			wg.Wait()
-			h.Log.Debug("shipment page", page)
-			// h.Log.Debug("shipment order", *page.Order)
-			// h.Log.Debug("shipment order notes", *page.OrderComments)
-			// h.Log.Debug("shipment order items", *page.OrderItems)
-
-			h.AdminTemplates.ExecuteTemplate(w, adminAddShipmentPage, &page)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	asvars := mux.Vars(r)
+	asidstr := asvars["id"]
+	asOIID, _ := strconv.ParseInt(asidstr, 10, 64)
+	aspErr := r.URL.Query().Get("error")
+	var page ShipPage
+	page.Error = aspErr
+	hd := h.getAdminHeader(r)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func(oid int64, header *six910api.Headers) {
+		defer wg.Done()
+		page.Order = h.API.GetOrder(oid, header)
+	}(asOIID, hd)
+
+	wg.Add(1)
+	go func(oid int64, header *six910api.Headers) {
+		defer wg.Done()
+		page.OrderComments = h.API.GetOrderCommentList(oid, header)
+	}(asOIID, hd)
+
+	wg.Add(1)
+	go func(oid int64, header *six910api.Headers) {
+		defer wg.Done()
+		page.OrderItems = h.API.GetOrderItemList(oid, header)
+	}(asOIID, hd)
+
+	wg.Wait()
+	h.Log.Debug("shipment page", page)
+	// h.Log.Debug("shipment order", *page.Order)
+	// h.Log.Debug("shipment order notes", *page.OrderComments)
+	// h.Log.Debug("shipment order items", *page.OrderItems)
+
+	h.executeAdminTemplate(w, r, adminAddShipmentPage, &page)
 }
 
 //StoreAdminAddShipment StoreAdminAddShipment
 func (h *Six910Handler) StoreAdminAddShipment(w http.ResponseWriter, r *http.Request) {
-	as, suc := h.getSession(r)
-	h.Log.Debug("session suc in shipment add", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(as) {
-			sh := h.processShipment(r)
-			h.Log.Debug("shipment in add", *sh)
-			hd := h.getHeader(as)
-			shres := h.API.AddShipment(sh, hd)
-			h.Log.Debug("shipment add resp", *shres)
-			var success = true
-			if shres.Success {
-				oil := h.API.GetOrderItemList(sh.OrderID, hd)
-				var oichan = make(chan *api.ResponseID, len(*oil))
-				var wg sync.WaitGroup
-				for i := range *oil {
-					wg.Add(1)
-					go func(oi *sdbi.OrderItem, header *six910api.Headers, ch chan *api.ResponseID) {
-						defer wg.Done()
-						h.Log.Debug("order item in goroutine", *oi)
-						var si sdbi.ShipmentItem
-						si.OrderItemID = oi.ID
-						si.Quantity = oi.Quantity
-						si.ShipmentID = shres.ID
-						h.Log.Debug("shipment item in goroutine", si)
-						ires := h.API.AddShipmentItem(&si, header)
-						ch <- ires
-					}(&(*oil)[i], hd, oichan)
-				}
-				wg.Wait()
-				close(oichan)
-				for res := range oichan {
-					if !res.Success {
-						success = false
-					}
+	sh, fe := h.processShipment(r)
+	if len(fe) > 0 {
+		var fp ShipPage
+		fp.Error = invalidForm
+		fp.Shipment = sh
+		fp.FormErrors = formErrors(r, fe)
+		hd := h.getAdminHeader(r)
+		fp.Order = h.API.GetOrder(sh.OrderID, hd)
+		fp.OrderComments = h.API.GetOrderCommentList(sh.OrderID, hd)
+		fp.OrderItems = h.API.GetOrderItemList(sh.OrderID, hd)
+		h.executeAdminTemplate(w, r, adminAddShipmentPage, &fp)
+		return
+	}
+	h.Log.Debug("shipment in add", *sh)
+	hd := h.getAdminHeader(r)
+	shres := h.API.AddShipment(sh, hd)
+	h.Log.Debug("shipment add resp", *shres)
+	var success = true
+	if shres.Success {
+		h.auditCreate(r, auditShipment, shres.ID, sh)
+		oil := h.API.GetOrderItemList(sh.OrderID, hd)
+		var oichan = make(chan *api.ResponseID, len(*oil))
+		var wg sync.WaitGroup
+		for i := range *oil {
+			wg.Add(1)
+			go func(oi *sdbi.OrderItem, header *six910api.Headers, ch chan *api.ResponseID) {
+				defer wg.Done()
+				h.Log.Debug("order item in goroutine", *oi)
+				var si sdbi.ShipmentItem
+				si.OrderItemID = oi.ID
+				si.Quantity = oi.Quantity
+				si.ShipmentID = shres.ID
+				h.Log.Debug("shipment item in goroutine", si)
+				ires := h.API.AddShipmentItem(&si, header)
+				if ires.Success {
+					h.auditCreate(r, auditShipmentItem, ires.ID, &si)
 				}
-			} else {
+				ch <- ires
+			}(&(*oil)[i], hd, oichan)
+		}
+		wg.Wait()
+		close(oichan)
+		for res := range oichan {
+			if !res.Success {
 				success = false
 			}
-			h.Log.Debug("shipment all add suc", success)
-			if success {
-				http.Redirect(w, r, adminOrderListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminAddShipmentViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
 		}
+	} else {
+		success = false
+	}
+	h.Log.Debug("shipment all add suc", success)
+	if success {
+		http.Redirect(w, r, adminOrderListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminAddShipmentViewFail, http.StatusFound)
 	}
 }
 
 //StoreAdminEditShipmentPage StoreAdminEditShipmentPage
 func (h *Six910Handler) StoreAdminEditShipmentPage(w http.ResponseWriter, r *http.Request) {
-	ess, suc := h.getSession(r)
-	h.Log.Debug("session suc in shipment edit view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(ess) {
-			var esparm ShipPage
-			edErr := r.URL.Query().Get("error")
-			esparm.Error = edErr
-
-			hd := h.getHeader(ess)
-			esvars := mux.Vars(r)
-			esidstr := esvars["id"]
-			esID, _ := strconv.ParseInt(esidstr, 10, 64)
-			h.Log.Debug("shipment id in edit", esID)
-
-			ship := h.API.GetShipment(esID, hd)
-			esparm.Shipment = ship
-			h.Log.Debug("shipment in edit", ship)
-
-			var wg sync.WaitGroup
-			wg.Add(1)
-			go func(oid int64, header *six910api.Headers) {
-				defer wg.Done()
-				esparm.Order = h.API.GetOrder(oid, header)
-			}(ship.OrderID, hd)
-
-			wg.Add(1)
-			go func(oid int64, header *six910api.Headers) {
-				defer wg.Done()
-				esparm.OrderComments = h.API.GetOrderCommentList(oid, header)
-			}(ship.OrderID, hd)
-
-			wg.Add(1)
-			go func(oid int64, header *six910api.Headers) {
-				defer wg.Done()
-				esparm.OrderItems = h.API.GetOrderItemList(oid, header)
-			}(ship.OrderID, hd)
-
-			wg.Add(1)
-			go func(oid int64, header *six910api.Headers) {
-				defer wg.Done()
-				esparm.Shipments = h.API.GetShipmentList(oid, header)
-			}(ship.OrderID, hd)
-
-			wg.Add(1)
-			go func(spid int64, header *six910api.Headers) {
-				defer wg.Done()
-				esparm.ShipmentBoxes = h.API.GetShipmentBoxList(spid, header)
-			}(ship.ID, hd)
-
-			wg.Add(1)
-			go func(spid int64, header *six910api.Headers) {
-				defer wg.Done()
-				esparm.ShipmentItems = h.API.GetShipmentItemList(spid, header)
-			}(ship.ID, hd)
-
-			wg.Wait()
-
-			h.Log.Debug("shipment page", esparm)
-
-			h.AdminTemplates.ExecuteTemplate(w, adminEditShipmentPage, &esparm)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	var esparm ShipPage
+	edErr := r.URL.Query().Get("error")
+	esparm.Error = edErr
+
+	hd := h.getAdminHeader(r)
+	esvars := mux.Vars(r)
+	esidstr := esvars["id"]
+	esID, _ := strconv.ParseInt(esidstr, 10, 64)
+	h.Log.Debug("shipment id in edit", esID)
+
+	ship := h.API.GetShipment(esID, hd)
+	esparm.Shipment = ship
+	h.Log.Debug("shipment in edit", ship)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func(oid int64, header *six910api.Headers) {
+		defer wg.Done()
+		esparm.Order = h.API.GetOrder(oid, header)
+	}(ship.OrderID, hd)
+
+	wg.Add(1)
+	go func(oid int64, header *six910api.Headers) {
+		defer wg.Done()
+		esparm.OrderComments = h.API.GetOrderCommentList(oid, header)
+	}(ship.OrderID, hd)
+
+	wg.Add(1)
+	go func(oid int64, header *six910api.Headers) {
+		defer wg.Done()
+		esparm.OrderItems = h.API.GetOrderItemList(oid, header)
+	}(ship.OrderID, hd)
+
+	wg.Add(1)
+	go func(oid int64, header *six910api.Headers) {
+		defer wg.Done()
+		esparm.Shipments = h.API.GetShipmentList(oid, header)
+	}(ship.OrderID, hd)
+
+	wg.Add(1)
+	go func(spid int64, header *six910api.Headers) {
+		defer wg.Done()
+		esparm.ShipmentBoxes = h.API.GetShipmentBoxList(spid, header)
+	}(ship.ID, hd)
+
+	wg.Add(1)
+	go func(spid int64, header *six910api.Headers) {
+		defer wg.Done()
+		esparm.ShipmentItems = h.API.GetShipmentItemList(spid, header)
+	}(ship.ID, hd)
+
+	wg.Wait()
+
+	h.Log.Debug("shipment page", esparm)
+
+	h.executeAdminTemplate(w, r, adminEditShipmentPage, &esparm)
 }
 
 //StoreAdminEditShipment StoreAdminEditShipment
 func (h *Six910Handler) StoreAdminEditShipment(w http.ResponseWriter, r *http.Request) {
-	esss, suc := h.getSession(r)
-	h.Log.Debug("session suc in shipment edit", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(esss) {
-			epp := h.processShipment(r)
-			h.Log.Debug("shipment update", *epp)
-			hd := h.getHeader(esss)
-			res := h.API.UpdateShipment(epp, hd)
-			h.Log.Debug("shipment update resp", *res)
-			if res.Success {
-				http.Redirect(w, r, adminOrderListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminEditShipmentViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	epp, fe := h.processShipment(r)
+	if len(fe) > 0 {
+		var fp ShipPage
+		fp.Error = invalidForm
+		fp.Shipment = epp
+		fp.FormErrors = formErrors(r, fe)
+		hd := h.getAdminHeader(r)
+		fp.Order = h.API.GetOrder(epp.OrderID, hd)
+		fp.OrderComments = h.API.GetOrderCommentList(epp.OrderID, hd)
+		fp.OrderItems = h.API.GetOrderItemList(epp.OrderID, hd)
+		fp.Shipments = h.API.GetShipmentList(epp.OrderID, hd)
+		fp.ShipmentBoxes = h.API.GetShipmentBoxList(epp.ID, hd)
+		fp.ShipmentItems = h.API.GetShipmentItemList(epp.ID, hd)
+		h.executeAdminTemplate(w, r, adminEditShipmentPage, &fp)
+		return
+	}
+	h.Log.Debug("shipment update", *epp)
+	hd := h.getAdminHeader(r)
+	ae := h.auditBefore(r, auditShipment, epp.ID, func() interface{} { return h.API.GetShipment(epp.ID, hd) })
+	res := h.API.UpdateShipment(epp, hd)
+	h.Log.Debug("shipment update resp", *res)
+	if res.Success {
+		ae.updated(epp)
+		http.Redirect(w, r, adminOrderListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminEditShipmentViewFail, http.StatusFound)
 	}
 }
 
 //StoreAdminViewShipmentList StoreAdminViewShipmentList
 func (h *Six910Handler) StoreAdminViewShipmentList(w http.ResponseWriter, r *http.Request) {
-	sls, suc := h.getSession(r)
-	h.Log.Debug("session suc in shipment list view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(sls) {
-			hd := h.getHeader(sls)
-			vpvars := mux.Vars(r)
-			oidstr := vpvars["oid"]
-			foid, _ := strconv.ParseInt(oidstr, 10, 64)
-			//shps := h.API.GetShipmentList(oid, hd)
-			plErr := r.URL.Query().Get("error")
-			var slparm ShipPage
-			slparm.Error = plErr
-			//slparm.Shipments = shps
-
-			var wg sync.WaitGroup
-			wg.Add(1)
-			go func(oid int64, header *six910api.Headers) {
-				defer wg.Done()
-				slparm.Order = h.API.GetOrder(oid, header)
-			}(foid, hd)
-
-			wg.Add(1)
-			go func(oid int64, header *six910api.Headers) {
-				defer wg.Done()
-				slparm.OrderComments = h.API.GetOrderCommentList(oid, header)
-			}(foid, hd)
-
-			wg.Add(1)
-			go func(oid int64, header *six910api.Headers) {
-				defer wg.Done()
-				slparm.OrderItems = h.API.GetOrderItemList(oid, header)
-			}(foid, hd)
-
-			wg.Add(1)
-			go func(oid int64, header *six910api.Headers) {
-				defer wg.Done()
-				slparm.Shipments = h.API.GetShipmentList(oid, header)
-			}(foid, hd)
-
-			wg.Wait()
-			h.Log.Debug("shipments in list", slparm)
-			h.AdminTemplates.ExecuteTemplate(w, adminShipmentListView, &slparm)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	hd := h.getAdminHeader(r)
+	vpvars := mux.Vars(r)
+	oidstr := vpvars["oid"]
+	foid, _ := strconv.ParseInt(oidstr, 10, 64)
+	//shps := h.API.GetShipmentList(oid, hd)
+	plErr := r.URL.Query().Get("error")
+	var slparm ShipPage
+	slparm.Error = plErr
+	//slparm.Shipments = shps
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func(oid int64, header *six910api.Headers) {
+		defer wg.Done()
+		slparm.Order = h.API.GetOrder(oid, header)
+	}(foid, hd)
+
+	wg.Add(1)
+	go func(oid int64, header *six910api.Headers) {
+		defer wg.Done()
+		slparm.OrderComments = h.API.GetOrderCommentList(oid, header)
+	}(foid, hd)
+
+	wg.Add(1)
+	go func(oid int64, header *six910api.Headers) {
+		defer wg.Done()
+		slparm.OrderItems = h.API.GetOrderItemList(oid, header)
+	}(foid, hd)
+
+	wg.Add(1)
+	go func(oid int64, header *six910api.Headers) {
+		defer wg.Done()
+		slparm.Shipments = h.API.GetShipmentList(oid, header)
+	}(foid, hd)
+
+	wg.Wait()
+	h.Log.Debug("shipments in list", slparm)
+	h.executeAdminTemplate(w, r, adminShipmentListView, &slparm)
 }
 
 //StoreAdminDeleteShipment StoreAdminDeleteShipment
 func (h *Six910Handler) StoreAdminDeleteShipment(w http.ResponseWriter, r *http.Request) {
-	dss, suc := h.getSession(r)
-	h.Log.Debug("session suc in shipment delete", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(dss) {
-			hd := h.getHeader(dss)
-			dsvars := mux.Vars(r)
-			idstrd := dsvars["id"]
-			idd, _ := strconv.ParseInt(idstrd, 10, 64)
-			res := h.API.DeleteShipment(idd, hd)
-			h.Log.Debug("shipment delete resp", *res)
-			if res.Success {
-				http.Redirect(w, r, adminShipmentListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminShipmentListViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	hd := h.getAdminHeader(r)
+	dsvars := mux.Vars(r)
+	idstrd := dsvars["id"]
+	idd, _ := strconv.ParseInt(idstrd, 10, 64)
+	ae := h.auditBefore(r, auditShipment, idd, func() interface{} { return h.API.GetShipment(idd, hd) })
+	res := h.API.DeleteShipment(idd, hd)
+	h.Log.Debug("shipment delete resp", *res)
+	if res.Success {
+		ae.deleted()
+		http.Redirect(w, r, adminShipmentListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminShipmentListViewFail, http.StatusFound)
 	}
 }
 
-func (h *Six910Handler) processShipment(r *http.Request) *sdbi.Shipment {
+func (h *Six910Handler) processShipment(r *http.Request) (*sdbi.Shipment, FieldErrors) {
 	var p sdbi.Shipment
-	id := r.FormValue("id")
-	p.ID, _ = strconv.ParseInt(id, 10, 64)
+	f := newFormReader(r)
+	p.ID = f.int("id")
 	p.Status = r.FormValue("status")
-	boxes := r.FormValue("boxes")
-	p.Boxes, _ = strconv.ParseInt(boxes, 10, 64)
-	shippingHandling := r.FormValue("shippingHandling")
-	p.ShippingHandling, _ = strconv.ParseFloat(shippingHandling, 64)
-	insurance := r.FormValue("insurance")
-	p.Insurance, _ = strconv.ParseFloat(insurance, 64)
-	orderID := r.FormValue("orderId")
-	p.OrderID, _ = strconv.ParseInt(orderID, 10, 64)
-
-	return &p
+	p.Boxes = f.int("boxes")
+	p.ShippingHandling = f.float("shippingHandling")
+	p.Insurance = f.float("insurance")
+	p.OrderID = f.int("orderId")
+
+	return &p, f.check(validateShipment(&p))
 }
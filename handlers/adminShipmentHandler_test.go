@@ -69,11 +69,11 @@ func TestSix910Handler_StoreAdminAddShipmentPage(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddShipmentPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddShipmentPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -137,7 +137,7 @@ func TestSix910Handler_StoreAdminAddShipmentPageLogin(t *testing.T) {
 	//s.Values["loggedIn"] = true
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddShipmentPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddShipmentPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -208,13 +208,11 @@ func TestSix910Handler_StoreAdminAddShipment(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddShipment(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddShipment)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -285,13 +283,11 @@ func TestSix910Handler_StoreAdminAddShipmentItemInsertFail(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddShipment(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddShipment)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -335,11 +331,9 @@ func TestSix910Handler_StoreAdminAddShipmentLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddShipment(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddShipment)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -376,18 +370,16 @@ func TestSix910Handler_StoreAdminAddShipmentFail(t *testing.T) {
 
 	//-----------end mocking --------
 
-	r, _ := http.NewRequest("POST", "https://test.com", strings.NewReader("id=33&status=tester"))
+	r, _ := http.NewRequest("POST", "https://test.com", strings.NewReader("id=33&status=tester&orderId=2"))
 	r.Header.Set("Content-Type", "application/x-www-form-urlencoded; param=value")
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddShipment(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddShipment)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -469,11 +461,11 @@ func TestSix910Handler_StoreAdminEditShipmentPage(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditShipmentPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditShipmentPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -559,7 +551,7 @@ func TestSix910Handler_StoreAdminEditShipmentPageLogin(t *testing.T) {
 	s.Values["storeAdminUser"] = true
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditShipmentPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditShipmentPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -600,13 +592,11 @@ func TestSix910Handler_StoreAdminEditShipment(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditShipment(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditShipment)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -649,11 +639,9 @@ func TestSix910Handler_StoreAdminEditShipmentLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditShipment(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditShipment)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -694,13 +682,11 @@ func TestSix910Handler_StoreAdminEditShipmentFail(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditShipment(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditShipment)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -772,13 +758,11 @@ func TestSix910Handler_StoreAdminViewShipmentList(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminViewShipmentList(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminViewShipmentList)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -852,11 +836,9 @@ func TestSix910Handler_StoreAdminViewShipmentListLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminViewShipmentList(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminViewShipmentList)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -900,13 +882,11 @@ func TestSix910Handler_StoreAdminDeleteShipment(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminDeleteShipment(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminDeleteShipment)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -952,11 +932,9 @@ func TestSix910Handler_StoreAdminDeleteShipmentLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminDeleteShipment(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminDeleteShipment)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -1000,13 +978,11 @@ func TestSix910Handler_StoreAdminDeleteShipmentFail(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminDeleteShipment(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminDeleteShipment)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -30,137 +30,110 @@ import (
 type ShipCarPage struct {
 	Error           string
 	ShippingCarrier *sdbi.ShippingCarrier
+	FormErrors
 }
 
 //StoreAdminAddCarrierPage StoreAdminAddCarrierPage
 func (h *Six910Handler) StoreAdminAddCarrierPage(w http.ResponseWriter, r *http.Request) {
-	ascs, suc := h.getSession(r)
-	h.Log.Debug("session suc in shipping carrier add view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(ascs) {
-			ascErr := r.URL.Query().Get("error")
-			var ascpg InsPage
-			ascpg.Error = ascErr
-			h.AdminTemplates.ExecuteTemplate(w, adminAddShippingCarrierPage, &ascpg)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	ascErr := r.URL.Query().Get("error")
+	var ascpg InsPage
+	ascpg.Error = ascErr
+	h.executeAdminTemplate(w, r, adminAddShippingCarrierPage, &ascpg)
 }
 
 //StoreAdminAddCarrier StoreAdminAddCarrier
 func (h *Six910Handler) StoreAdminAddCarrier(w http.ResponseWriter, r *http.Request) {
-	adscs, suc := h.getSession(r)
-	h.Log.Debug("session suc in shipping carrier add", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(adscs) {
-			asc := h.processShippingCarrier(r)
-			h.Log.Debug("shipping carrier add", *asc)
-			hd := h.getHeader(adscs)
-			scres := h.API.AddShippingCarrier(asc, hd)
-			h.Log.Debug("shipping carrier add resp", *scres)
-			if scres.Success {
-				http.Redirect(w, r, adminAddShippingCarrierView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminAddShippingCarrierViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	asc, fe := h.processShippingCarrier(r)
+	if len(fe) > 0 {
+		var fp ShipCarPage
+		fp.Error = invalidForm
+		fp.ShippingCarrier = asc
+		fp.FormErrors = formErrors(r, fe)
+		h.executeAdminTemplate(w, r, adminAddShippingCarrierPage, &fp)
+		return
+	}
+	h.Log.Debug("shipping carrier add", *asc)
+	hd := h.getAdminHeader(r)
+	scres := h.API.AddShippingCarrier(asc, hd)
+	h.Log.Debug("shipping carrier add resp", *scres)
+	if scres.Success {
+		h.auditCreate(r, auditShippingCarrier, scres.ID, asc)
+		http.Redirect(w, r, adminAddShippingCarrierView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminAddShippingCarrierViewFail, http.StatusFound)
 	}
 }
 
 //StoreAdminEditCarrierPage StoreAdminEditCarrierPage
 func (h *Six910Handler) StoreAdminEditCarrierPage(w http.ResponseWriter, r *http.Request) {
-	escs, suc := h.getSession(r)
-	h.Log.Debug("session suc in shipping carrier edit view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(escs) {
-			hd := h.getHeader(escs)
-			eipErr := r.URL.Query().Get("error")
-			escvars := mux.Vars(r)
-			idstr := escvars["id"]
-			iID, _ := strconv.ParseInt(idstr, 10, 64)
-			h.Log.Debug("shipping carrier id in edit", iID)
-			var scgp ShipCarPage
-			scgp.Error = eipErr
-			scgp.ShippingCarrier = h.API.GetShippingCarrier(iID, hd)
-			h.AdminTemplates.ExecuteTemplate(w, adminEditShippingCarrierPage, &scgp)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	hd := h.getAdminHeader(r)
+	eipErr := r.URL.Query().Get("error")
+	escvars := mux.Vars(r)
+	idstr := escvars["id"]
+	iID, _ := strconv.ParseInt(idstr, 10, 64)
+	h.Log.Debug("shipping carrier id in edit", iID)
+	var scgp ShipCarPage
+	scgp.Error = eipErr
+	scgp.ShippingCarrier = h.API.GetShippingCarrier(iID, hd)
+	h.executeAdminTemplate(w, r, adminEditShippingCarrierPage, &scgp)
 }
 
 //StoreAdminEditCarrier StoreAdminEditCarrier
 func (h *Six910Handler) StoreAdminEditCarrier(w http.ResponseWriter, r *http.Request) {
-	escs, suc := h.getSession(r)
-	h.Log.Debug("session suc in shipping carrier edit", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(escs) {
-			esc := h.processShippingCarrier(r)
-			h.Log.Debug("shipping carrier update", *esc)
-			hd := h.getHeader(escs)
-			res := h.API.UpdateShippingCarrier(esc, hd)
-			h.Log.Debug("shipping carrier update resp", *res)
-			if res.Success {
-				http.Redirect(w, r, adminShippingCarrierListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminShippingCarrierListViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	esc, fe := h.processShippingCarrier(r)
+	if len(fe) > 0 {
+		var fp ShipCarPage
+		fp.Error = invalidForm
+		fp.ShippingCarrier = esc
+		fp.FormErrors = formErrors(r, fe)
+		h.executeAdminTemplate(w, r, adminEditShippingCarrierPage, &fp)
+		return
+	}
+	h.Log.Debug("shipping carrier update", *esc)
+	hd := h.getAdminHeader(r)
+	ae := h.auditBefore(r, auditShippingCarrier, esc.ID, func() interface{} { return h.API.GetShippingCarrier(esc.ID, hd) })
+	res := h.API.UpdateShippingCarrier(esc, hd)
+	h.Log.Debug("shipping carrier update resp", *res)
+	if res.Success {
+		ae.updated(esc)
+		http.Redirect(w, r, adminShippingCarrierListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminShippingCarrierListViewFail, http.StatusFound)
 	}
 }
 
 //StoreAdminViewCarrierList StoreAdminViewCarrierList
 func (h *Six910Handler) StoreAdminViewCarrierList(w http.ResponseWriter, r *http.Request) {
-	gscls, suc := h.getSession(r)
-	h.Log.Debug("session suc in shipping carrier view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(gscls) {
-			hd := h.getHeader(gscls)
-			scsl := h.API.GetShippingCarrierList(hd)
-			h.Log.Debug("shipping carrier  in list", *scsl)
-			h.AdminTemplates.ExecuteTemplate(w, adminShippingCarrierListView, &scsl)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	hd := h.getAdminHeader(r)
+	scsl := h.API.GetShippingCarrierList(hd)
+	h.Log.Debug("shipping carrier  in list", *scsl)
+	h.executeAdminTemplate(w, r, adminShippingCarrierListView, &scsl)
 }
 
 //StoreAdminDeleteCarrier StoreAdminDeleteCarrier
 func (h *Six910Handler) StoreAdminDeleteCarrier(w http.ResponseWriter, r *http.Request) {
-	dscs, suc := h.getSession(r)
-	h.Log.Debug("session suc in shipping carrier list delete", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(dscs) {
-			hd := h.getHeader(dscs)
-			divars := mux.Vars(r)
-			idscrd := divars["id"]
-			idddsc, _ := strconv.ParseInt(idscrd, 10, 64)
-			res := h.API.DeleteShippingCarrier(idddsc, hd)
-			h.Log.Debug("shipping carrier delete resp", *res)
-			if res.Success {
-				http.Redirect(w, r, adminShippingCarrierListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminShippingCarrierListViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	hd := h.getAdminHeader(r)
+	divars := mux.Vars(r)
+	idscrd := divars["id"]
+	idddsc, _ := strconv.ParseInt(idscrd, 10, 64)
+	ae := h.auditBefore(r, auditShippingCarrier, idddsc, func() interface{} { return h.API.GetShippingCarrier(idddsc, hd) })
+	res := h.API.DeleteShippingCarrier(idddsc, hd)
+	h.Log.Debug("shipping carrier delete resp", *res)
+	if res.Success {
+		ae.deleted()
+		http.Redirect(w, r, adminShippingCarrierListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminShippingCarrierListViewFail, http.StatusFound)
 	}
 }
 
-func (h *Six910Handler) processShippingCarrier(r *http.Request) *sdbi.ShippingCarrier {
+func (h *Six910Handler) processShippingCarrier(r *http.Request) (*sdbi.ShippingCarrier, FieldErrors) {
 	var s sdbi.ShippingCarrier
-	id := r.FormValue("id")
-	s.ID, _ = strconv.ParseInt(id, 10, 64)
+	f := newFormReader(r)
+	s.ID = f.int("id")
 	s.Carrier = r.FormValue("carrier")
 	s.Type = r.FormValue("type")
-	storeID := r.FormValue("storeId")
-	s.StoreID, _ = strconv.ParseInt(storeID, 10, 64)
+	s.StoreID = f.int("storeId")
 
-	return &s
+	return &s, f.check(validateShippingCarrier(&s))
 }
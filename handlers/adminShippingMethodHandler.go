@@ -30,152 +30,117 @@ import (
 type ShipMethPage struct {
 	Error          string
 	ShippingMethod *sdbi.ShippingMethod
+	FormErrors
 }
 
 //StoreAdminAddShippingMethodPage StoreAdminAddShippingMethodPage
 func (h *Six910Handler) StoreAdminAddShippingMethodPage(w http.ResponseWriter, r *http.Request) {
-	asms, suc := h.getSession(r)
-	h.Log.Debug("session suc in shipping method add view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(asms) {
-			asmErr := r.URL.Query().Get("error")
-			var asmpg ShipMethPage
-			asmpg.Error = asmErr
-			h.AdminTemplates.ExecuteTemplate(w, adminAddShippingMethodPage, &asmpg)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	asmErr := r.URL.Query().Get("error")
+	var asmpg ShipMethPage
+	asmpg.Error = asmErr
+	h.executeAdminTemplate(w, r, adminAddShippingMethodPage, &asmpg)
 }
 
 //StoreAdminAddShippingMethod StoreAdminAddShippingMethod
 func (h *Six910Handler) StoreAdminAddShippingMethod(w http.ResponseWriter, r *http.Request) {
-	aasms, suc := h.getSession(r)
-	h.Log.Debug("session suc in dist add", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(aasms) {
-			aasm := h.processShippingMethod(r)
-			h.Log.Debug("shipping method add", *aasm)
-			hd := h.getHeader(aasms)
-			aasmres := h.API.AddShippingMethod(aasm, hd)
-			h.Log.Debug("shipping method add resp", *aasmres)
-			if aasmres.Success {
-				http.Redirect(w, r, adminAddShippingMethodView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminAddShippingMethodViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	aasm, fe := h.processShippingMethod(r)
+	if len(fe) > 0 {
+		var fp ShipMethPage
+		fp.Error = invalidForm
+		fp.ShippingMethod = aasm
+		fp.FormErrors = formErrors(r, fe)
+		h.executeAdminTemplate(w, r, adminAddShippingMethodPage, &fp)
+		return
+	}
+	h.Log.Debug("shipping method add", *aasm)
+	hd := h.getAdminHeader(r)
+	aasmres := h.API.AddShippingMethod(aasm, hd)
+	h.Log.Debug("shipping method add resp", *aasmres)
+	if aasmres.Success {
+		h.auditCreate(r, auditShippingMethod, aasmres.ID, aasm)
+		http.Redirect(w, r, adminAddShippingMethodView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminAddShippingMethodViewFail, http.StatusFound)
 	}
 }
 
 //StoreAdminEditShippingMethodPage StoreAdminEditShippingMethodPage
 func (h *Six910Handler) StoreAdminEditShippingMethodPage(w http.ResponseWriter, r *http.Request) {
-	esms, suc := h.getSession(r)
-	h.Log.Debug("session suc in shipping method edit view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(esms) {
-			hd := h.getHeader(esms)
-			eipErr := r.URL.Query().Get("error")
-			esmvars := mux.Vars(r)
-			idesmstr := esmvars["id"]
-			iID, _ := strconv.ParseInt(idesmstr, 10, 64)
-			h.Log.Debug("shipping method id in edit", iID)
-			var esmgp ShipMethPage
-			esmgp.Error = eipErr
-			esmgp.ShippingMethod = h.API.GetShippingMethod(iID, hd)
-			h.AdminTemplates.ExecuteTemplate(w, adminEditShippingMethodPage, &esmgp)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	hd := h.getAdminHeader(r)
+	eipErr := r.URL.Query().Get("error")
+	esmvars := mux.Vars(r)
+	idesmstr := esmvars["id"]
+	iID, _ := strconv.ParseInt(idesmstr, 10, 64)
+	h.Log.Debug("shipping method id in edit", iID)
+	var esmgp ShipMethPage
+	esmgp.Error = eipErr
+	esmgp.ShippingMethod = h.API.GetShippingMethod(iID, hd)
+	h.executeAdminTemplate(w, r, adminEditShippingMethodPage, &esmgp)
 }
 
 //StoreAdminEditShippingMethod StoreAdminEditShippingMethod
 func (h *Six910Handler) StoreAdminEditShippingMethod(w http.ResponseWriter, r *http.Request) {
-	esmms, suc := h.getSession(r)
-	h.Log.Debug("session suc in shipping method edit", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(esmms) {
-			esmm := h.processShippingMethod(r)
-			h.Log.Debug("shipping method update", *esmm)
-			hd := h.getHeader(esmms)
-			res := h.API.UpdateShippingMethod(esmm, hd)
-			h.Log.Debug("shipping method update resp", *res)
-			if res.Success {
-				http.Redirect(w, r, adminShippingMethodListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminShippingMethodListViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	esmm, fe := h.processShippingMethod(r)
+	if len(fe) > 0 {
+		var fp ShipMethPage
+		fp.Error = invalidForm
+		fp.ShippingMethod = esmm
+		fp.FormErrors = formErrors(r, fe)
+		h.executeAdminTemplate(w, r, adminEditShippingMethodPage, &fp)
+		return
+	}
+	h.Log.Debug("shipping method update", *esmm)
+	hd := h.getAdminHeader(r)
+	ae := h.auditBefore(r, auditShippingMethod, esmm.ID, func() interface{} { return h.API.GetShippingMethod(esmm.ID, hd) })
+	res := h.API.UpdateShippingMethod(esmm, hd)
+	h.Log.Debug("shipping method update resp", *res)
+	if res.Success {
+		ae.updated(esmm)
+		http.Redirect(w, r, adminShippingMethodListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminShippingMethodListViewFail, http.StatusFound)
 	}
 }
 
 //StoreAdminViewShippingMethodList StoreAdminViewShippingMethodList
 func (h *Six910Handler) StoreAdminViewShippingMethodList(w http.ResponseWriter, r *http.Request) {
-	gsmls, suc := h.getSession(r)
-	h.Log.Debug("session suc in shipping method view", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(gsmls) {
-			hd := h.getHeader(gsmls)
-			smsl := h.API.GetShippingMethodList(hd)
-			h.Log.Debug("shipping method  in list", smsl)
-			h.AdminTemplates.ExecuteTemplate(w, adminShippingMethodListPage, &smsl)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	hd := h.getAdminHeader(r)
+	smsl := h.API.GetShippingMethodList(hd)
+	h.Log.Debug("shipping method  in list", smsl)
+	h.executeAdminTemplate(w, r, adminShippingMethodListPage, &smsl)
 }
 
 //StoreAdminDeleteShippingMethod StoreAdminDeleteShippingMethod
 func (h *Six910Handler) StoreAdminDeleteShippingMethod(w http.ResponseWriter, r *http.Request) {
-	dsms, suc := h.getSession(r)
-	h.Log.Debug("session suc in shipping method list delete", suc)
-	if suc {
-		if h.isStoreAdminLoggedIn(dsms) {
-			hd := h.getHeader(dsms)
-			dsmvars := mux.Vars(r)
-			idsmstrd := dsmvars["id"]
-			idddsm, _ := strconv.ParseInt(idsmstrd, 10, 64)
-			res := h.API.DeleteShippingMethod(idddsm, hd)
-			h.Log.Debug("shipping method delete resp", *res)
-			if res.Success {
-				http.Redirect(w, r, adminShippingMethodListView, http.StatusFound)
-			} else {
-				http.Redirect(w, r, adminShippingMethodListViewFail, http.StatusFound)
-			}
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
+	hd := h.getAdminHeader(r)
+	dsmvars := mux.Vars(r)
+	idsmstrd := dsmvars["id"]
+	idddsm, _ := strconv.ParseInt(idsmstrd, 10, 64)
+	ae := h.auditBefore(r, auditShippingMethod, idddsm, func() interface{} { return h.API.GetShippingMethod(idddsm, hd) })
+	res := h.API.DeleteShippingMethod(idddsm, hd)
+	h.Log.Debug("shipping method delete resp", *res)
+	if res.Success {
+		ae.deleted()
+		http.Redirect(w, r, adminShippingMethodListView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminShippingMethodListViewFail, http.StatusFound)
 	}
 }
 
-func (h *Six910Handler) processShippingMethod(r *http.Request) *sdbi.ShippingMethod {
+func (h *Six910Handler) processShippingMethod(r *http.Request) (*sdbi.ShippingMethod, FieldErrors) {
 	var s sdbi.ShippingMethod
-	id := r.FormValue("id")
-	s.ID, _ = strconv.ParseInt(id, 10, 64)
+	f := newFormReader(r)
+	s.ID = f.int("id")
 	s.Name = r.FormValue("name")
-	cost := r.FormValue("cost")
-	s.Cost, _ = strconv.ParseFloat(cost, 64)
-	maxWeight := r.FormValue("maxWeight")
-	s.MaxWeight, _ = strconv.ParseInt(maxWeight, 10, 64)
-	handling := r.FormValue("handling")
-	s.Handling, _ = strconv.ParseFloat(handling, 64)
-	min := r.FormValue("minOrderAmount")
-	s.MinOrderAmount, _ = strconv.ParseFloat(min, 64)
-	max := r.FormValue("maxOrderAmount")
-	s.MaxOrderAmount, _ = strconv.ParseFloat(max, 64)
-	storeID := r.FormValue("storeId")
-	s.StoreID, _ = strconv.ParseInt(storeID, 10, 64)
-	regionID := r.FormValue("regionId")
-	s.RegionID, _ = strconv.ParseInt(regionID, 10, 64)
-	shippingCarrierID := r.FormValue("shippingCarrierId")
-	s.ShippingCarrierID, _ = strconv.ParseInt(shippingCarrierID, 10, 64)
-	insuranceID := r.FormValue("insuranceId")
-	s.InsuranceID, _ = strconv.ParseInt(insuranceID, 10, 64)
+	s.Cost = f.float("cost")
+	s.MaxWeight = f.int("maxWeight")
+	s.Handling = f.float("handling")
+	s.MinOrderAmount = f.float("minOrderAmount")
+	s.MaxOrderAmount = f.float("maxOrderAmount")
+	s.StoreID = f.int("storeId")
+	s.RegionID = f.int("regionId")
+	s.ShippingCarrierID = f.int("shippingCarrierId")
+	s.InsuranceID = f.int("insuranceId")
 
-	return &s
+	return &s, f.check(validateShippingMethod(&s))
 }
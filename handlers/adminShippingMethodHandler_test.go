@@ -56,13 +56,11 @@ func TestSix910Handler_StoreAdminAddShippingMethodPage(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddShippingMethodPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddShippingMethodPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -112,11 +110,9 @@ func TestSix910Handler_StoreAdminAddShippingMethodPageLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddShippingMethodPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddShippingMethodPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -153,18 +149,16 @@ func TestSix910Handler_StoreAdminAddShippingMethod(t *testing.T) {
 
 	//-----------end mocking --------
 
-	r, _ := http.NewRequest("POST", "https://test.com", strings.NewReader("name=ups ground&cost=11.23&maxOrderAmount=300.50"))
+	r, _ := http.NewRequest("POST", "https://test.com", strings.NewReader("name=ups ground&cost=11.23&maxOrderAmount=300.50&regionId=2&shippingCarrierId=3"))
 	r.Header.Set("Content-Type", "application/x-www-form-urlencoded; param=value")
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddShippingMethod(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddShippingMethod)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -208,11 +202,9 @@ func TestSix910Handler_StoreAdminAddShippingMethodLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddShippingMethod(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddShippingMethod)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -249,18 +241,16 @@ func TestSix910Handler_StoreAdminAddShippingMethodFail(t *testing.T) {
 
 	//-----------end mocking --------
 
-	r, _ := http.NewRequest("POST", "https://test.com", strings.NewReader("name=ups ground&cost=11.23&maxOrderAmount=300.50"))
+	r, _ := http.NewRequest("POST", "https://test.com", strings.NewReader("name=ups ground&cost=11.23&maxOrderAmount=300.50&regionId=2&shippingCarrierId=3"))
 	r.Header.Set("Content-Type", "application/x-www-form-urlencoded; param=value")
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminAddShippingMethod(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminAddShippingMethod)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -310,13 +300,11 @@ func TestSix910Handler_StoreAdminEditShippingMethodPage(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditShippingMethodPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditShippingMethodPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -368,11 +356,9 @@ func TestSix910Handler_StoreAdminEditShippingMethodPageLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditShippingMethodPage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditShippingMethodPage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -408,18 +394,16 @@ func TestSix910Handler_StoreAdminEditShippingMethod(t *testing.T) {
 
 	//-----------end mocking --------
 
-	r, _ := http.NewRequest("PUT", "https://test.com", strings.NewReader("id=3&cost=11.23&maxOrderAmount=300.50"))
+	r, _ := http.NewRequest("PUT", "https://test.com", strings.NewReader("id=3&name=ups ground&cost=11.23&maxOrderAmount=300.50&regionId=2&shippingCarrierId=3"))
 	r.Header.Set("Content-Type", "application/x-www-form-urlencoded; param=value")
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditShippingMethod(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditShippingMethod)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -462,11 +446,9 @@ func TestSix910Handler_StoreAdminEditShippingMethodLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditShippingMethod(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditShippingMethod)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -502,18 +484,16 @@ func TestSix910Handler_StoreAdminEditShippingMethodFail(t *testing.T) {
 
 	//-----------end mocking --------
 
-	r, _ := http.NewRequest("PUT", "https://test.com", strings.NewReader("id=3&cost=11.23&maxOrderAmount=300.50"))
+	r, _ := http.NewRequest("PUT", "https://test.com", strings.NewReader("id=3&name=ups ground&cost=11.23&maxOrderAmount=300.50&regionId=2&shippingCarrierId=3"))
 	r.Header.Set("Content-Type", "application/x-www-form-urlencoded; param=value")
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminEditShippingMethod(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditShippingMethod)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -562,13 +542,11 @@ func TestSix910Handler_StoreAdminViewShippingMethodList(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminViewShippingMethodList(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminViewShippingMethodList)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -619,11 +597,9 @@ func TestSix910Handler_StoreAdminViewShippingMethodListLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminViewShippingMethodList(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminViewShippingMethodList)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -667,13 +643,11 @@ func TestSix910Handler_StoreAdminDeleteShippingMethod(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminDeleteShippingMethod(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminDeleteShippingMethod)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -719,11 +693,9 @@ func TestSix910Handler_StoreAdminDeleteShippingMethodLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	//s.Values["loggedIn"] = true
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminDeleteShippingMethod(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminDeleteShippingMethod)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -767,13 +739,11 @@ func TestSix910Handler_StoreAdminDeleteShippingMethodfail(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminDeleteShippingMethod(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminDeleteShippingMethod)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
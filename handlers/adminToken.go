@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	ss "github.com/Ulbora/Six910-ui/sessrv"
+	oauth2 "github.com/Ulbora/go-oauth2-client"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+//tokenRefreshMargin is how long before expiry an access token is refreshed
+const tokenRefreshMargin = time.Minute
+
+//setAdminToken copies t into the admin session. A token without
+//expires_in is treated as never expiring.
+func setAdminToken(as *ss.AdminSession, t *oauth2.Token) {
+	as.AccessToken = t.AccessToken
+	if t.RefreshToken != "" {
+		as.RefreshToken = t.RefreshToken
+	}
+	as.TokenExpires = time.Time{}
+	if t.ExpiresIn > 0 {
+		as.TokenExpires = time.Now().Add(time.Duration(t.ExpiresIn) * time.Second)
+	}
+}
+
+//tokenClaims tokenClaims are the access token claims that can name the
+//admin; the Ulbora OAuth2 server puts the username in userId
+type tokenClaims struct {
+	UserID   string `json:"userId"`
+	Username string `json:"username"`
+	Subject  string `json:"sub"`
+}
+
+//tokenUsername reads the admin username from a JWT access token. The
+//signature is not checked: the token comes straight from OauthHost in the
+//code exchange, never from the browser. It returns "" for a token that
+//is not a JWT or names nobody.
+func tokenUsername(accessToken string) string {
+	var rtn string
+	parts := strings.Split(accessToken, ".")
+	if len(parts) != 3 {
+		return rtn
+	}
+	pl, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return rtn
+	}
+	var c tokenClaims
+	if json.Unmarshal(pl, &c) == nil {
+		switch {
+		case c.UserID != "":
+			rtn = c.UserID
+		case c.Username != "":
+			rtn = c.Username
+		default:
+			rtn = c.Subject
+		}
+	}
+	return rtn
+}
+
+//refreshAdminToken gets a new access token with the refresh token when the
+//one in as is about to expire. It returns false when the session has no
+//usable token left and the admin has to log in again.
+func (h *Six910Handler) refreshAdminToken(as *ss.AdminSession) bool {
+	if as.AccessToken == "" {
+		return false
+	}
+	if as.TokenExpires.IsZero() || time.Until(as.TokenExpires) > tokenRefreshMargin {
+		return true
+	}
+	if as.RefreshToken != "" {
+		h.authMu.Lock()
+		h.Auth.SetOauthHost(h.OauthHost)
+		h.Auth.SetClientID(h.ClientCreds.AuthCodeClient)
+		h.Auth.SetSecret(h.ClientCreds.AuthCodeSecret)
+		h.Auth.SetRefreshToken(as.RefreshToken)
+		resp := h.Auth.AuthCodeRefreshToken()
+		h.authMu.Unlock()
+		if resp != nil && resp.AccessToken != "" {
+			setAdminToken(as, resp)
+			h.AdminSessions.Update(as)
+			h.Log.Debug("access token refreshed for session: ", as.Ref())
+			return true
+		}
+		h.Log.Info("access token refresh failed for session: ", as.Ref())
+	}
+	return time.Now().Before(as.TokenExpires)
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	lg "github.com/Ulbora/Level_Logger"
+	ss "github.com/Ulbora/Six910-ui/sessrv"
+	oauth2 "github.com/Ulbora/go-oauth2-client"
+)
+
+func tokenTestHandler(refreshed *oauth2.Token) *Six910Handler {
+	var sh Six910Handler
+	var l lg.Logger
+	l.LogLevel = lg.AllLevel
+	sh.Log = &l
+	sh.OAuth2Enabled = true
+	var cc ClientCreds
+	cc.AuthCodeClient = "2"
+	cc.AuthCodeSecret = "12345"
+	sh.ClientCreds = &cc
+	var mockAcTkn oauth2.MockAuthCodeToken
+	mockAcTkn.MockToken = refreshed
+	sh.Auth = &mockAcTkn
+	var ms ss.MemoryStore
+	sh.AdminSessions = ms.GetNew()
+	return &sh
+}
+
+func TestSix910Handler_refreshAdminToken(t *testing.T) {
+	var nt oauth2.Token
+	nt.AccessToken = "newtoken"
+	nt.ExpiresIn = 3600
+	sh := tokenTestHandler(&nt)
+
+	var ot oauth2.Token
+	ot.AccessToken = "oldtoken"
+	ot.RefreshToken = "refresh1"
+	ot.ExpiresIn = 30
+	var as ss.AdminSession
+	setAdminToken(&as, &ot)
+	nas := sh.AdminSessions.Create(&as)
+	suc := sh.refreshAdminToken(nas)
+	fmt.Println("token: ", nas.AccessToken)
+	if !suc || nas.AccessToken != "newtoken" || nas.RefreshToken != "refresh1" ||
+		sh.AdminSessions.Get(nas.ID).AccessToken != "newtoken" || time.Until(nas.TokenExpires) < time.Hour-time.Minute {
+		t.Fail()
+	}
+	if sh.Auth.(*oauth2.MockAuthCodeToken).RefreshToken != "refresh1" {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_refreshAdminTokenNotDue(t *testing.T) {
+	var nt oauth2.Token
+	nt.AccessToken = "newtoken"
+	sh := tokenTestHandler(&nt)
+
+	var as ss.AdminSession
+	as.AccessToken = "oldtoken"
+	as.RefreshToken = "refresh1"
+	as.TokenExpires = time.Now().Add(time.Hour)
+	if !sh.refreshAdminToken(&as) || as.AccessToken != "oldtoken" {
+		t.Fail()
+	}
+	as.TokenExpires = time.Time{}
+	if !sh.refreshAdminToken(&as) || as.AccessToken != "oldtoken" {
+		t.Fail()
+	}
+	var none ss.AdminSession
+	if sh.refreshAdminToken(&none) {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_refreshAdminTokenFailed(t *testing.T) {
+	var nt oauth2.Token
+	sh := tokenTestHandler(&nt)
+
+	var as ss.AdminSession
+	as.AccessToken = "oldtoken"
+	as.RefreshToken = "refresh1"
+	as.TokenExpires = time.Now().Add(30 * time.Second)
+	if !sh.refreshAdminToken(&as) || as.AccessToken != "oldtoken" {
+		t.Fail()
+	}
+	as.TokenExpires = time.Now().Add(-time.Second)
+	if sh.refreshAdminToken(&as) {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_AdminAuthTokenPerSession(t *testing.T) {
+	var nt oauth2.Token
+	sh := tokenTestHandler(&nt)
+	var auth string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = fmt.Sprint(sh.getAdminHeader(r))
+	})
+	h := sh.GetNew()
+
+	var t1, t2 oauth2.Token
+	t1.AccessToken = "token1"
+	t2.AccessToken = "token2"
+	r1, _ := http.NewRequest("GET", "/admin/index", nil)
+	s1, _ := sh.getSession(r1)
+	s1.Values["storeAdminUser"] = true
+	loginTestOAuthAdmin(sh, s1, &t1)
+	r2, _ := http.NewRequest("GET", "/admin/index", nil)
+	s2, _ := sh.getSession(r2)
+	s2.Values["storeAdminUser"] = true
+	loginTestOAuthAdmin(sh, s2, &t2)
+
+	h.AdminAuth(next).ServeHTTP(httptest.NewRecorder(), r1)
+	a1 := auth
+	h.AdminAuth(next).ServeHTTP(httptest.NewRecorder(), r2)
+	fmt.Println("headers: ", a1, auth)
+	if !strings.Contains(a1, "Bearer token1") || !strings.Contains(auth, "Bearer token2") {
+		t.Fail()
+	}
+}
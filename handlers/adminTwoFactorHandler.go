@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	thr "github.com/Ulbora/Six910-ui/throtsrv"
+	"github.com/gorilla/mux"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+const (
+	//twoFactorTimeout is how long a password login waits for the code
+	twoFactorTimeout = 5 * time.Minute
+
+	//twoFactorMaxFailures wrong codes end the pending login
+	twoFactorMaxFailures = 5
+)
+
+//TwoFactorPage TwoFactorPage
+type TwoFactorPage struct {
+	Error           string
+	Username        string
+	Enrolled        bool
+	Secret          string
+	ProvisioningURI string
+	RecoveryCodes   []string
+	SuperAdmin      bool
+}
+
+//TwoFactorUsersPage TwoFactorUsersPage
+type TwoFactorUsersPage struct {
+	Error string
+	Users []string
+}
+
+//StoreAdminLoginTwoFactorPage StoreAdminLoginTwoFactorPage
+func (h *Six910Handler) StoreAdminLoginTwoFactorPage(w http.ResponseWriter, r *http.Request) {
+	var lge ProcError
+	lge.Error = r.URL.Query().Get("error")
+	h.executeAdminTemplate(w, r, adminLoginTwoFactorPage, &lge)
+}
+
+//StoreAdminLoginTwoFactor StoreAdminLoginTwoFactor finishes a password
+//login for an admin with two factor turned on. The code can be from the
+//authenticator app or a recovery code.
+func (h *Six910Handler) StoreAdminLoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	s, suc := h.getSession(r)
+	if !suc {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	id, _ := s.Values[adminSessionIDKey].(string)
+	as := h.AdminSessions.Get(id)
+	if as == nil || !as.TwoFactorPending || h.TwoFactorService == nil {
+		http.Redirect(w, r, adminLogin, http.StatusFound)
+		return
+	}
+	if time.Since(as.Created) > twoFactorTimeout {
+		h.AdminSessions.Delete(id)
+		http.Redirect(w, r, adminLoginFailedURL, http.StatusFound)
+		return
+	}
+	if h.TwoFactorService.Verify(as.Username, r.FormValue("code")) {
+		h.LoginThrottle.Reset(thr.UserKey(adminScope, as.Username))
+		as.TwoFactorPending = false
+		as.TwoFactorFailures = 0
+		serr := h.startAdminSession(w, r, s, as)
+		h.Log.Debug("serr", serr)
+		http.Redirect(w, r, adminIndex, http.StatusFound)
+		return
+	}
+	as.TwoFactorFailures++
+	h.LoginThrottle.Fail(adminLoginKeys(as.Username, r)...)
+	h.Log.Info("two factor code failed for: ", as.Username)
+	if as.TwoFactorFailures >= twoFactorMaxFailures {
+		h.AdminSessions.Delete(id)
+		http.Redirect(w, r, adminLoginFailedURL, http.StatusFound)
+	} else {
+		h.AdminSessions.Update(as)
+		http.Redirect(w, r, adminLoginTwoFactorFail, http.StatusFound)
+	}
+}
+
+//StoreAdminTwoFactorPage StoreAdminTwoFactorPage
+func (h *Six910Handler) StoreAdminTwoFactorPage(w http.ResponseWriter, r *http.Request) {
+	tp := h.newTwoFactorPage(r)
+	if tp == nil {
+		http.Redirect(w, r, adminIndex, http.StatusFound)
+		return
+	}
+	h.executeAdminTemplate(w, r, adminTwoFactorPage, tp)
+}
+
+//StoreAdminStartTwoFactor StoreAdminStartTwoFactor makes a new secret and
+//shows it with the provisioning URI for the QR code
+func (h *Six910Handler) StoreAdminStartTwoFactor(w http.ResponseWriter, r *http.Request) {
+	tp := h.newTwoFactorPage(r)
+	if tp == nil || tp.Enrolled {
+		http.Redirect(w, r, adminTwoFactorView, http.StatusFound)
+		return
+	}
+	e := h.TwoFactorService.StartEnrollment(tp.Username)
+	if e == nil {
+		http.Redirect(w, r, adminTwoFactorView, http.StatusFound)
+		return
+	}
+	tp.Secret = e.Secret
+	tp.ProvisioningURI = h.TwoFactorService.ProvisioningURI(e)
+	h.executeAdminTemplate(w, r, adminTwoFactorPage, tp)
+}
+
+//StoreAdminConfirmTwoFactor StoreAdminConfirmTwoFactor turns two factor on
+//and shows the recovery codes once
+func (h *Six910Handler) StoreAdminConfirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	tp := h.newTwoFactorPage(r)
+	if tp == nil {
+		http.Redirect(w, r, adminIndex, http.StatusFound)
+		return
+	}
+	codes, suc := h.TwoFactorService.ConfirmEnrollment(tp.Username, r.FormValue("code"))
+	if !suc {
+		http.Redirect(w, r, adminTwoFactorViewFail, http.StatusFound)
+		return
+	}
+	h.Log.Info("two factor turned on for: ", tp.Username)
+	tp.Enrolled = true
+	tp.RecoveryCodes = codes
+	h.executeAdminTemplate(w, r, adminTwoFactorPage, tp)
+}
+
+//StoreAdminDisableTwoFactor StoreAdminDisableTwoFactor turns two factor
+//off for the logged in admin after checking a current code
+func (h *Six910Handler) StoreAdminDisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	tp := h.newTwoFactorPage(r)
+	if tp == nil {
+		http.Redirect(w, r, adminIndex, http.StatusFound)
+		return
+	}
+	if h.TwoFactorService.Verify(tp.Username, r.FormValue("code")) && h.TwoFactorService.Reset(tp.Username) {
+		h.Log.Info("two factor turned off by: ", tp.Username)
+		http.Redirect(w, r, adminTwoFactorView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminTwoFactorViewFail, http.StatusFound)
+	}
+}
+
+//StoreAdminViewTwoFactorUsers StoreAdminViewTwoFactorUsers lists admins
+//with two factor turned on; super admins only
+func (h *Six910Handler) StoreAdminViewTwoFactorUsers(w http.ResponseWriter, r *http.Request) {
+	if !h.requireSuperAdmin(w, r) {
+		return
+	}
+	var up TwoFactorUsersPage
+	up.Error = r.URL.Query().Get("error")
+	up.Users = h.TwoFactorService.ListEnrolled()
+	h.executeAdminTemplate(w, r, adminTwoFactorUsersPage, &up)
+}
+
+//StoreAdminResetTwoFactor StoreAdminResetTwoFactor removes the two factor
+//setup of another admin who lost their device; super admins only
+func (h *Six910Handler) StoreAdminResetTwoFactor(w http.ResponseWriter, r *http.Request) {
+	if !h.requireSuperAdmin(w, r) {
+		return
+	}
+	username := mux.Vars(r)["username"]
+	if h.TwoFactorService.Reset(username) {
+		h.Log.Info("two factor reset for: ", username, " by: ", h.getAdminUser(r).Username)
+		http.Redirect(w, r, adminTwoFactorUsersView, http.StatusFound)
+	} else {
+		http.Redirect(w, r, adminTwoFactorUsersViewFail, http.StatusFound)
+	}
+}
+
+//newTwoFactorPage returns nil when two factor is not available, which is
+//when it is not configured or OAuth2 logins are used
+func (h *Six910Handler) newTwoFactorPage(r *http.Request) *TwoFactorPage {
+	as := h.getAdminUser(r)
+	if h.TwoFactorService == nil || h.OAuth2Enabled || as == nil || as.Username == "" {
+		return nil
+	}
+	var tp TwoFactorPage
+	tp.Error = r.URL.Query().Get("error")
+	tp.Username = as.Username
+	tp.Enrolled = h.TwoFactorService.Enrolled(as.Username)
+	tp.SuperAdmin = h.isSuperAdmin(as.Username)
+	return &tp
+}
+
+func (h *Six910Handler) requireSuperAdmin(w http.ResponseWriter, r *http.Request) bool {
+	as := h.getAdminUser(r)
+	if h.TwoFactorService == nil || as == nil || !h.isSuperAdmin(as.Username) {
+		h.Log.Info("super admin page denied: ", r.URL.Path)
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return false
+	}
+	return true
+}
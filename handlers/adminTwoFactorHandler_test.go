@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	lg "github.com/Ulbora/Level_Logger"
+	totps "github.com/Ulbora/Six910-ui/totpsrv"
+	"github.com/gorilla/mux"
+)
+
+var twoFactorTestTemplates = template.Must(template.New("admin").Parse(
+	`{{define "twoFactor.html"}}{{.Secret}}|{{len .RecoveryCodes}}{{end}}` +
+		`{{define "twoFactorUsers.html"}}{{range .Users}}[{{.}}]{{end}}{{end}}`))
+
+func postForm(path string, form url.Values) *http.Request {
+	r, _ := http.NewRequest("POST", path, strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+func TestSix910Handler_TwoFactorEnrollAndLogin(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	useTwoFactor(sh, sapi, dir)
+	sh.AdminTemplates = twoFactorTestTemplates
+	defer os.RemoveAll(dir)
+	h := sh.GetNew()
+
+	r, _ := http.NewRequest("POST", "/admin/twoFactorEnroll", nil)
+	s, _ := sh.getSession(r)
+	loginTestAdmin(sh, s)
+	s.Values["storeAdminUser"] = true
+	w := httptest.NewRecorder()
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminStartTwoFactor)).ServeHTTP(w, r)
+	secret := strings.Split(w.Body.String(), "|")[0]
+	fmt.Println("secret: ", secret)
+	if w.Code != 200 || len(secret) != 32 {
+		t.FailNow()
+	}
+
+	code, _ := totps.Code(secret, time.Now().Add(-30*time.Second))
+	r2 := postForm("/admin/twoFactorConfirm", url.Values{"code": {code}})
+	s2, _ := sh.getSession(r2)
+	loginTestAdmin(sh, s2)
+	s2.Values["storeAdminUser"] = true
+	w2 := httptest.NewRecorder()
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminConfirmTwoFactor)).ServeHTTP(w2, r2)
+	if w2.Body.String() != "|10" || !sh.TwoFactorService.Enrolled("tester") {
+		t.FailNow()
+	}
+
+	//password alone does not log in
+	r3 := postForm("/admin/login", url.Values{"username": {"tester"}, "password": {"tester"}})
+	w3 := httptest.NewRecorder()
+	h.StoreAdminLoginNonOAuthUser(w3, r3)
+	s3, _ := sh.getSession(r3)
+	if w3.Header().Get("Location") != adminLoginTwoFactor || s3.Values["storeAdminUser"] != nil || sh.getStoreAdmin(s3) != nil {
+		t.Fail()
+	}
+	pid, _ := s3.Values[adminSessionIDKey].(string)
+
+	r3.Form.Set("code", "000000")
+	w4 := httptest.NewRecorder()
+	h.StoreAdminLoginTwoFactor(w4, r3)
+	if w4.Header().Get("Location") != adminLoginTwoFactorFail || sh.AdminSessions.Get(pid).TwoFactorFailures != 1 {
+		t.Fail()
+	}
+
+	now, _ := totps.Code(secret, time.Now())
+	r3.Form.Set("code", now)
+	w5 := httptest.NewRecorder()
+	h.StoreAdminLoginTwoFactor(w5, r3)
+	as := sh.getStoreAdmin(s3)
+	if w5.Header().Get("Location") != adminIndex || as == nil || as.ID == pid || as.Password != "tester" {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_TwoFactorLoginTooManyFailures(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	useTwoFactor(sh, sapi, dir)
+	sh.AdminTemplates = twoFactorTestTemplates
+	defer os.RemoveAll(dir)
+	h := sh.GetNew()
+	e := sh.TwoFactorService.StartEnrollment("tester")
+	c, _ := totps.Code(e.Secret, time.Now())
+	sh.TwoFactorService.ConfirmEnrollment("tester", c)
+
+	r := postForm("/admin/login", url.Values{"username": {"tester"}, "password": {"tester"}})
+	h.StoreAdminLoginNonOAuthUser(httptest.NewRecorder(), r)
+	s, _ := sh.getSession(r)
+	pid, _ := s.Values[adminSessionIDKey].(string)
+	r.Form.Set("code", "123")
+	var w *httptest.ResponseRecorder
+	for i := 0; i < twoFactorMaxFailures; i++ {
+		w = httptest.NewRecorder()
+		h.StoreAdminLoginTwoFactor(w, r)
+	}
+	if w.Header().Get("Location") != adminLoginFailedURL || sh.AdminSessions.Get(pid) != nil {
+		t.Fail()
+	}
+	w2 := httptest.NewRecorder()
+	h.StoreAdminLoginTwoFactor(w2, r)
+	if w2.Header().Get("Location") != adminLogin {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_TwoFactorSuperAdminReset(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	useTwoFactor(sh, sapi, dir)
+	sh.AdminTemplates = twoFactorTestTemplates
+	defer os.RemoveAll(dir)
+	h := sh.GetNew()
+	e := sh.TwoFactorService.StartEnrollment("tester")
+	c, _ := totps.Code(e.Secret, time.Now())
+	sh.TwoFactorService.ConfirmEnrollment("tester", c)
+
+	r, _ := http.NewRequest("GET", "/admin/twoFactorUsersView", nil)
+	s, _ := sh.getSession(r)
+	loginTestAdmin(sh, s)
+	s.Values["storeAdminUser"] = true
+	w := httptest.NewRecorder()
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminViewTwoFactorUsers)).ServeHTTP(w, r)
+	if w.Code != http.StatusForbidden {
+		t.Fail()
+	}
+
+	as := sh.getStoreAdmin(s)
+	as.Username = "super"
+	sh.AdminSessions.Update(as)
+	w2 := httptest.NewRecorder()
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminViewTwoFactorUsers)).ServeHTTP(w2, r)
+	if w2.Code != 200 || w2.Body.String() != "[tester]" {
+		t.Fail()
+	}
+
+	r3, _ := http.NewRequest("POST", "/admin/twoFactorReset/tester", nil)
+	r3 = mux.SetURLVars(r3, map[string]string{"username": "tester"})
+	s3, _ := sh.getSession(r3)
+	s3.Values = s.Values
+	w3 := httptest.NewRecorder()
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminResetTwoFactor)).ServeHTTP(w3, r3)
+	if w3.Header().Get("Location") != adminTwoFactorUsersView || sh.TwoFactorService.Enrolled("tester") {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_TwoFactorNotConfigured(t *testing.T) {
+	var sh Six910Handler
+	var l lg.Logger
+	sh.Log = &l
+	r, _ := http.NewRequest("GET", "/admin/twoFactorView", nil)
+	s, _ := sh.getSession(r)
+	loginTestAdmin(&sh, s)
+	s.Values["storeAdminUser"] = true
+	w := httptest.NewRecorder()
+	h := sh.GetNew()
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminTwoFactorPage)).ServeHTTP(w, r)
+	if w.Code != 302 || w.Header().Get("Location") != adminIndex {
+		t.Fail()
+	}
+}
@@ -0,0 +1,533 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	rsts "github.com/Ulbora/Six910-ui/resetsrv"
+	userv "github.com/Ulbora/Six910-ui/usersrv"
+	api "github.com/Ulbora/Six910API-Go"
+	"github.com/gorilla/mux"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+const (
+	adminUserNotFound    = "Admin user not found"
+	adminUserSelf        = "You can not disable your own login"
+	adminUserResetOff    = "Password reset is not turned on"
+	adminUserResetFailed = "Password reset failed"
+	adminUserTaken       = "That username is already taken"
+	adminUserHostDown    = "The user service could not be reached"
+	adminUserRejected    = "The user service did not make the change"
+	adminUserBasicNote   = "With Basic auth admins log in with their email; names, email and role can only be edited with OAuth2"
+
+	//adminUserPageSize is how many OAuth2 users are listed per page
+	adminUserPageSize = 50
+)
+
+//AdminUserRow AdminUserRow is one store admin. Name and email are only
+//known in OAuth2 mode; Basic auth admins log in with their email.
+type AdminUserRow struct {
+	Username     string
+	FirstName    string
+	LastName     string
+	EmailAddress string
+	Enabled      bool
+	RoleID       int64
+	Current      bool
+}
+
+//AdminUserPage AdminUserPage. Start, Prev, Next and More page through
+//OAuth2 users; Basic auth lists every admin at once. Note says what can
+//not be edited in Basic auth mode.
+type AdminUserPage struct {
+	Error    string
+	Message  string
+	Note     string
+	OAuth2   bool
+	CanReset bool
+	Search   string
+	Users    []AdminUserRow
+	User     *AdminUserRow
+	Roles    *[]userv.Role
+	Start    int64
+	Prev     int64
+	Next     int64
+	More     bool
+}
+
+//StoreAdminViewAdminUserList StoreAdminViewAdminUserList lists the store
+//admins. In OAuth2 mode the users of the client are paged and search
+//finds a user by email or username.
+func (h *Six910Handler) StoreAdminViewAdminUserList(w http.ResponseWriter, r *http.Request) {
+	up := h.newAdminUserPage(r)
+	if h.OAuth2Enabled {
+		h.oauthAdminUsers(r, up)
+	} else {
+		hd := h.getAdminHeader(r)
+		if aul := h.API.GetAdminUsers(hd); aul != nil {
+			for _, au := range *aul {
+				up.Users = append(up.Users, basicAdminRow(&au))
+			}
+		}
+	}
+	cur := h.getAdminUser(r)
+	for i := range up.Users {
+		up.Users[i].Current = cur != nil && up.Users[i].Username == cur.Username
+	}
+	h.Log.Debug("admin users in list: ", len(up.Users))
+	h.executeAdminTemplate(w, r, adminUserListPage, up)
+}
+
+//StoreAdminAddAdminUserPage StoreAdminAddAdminUserPage
+func (h *Six910Handler) StoreAdminAddAdminUserPage(w http.ResponseWriter, r *http.Request) {
+	up := h.newAdminUserPage(r)
+	up.Roles = h.adminRoles(r)
+	h.executeAdminTemplate(w, r, adminAddAdminUserPage, up)
+}
+
+//StoreAdminAddAdminUser StoreAdminAddAdminUser adds an enabled store admin
+func (h *Six910Handler) StoreAdminAddAdminUser(w http.ResponseWriter, r *http.Request) {
+	username := strings.TrimSpace(r.FormValue("username"))
+	pw := r.FormValue("password")
+	if username == "" || len(pw) < minPasswordLength || pw != r.FormValue("confirmPassword") {
+		http.Redirect(w, r, adminAddAdminUserView+"?error="+url.QueryEscape(resetPasswordInvalid), http.StatusFound)
+		return
+	}
+	if h.OAuth2Enabled {
+		h.addOauthAdminUser(w, r, username, pw)
+		return
+	}
+	var u api.User
+	u.Username = username
+	u.Password = pw
+	u.Role = storeAdmin
+	u.Enabled = true
+	res := h.API.AddCustomerUser(&u, h.getAdminHeader(r))
+	h.Log.Debug("admin user add resp: ", res)
+	if res == nil || !res.Success {
+		http.Redirect(w, r, adminAddAdminUserViewFail, http.StatusFound)
+		return
+	}
+	h.Log.Info("admin user added: ", username)
+	h.auditCreateKey(r, auditAdminUser, username, &u)
+	http.Redirect(w, r, adminUserListView, http.StatusFound)
+}
+
+//StoreAdminEditAdminUserPage StoreAdminEditAdminUserPage
+func (h *Six910Handler) StoreAdminEditAdminUserPage(w http.ResponseWriter, r *http.Request) {
+	up := h.newAdminUserPage(r)
+	up.User = h.getAdminUserRow(r, mux.Vars(r)["username"])
+	if up.User == nil {
+		http.Redirect(w, r, adminUserListView+"?error="+url.QueryEscape(adminUserNotFound), http.StatusFound)
+		return
+	}
+	up.Roles = h.adminRoles(r)
+	h.executeAdminTemplate(w, r, adminEditAdminUserPage, up)
+}
+
+//StoreAdminEditAdminUser StoreAdminEditAdminUser saves name and email in
+//OAuth2 mode and the enabled flag in both modes
+func (h *Six910Handler) StoreAdminEditAdminUser(w http.ResponseWriter, r *http.Request) {
+	username := r.FormValue("username")
+	back := adminEditAdminUserView + "/" + url.PathEscape(username)
+	eu := h.getAdminUserRow(r, username)
+	if eu == nil {
+		http.Redirect(w, r, adminUserListView+"?error="+url.QueryEscape(adminUserNotFound), http.StatusFound)
+		return
+	}
+	enabled, _ := strconv.ParseBool(r.FormValue("enabled"))
+	if !enabled && h.isCurrentAdmin(r, username) {
+		http.Redirect(w, r, back+"?error="+url.QueryEscape(adminUserSelf), http.StatusFound)
+		return
+	}
+	ae := h.auditBeforeKey(r, auditAdminUser, username, func() interface{} { return eu })
+	var nu = *eu
+	nu.Enabled = enabled
+	var suc = true
+	if h.OAuth2Enabled {
+		nu.FirstName = strings.TrimSpace(r.FormValue("firstName"))
+		nu.LastName = strings.TrimSpace(r.FormValue("lastName"))
+		nu.EmailAddress = strings.TrimSpace(r.FormValue("emailAddress"))
+		var ui userv.UserInfo
+		ui.Username = username
+		ui.FirstName = nu.FirstName
+		ui.LastName = nu.LastName
+		ui.EmailAddress = nu.EmailAddress
+		ui.RoleID = eu.RoleID
+		if rid, err := strconv.ParseInt(r.FormValue("roleId"), 10, 64); err == nil && rid > 0 {
+			ui.RoleID = rid
+		}
+		nu.RoleID = ui.RoleID
+		ui.ClientID = h.userClientID()
+		_, err := h.adminUserService(r).UpdateUser(&ui)
+		suc = err == nil
+	}
+	if suc && enabled != eu.Enabled {
+		suc = h.setAdminEnabled(r, username, enabled)
+	}
+	if !suc {
+		http.Redirect(w, r, back+"?error="+url.QueryEscape("Update Failed"), http.StatusFound)
+		return
+	}
+	ae.updated(&nu)
+	http.Redirect(w, r, adminUserListView, http.StatusFound)
+}
+
+//StoreAdminEnableAdminUser StoreAdminEnableAdminUser
+func (h *Six910Handler) StoreAdminEnableAdminUser(w http.ResponseWriter, r *http.Request) {
+	h.enableAdminUser(w, r, true)
+}
+
+//StoreAdminDisableAdminUser StoreAdminDisableAdminUser also ends the
+//admin's sessions
+func (h *Six910Handler) StoreAdminDisableAdminUser(w http.ResponseWriter, r *http.Request) {
+	h.enableAdminUser(w, r, false)
+}
+
+func (h *Six910Handler) enableAdminUser(w http.ResponseWriter, r *http.Request, enabled bool) {
+	username := mux.Vars(r)["username"]
+	if !enabled && h.isCurrentAdmin(r, username) {
+		http.Redirect(w, r, adminUserListView+"?error="+url.QueryEscape(adminUserSelf), http.StatusFound)
+		return
+	}
+	eu := h.getAdminUserRow(r, username)
+	if eu == nil {
+		http.Redirect(w, r, adminUserListView+"?error="+url.QueryEscape(adminUserNotFound), http.StatusFound)
+		return
+	}
+	ae := h.auditBeforeKey(r, auditAdminUser, username, func() interface{} { return eu })
+	if !h.setAdminEnabled(r, username, enabled) {
+		http.Redirect(w, r, adminUserListViewFail, http.StatusFound)
+		return
+	}
+	var nu = *eu
+	nu.Enabled = enabled
+	ae.updated(&nu)
+	http.Redirect(w, r, adminUserListView, http.StatusFound)
+}
+
+//StoreAdminResetAdminUserPassword StoreAdminResetAdminUserPassword
+//replaces the admin's password with a random one, ends their sessions
+//and emails them a reset link
+func (h *Six910Handler) StoreAdminResetAdminUserPassword(w http.ResponseWriter, r *http.Request) {
+	username := mux.Vars(r)["username"]
+	if h.ResetService == nil {
+		http.Redirect(w, r, adminUserListView+"?error="+url.QueryEscape(adminUserResetOff), http.StatusFound)
+		return
+	}
+	if h.getAdminUserRow(r, username) == nil {
+		http.Redirect(w, r, adminUserListView+"?error="+url.QueryEscape(adminUserNotFound), http.StatusFound)
+		return
+	}
+	if !h.setAdminPassword(r, username, randomPassword()) {
+		http.Redirect(w, r, adminUserListView+"?error="+url.QueryEscape(adminUserResetFailed), http.StatusFound)
+		return
+	}
+	h.auditPassword(r, auditAdminUser, username)
+	h.passwordChanged(rsts.ScopeAdmin, username)
+	h.endUserSessions(username)
+	if !h.sendPasswordReset(r, rsts.ScopeAdmin, username, adminResetPassword) {
+		http.Redirect(w, r, adminUserListView+"?error="+url.QueryEscape(adminUserResetFailed), http.StatusFound)
+		return
+	}
+	h.Log.Info("admin password reset forced for: ", username)
+	http.Redirect(w, r, adminUserListView+"?message="+url.QueryEscape("Reset link sent to "+username), http.StatusFound)
+}
+
+//StoreAdminDeleteAdminUser StoreAdminDeleteAdminUser removes an admin
+//from the user service. Basic auth admins can only be disabled.
+func (h *Six910Handler) StoreAdminDeleteAdminUser(w http.ResponseWriter, r *http.Request) {
+	username := mux.Vars(r)["username"]
+	if !h.OAuth2Enabled {
+		http.NotFound(w, r)
+		return
+	}
+	if h.isCurrentAdmin(r, username) {
+		http.Redirect(w, r, adminUserListView+"?error="+url.QueryEscape(adminUserSelf), http.StatusFound)
+		return
+	}
+	eu := h.getAdminUserRow(r, username)
+	if eu == nil {
+		http.Redirect(w, r, adminUserListView+"?error="+url.QueryEscape(adminUserNotFound), http.StatusFound)
+		return
+	}
+	ae := h.auditBeforeKey(r, auditAdminUser, username, func() interface{} { return eu })
+	_, err := h.adminUserService(r).DeleteUser(username, h.ClientCreds.AuthCodeClient)
+	if err != nil {
+		h.Log.Error("admin user delete failed for ", username, ": ", err)
+		http.Redirect(w, r, adminUserListView+"?error="+url.QueryEscape(adminUserMessage(err)), http.StatusFound)
+		return
+	}
+	h.Log.Info("admin user deleted: ", username)
+	ae.deleted()
+	h.endUserSessions(username)
+	http.Redirect(w, r, adminUserListView, http.StatusFound)
+}
+
+func (h *Six910Handler) oauthAdminUsers(r *http.Request, up *AdminUserPage) {
+	us := h.adminUserService(r)
+	clientID := h.ClientCreds.AuthCodeClient
+	up.Search = strings.TrimSpace(r.URL.Query().Get("search"))
+	var ul *[]userv.User
+	var err error
+	if strings.Contains(up.Search, "@") {
+		ul, err = us.SearchUsersByEmail(up.Search, clientID)
+	} else if up.Search != "" {
+		if u := h.getAdminUserRow(r, up.Search); u != nil {
+			up.Users = append(up.Users, *u)
+		}
+	} else {
+		up.Start, _ = strconv.ParseInt(r.URL.Query().Get("start"), 10, 64)
+		if up.Start < 0 {
+			up.Start = 0
+		}
+		ul, err = us.ListUsers(clientID, up.Start, up.Start+adminUserPageSize)
+		if ul != nil {
+			up.More = len(*ul) == adminUserPageSize
+		}
+		up.Next = up.Start + adminUserPageSize
+		up.Prev = up.Start - adminUserPageSize
+		if up.Prev < 0 {
+			up.Prev = 0
+		}
+	}
+	if err != nil && !errors.Is(err, userv.ErrNotFound) {
+		h.Log.Error("admin user list failed: ", err)
+		up.Error = adminUserMessage(err)
+	}
+	if ul != nil {
+		for i := range *ul {
+			up.Users = append(up.Users, oauthAdminRow(&(*ul)[i]))
+		}
+	}
+}
+
+func (h *Six910Handler) addOauthAdminUser(w http.ResponseWriter, r *http.Request, username string, pw string) {
+	var u userv.User
+	u.Username = username
+	u.Password = pw
+	u.Enabled = true
+	u.FirstName = strings.TrimSpace(r.FormValue("firstName"))
+	u.LastName = strings.TrimSpace(r.FormValue("lastName"))
+	u.EmailAddress = strings.TrimSpace(r.FormValue("emailAddress"))
+	u.RoleID, _ = strconv.ParseInt(r.FormValue("roleId"), 10, 64)
+	u.ClientID = h.userClientID()
+	_, err := h.adminUserService(r).AddUser(&u)
+	if err != nil {
+		h.Log.Error("admin user add failed for ", username, ": ", err)
+		http.Redirect(w, r, adminAddAdminUserView+"?error="+url.QueryEscape(adminUserMessage(err)), http.StatusFound)
+		return
+	}
+	h.Log.Info("admin user added: ", username)
+	h.auditCreateKey(r, auditAdminUser, username, &u)
+	http.Redirect(w, r, adminUserListView, http.StatusFound)
+}
+
+//adminRoles lists the user service roles in OAuth2 mode
+func (h *Six910Handler) adminRoles(r *http.Request) *[]userv.Role {
+	if !h.OAuth2Enabled {
+		return nil
+	}
+	rl, err := h.adminUserService(r).GetRoleList()
+	if err != nil {
+		h.Log.Error("role list failed: ", err)
+	}
+	return rl
+}
+
+//adminUserMessage turns a user service error into text for the page
+func adminUserMessage(err error) string {
+	var rtn = "Update Failed"
+	switch {
+	case errors.Is(err, userv.ErrConflict):
+		rtn = adminUserTaken
+	case errors.Is(err, userv.ErrNotFound):
+		rtn = adminUserNotFound
+	case errors.Is(err, userv.ErrUnavailable):
+		rtn = adminUserHostDown
+	case errors.Is(err, userv.ErrRejected):
+		rtn = adminUserRejected
+	}
+	return rtn
+}
+
+func (h *Six910Handler) newAdminUserPage(r *http.Request) *AdminUserPage {
+	var up AdminUserPage
+	up.Error = r.URL.Query().Get("error")
+	up.Message = r.URL.Query().Get("message")
+	up.OAuth2 = h.OAuth2Enabled
+	if !h.OAuth2Enabled {
+		up.Note = adminUserBasicNote
+	}
+	up.CanReset = h.ResetService != nil
+	return &up
+}
+
+//getAdminUserRow returns the store admin named username or nil
+func (h *Six910Handler) getAdminUserRow(r *http.Request, username string) *AdminUserRow {
+	if username == "" {
+		return nil
+	}
+	if h.OAuth2Enabled {
+		u, err := h.adminUserService(r).GetUser(username, h.ClientCreds.AuthCodeClient)
+		if err != nil || u == nil || u.Username != username {
+			return nil
+		}
+		row := oauthAdminRow(u)
+		return &row
+	}
+	var u api.User
+	u.Username = username
+	au := h.API.GetUser(&u, h.getAdminHeader(r))
+	if au == nil || au.Username != username || au.Role != storeAdmin {
+		return nil
+	}
+	row := basicAdminRow(au)
+	return &row
+}
+
+func oauthAdminRow(u *userv.User) AdminUserRow {
+	var row AdminUserRow
+	row.Username = u.Username
+	row.FirstName = u.FirstName
+	row.LastName = u.LastName
+	row.EmailAddress = u.EmailAddress
+	row.Enabled = u.Enabled
+	row.RoleID = u.RoleID
+	return row
+}
+
+func basicAdminRow(au *api.UserResponse) AdminUserRow {
+	var row AdminUserRow
+	row.Username = au.Username
+	row.Enabled = au.Enabled
+	if strings.Contains(au.Username, "@") {
+		row.EmailAddress = au.Username
+	}
+	return row
+}
+
+func (h *Six910Handler) setAdminEnabled(r *http.Request, username string, enabled bool) bool {
+	var suc bool
+	if h.OAuth2Enabled {
+		var ud userv.UserDis
+		ud.Username = username
+		ud.Enabled = enabled
+		ud.ClientID = h.userClientID()
+		_, err := h.adminUserService(r).UpdateUser(&ud)
+		suc = err == nil
+	} else if u := h.basicAdminUser(r, username); u != nil {
+		u.Enabled = enabled
+		res := h.API.UpdateUser(u, h.getAdminHeader(r))
+		suc = res != nil && res.Success
+	}
+	h.Log.Info("admin user ", username, " enabled set to ", enabled, " suc: ", suc)
+	if suc && !enabled {
+		h.endUserSessions(username)
+	}
+	return suc
+}
+
+func (h *Six910Handler) setAdminPassword(r *http.Request, username string, password string) bool {
+	if h.OAuth2Enabled {
+		var uu userv.UserPW
+		uu.Username = username
+		uu.Password = password
+		uu.ClientID = h.userClientID()
+		_, err := h.adminUserService(r).UpdateUser(&uu)
+		return err == nil
+	}
+	u := h.basicAdminUser(r, username)
+	if u == nil {
+		return false
+	}
+	u.Password = password
+	res := h.API.UpdateUser(u, h.getAdminHeader(r))
+	return res != nil && res.Success
+}
+
+//basicAdminUser basicAdminUser reads the backend admin so an update
+//sends back what it already has. The backend never returns the password;
+//it keeps the stored one when Password is blank.
+func (h *Six910Handler) basicAdminUser(r *http.Request, username string) *api.User {
+	var u api.User
+	u.Username = username
+	au := h.API.GetUser(&u, h.getAdminHeader(r))
+	if au == nil || au.Username != username || au.Role != storeAdmin {
+		return nil
+	}
+	u.Role = au.Role
+	u.CustomerID = au.CustomerID
+	u.Enabled = au.Enabled
+	return &u
+}
+
+//adminUserService calls the user service with the logged in admin's token
+func (h *Six910Handler) adminUserService(r *http.Request) userv.UserService {
+	var tkn string
+	if as := h.getAdminUser(r); as != nil {
+		tkn = as.AccessToken
+	}
+	return h.UserService.SetToken(tkn)
+}
+
+func (h *Six910Handler) userClientID() int64 {
+	var rtn int64
+	if h.ClientCreds != nil {
+		rtn, _ = strconv.ParseInt(h.ClientCreds.AuthCodeClient, 10, 64)
+	}
+	return rtn
+}
+
+//isCurrentAdmin is true when username is the admin making the request
+func (h *Six910Handler) isCurrentAdmin(r *http.Request, username string) bool {
+	as := h.getAdminUser(r)
+	return as != nil && as.Username != "" && as.Username == username
+}
+
+//endUserSessions ends every admin session and API token for username
+func (h *Six910Handler) endUserSessions(username string) {
+	if h.AdminSessions != nil {
+		for _, as := range *h.AdminSessions.List() {
+			if as.Username == username {
+				h.AdminSessions.Delete(as.ID)
+			}
+		}
+	}
+	if h.APITokenService != nil {
+		for _, t := range *h.APITokenService.List(username) {
+			h.APITokenService.Delete(t.ID)
+		}
+	}
+}
+
+//randomPassword is never shown to anyone; it locks the old password out
+//until a reset link is used
+func randomPassword() string {
+	var b = make([]byte, 24)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	mapi "github.com/Ulbora/Six910-ui/mockapi"
+	ss "github.com/Ulbora/Six910-ui/sessrv"
+	userv "github.com/Ulbora/Six910-ui/usersrv"
+	api "github.com/Ulbora/Six910API-Go"
+	"github.com/gorilla/mux"
+)
+
+var adminUserTestTemplates = template.Must(template.New("admin").Parse(
+	`{{define "adminUserList.html"}}{{.Error}}{{range .Users}}[{{.Username}} {{.EmailAddress}} {{.Enabled}} {{.Current}}]{{end}}{{end}}` +
+		`{{define "editAdminUser.html"}}{{.User.Username}} {{.User.FirstName}} {{.OAuth2}}{{with .Note}} {{.}}{{end}}{{end}}`))
+
+func TestSix910Handler_StoreAdminViewAdminUserList(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	useReset(sh, sapi, dir, "jane@test.com", storeAdmin)
+	sh.AdminTemplates = adminUserTestTemplates
+	defer os.RemoveAll(dir)
+	var aul = []api.UserResponse{{Username: "tester", Role: storeAdmin, Enabled: true}, {Username: "jane@test.com", Role: storeAdmin}}
+	sh.API.(*mapi.MockAPI).MockAdminUsers = &aul
+	r, _ := http.NewRequest("GET", adminUserListView, nil)
+	w := serveAsAdmin(sh, sh.StoreAdminViewAdminUserList, r)
+	fmt.Println("body: ", w.Body.String())
+	if w.Code != 200 || w.Body.String() != "[tester  true true][jane@test.com jane@test.com false false]" {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAdminDisableAdminUser(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	useReset(sh, sapi, dir, "jane@test.com", storeAdmin)
+	sh.AdminTemplates = adminUserTestTemplates
+	defer os.RemoveAll(dir)
+	var jas ss.AdminSession
+	jas.Username = "jane@test.com"
+	sh.getSession(&http.Request{})
+	jane := sh.AdminSessions.Create(&jas)
+
+	r, _ := http.NewRequest("POST", "/admin/disableAdminUser/tester", nil)
+	r = mux.SetURLVars(r, map[string]string{"username": "tester"})
+	w := serveAsAdmin(sh, sh.StoreAdminDisableAdminUser, r)
+	if !strings.Contains(w.Header().Get("Location"), "error=") {
+		t.Fail()
+	}
+
+	r2, _ := http.NewRequest("POST", "/admin/disableAdminUser/jane@test.com", nil)
+	r2 = mux.SetURLVars(r2, map[string]string{"username": "jane@test.com"})
+	w2 := serveAsAdmin(sh, sh.StoreAdminDisableAdminUser, r2)
+	fmt.Println("location: ", w2.Header().Get("Location"))
+	if w2.Header().Get("Location") != adminUserListView || sh.AdminSessions.Get(jane.ID) != nil {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAdminDisableAdminUserKeepsFields(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	useReset(sh, sapi, dir, "jane@test.com", storeAdmin)
+	defer os.RemoveAll(dir)
+	sh.AdminTemplates = adminUserTestTemplates
+	sapi.MockUser.CustomerID = 12
+	ha := &userCallAPI{MockAPI: sapi}
+	sh.API = ha
+	r0, _ := http.NewRequest("GET", "/admin/editAdminUserView/jane@test.com", nil)
+	r0 = mux.SetURLVars(r0, map[string]string{"username": "jane@test.com"})
+	w0 := serveAsAdmin(sh, sh.StoreAdminEditAdminUserPage, r0)
+	if w0.Body.String() != "jane@test.com  false "+adminUserBasicNote {
+		fmt.Println("body: ", w0.Body.String())
+		t.Fail()
+	}
+	r, _ := http.NewRequest("POST", "/admin/disableAdminUser/jane@test.com", nil)
+	r = mux.SetURLVars(r, map[string]string{"username": "jane@test.com"})
+	w := serveAsAdmin(sh, sh.StoreAdminDisableAdminUser, r)
+	fmt.Println("updated: ", ha.updated)
+	if w.Header().Get("Location") != adminUserListView || len(ha.updated) != 1 {
+		t.FailNow()
+	}
+	u := ha.updated[0]
+	if u.Enabled || u.Password != "" || u.Role != storeAdmin || u.CustomerID != 12 {
+		t.Fail()
+	}
+	for _, hd := range ha.headers {
+		if !strings.Contains(fmt.Sprint(hd), "Basic dGVzdGVyOnRlc3Rlcg==") {
+			t.Fail()
+		}
+	}
+
+	sapi.MockUser.Enabled = false
+	r2, _ := http.NewRequest("POST", "/admin/resetAdminUserPassword/jane@test.com", nil)
+	r2 = mux.SetURLVars(r2, map[string]string{"username": "jane@test.com"})
+	serveAsAdmin(sh, sh.StoreAdminResetAdminUserPassword, r2)
+	if len(ha.updated) != 2 || ha.updated[1].Password == "" || ha.updated[1].Enabled {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAdminResetAdminUserPassword(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	ms := useReset(sh, sapi, dir, "jane@test.com", storeAdmin)
+	sh.AdminTemplates = adminUserTestTemplates
+	defer os.RemoveAll(dir)
+	r, _ := http.NewRequest("POST", "/admin/resetAdminUserPassword/jane@test.com", nil)
+	r = mux.SetURLVars(r, map[string]string{"username": "jane@test.com"})
+	w := serveAsAdmin(sh, sh.StoreAdminResetAdminUserPassword, r)
+	fmt.Println("location: ", w.Header().Get("Location"))
+	if !strings.Contains(w.Header().Get("Location"), "message=") || len(ms.sent) != 1 || ms.sent[0].Recipients[0] != "jane@test.com" {
+		t.Fail()
+	}
+
+	sh.ResetService = nil
+	w2 := serveAsAdmin(sh, sh.StoreAdminResetAdminUserPassword, r)
+	if !strings.Contains(w2.Header().Get("Location"), url.QueryEscape(adminUserResetOff)) || len(ms.sent) != 1 {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAdminAddAdminUser(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	useReset(sh, sapi, dir, "jane@test.com", storeAdmin)
+	sh.AdminTemplates = adminUserTestTemplates
+	defer os.RemoveAll(dir)
+	var ar api.Response
+	ar.Success = true
+	sh.API.(*mapi.MockAPI).MockAddCustomerUserRes = &ar
+
+	r := postForm(adminAddAdminUserView, url.Values{"username": {"bob@test.com"}, "password": {"password1"}, "confirmPassword": {"password2"}})
+	w := serveAsAdmin(sh, sh.StoreAdminAddAdminUser, r)
+	if !strings.HasPrefix(w.Header().Get("Location"), adminAddAdminUserView+"?error=") {
+		t.Fail()
+	}
+	r2 := postForm(adminAddAdminUserView, url.Values{"username": {"bob@test.com"}, "password": {"password1"}, "confirmPassword": {"password1"}})
+	w2 := serveAsAdmin(sh, sh.StoreAdminAddAdminUser, r2)
+	if w2.Header().Get("Location") != adminUserListView {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAdminEditAdminUserOauth(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	useReset(sh, sapi, dir, "jane@test.com", storeAdmin)
+	sh.AdminTemplates = adminUserTestTemplates
+	defer os.RemoveAll(dir)
+	sh.OAuth2Enabled = true
+	var cc ClientCreds
+	cc.AuthCodeClient = "10"
+	sh.ClientCreds = &cc
+	var us userv.MockOauth2UserService
+	var u userv.User
+	u.Username = "jane"
+	u.FirstName = "Jane"
+	u.Enabled = true
+	us.MockUser = &u
+	us.MockUserCode = 200
+	var ur userv.UserResponse
+	ur.Success = true
+	us.MockUpdateUserResponse = &ur
+	sh.UserService = us.GetNew()
+
+	r, _ := http.NewRequest("GET", "/admin/editAdminUserView/jane", nil)
+	r = mux.SetURLVars(r, map[string]string{"username": "jane"})
+	w := serveAsAdmin(sh, sh.StoreAdminEditAdminUserPage, r)
+	if w.Body.String() != "jane Jane true" {
+		t.Fail()
+	}
+
+	r2 := postForm("/admin/editAdminUser", url.Values{"username": {"jane"}, "firstName": {"Janet"}, "enabled": {"true"}})
+	w2 := serveAsAdmin(sh, sh.StoreAdminEditAdminUser, r2)
+	if w2.Header().Get("Location") != adminUserListView {
+		t.Fail()
+	}
+
+	r3 := postForm(adminAddAdminUserView, url.Values{"username": {"bob"}, "password": {"password1"}, "confirmPassword": {"password1"}, "roleId": {"2"}})
+	w3 := serveAsAdmin(sh, sh.StoreAdminAddAdminUser, r3)
+	if w3.Header().Get("Location") != adminUserListView {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAdminViewAdminUserListOauth(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	useReset(sh, sapi, dir, "jane@test.com", storeAdmin)
+	sh.AdminTemplates = adminUserTestTemplates
+	us := useOauthAdminUsers(sh)
+	defer os.RemoveAll(dir)
+	r, _ := http.NewRequest("GET", adminUserListView, nil)
+	w := serveAsAdmin(sh, sh.StoreAdminViewAdminUserList, r)
+	fmt.Println("body: ", w.Body.String())
+	if w.Body.String() != "[jane jane@test.com true false][bob  false false]" {
+		t.Fail()
+	}
+	r2, _ := http.NewRequest("GET", adminUserListView+"?search=JANE@test.com", nil)
+	w2 := serveAsAdmin(sh, sh.StoreAdminViewAdminUserList, r2)
+	if w2.Body.String() != "[jane jane@test.com true false]" {
+		t.Fail()
+	}
+	us.MockUserListErr = userv.CodeError("list users", 0, "")
+	w3 := serveAsAdmin(sh, sh.StoreAdminViewAdminUserList, r)
+	if !strings.HasPrefix(w3.Body.String(), adminUserHostDown) {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAdminAddAdminUserOauthTaken(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	useReset(sh, sapi, dir, "jane@test.com", storeAdmin)
+	sh.AdminTemplates = adminUserTestTemplates
+	us := useOauthAdminUsers(sh)
+	defer os.RemoveAll(dir)
+	us.MockAddUserErr = userv.CodeError("add user", 409, "")
+	r := postForm(adminAddAdminUserView, url.Values{"username": {"jane"}, "password": {"password1"}, "confirmPassword": {"password1"}})
+	w := serveAsAdmin(sh, sh.StoreAdminAddAdminUser, r)
+	if !strings.Contains(w.Header().Get("Location"), url.QueryEscape(adminUserTaken)) {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAdminDeleteAdminUser(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	useReset(sh, sapi, dir, "jane@test.com", storeAdmin)
+	sh.AdminTemplates = adminUserTestTemplates
+	us := useOauthAdminUsers(sh)
+	defer os.RemoveAll(dir)
+	var ur userv.UserResponse
+	ur.Success = true
+	us.MockDeleteUserResponse = &ur
+	r, _ := http.NewRequest("POST", "/admin/deleteAdminUser/jane", nil)
+	r = mux.SetURLVars(r, map[string]string{"username": "jane"})
+	w := serveAsAdmin(sh, sh.StoreAdminDeleteAdminUser, r)
+	if w.Header().Get("Location") != adminUserListView {
+		t.Fail()
+	}
+
+	us.MockDeleteUserErr = userv.CodeError("delete user", 404, "")
+	w2 := serveAsAdmin(sh, sh.StoreAdminDeleteAdminUser, r)
+	if !strings.Contains(w2.Header().Get("Location"), url.QueryEscape(adminUserNotFound)) {
+		t.Fail()
+	}
+
+	us.MockDeleteUserErr = &userv.UserError{Op: "delete user", Code: 200, Message: "last admin"}
+	w4 := serveAsAdmin(sh, sh.StoreAdminDeleteAdminUser, r)
+	if !strings.Contains(w4.Header().Get("Location"), url.QueryEscape(adminUserRejected)) {
+		t.Fail()
+	}
+
+	sh.OAuth2Enabled = false
+	w3 := serveAsAdmin(sh, sh.StoreAdminDeleteAdminUser, r)
+	if w3.Code != 404 {
+		t.Fail()
+	}
+}
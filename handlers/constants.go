@@ -22,9 +22,50 @@ const (
 	//routes
 	authCodeRedirectURI = "/tokenHandler"
 	adminIndex          = "/admin/index"
+	adminLogin          = "/admin/login"
 	adminLoginFailedURL = "/admin/login?error=Login Failed"
 	adminChangePassword = "/admin/changePassword"
 
+	//routes two factor
+	adminLoginTwoFactor         = "/admin/loginTwoFactor"
+	adminLoginTwoFactorFail     = "/admin/loginTwoFactor?error=Invalid Code"
+	adminTwoFactorView          = "/admin/twoFactorView"
+	adminTwoFactorViewFail      = "/admin/twoFactorView?error=Invalid Code"
+	adminTwoFactorUsersView     = "/admin/twoFactorUsersView"
+	adminTwoFactorUsersViewFail = "/admin/twoFactorUsersView?error=Reset Failed"
+
+	//routes admin sessions
+	adminSessionListView     = "/admin/sessionListView"
+	adminSessionListViewFail = "/admin/sessionListView?error=Revoke Failed"
+
+	//routes audit log
+	adminAuditLogView   = "/admin/auditLogView"
+	adminAuditLogExport = "/admin/auditLogExport"
+
+	//routes admin users
+	adminUserListView         = "/admin/adminUserListView"
+	adminUserListViewFail     = "/admin/adminUserListView?error=Update Failed"
+	adminAddAdminUserView     = "/admin/addAdminUserView"
+	adminAddAdminUserViewFail = "/admin/addAdminUserView?error=Add Failed"
+	adminEditAdminUserView    = "/admin/editAdminUserView"
+
+	//routes password reset
+	adminForgotPassword    = "/admin/forgotPassword"
+	adminResetPassword     = "/admin/resetPassword"
+	customerForgotPassword = "/forgotPassword"
+	customerResetPassword  = "/resetPassword"
+
+	//routes search
+	adminSearch = "/admin/search"
+
+	//routes api tokens
+	adminAPITokenListView     = "/admin/apiTokenListView"
+	adminAPITokenListViewFail = "/admin/apiTokenListView?error=Delete Failed"
+
+	//routes stock alerts
+	adminStockAlertView   = "/admin/stockAlertView"
+	adminStockAlertExport = "/admin/stockAlertExport"
+
 	//routes product upload
 	adminProdUploadView = "/admin/productUploadView"
 	adminProdUpload     = "/admin/productUpload"
@@ -203,6 +244,40 @@ const (
 	adminChangePwPage = "changePassword.html"
 	adminIndexPage    = "index.html"
 
+	//pages permissions
+	adminForbiddenPage = "forbidden.html"
+
+	//pages two factor
+	adminLoginTwoFactorPage = "loginTwoFactor.html"
+	adminTwoFactorPage      = "twoFactor.html"
+	adminTwoFactorUsersPage = "twoFactorUsers.html"
+
+	//pages admin sessions
+	adminSessionListPage = "sessionList.html"
+
+	//pages audit log
+	adminAuditLogPage = "auditLog.html"
+
+	//pages admin users
+	adminUserListPage      = "adminUserList.html"
+	adminAddAdminUserPage  = "addAdminUser.html"
+	adminEditAdminUserPage = "editAdminUser.html"
+
+	//pages password reset
+	adminForgotPasswordPage    = "forgotPassword.html"
+	adminResetPasswordPage     = "resetPassword.html"
+	customerForgotPasswordPage = "customerForgotPassword.html"
+	customerResetPasswordPage  = "customerResetPassword.html"
+
+	//pages search
+	adminSearchPage = "search.html"
+
+	//pages api tokens
+	adminAPITokenPage = "apiTokens.html"
+
+	//pages stock alerts
+	adminStockAlertPage = "stockAlerts.html"
+
 	//pages product upload
 	productFileUploadPage   = "productUpload.html"
 	productUploadResultPage = "productUploadResults.html"
@@ -212,6 +287,9 @@ const (
 	adminEditProductPage = "editProduct.html"
 	adminProductListPage = "productList.html"
 
+	//pages product bulk actions
+	adminProductBulkResultPage = "productBulkResults.html"
+
 	//pages product
 	adminAddShipmentPage  = "addShipment.html"
 	adminEditShipmentPage = "editShipment.html"
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"html/template"
+	"net/http"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+const (
+	csrfTokenKey    = "csrfToken"
+	csrfFormField   = "csrfToken"
+	csrfTokenHeader = "X-CSRF-Token"
+)
+
+//AdminTemplateFuncs AdminTemplateFuncs must be added to AdminTemplates
+//before parsing so pages can use {{csrfToken}} and {{csrfField}}; the
+//real token is bound for each request when the page is rendered
+func AdminTemplateFuncs() template.FuncMap {
+	return csrfFuncs("")
+}
+
+func csrfFuncs(token string) template.FuncMap {
+	return template.FuncMap{
+		"csrfToken": func() string {
+			return token
+		},
+		"csrfField": func() template.HTML {
+			return template.HTML(`<input type="hidden" name="` + csrfFormField + `" value="` +
+				template.HTMLEscapeString(token) + `">`)
+		},
+	}
+}
+
+//AdminCSRF AdminCSRF gives each admin session a CSRF token and rejects
+//any request other than GET, HEAD or OPTIONS that does not send it back in
+//the csrfToken form field or the X-CSRF-Token header. It must run after
+//AdminAuth.
+func (h *Six910Handler) AdminCSRF(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s := h.getAdminSession(r)
+		if s == nil {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		token, _ := s.Values[csrfTokenKey].(string)
+		if token == "" {
+			token = newCsrfToken()
+			s.Values[csrfTokenKey] = token
+			serr := s.Save(r, w)
+			h.Log.Debug("csrf session save err: ", serr)
+		}
+		if !csrfValid(r, token) {
+			h.Log.Error("csrf token missing or invalid for ", r.Method, " ", r.URL.Path)
+			http.Error(w, "invalid csrf token", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+//csrfValid reports whether r is a safe method or sends token back
+func csrfValid(r *http.Request, token string) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	sent := r.Header.Get(csrfTokenHeader)
+	if sent == "" {
+		sent = r.FormValue(csrfFormField)
+	}
+	return token != "" && subtle.ConstantTimeCompare([]byte(sent), []byte(token)) == 1
+}
+
+//executeAdminTemplate renders an admin page with the session's CSRF
+//token bound to csrfToken and csrfField
+func (h *Six910Handler) executeAdminTemplate(w http.ResponseWriter, r *http.Request, name string, data interface{}) {
+	var token string
+	if s := h.getAdminSession(r); s != nil {
+		token, _ = s.Values[csrfTokenKey].(string)
+	}
+	t, err := h.AdminTemplates.Clone()
+	if err != nil {
+		h.Log.Error("admin template clone failed: ", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if eerr := t.Funcs(csrfFuncs(token)).ExecuteTemplate(w, name, data); eerr != nil {
+		h.Log.Error("admin template ", name, " failed: ", eerr)
+	}
+}
+
+func newCsrfToken() string {
+	var b = make([]byte, 32)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	lg "github.com/Ulbora/Level_Logger"
+)
+
+func csrfTestHandler(called *bool) (*Six910Handler, http.Handler) {
+	var sh Six910Handler
+	var l lg.Logger
+	l.LogLevel = lg.AllLevel
+	sh.Log = &l
+	sh.AdminTemplates = template.Must(template.New("admin").Funcs(AdminTemplateFuncs()).Parse(
+		`{{define "form.html"}}<form method="post">{{csrfField}}</form>{{end}}`))
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*called = true
+		sh.executeAdminTemplate(w, r, "form.html", nil)
+	})
+	return &sh, sh.AdminAuth(sh.AdminCSRF(next))
+}
+
+func loginTestSession(sh *Six910Handler, r *http.Request) {
+	s, _ := sh.getSession(r)
+	loginTestAdmin(sh, s)
+	s.Values["storeAdminUser"] = true
+}
+
+func TestSix910Handler_AdminCSRF(t *testing.T) {
+	var called bool
+	sh, hh := csrfTestHandler(&called)
+
+	r, _ := http.NewRequest("GET", "/admin/addProdView", nil)
+	w := httptest.NewRecorder()
+	loginTestSession(sh, r)
+	hh.ServeHTTP(w, r)
+	s, _ := sh.getSession(r)
+	token, _ := s.Values[csrfTokenKey].(string)
+	fmt.Println("body: ", w.Body.String())
+	if !called || len(token) != 64 || !strings.Contains(w.Body.String(), `name="csrfToken" value="`+token+`"`) {
+		t.Fail()
+	}
+
+	called = false
+	form := url.Values{}
+	form.Set("csrfToken", token)
+	r2, _ := http.NewRequest("POST", "/admin/addProduct", strings.NewReader(form.Encode()))
+	r2.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	s2, _ := sh.getSession(r2)
+	for k, v := range s.Values {
+		s2.Values[k] = v
+	}
+	w2 := httptest.NewRecorder()
+	hh.ServeHTTP(w2, r2)
+	if !called || w2.Code != 200 {
+		t.Fail()
+	}
+
+	called = false
+	r3, _ := http.NewRequest("POST", "/admin/deleteProduct/4", nil)
+	r3.Header.Set(csrfTokenHeader, token)
+	s3, _ := sh.getSession(r3)
+	for k, v := range s.Values {
+		s3.Values[k] = v
+	}
+	w3 := httptest.NewRecorder()
+	hh.ServeHTTP(w3, r3)
+	if !called || w3.Code != 200 {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_AdminCSRFRejected(t *testing.T) {
+	var called bool
+	sh, hh := csrfTestHandler(&called)
+
+	r, _ := http.NewRequest("POST", "/admin/deleteProduct/4", nil)
+	w := httptest.NewRecorder()
+	loginTestSession(sh, r)
+	hh.ServeHTTP(w, r)
+	if called || w.Code != http.StatusForbidden {
+		t.Fail()
+	}
+
+	form := url.Values{}
+	form.Set("csrfToken", "notthetoken")
+	r2, _ := http.NewRequest("POST", "/admin/editPaymentGateway", strings.NewReader(form.Encode()))
+	r2.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	loginTestSession(sh, r2)
+	s2, _ := sh.getSession(r2)
+	s2.Values[csrfTokenKey] = "thetoken"
+	w2 := httptest.NewRecorder()
+	hh.ServeHTTP(w2, r2)
+	if called || w2.Code != http.StatusForbidden {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_executeAdminTemplateNoSession(t *testing.T) {
+	var sh Six910Handler
+	var l lg.Logger
+	sh.Log = &l
+	sh.AdminTemplates = template.Must(template.New("admin").Funcs(AdminTemplateFuncs()).Parse(
+		`{{define "login.html"}}[{{csrfToken}}]{{end}}`))
+	r, _ := http.NewRequest("GET", "/admin/login", nil)
+	w := httptest.NewRecorder()
+	sh.executeAdminTemplate(w, r, "login.html", nil)
+	w2 := httptest.NewRecorder()
+	sh.executeAdminTemplate(w2, r, "login.html", nil)
+	if w.Body.String() != "[]" || w2.Body.String() != "[]" {
+		t.Fail()
+	}
+}
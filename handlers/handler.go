@@ -40,15 +40,87 @@ type ClientCreds struct {
 
 //Handler Handler
 type Handler interface {
+	Healthz(w http.ResponseWriter, r *http.Request)
+	Readyz(w http.ResponseWriter, r *http.Request)
+
+	AdminAuth(next http.Handler) http.Handler
+	AdminCSRF(next http.Handler) http.Handler
+	AdminPermission(area string) func(http.Handler) http.Handler
+	AdminAPIAuth(next http.Handler) http.Handler
+	StoreAPIAuth(next http.Handler) http.Handler
+
 	//--- admin methods----------------------------------------------------------
 
 	StoreAdminLogin(w http.ResponseWriter, r *http.Request)
 	StoreAdminLoginNonOAuthUser(w http.ResponseWriter, r *http.Request)
 	StoreAdminHandleToken(w http.ResponseWriter, r *http.Request)
 	StoreAdminLogout(w http.ResponseWriter, r *http.Request)
+
 	StoreAdminChangePassword(w http.ResponseWriter, r *http.Request)
 	StoreAdminChangeUserPassword(w http.ResponseWriter, r *http.Request)
 
+	StoreAdminLoginTwoFactorPage(w http.ResponseWriter, r *http.Request)
+	StoreAdminLoginTwoFactor(w http.ResponseWriter, r *http.Request)
+	StoreAdminTwoFactorPage(w http.ResponseWriter, r *http.Request)
+	StoreAdminStartTwoFactor(w http.ResponseWriter, r *http.Request)
+	StoreAdminConfirmTwoFactor(w http.ResponseWriter, r *http.Request)
+	StoreAdminDisableTwoFactor(w http.ResponseWriter, r *http.Request)
+	StoreAdminViewTwoFactorUsers(w http.ResponseWriter, r *http.Request)
+	StoreAdminResetTwoFactor(w http.ResponseWriter, r *http.Request)
+
+	StoreAdminViewSessionList(w http.ResponseWriter, r *http.Request)
+	StoreAdminRevokeSession(w http.ResponseWriter, r *http.Request)
+
+	StoreAdminViewAuditLog(w http.ResponseWriter, r *http.Request)
+	StoreAdminExportAuditLog(w http.ResponseWriter, r *http.Request)
+
+	StoreAdminViewAdminUserList(w http.ResponseWriter, r *http.Request)
+	StoreAdminAddAdminUserPage(w http.ResponseWriter, r *http.Request)
+	StoreAdminAddAdminUser(w http.ResponseWriter, r *http.Request)
+	StoreAdminEditAdminUserPage(w http.ResponseWriter, r *http.Request)
+	StoreAdminEditAdminUser(w http.ResponseWriter, r *http.Request)
+	StoreAdminEnableAdminUser(w http.ResponseWriter, r *http.Request)
+	StoreAdminDisableAdminUser(w http.ResponseWriter, r *http.Request)
+	StoreAdminResetAdminUserPassword(w http.ResponseWriter, r *http.Request)
+	StoreAdminDeleteAdminUser(w http.ResponseWriter, r *http.Request)
+
+	StoreAdminSearch(w http.ResponseWriter, r *http.Request)
+
+	StoreAdminViewStockAlerts(w http.ResponseWriter, r *http.Request)
+	StoreAdminExportStockAlerts(w http.ResponseWriter, r *http.Request)
+
+	StoreAdminViewAPITokenList(w http.ResponseWriter, r *http.Request)
+	StoreAdminAddAPIToken(w http.ResponseWriter, r *http.Request)
+	StoreAdminDeleteAPIToken(w http.ResponseWriter, r *http.Request)
+
+	AdminAPIList(w http.ResponseWriter, r *http.Request)
+	AdminAPIGet(w http.ResponseWriter, r *http.Request)
+	AdminAPIAdd(w http.ResponseWriter, r *http.Request)
+	AdminAPIUpdate(w http.ResponseWriter, r *http.Request)
+	AdminAPIDelete(w http.ResponseWriter, r *http.Request)
+	AdminAPINotFound(w http.ResponseWriter, r *http.Request)
+
+	StoreAdminForgotPasswordPage(w http.ResponseWriter, r *http.Request)
+	StoreAdminForgotPassword(w http.ResponseWriter, r *http.Request)
+	StoreAdminResetPasswordPage(w http.ResponseWriter, r *http.Request)
+	StoreAdminResetPassword(w http.ResponseWriter, r *http.Request)
+	CustomerForgotPasswordPage(w http.ResponseWriter, r *http.Request)
+	CustomerForgotPassword(w http.ResponseWriter, r *http.Request)
+	CustomerResetPasswordPage(w http.ResponseWriter, r *http.Request)
+	CustomerResetPassword(w http.ResponseWriter, r *http.Request)
+
+	StoreAPIProductList(w http.ResponseWriter, r *http.Request)
+	StoreAPIProduct(w http.ResponseWriter, r *http.Request)
+	StoreAPICategoryList(w http.ResponseWriter, r *http.Request)
+	StoreAPICreateAccount(w http.ResponseWriter, r *http.Request)
+	StoreAPILogin(w http.ResponseWriter, r *http.Request)
+	StoreAPILogout(w http.ResponseWriter, r *http.Request)
+	StoreAPIViewCart(w http.ResponseWriter, r *http.Request)
+	StoreAPIAddToCart(w http.ResponseWriter, r *http.Request)
+	StoreAPIUpdateCart(w http.ResponseWriter, r *http.Request)
+	StoreAPICheckOut(w http.ResponseWriter, r *http.Request)
+	StoreAPIOrderList(w http.ResponseWriter, r *http.Request)
+
 	StoreAdminIndex(w http.ResponseWriter, r *http.Request)
 
 	//products
@@ -61,6 +133,7 @@ type Handler interface {
 	StoreAdminEditProduct(w http.ResponseWriter, r *http.Request)
 	StoreAdminViewProductList(w http.ResponseWriter, r *http.Request)
 	StoreAdminDeleteProduct(w http.ResponseWriter, r *http.Request)
+	StoreAdminProductBulk(w http.ResponseWriter, r *http.Request)
 
 	//orders
 	StoreAdminEditOrderPage(w http.ResponseWriter, r *http.Request)
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	api "github.com/Ulbora/Six910API-Go"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+const (
+	healthOk   = "ok"
+	healthFail = "fail"
+
+	readyCheckTimeout = 5 * time.Second
+)
+
+//HealthCheck HealthCheck
+type HealthCheck struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+//HealthResponse HealthResponse
+type HealthResponse struct {
+	Status string        `json:"status"`
+	Checks []HealthCheck `json:"checks,omitempty"`
+}
+
+type healthProbe struct {
+	name  string
+	check func() error
+}
+
+//Healthz Healthz reports that the process is up and serving requests
+func (h *Six910Handler) Healthz(w http.ResponseWriter, r *http.Request) {
+	var rtn HealthResponse
+	rtn.Status = healthOk
+	h.writeHealth(w, http.StatusOK, &rtn)
+}
+
+//Readyz Readyz runs every readiness check in parallel and returns 503
+//if any of them fail
+func (h *Six910Handler) Readyz(w http.ResponseWriter, r *http.Request) {
+	var probes = []healthProbe{
+		{"backend", h.checkBackend},
+		{"contentStore", func() error { return checkReadWriteDir(h.ContentStorePath) }},
+		{"templateStore", func() error { return checkReadWriteDir(h.TemplateStorePath) }},
+		{"imagePath", func() error { return checkDirExists(h.ImagePath) }},
+		{"templateFilePath", func() error { return checkDirExists(h.TemplateFilePath) }},
+		{"activeTemplate", h.checkActiveTemplate},
+	}
+	var rtn HealthResponse
+	rtn.Status = healthOk
+	rtn.Checks = make([]HealthCheck, len(probes))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for i := range probes {
+		rtn.Checks[i].Name = probes[i].name
+		rtn.Checks[i].Status = healthFail
+		rtn.Checks[i].Error = "timed out"
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			start := time.Now()
+			err := probes[i].check()
+			mu.Lock()
+			defer mu.Unlock()
+			rtn.Checks[i].LatencyMs = float64(time.Since(start).Microseconds()) / 1000
+			if err != nil {
+				rtn.Checks[i].Error = err.Error()
+			} else {
+				rtn.Checks[i].Status = healthOk
+				rtn.Checks[i].Error = ""
+			}
+		}(i)
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(readyCheckTimeout):
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	var code = http.StatusOK
+	for _, c := range rtn.Checks {
+		if c.Status != healthOk {
+			rtn.Status = healthFail
+			code = http.StatusServiceUnavailable
+			h.Log.Error("readiness check failed: ", c.Name, ": ", c.Error)
+		}
+	}
+	h.writeHealth(w, code, &rtn)
+}
+
+func (h *Six910Handler) writeHealth(w http.ResponseWriter, code int, res *HealthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *Six910Handler) checkBackend() error {
+	if h.API == nil {
+		return errors.New("api not configured")
+	}
+	var hd api.Headers
+	str := h.API.GetStore(h.StoreName, h.LocalDomain, &hd)
+	if str == nil || str.ID == 0 {
+		return errors.New("store " + h.StoreName + " not returned by backend")
+	}
+	return nil
+}
+
+func (h *Six910Handler) checkActiveTemplate() error {
+	if h.TemplateService == nil {
+		return errors.New("template service not configured")
+	}
+	if h.TemplateService.GetActiveTemplateName() == "" {
+		return errors.New("no active template")
+	}
+	return nil
+}
+
+func checkDirExists(path string) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return errors.New(path + " is not a directory")
+	}
+	return nil
+}
+
+func checkReadWriteDir(path string) error {
+	if err := checkDirExists(path); err != nil {
+		return err
+	}
+	if _, err := ioutil.ReadDir(path); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(path, ".readyz")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	lg "github.com/Ulbora/Level_Logger"
+	mapi "github.com/Ulbora/Six910-ui/mockapi"
+	tmpts "github.com/Ulbora/Six910-ui/tmptsrv"
+	ds "github.com/Ulbora/json-datastore"
+	sdbi "github.com/Ulbora/six910-database-interface"
+)
+
+func TestSix910Handler_Healthz(t *testing.T) {
+	var sh Six910Handler
+	var l lg.Logger
+	sh.Log = &l
+	r, _ := http.NewRequest("GET", "/healthz", nil)
+	w := httptest.NewRecorder()
+	h := sh.GetNew()
+	h.Healthz(w, r)
+	var res HealthResponse
+	json.NewDecoder(w.Body).Decode(&res)
+	if w.Code != 200 || res.Status != "ok" {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_Readyz(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "six910ready")
+	defer os.RemoveAll(dir)
+	for _, d := range []string{"content", "templates", "tfiles", "images"} {
+		os.Mkdir(filepath.Join(dir, d), 0755)
+	}
+	var sh Six910Handler
+	var l lg.Logger
+	l.LogLevel = lg.AllLevel
+	sh.Log = &l
+	sh.StoreName = "teststore"
+	sh.LocalDomain = "test.com"
+	sh.ContentStorePath = filepath.Join(dir, "content")
+	sh.TemplateStorePath = filepath.Join(dir, "templates")
+	sh.TemplateFilePath = filepath.Join(dir, "tfiles")
+	sh.ImagePath = filepath.Join(dir, "images")
+
+	var sapi mapi.MockAPI
+	var str sdbi.Store
+	str.ID = 5
+	sapi.MockStore = &str
+	sh.API = sapi.GetNew()
+
+	var tds ds.DataStore
+	tds.Path = sh.TemplateStorePath
+	var ts tmpts.Six910TemplateService
+	ts.TemplateStore = tds.GetNew()
+	ts.Log = &l
+	sh.TemplateService = ts.GetNew()
+	var tpl tmpts.Template
+	tpl.Name = "default"
+	tpl.Active = true
+	sh.TemplateService.AddTemplate(&tpl)
+	sh.TemplateService.ActivateTemplate("default")
+
+	r, _ := http.NewRequest("GET", "/readyz", nil)
+	w := httptest.NewRecorder()
+	h := sh.GetNew()
+	h.Readyz(w, r)
+	var res HealthResponse
+	json.NewDecoder(w.Body).Decode(&res)
+	fmt.Println("readyz: ", res)
+	if w.Code != 200 || res.Status != "ok" || len(res.Checks) != 6 {
+		t.Fail()
+	}
+	for _, c := range res.Checks {
+		if c.Status != "ok" {
+			fmt.Println("failed check: ", c)
+			t.Fail()
+		}
+	}
+}
+
+func TestSix910Handler_ReadyzNotReady(t *testing.T) {
+	var sh Six910Handler
+	var l lg.Logger
+	sh.Log = &l
+	sh.ContentStorePath = "./noContentStore"
+	sh.TemplateStorePath = "./noTemplateStore"
+	sh.TemplateFilePath = "./noTemplates"
+	sh.ImagePath = "./testHtmls/test.html"
+	var sapi mapi.MockAPI
+	sh.API = sapi.GetNew()
+
+	r, _ := http.NewRequest("GET", "/readyz", nil)
+	w := httptest.NewRecorder()
+	h := sh.GetNew()
+	h.Readyz(w, r)
+	var res HealthResponse
+	json.NewDecoder(w.Body).Decode(&res)
+	fmt.Println("readyz: ", res)
+	if w.Code != 503 || res.Status != "fail" {
+		t.Fail()
+	}
+	for _, c := range res.Checks {
+		if c.Status != "fail" || c.Error == "" {
+			t.Fail()
+		}
+	}
+}
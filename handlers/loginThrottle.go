@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	thr "github.com/Ulbora/Six910-ui/throtsrv"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+//adminScope keeps admin failures apart from customer failures for the
+//same username
+const adminScope = "admin"
+
+//clientIP clientIP is the host part of RemoteAddr; put a proxy that
+//rewrites RemoteAddr in front if the UI sits behind a load balancer
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func adminLoginKeys(username string, r *http.Request) []string {
+	return []string{thr.UserKey(adminScope, username), thr.IPKey(clientIP(r))}
+}
+
+//throttleMessage throttleMessage is shown on the login page
+func throttleMessage(wait time.Duration) string {
+	n, unit := int((wait+time.Minute-1)/time.Minute), " minute"
+	if wait < time.Minute {
+		n, unit = int((wait+time.Second-1)/time.Second), " second"
+	}
+	if n != 1 {
+		unit += "s"
+	}
+	return "Too many failed logins. Try again in " + strconv.Itoa(n) + unit + "."
+}
+
+//adminLoginThrottled adminLoginThrottled redirects back to the login
+//page with the wait time when the username or client IP is throttled
+func (h *Six910Handler) adminLoginThrottled(w http.ResponseWriter, r *http.Request, username string) bool {
+	wait := h.LoginThrottle.Check(adminLoginKeys(username, r)...)
+	if wait <= 0 {
+		return false
+	}
+	h.Log.Info("admin login throttled for: ", username, " from: ", clientIP(r))
+	http.Redirect(w, r, adminLogin+"?error="+url.QueryEscape(throttleMessage(wait)), http.StatusFound)
+	return true
+}
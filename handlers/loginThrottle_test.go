@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	lg "github.com/Ulbora/Level_Logger"
+	mapi "github.com/Ulbora/Six910-ui/mockapi"
+	thr "github.com/Ulbora/Six910-ui/throtsrv"
+	api "github.com/Ulbora/Six910API-Go"
+)
+
+func throttleTestHandler(enabled bool) *Six910Handler {
+	var sh Six910Handler
+	var l lg.Logger
+	l.LogLevel = lg.AllLevel
+	sh.Log = &l
+	var sapi mapi.MockAPI
+	var user api.UserResponse
+	user.Username = "tester123"
+	user.Role = storeAdmin
+	user.Enabled = enabled
+	sapi.MockUser = &user
+	sh.API = &sapi
+	return &sh
+}
+
+func throttleTestLogin(h Handler, addr string) *httptest.ResponseRecorder {
+	r, _ := http.NewRequest("POST", "/admin/login", strings.NewReader("username=tester123&password=bad"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded; param=value")
+	r.RemoteAddr = addr
+	w := httptest.NewRecorder()
+	h.StoreAdminLoginNonOAuthUser(w, r)
+	return w
+}
+
+func TestSix910Handler_StoreAdminLoginThrottled(t *testing.T) {
+	sh := throttleTestHandler(false)
+	h := sh.GetNew()
+	for i := 0; i < 4; i++ {
+		w := throttleTestLogin(h, "10.0.0.1:5555")
+		if w.Header().Get("Location") != adminLoginFailedURL {
+			fmt.Println("location: ", w.Header().Get("Location"))
+			t.Fail()
+		}
+	}
+	w := throttleTestLogin(h, "10.0.0.2:5555")
+	loc, _ := url.Parse(w.Header().Get("Location"))
+	fmt.Println("location: ", loc)
+	if loc.Path != adminLogin || !strings.HasPrefix(loc.Query().Get("error"), "Too many failed logins. Try again in") {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAdminLoginThrottleReset(t *testing.T) {
+	sh := throttleTestHandler(true)
+	h := sh.GetNew()
+	sh.getSession(nil)
+	uk := thr.UserKey(adminScope, "tester123")
+	sh.LoginThrottle.Fail(uk)
+	sh.LoginThrottle.Fail(uk)
+	w := throttleTestLogin(h, "10.0.0.1:5555")
+	if w.Header().Get("Location") != adminIndex {
+		t.Fail()
+	}
+	sh.LoginThrottle.Fail(uk)
+	sh.LoginThrottle.Fail(uk)
+	sh.LoginThrottle.Fail(uk)
+	if sh.LoginThrottle.Check(uk) != 0 {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAdminViewSessionListLockouts(t *testing.T) {
+	var sh Six910Handler
+	var l lg.Logger
+	sh.Log = &l
+	sh.AdminTemplates = template.Must(template.New("admin").Parse(
+		`{{define "sessionList.html"}}{{range .Lockouts}}[{{.Key}} {{.Failures}}]{{end}}{{end}}`))
+	r, _ := http.NewRequest("GET", "/admin/sessionListView", nil)
+	s, _ := sh.getSession(r)
+	loginTestAdmin(&sh, s)
+	s.Values["storeAdminUser"] = true
+	for i := 0; i < 10; i++ {
+		sh.LoginThrottle.Fail(thr.UserKey(adminScope, "admin"))
+	}
+	w := httptest.NewRecorder()
+	h := sh.GetNew()
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminViewSessionList)).ServeHTTP(w, r)
+	fmt.Println("body: ", w.Body.String())
+	if w.Body.String() != "[admin:user:admin 10]" {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_clientIP(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.RemoteAddr = "[::1]:4000"
+	if clientIP(r) != "::1" {
+		t.Fail()
+	}
+	r.RemoteAddr = "pipe"
+	if clientIP(r) != "pipe" {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_throttleMessage(t *testing.T) {
+	if throttleMessage(1500*time.Millisecond) != "Too many failed logins. Try again in 2 seconds." {
+		t.Fail()
+	}
+	if throttleMessage(time.Second) != "Too many failed logins. Try again in 1 second." {
+		t.Fail()
+	}
+	if throttleMessage(14*time.Minute+time.Second) != "Too many failed logins. Try again in 15 minutes." {
+		t.Fail()
+	}
+}
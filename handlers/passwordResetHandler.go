@@ -0,0 +1,298 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ulbora/Six910-ui/logging"
+	rsts "github.com/Ulbora/Six910-ui/resetsrv"
+	thr "github.com/Ulbora/Six910-ui/throtsrv"
+	userv "github.com/Ulbora/Six910-ui/usersrv"
+	api "github.com/Ulbora/Six910API-Go"
+	ml "github.com/Ulbora/go-mail-sender"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+const (
+	//minPasswordLength applies to passwords set with a reset link
+	minPasswordLength = 8
+
+	resetPasswordInvalid = "Passwords must match and be at least 8 characters"
+	resetPasswordFailed  = "Password not changed, ask for a new link"
+)
+
+//PasswordResetPage PasswordResetPage
+type PasswordResetPage struct {
+	Error string
+	Sent  bool
+	Done  bool
+	Token string
+}
+
+//StoreAdminForgotPasswordPage StoreAdminForgotPasswordPage
+func (h *Six910Handler) StoreAdminForgotPasswordPage(w http.ResponseWriter, r *http.Request) {
+	h.forgotPasswordPage(w, r, adminForgotPasswordPage)
+}
+
+//StoreAdminForgotPassword StoreAdminForgotPassword
+func (h *Six910Handler) StoreAdminForgotPassword(w http.ResponseWriter, r *http.Request) {
+	h.forgotPassword(w, r, rsts.ScopeAdmin, adminForgotPassword, adminResetPassword)
+}
+
+//StoreAdminResetPasswordPage StoreAdminResetPasswordPage
+func (h *Six910Handler) StoreAdminResetPasswordPage(w http.ResponseWriter, r *http.Request) {
+	h.resetPasswordPage(w, r, adminResetPasswordPage)
+}
+
+//StoreAdminResetPassword StoreAdminResetPassword
+func (h *Six910Handler) StoreAdminResetPassword(w http.ResponseWriter, r *http.Request) {
+	h.resetPassword(w, r, rsts.ScopeAdmin, adminResetPassword)
+}
+
+//CustomerForgotPasswordPage CustomerForgotPasswordPage
+func (h *Six910Handler) CustomerForgotPasswordPage(w http.ResponseWriter, r *http.Request) {
+	h.forgotPasswordPage(w, r, customerForgotPasswordPage)
+}
+
+//CustomerForgotPassword CustomerForgotPassword
+func (h *Six910Handler) CustomerForgotPassword(w http.ResponseWriter, r *http.Request) {
+	h.forgotPassword(w, r, rsts.ScopeCustomer, customerForgotPassword, customerResetPassword)
+}
+
+//CustomerResetPasswordPage CustomerResetPasswordPage
+func (h *Six910Handler) CustomerResetPasswordPage(w http.ResponseWriter, r *http.Request) {
+	h.resetPasswordPage(w, r, customerResetPasswordPage)
+}
+
+//CustomerResetPassword CustomerResetPassword
+func (h *Six910Handler) CustomerResetPassword(w http.ResponseWriter, r *http.Request) {
+	h.resetPassword(w, r, rsts.ScopeCustomer, customerResetPassword)
+}
+
+func (h *Six910Handler) forgotPasswordPage(w http.ResponseWriter, r *http.Request, page string) {
+	if h.ResetService == nil {
+		http.NotFound(w, r)
+		return
+	}
+	var pp PasswordResetPage
+	pp.Error = r.URL.Query().Get("error")
+	pp.Sent = r.URL.Query().Get("sent") == "true"
+	h.executeAdminTemplate(w, r, page, &pp)
+}
+
+//forgotPassword always reports the link as sent so the form can not be
+//used to find out which usernames exist
+func (h *Six910Handler) forgotPassword(w http.ResponseWriter, r *http.Request, scope string, back string, resetPath string) {
+	if h.ResetService == nil {
+		http.NotFound(w, r)
+		return
+	}
+	username := strings.TrimSpace(r.FormValue("username"))
+	if username != "" {
+		h.sendPasswordReset(r, scope, username, resetPath)
+	}
+	http.Redirect(w, r, back+"?sent=true", http.StatusFound)
+}
+
+func (h *Six910Handler) sendPasswordReset(r *http.Request, scope string, username string, resetPath string) bool {
+	email := h.resetEmail(r, scope, username)
+	if email == "" {
+		h.Log.Info("password reset asked for unknown ", scope, ": ", username)
+		return false
+	}
+	tkn, err := h.ResetService.NewToken(scope, username)
+	if err != nil {
+		h.Log.Info("password reset not sent for: ", username, " ", err)
+		return false
+	}
+	var expires string
+	if c, _ := h.ResetService.Check(tkn); c != nil {
+		expires = c.Expires.Format(time.RFC1123)
+	}
+	var mm ml.Mailer
+	mm.Subject = h.StoreName + " password reset"
+	mm.Body = "A password reset was asked for " + username + ".\n\n" +
+		"Use this link to set a new password. It works once and expires at " + expires + ".\n\n" +
+		strings.TrimRight(h.PublicURL, "/") + resetPath + "?token=" + url.QueryEscape(tkn) + "\n\n" +
+		"If you did not ask for this you can ignore this email.\n"
+	mm.Recipients = []string{email}
+	mm.SenderAddress = h.MailFrom
+	suc := h.MailService.SendMail(&mm)
+	h.Log.Info("password reset mail for: ", username, " sent: ", suc)
+	return suc
+}
+
+func (h *Six910Handler) resetPasswordPage(w http.ResponseWriter, r *http.Request, page string) {
+	if h.ResetService == nil {
+		http.NotFound(w, r)
+		return
+	}
+	var pp PasswordResetPage
+	pp.Done = r.URL.Query().Get("done") == "true"
+	if !pp.Done {
+		pp.Token = r.URL.Query().Get("token")
+		pp.Error = r.URL.Query().Get("error")
+		if _, err := h.ResetService.Check(pp.Token); err != nil {
+			pp.Error = resetTokenMessage(err)
+			pp.Token = ""
+		}
+	}
+	h.executeAdminTemplate(w, r, page, &pp)
+}
+
+//resetPassword uses up the token before the password is set, so a
+//failed update needs a new link
+func (h *Six910Handler) resetPassword(w http.ResponseWriter, r *http.Request, scope string, back string) {
+	if h.ResetService == nil {
+		http.NotFound(w, r)
+		return
+	}
+	tkn := r.FormValue("token")
+	pw := r.FormValue("password")
+	if len(pw) < minPasswordLength || pw != r.FormValue("confirmPassword") {
+		http.Redirect(w, r, back+"?token="+url.QueryEscape(tkn)+"&error="+url.QueryEscape(resetPasswordInvalid), http.StatusFound)
+		return
+	}
+	c, err := h.ResetService.Check(tkn)
+	if err == nil && c.Scope != scope {
+		err = rsts.ErrInvalidToken
+	}
+	if err == nil {
+		c, err = h.ResetService.Redeem(tkn)
+	}
+	if err != nil {
+		h.Log.Info("password reset refused: ", err)
+		http.Redirect(w, r, back+"?error="+url.QueryEscape(resetTokenMessage(err)), http.StatusFound)
+		return
+	}
+	if !h.setResetPassword(r, scope, c.Username, pw) {
+		h.Log.Error("password reset update failed for: ", c.Username)
+		http.Redirect(w, r, back+"?error="+url.QueryEscape(resetPasswordFailed), http.StatusFound)
+		return
+	}
+	h.Log.Info("password reset for ", scope, ": ", c.Username)
+	if scope == rsts.ScopeAdmin {
+		h.endUserSessions(c.Username)
+	} else if h.CustomerSessions != nil {
+		h.CustomerSessions.DeleteUser(c.Username)
+	}
+	if h.LoginThrottle != nil {
+		h.LoginThrottle.Reset(thr.UserKey(scope, c.Username))
+	}
+	http.Redirect(w, r, back+"?done=true", http.StatusFound)
+}
+
+//passwordChanged drops any open reset link after a password is changed
+//some other way
+func (h *Six910Handler) passwordChanged(scope string, username string) {
+	if h.ResetService != nil && h.ResetService.PasswordChanged(scope, username) {
+		h.Log.Debug("password reset token dropped for: ", username)
+	}
+}
+
+//resetEmail resetEmail finds where to send the link. Customers and
+//Basic auth admins log in with their email address; OAuth2 admins have
+//one in the user service.
+func (h *Six910Handler) resetEmail(r *http.Request, scope string, username string) string {
+	var rtn string
+	if scope == rsts.ScopeAdmin && h.OAuth2Enabled {
+		u, err := h.resetUserService().GetUser(username, h.ClientCreds.AuthCodeClient)
+		if err == nil && u != nil && u.Enabled {
+			rtn = u.EmailAddress
+		}
+	} else if h.getResetUser(r, scope, username) != nil && strings.Contains(username, "@") {
+		rtn = username
+	}
+	return rtn
+}
+
+func (h *Six910Handler) setResetPassword(r *http.Request, scope string, username string, password string) bool {
+	if scope == rsts.ScopeAdmin && h.OAuth2Enabled {
+		var uu userv.UserPW
+		uu.Username = username
+		uu.Password = password
+		uu.ClientID, _ = strconv.ParseInt(h.ClientCreds.AuthCodeClient, 10, 64)
+		_, err := h.resetUserService().UpdateUser(&uu)
+		return err == nil
+	}
+	eu := h.getResetUser(r, scope, username)
+	if eu == nil {
+		return false
+	}
+	var u api.User
+	u.Username = username
+	u.Password = password
+	u.Role = eu.Role
+	u.CustomerID = eu.CustomerID
+	u.Enabled = true
+	res := h.API.UpdateUser(&u, h.resetUserHeader(r))
+	return res != nil && res.Success
+}
+
+//getResetUser returns the enabled backend user for scope or nil
+func (h *Six910Handler) getResetUser(r *http.Request, scope string, username string) *api.UserResponse {
+	var role = customerRole
+	if scope == rsts.ScopeAdmin {
+		role = storeAdmin
+	}
+	var u api.User
+	u.Username = username
+	eu := h.API.GetUser(&u, h.resetUserHeader(r))
+	if eu == nil || !eu.Enabled || eu.Username != username || eu.Role != role {
+		return nil
+	}
+	return eu
+}
+
+//resetUserService resetUserService calls the user service with a client
+//credentials token since nobody is logged in
+func (h *Six910Handler) resetUserService() userv.UserService {
+	var tkn string
+	if h.ClientCredentials != nil {
+		if t := h.ClientCredentials.ClientCredentialsToken(); t != nil {
+			tkn = t.AccessToken
+		}
+	}
+	return h.UserService.SetToken(tkn)
+}
+
+//resetUserHeader resetUserHeader reads and changes backend users as
+//ResetUser since nobody is logged in
+func (h *Six910Handler) resetUserHeader(r *http.Request) *api.Headers {
+	hd := h.serviceHeader(h.ResetUser, h.ResetPassword)
+	hd.Set(logging.RequestIDHeader, logging.RequestID(r))
+	return hd
+}
+
+func resetHeader(r *http.Request) *api.Headers {
+	var hd api.Headers
+	hd.Set(logging.RequestIDHeader, logging.RequestID(r))
+	return &hd
+}
+
+func resetTokenMessage(err error) string {
+	if err == rsts.ErrExpiredToken {
+		return "This reset link has expired, ask for a new one"
+	}
+	return "This reset link is not valid or was already used"
+}
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	lg "github.com/Ulbora/Level_Logger"
+	rsts "github.com/Ulbora/Six910-ui/resetsrv"
+	ss "github.com/Ulbora/Six910-ui/sessrv"
+	thr "github.com/Ulbora/Six910-ui/throtsrv"
+	userv "github.com/Ulbora/Six910-ui/usersrv"
+	oauth2 "github.com/Ulbora/go-oauth2-client"
+)
+
+var resetTestTemplates = template.Must(template.New("admin").Parse(
+	`{{define "resetPassword.html"}}[{{.Error}}][{{.Token}}][{{.Done}}]{{end}}` +
+		`{{define "customerForgotPassword.html"}}[{{.Sent}}]{{end}}`))
+
+func resetTestLink(ms *testMailService) string {
+	if len(ms.sent) == 0 {
+		return ""
+	}
+	body := ms.sent[0].Body
+	i := strings.Index(body, "https://")
+	return strings.Fields(body[i:])[0]
+}
+
+func serveForm(h func(http.ResponseWriter, *http.Request), path string, form url.Values) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h(w, postForm(path, form))
+	return w
+}
+
+func TestSix910Handler_AdminPasswordReset(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	ms := useReset(sh, sapi, dir, "admin@test.com", storeAdmin)
+	sh.AdminTemplates = resetTestTemplates
+	defer os.RemoveAll(dir)
+	h := sh.GetNew()
+	sh.getSession(nil)
+	var as ss.AdminSession
+	as.Username = "admin@test.com"
+	sh.AdminSessions.Create(&as)
+	sh.LoginThrottle.Fail(thr.UserKey(rsts.ScopeAdmin, "admin@test.com"))
+
+	w := serveForm(h.StoreAdminForgotPassword, adminForgotPassword, url.Values{"username": {"admin@test.com"}})
+	link := resetTestLink(ms)
+	fmt.Println("link: ", link)
+	if w.Header().Get("Location") != adminForgotPassword+"?sent=true" || len(ms.sent) != 1 ||
+		ms.sent[0].Recipients[0] != "admin@test.com" || ms.sent[0].SenderAddress != "store@test.com" ||
+		!strings.HasPrefix(link, "https://shop.test.com/admin/resetPassword?token=") {
+		t.FailNow()
+	}
+	lu, _ := url.Parse(link)
+	tkn := lu.Query().Get("token")
+
+	r, _ := http.NewRequest("GET", link, nil)
+	w = httptest.NewRecorder()
+	h.StoreAdminResetPasswordPage(w, r)
+	if w.Body.String() != "[]["+tkn+"][false]" {
+		fmt.Println("body: ", w.Body.String())
+		t.Fail()
+	}
+
+	w = serveForm(h.StoreAdminResetPassword, adminResetPassword, url.Values{"token": {tkn}, "password": {"newpassword1"}, "confirmPassword": {"newpassword2"}})
+	if !strings.Contains(w.Header().Get("Location"), "error=") {
+		t.Fail()
+	}
+	w = serveForm(h.StoreAdminResetPassword, adminResetPassword, url.Values{"token": {tkn}, "password": {"newpassword1"}, "confirmPassword": {"newpassword1"}})
+	fmt.Println("location: ", w.Header().Get("Location"))
+	if w.Header().Get("Location") != adminResetPassword+"?done=true" {
+		t.Fail()
+	}
+	if len(*sh.AdminSessions.List()) != 0 || sh.LoginThrottle.Check(thr.UserKey(rsts.ScopeAdmin, "admin@test.com")) != 0 {
+		t.Fail()
+	}
+	w = serveForm(h.StoreAdminResetPassword, adminResetPassword, url.Values{"token": {tkn}, "password": {"newpassword1"}, "confirmPassword": {"newpassword1"}})
+	if !strings.Contains(w.Header().Get("Location"), "already+used") {
+		fmt.Println("location: ", w.Header().Get("Location"))
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_CustomerPasswordResetWrongScope(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	ms := useReset(sh, sapi, dir, "bob@test.com", customerRole)
+	sh.AdminTemplates = resetTestTemplates
+	defer os.RemoveAll(dir)
+	h := sh.GetNew()
+	serveForm(h.CustomerForgotPassword, customerForgotPassword, url.Values{"username": {"bob@test.com"}})
+	serveForm(h.StoreAdminForgotPassword, adminForgotPassword, url.Values{"username": {"bob@test.com"}})
+	if len(ms.sent) != 1 {
+		t.FailNow()
+	}
+	lu, _ := url.Parse(resetTestLink(ms))
+	if lu.Path != customerResetPassword {
+		t.Fail()
+	}
+	tkn := lu.Query().Get("token")
+	w := serveForm(h.StoreAdminResetPassword, adminResetPassword, url.Values{"token": {tkn}, "password": {"newpassword1"}, "confirmPassword": {"newpassword1"}})
+	if !strings.Contains(w.Header().Get("Location"), "error=") {
+		t.Fail()
+	}
+	if _, err := sh.ResetService.Check(tkn); err != nil {
+		t.Fail()
+	}
+	w = serveForm(h.CustomerResetPassword, customerResetPassword, url.Values{"token": {tkn}, "password": {"newpassword1"}, "confirmPassword": {"newpassword1"}})
+	if w.Header().Get("Location") != customerResetPassword+"?done=true" {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_CustomerPasswordResetServiceUser(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	ms := useReset(sh, sapi, dir, "bob@test.com", customerRole)
+	defer os.RemoveAll(dir)
+	ha := &userCallAPI{MockAPI: sapi}
+	sh.API = ha
+	sh.ResetUser = "reset"
+	sh.ResetPassword = "pw"
+	h := sh.GetNew()
+	serveForm(h.CustomerForgotPassword, customerForgotPassword, url.Values{"username": {"bob@test.com"}})
+	lu, _ := url.Parse(resetTestLink(ms))
+	tkn := lu.Query().Get("token")
+	sapi.MockUpdateUserResp = nil
+	w := serveForm(h.CustomerResetPassword, customerResetPassword, url.Values{"token": {tkn}, "password": {"newpassword1"}, "confirmPassword": {"newpassword1"}})
+	fmt.Println("location: ", w.Header().Get("Location"))
+	if !strings.Contains(w.Header().Get("Location"), "error=") || len(ha.headers) < 3 {
+		t.FailNow()
+	}
+	for _, hd := range ha.headers {
+		if !strings.Contains(fmt.Sprint(hd), "Basic cmVzZXQ6cHc=") {
+			fmt.Println("headers: ", hd)
+			t.Fail()
+		}
+	}
+}
+
+func TestSix910Handler_CustomerForgotPasswordUnknown(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	ms := useReset(sh, sapi, dir, "", customerRole)
+	sh.AdminTemplates = resetTestTemplates
+	defer os.RemoveAll(dir)
+	h := sh.GetNew()
+	w := serveForm(h.CustomerForgotPassword, customerForgotPassword, url.Values{"username": {"nobody@test.com"}})
+	if w.Header().Get("Location") != customerForgotPassword+"?sent=true" || len(ms.sent) != 0 {
+		t.Fail()
+	}
+	r, _ := http.NewRequest("GET", customerForgotPassword+"?sent=true", nil)
+	w = httptest.NewRecorder()
+	h.CustomerForgotPasswordPage(w, r)
+	if w.Body.String() != "[true]" {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_PasswordResetChangedPassword(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	useReset(sh, sapi, dir, "bob@test.com", customerRole)
+	sh.AdminTemplates = resetTestTemplates
+	defer os.RemoveAll(dir)
+	tkn, _ := sh.ResetService.NewToken(rsts.ScopeCustomer, "bob@test.com")
+	h := sh.GetNew()
+	r, _ := http.NewRequest("POST", "/admin/editCustomerUser", strings.NewReader("username=bob@test.com&password=changed1"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	s, _ := sh.getSession(r)
+	loginTestAdmin(sh, s)
+	s.Values["storeAdminUser"] = true
+	w := httptest.NewRecorder()
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminEditCustomerUser)).ServeHTTP(w, r)
+	if _, err := sh.ResetService.Check(tkn); err != rsts.ErrUsedToken {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_AdminPasswordResetOauth(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	ms := useReset(sh, sapi, dir, "", "")
+	sh.AdminTemplates = resetTestTemplates
+	defer os.RemoveAll(dir)
+	sh.OAuth2Enabled = true
+	var cc ClientCreds
+	cc.AuthCodeClient = "10"
+	sh.ClientCreds = &cc
+	var cct oauth2.MockClientCredentialsToken
+	var tk oauth2.Token
+	tk.AccessToken = "cctoken"
+	cct.MockToken = &tk
+	sh.ClientCredentials = cct.GetNew()
+	var us userv.MockOauth2UserService
+	var u userv.User
+	u.Username = "admin"
+	u.Enabled = true
+	u.EmailAddress = "admin@test.com"
+	us.MockUser = &u
+	us.MockUserCode = 200
+	var ur userv.UserResponse
+	ur.Success = true
+	us.MockUpdateUserResponse = &ur
+	sh.UserService = us.GetNew()
+	h := sh.GetNew()
+
+	serveForm(h.StoreAdminForgotPassword, adminForgotPassword, url.Values{"username": {"admin"}})
+	if len(ms.sent) != 1 || ms.sent[0].Recipients[0] != "admin@test.com" {
+		t.FailNow()
+	}
+	lu, _ := url.Parse(resetTestLink(ms))
+	w := serveForm(h.StoreAdminResetPassword, adminResetPassword, url.Values{"token": {lu.Query().Get("token")}, "password": {"newpassword1"}, "confirmPassword": {"newpassword1"}})
+	if w.Header().Get("Location") != adminResetPassword+"?done=true" {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_PasswordResetOff(t *testing.T) {
+	var sh Six910Handler
+	var l lg.Logger
+	sh.Log = &l
+	r, _ := http.NewRequest("GET", adminForgotPassword, nil)
+	w := httptest.NewRecorder()
+	sh.StoreAdminForgotPasswordPage(w, r)
+	if w.Code != http.StatusNotFound {
+		t.Fail()
+	}
+}
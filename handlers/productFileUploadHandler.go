@@ -34,68 +34,45 @@ type PageValues struct {
 
 //StoreAdminUploadProductFilePage StoreAdminUploadProductFilePage
 func (h *Six910Handler) StoreAdminUploadProductFilePage(w http.ResponseWriter, r *http.Request) {
-	s, suc := h.getSession(r)
-	h.Log.Debug("session suc", suc)
-	if suc {
-		// loggedInAuth := s.Values["loggedIn"]
-		// storeAdminUser := s.Values["storeAdminUser"]
-		// h.Log.Debug("loggedIn in backups: ", loggedInAuth)
-		// if loggedInAuth == true && storeAdminUser == true {
-		if h.isStoreAdminLoggedIn(s) {
-			h.AdminTemplates.ExecuteTemplate(w, productFileUploadPage, nil)
-		} else {
-			http.Redirect(w, r, adminloginPage, http.StatusFound)
-		}
-	}
+	h.executeAdminTemplate(w, r, productFileUploadPage, nil)
 }
 
 //StoreAdminUploadProductFile StoreAdminUploadProductFile
 func (h *Six910Handler) StoreAdminUploadProductFile(w http.ResponseWriter, r *http.Request) {
-	s, suc := h.getSession(r)
-	h.Log.Debug("session suc", suc)
-	if suc {
-		// loggedInAuth := s.Values["loggedIn"]
-		// storeAdminUser := s.Values["storeAdminUser"]
-		// h.Log.Debug("loggedIn in upload : ", loggedInAuth)
-		// h.Log.Debug("storeAdminUser in upload : ", storeAdminUser)
-		// if loggedInAuth == true && storeAdminUser == true {
-		if h.isStoreAdminLoggedIn(s) {
+	defer h.startJob()()
 
-			uplerr := r.ParseMultipartForm(50000000)
-			h.Log.Debug("ParseMultipartForm err: ", uplerr)
+	uplerr := r.ParseMultipartForm(50000000)
+	h.Log.Debug("ParseMultipartForm err: ", uplerr)
 
-			file, handler, ferr := r.FormFile("productupload")
-			if ferr == nil {
-				defer file.Close()
-			}
-			h.Log.Debug("file err: ", ferr)
+	file, handler, ferr := r.FormFile("productupload")
+	if ferr == nil {
+		defer file.Close()
+	}
+	h.Log.Debug("file err: ", ferr)
 
-			updata, rferr := ioutil.ReadAll(file)
-			h.Log.Debug("read file  err: ", rferr)
-			//h.Log.Debug("read file  bkdata: ", bkdata)
+	updata, rferr := ioutil.ReadAll(file)
+	h.Log.Debug("read file  err: ", rferr)
+	//h.Log.Debug("read file  bkdata: ", bkdata)
 
-			h.Log.Debug("handler.Filename: ", handler.Filename)
+	h.Log.Debug("handler.Filename: ", handler.Filename)
 
-			//h.Log.Debug("updata not zip: ", string(updata))
-			dcupdata := h.extractTarGz(&updata)
-			//h.Log.Debug("updata file in handlers: ", string(dcupdata))
+	//h.Log.Debug("updata not zip: ", string(updata))
+	dcupdata := h.extractTarGz(&updata)
+	//h.Log.Debug("updata file in handlers: ", string(dcupdata))
 
-			hd := h.getHeader(s)
-			suc, notImported := h.Manager.UploadProductFile(dcupdata, hd)
-			h.Log.Debug("notImported: ", notImported)
+	hd := h.getAdminHeader(r)
+	suc, notImported := h.Manager.UploadProductFile(dcupdata, hd)
+	h.Log.Debug("notImported: ", notImported)
 
-			var pg PageValues
-			if suc {
-				pg.Suc = suc
-				pg.RecordsNotImported = notImported
-				h.AdminTemplates.ExecuteTemplate(w, productUploadResultPage, &pg)
-			} else {
-				h.Log.Debug("csv upload of " + handler.Filename + " failed")
-				h.AdminTemplates.ExecuteTemplate(w, productUploadResultPage, &pg)
-			}
-		} else {
-			h.authorize(w, r)
-		}
+	var pg PageValues
+	if suc {
+		h.auditUpload(r, handler.Filename, notImported)
+		pg.Suc = suc
+		pg.RecordsNotImported = notImported
+		h.executeAdminTemplate(w, r, productUploadResultPage, &pg)
+	} else {
+		h.Log.Debug("csv upload of " + handler.Filename + " failed")
+		h.executeAdminTemplate(w, r, productUploadResultPage, &pg)
 	}
 }
 
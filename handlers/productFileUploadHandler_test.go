@@ -38,7 +38,7 @@ func TestSix910Handler_StoreAdminUploadProductFilePage(t *testing.T) {
 	//s.Values["loggedIn"] = true
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminUploadProductFilePage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminUploadProductFilePage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -62,11 +62,11 @@ func TestSix910Handler_StoreAdminUploadProductFilePageLoggedIn(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminUploadProductFilePage(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminUploadProductFilePage)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -181,13 +181,11 @@ func TestSix910Handler_StoreAdminUploadProductFile(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminUploadProductFile(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminUploadProductFile)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -302,13 +300,11 @@ func TestSix910Handler_StoreAdminUploadProductFileNotGzfile(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminUploadProductFile(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminUploadProductFile)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -425,11 +421,9 @@ func TestSix910Handler_StoreAdminUploadProductFileLogin(t *testing.T) {
 	fmt.Println("suc: ", suc)
 	s.Values["loggedIn"] = false
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminUploadProductFile(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminUploadProductFile)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 302 {
@@ -544,13 +538,11 @@ func TestSix910Handler_StoreAdminUploadProductFileFail(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminUploadProductFile(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminUploadProductFile)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
@@ -572,7 +564,6 @@ func TestSix910Handler_StoreAdminUploadProductFileOauth(t *testing.T) {
 	mockAcTkn.MockToken = &mTkn
 
 	sh.Auth = &mockAcTkn
-	sh.token = &mTkn
 
 	var sapi mapi.MockAPI
 	sapi.SetStoreID(59)
@@ -675,13 +666,11 @@ func TestSix910Handler_StoreAdminUploadProductFileOauth(t *testing.T) {
 	w := httptest.NewRecorder()
 	s, suc := sh.getSession(r)
 	fmt.Println("suc: ", suc)
-	s.Values["loggedIn"] = true
+	loginTestAdmin(&sh, s)
 	s.Values["storeAdminUser"] = true
-	s.Values["username"] = "tester"
-	s.Values["password"] = "tester"
 	s.Save(r, w)
 	h := sh.GetNew()
-	h.StoreAdminUploadProductFile(w, r)
+	h.AdminAuth(http.HandlerFunc(h.StoreAdminUploadProductFile)).ServeHTTP(w, r)
 	fmt.Println("code: ", w.Code)
 
 	if w.Code != 200 {
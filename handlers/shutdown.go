@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+//startJob registers long running work such as product imports and
+//backups; call the returned func when the work is done
+func (h *Six910Handler) startJob() func() {
+	h.jobs.Add(1)
+	return h.jobs.Done
+}
+
+//Shutdown Shutdown waits for running jobs until ctx is done, then
+//saves content hits. The json datastores write every Save straight to
+//disk so there is nothing else to flush. It may be called again, such as
+//on a second signal.
+func (h *Six910Handler) Shutdown(ctx context.Context) error {
+	var rtn error
+	h.stockOnce.Do(func() {
+		if h.stockStop != nil {
+			close(h.stockStop)
+		}
+	})
+	done := make(chan struct{})
+	go func() {
+		h.jobs.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		h.Log.Info("all jobs finished")
+	case <-ctx.Done():
+		rtn = ctx.Err()
+		h.Log.Error("shutdown deadline passed with jobs still running: ", rtn)
+	}
+	if h.ContentService != nil {
+		h.ContentService.SaveHits()
+		h.Log.Info("content hits saved")
+	}
+	return rtn
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	lg "github.com/Ulbora/Level_Logger"
+	conts "github.com/Ulbora/Six910-ui/contsrv"
+	ds "github.com/Ulbora/json-datastore"
+)
+
+func TestSix910Handler_Shutdown(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "six910shut")
+	defer os.RemoveAll(dir)
+
+	var l lg.Logger
+	l.LogLevel = lg.AllLevel
+
+	var cds ds.DataStore
+	cds.Path = dir
+	var ci conts.CmsService
+	ci.Store = cds.GetNew()
+	ci.Log = &l
+	ci.HitLimit = 100
+	cs := ci.GetNew()
+	var ct conts.Content
+	ct.Name = "page1"
+	ct.Text = "some text"
+	cs.AddContent(&ct)
+	cs.GetContent("page1")
+	cs.GetContent("page1")
+
+	var sh Six910Handler
+	sh.Log = &l
+	sh.ContentService = cs
+
+	done := sh.startJob()
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		done()
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := sh.Shutdown(ctx)
+	fmt.Println("shutdown err: ", err)
+	if err != nil {
+		t.Fail()
+	}
+	cds2 := cds.GetNew()
+	var saved conts.Content
+	json.Unmarshal(*cds2.Read("page1"), &saved)
+	fmt.Println("saved hits: ", saved.Hits)
+	if saved.Hits != 2 {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_ShutdownDeadline(t *testing.T) {
+	var l lg.Logger
+	var sh Six910Handler
+	sh.Log = &l
+
+	done := sh.startJob()
+	defer done()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := sh.Shutdown(ctx)
+	if err != context.DeadlineExceeded {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_ShutdownTwice(t *testing.T) {
+	var l lg.Logger
+	var sh Six910Handler
+	sh.Log = &l
+	sh.StockAlertService = newTestStockService()
+	sh.StartStockAlerts(time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := sh.Shutdown(ctx); err != nil {
+		t.Fail()
+	}
+	if err := sh.Shutdown(ctx); err != nil {
+		t.Fail()
+	}
+}
@@ -4,13 +4,26 @@ import (
 	b64 "encoding/base64"
 	"html/template"
 	"net/http"
+	"sync"
+	"time"
 
 	lg "github.com/Ulbora/Level_Logger"
+	apitok "github.com/Ulbora/Six910-ui/apitoksrv"
+	audit "github.com/Ulbora/Six910-ui/auditsrv"
 	bks "github.com/Ulbora/Six910-ui/bkupsrv"
 	conts "github.com/Ulbora/Six910-ui/contsrv"
+	cust "github.com/Ulbora/Six910-ui/custsrv"
 	imgs "github.com/Ulbora/Six910-ui/imgsrv"
+	"github.com/Ulbora/Six910-ui/logging"
 	mails "github.com/Ulbora/Six910-ui/mailsrv"
 	m "github.com/Ulbora/Six910-ui/managers"
+	perms "github.com/Ulbora/Six910-ui/permsrv"
+	rsts "github.com/Ulbora/Six910-ui/resetsrv"
+	ss "github.com/Ulbora/Six910-ui/sessrv"
+	stock "github.com/Ulbora/Six910-ui/stocksrv"
+	thr "github.com/Ulbora/Six910-ui/throtsrv"
+	tmpts "github.com/Ulbora/Six910-ui/tmptsrv"
+	totps "github.com/Ulbora/Six910-ui/totpsrv"
 	users "github.com/Ulbora/Six910-ui/usersrv"
 	api "github.com/Ulbora/Six910API-Go"
 	oauth2 "github.com/Ulbora/go-oauth2-client"
@@ -51,6 +64,10 @@ type Six910Handler struct {
 	Templates      *template.Template
 	AdminTemplates *template.Template
 	Store          *sessions.CookieStore
+	AdminSessions  ss.Store
+
+	//CustomerSessions holds customers logged in to the store API
+	CustomerSessions cust.Store
 
 	//services
 	BackupService  bks.BackupService
@@ -59,22 +76,79 @@ type Six910Handler struct {
 	MailService    mails.MailService
 	UserService    users.UserService
 
+	TemplateService  tmpts.TemplateService
+	TwoFactorService totps.Service
+	LoginThrottle    thr.Throttle
+	ResetService     rsts.Service
+
+	PermissionService perms.Service
+	AuditService      audit.Service
+	APITokenService   apitok.Service
+
+	//StockAlertService runs every StartStockAlerts interval as
+	//StockAlertUser when OAuth2 is off
+	StockAlertService  stock.Service
+	StockAlertUser     string
+	StockAlertPassword string
+
+	//ResetUser looks up and updates users for password reset when OAuth2
+	//is off
+	ResetUser     string
+	ResetPassword string
+
 	OauthHost     string
 	UserHost      string
 	SchemeDefault string // = "http://"
 	Auth          oauth2.AuthToken
-	token         *oauth2.Token
 	ClientCreds   *ClientCreds
 
+	//ClientCredentials gets a token for the user service when nobody is
+	//logged in, such as during a password reset
+	ClientCredentials oauth2.Credentials
+
 	BackendURL    string
 	StoreName     string
 	LocalDomain   string
 	APIKey        string
 	OAuth2Enabled bool
+	SuperAdmins   []string
+	PublicURL     string
+	MailFrom      string
+
+	ContentStorePath  string
+	TemplateStorePath string
+	TemplateFilePath  string
+	ImagePath         string
+
+	//OrderStatuses are counted on the dashboard, which gives each widget
+	//DashboardTimeout (3 seconds when zero) to load
+	OrderStatuses    []string
+	DashboardTimeout time.Duration
+
+	jobs   sync.WaitGroup
+	authMu sync.Mutex
+
+	stockStop chan struct{}
+	stockOnce sync.Once
 }
 
-//GetNew GetNew
+//GetNew GetNew makes an in-memory admin session store and login throttle
+//when none were set. It is called once before serving so requests never
+//build them.
 func (h *Six910Handler) GetNew() Handler {
+	if h.AdminSessions == nil {
+		var ms ss.MemoryStore
+		h.AdminSessions = ms.GetNew()
+	}
+	if h.LoginThrottle == nil {
+		var ts thr.MemoryStore
+		var lt thr.Six910Throttle
+		lt.Store = ts.GetNew()
+		lt.UserPolicy = thr.DefaultUserPolicy()
+		lt.IPPolicy = thr.DefaultIPPolicy()
+		lt.Log = h.Log
+		h.LoginThrottle = lt.GetNew()
+	}
 	return h
 }
 
@@ -84,7 +158,9 @@ func (h *Six910Handler) getSession(r *http.Request) (*sessions.Session, bool) {
 	var srtn *sessions.Session
 	if h.Store == nil {
 		h.Session.Name = "goauth2-ui"
-		h.Session.MaxAge = 3600
+		if h.Session.MaxAge == 0 {
+			h.Session.MaxAge = 3600
+		}
 		h.Store = h.Session.InitSessionStore()
 		h.Log.Debug("h.Store : ", h.Store)
 		//errors without this
@@ -121,30 +197,45 @@ func (h *Six910Handler) getSession(r *http.Request) (*sessions.Session, bool) {
 	return srtn, suc
 }
 
-func (h *Six910Handler) getHeader(s *sessions.Session) *api.Headers {
+func (h *Six910Handler) getHeader(as *ss.AdminSession, r *http.Request) *api.Headers {
 	var hd api.Headers
 	if !h.OAuth2Enabled {
-		var sEnccl string
-		username := s.Values["username"]
-		password := s.Values["password"]
-		if username != nil && password != nil {
-			sEnccl = b64.StdEncoding.EncodeToString([]byte(username.(string) + ":" + password.(string)))
-		}
-		h.Log.Debug("sEnc: ", sEnccl)
+		sEnccl := b64.StdEncoding.EncodeToString([]byte(as.Username + ":" + as.Password))
 		hd.Set("Authorization", "Basic "+sEnccl)
 	} else {
-		hd.Set("Authorization", "Bearer "+h.token.AccessToken)
+		hd.Set("Authorization", "Bearer "+as.AccessToken)
 	}
+	hd.Set(logging.RequestIDHeader, logging.RequestID(r))
 	return &hd
 }
 
-func (h *Six910Handler) isStoreAdminLoggedIn(s *sessions.Session) bool {
-	var rtn bool
-	loggedInAuthpa := s.Values["loggedIn"]
+//getStoreAdmin returns the server side session named by the cookie, or
+//nil when the admin is not logged in or the session has expired or been
+//revoked. OAuth2 logins also need a token that is still valid after any
+//refresh.
+func (h *Six910Handler) getStoreAdmin(s *sessions.Session) *ss.AdminSession {
+	var rtn *ss.AdminSession
 	storeAdminUserpa := s.Values["storeAdminUser"]
-	h.Log.Debug("loggedIn in backups: ", loggedInAuthpa)
-	if loggedInAuthpa == true && storeAdminUserpa == true {
-		rtn = true
+	id, _ := s.Values[adminSessionIDKey].(string)
+	if storeAdminUserpa == true && id != "" {
+		rtn = h.AdminSessions.Get(id)
+	}
+	if rtn != nil && rtn.TwoFactorPending {
+		rtn = nil
 	}
+	if rtn != nil && h.OAuth2Enabled && !h.refreshAdminToken(rtn) {
+		rtn = nil
+	}
+	h.Log.Debug("admin session found in getStoreAdmin: ", rtn != nil)
 	return rtn
 }
+
+//isSuperAdmin isSuperAdmin
+func (h *Six910Handler) isSuperAdmin(username string) bool {
+	for _, sa := range h.SuperAdmins {
+		if username != "" && sa == username {
+			return true
+		}
+	}
+	return false
+}
@@ -1,12 +1,195 @@
 package handlers
 
 import (
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	lg "github.com/Ulbora/Level_Logger"
+	apitok "github.com/Ulbora/Six910-ui/apitoksrv"
+	audit "github.com/Ulbora/Six910-ui/auditsrv"
+	cust "github.com/Ulbora/Six910-ui/custsrv"
+	m "github.com/Ulbora/Six910-ui/managers"
+	mapi "github.com/Ulbora/Six910-ui/mockapi"
+	rsts "github.com/Ulbora/Six910-ui/resetsrv"
+	totps "github.com/Ulbora/Six910-ui/totpsrv"
+	userv "github.com/Ulbora/Six910-ui/usersrv"
+	api "github.com/Ulbora/Six910API-Go"
+	ml "github.com/Ulbora/go-mail-sender"
+	oauth2 "github.com/Ulbora/go-oauth2-client"
+	ds "github.com/Ulbora/json-datastore"
+	"github.com/gorilla/mux"
 )
 
+//testHandler is the handler the tests share, with a mock API for store 59
+//and a temp dir the test removes. The audit log is kept in the dir.
+func testHandler(t *testing.T) (*Six910Handler, *mapi.MockAPI, string) {
+	var sh Six910Handler
+	var l lg.Logger
+	l.LogLevel = lg.AllLevel
+	sh.Log = &l
+	var sapi mapi.MockAPI
+	sapi.SetStoreID(59)
+	sh.API = &sapi
+	dir, err := ioutil.TempDir("", "six910test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var as audit.Six910AuditService
+	as.Path = dir
+	as.Log = &l
+	sh.AuditService = as.GetNew()
+	sh.GetNew()
+	return &sh, &sapi, dir
+}
+
+//useTwoFactor keeps two factor secrets in dir for the admin the mock API
+//returns
+func useTwoFactor(sh *Six910Handler, sapi *mapi.MockAPI, dir string) {
+	var d ds.DataStore
+	d.Path = dir
+	var ts totps.Six910TotpService
+	ts.Store = d.GetNew()
+	ts.Issuer = "teststore"
+	ts.Log = sh.Log
+	sh.TwoFactorService = ts.GetNew()
+	sh.SuperAdmins = []string{"super"}
+	var user api.UserResponse
+	user.Username = "tester"
+	user.Role = storeAdmin
+	user.Enabled = true
+	sapi.MockUser = &user
+}
+
+type testMailService struct {
+	sent []ml.Mailer
+}
+
+func (m *testMailService) SendMail(mailer *ml.Mailer) bool {
+	m.sent = append(m.sent, *mailer)
+	return true
+}
+
+//useReset keeps password resets in dir and mails them to the returned
+//service, for the user the mock API returns
+func useReset(sh *Six910Handler, sapi *mapi.MockAPI, dir string, username string, role string) *testMailService {
+	sh.StoreName = "teststore"
+	sh.PublicURL = "https://shop.test.com/"
+	sh.MailFrom = "store@test.com"
+	var d ds.DataStore
+	d.Path = dir
+	var rs rsts.Six910ResetService
+	rs.Store = d.GetNew()
+	rs.Key = []byte("testkey1234567890")
+	rs.Log = sh.Log
+	sh.ResetService = rs.GetNew()
+	var ms testMailService
+	sh.MailService = &ms
+	var user api.UserResponse
+	user.Username = username
+	user.Role = role
+	user.Enabled = true
+	sapi.MockUser = &user
+	var ur api.Response
+	ur.Success = true
+	sapi.MockUpdateUserResp = &ur
+	return &ms
+}
+
+//userCallAPI records the headers and updates sent with backend user calls
+type userCallAPI struct {
+	*mapi.MockAPI
+	headers []*api.Headers
+	updated []api.User
+}
+
+func (a *userCallAPI) GetUser(u *api.User, headers *api.Headers) *api.UserResponse {
+	a.headers = append(a.headers, headers)
+	return a.MockAPI.GetUser(u, headers)
+}
+
+func (a *userCallAPI) UpdateUser(u *api.User, headers *api.Headers) *api.Response {
+	a.headers = append(a.headers, headers)
+	a.updated = append(a.updated, *u)
+	return a.MockAPI.UpdateUser(u, headers)
+}
+
+//serveAsAdmin runs hf for a logged in admin
+func serveAsAdmin(sh *Six910Handler, hf http.HandlerFunc, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	s, _ := sh.getSession(r)
+	if sh.OAuth2Enabled {
+		var tk oauth2.Token
+		tk.AccessToken = "admintoken"
+		loginTestOAuthAdmin(sh, s, &tk)
+	} else {
+		loginTestAdmin(sh, s)
+	}
+	s.Values["storeAdminUser"] = true
+	sh.AdminAuth(hf).ServeHTTP(w, r)
+	return w
+}
+
+//useOauthAdminUsers turns on OAuth2 with a user service holding jane and bob
+func useOauthAdminUsers(sh *Six910Handler) *userv.MockOauth2UserService {
+	sh.OAuth2Enabled = true
+	var cc ClientCreds
+	cc.AuthCodeClient = "10"
+	sh.ClientCreds = &cc
+	var us userv.MockOauth2UserService
+	us.MockUserList = &[]userv.User{{Username: "jane", EmailAddress: "jane@test.com", Enabled: true}, {Username: "bob"}}
+	us.MockUser = &(*us.MockUserList)[0]
+	us.MockUserCode = 200
+	sh.UserService = &us
+	return &us
+}
+
+//useAPITokens keeps admin API tokens in dir
+func useAPITokens(sh *Six910Handler, dir string) {
+	var tds ds.DataStore
+	tds.Path = dir
+	var ts apitok.Six910TokenService
+	ts.Store = tds.GetNew()
+	ts.Log = sh.Log
+	sh.APITokenService = ts.GetNew()
+}
+
+//useStoreAPI sets the manager and customer sessions the store API needs
+func useStoreAPI(sh *Six910Handler, sapi *mapi.MockAPI) {
+	var man m.Six910Manager
+	man.API = sapi
+	man.Log = sh.Log
+	sh.Manager = man.GetNew()
+	var cs cust.MemoryStore
+	sh.CustomerSessions = cs.GetNew()
+}
+
+func serveStore(sh *Six910Handler, token string, method string, url string, body string) *httptest.ResponseRecorder {
+	router := mux.NewRouter()
+	sa := router.PathPrefix("/api/v1").Subrouter()
+	sa.HandleFunc("/products", sh.StoreAPIProductList).Methods("GET")
+	sa.HandleFunc("/products/{id:[0-9]+}", sh.StoreAPIProduct).Methods("GET")
+	sa.HandleFunc("/categories", sh.StoreAPICategoryList).Methods("GET")
+	sa.HandleFunc("/customers", sh.StoreAPICreateAccount).Methods("POST")
+	sa.HandleFunc("/login", sh.StoreAPILogin).Methods("POST")
+	sa.Handle("/logout", sh.StoreAPIAuth(http.HandlerFunc(sh.StoreAPILogout))).Methods("POST")
+	sa.Handle("/cart", sh.StoreAPIAuth(http.HandlerFunc(sh.StoreAPIViewCart))).Methods("GET")
+	sa.Handle("/cart/items", sh.StoreAPIAuth(http.HandlerFunc(sh.StoreAPIAddToCart))).Methods("POST")
+	sa.Handle("/cart/items/{id:[0-9]+}", sh.StoreAPIAuth(http.HandlerFunc(sh.StoreAPIUpdateCart))).Methods("PUT")
+	sa.Handle("/checkout", sh.StoreAPIAuth(http.HandlerFunc(sh.StoreAPICheckOut))).Methods("POST")
+	sa.Handle("/orders", sh.StoreAPIAuth(http.HandlerFunc(sh.StoreAPIOrderList))).Methods("GET")
+	r, _ := http.NewRequest(method, url, strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	return w
+}
+
 func TestSix910Handler_getSession(t *testing.T) {
 	var h Six910Handler
 	var l lg.Logger
@@ -18,3 +201,31 @@ func TestSix910Handler_getSession(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestSix910Handler_GetNewAdminSessions(t *testing.T) {
+	var sh Six910Handler
+	var l lg.Logger
+	sh.Log = &l
+	h := sh.GetNew().(*Six910Handler)
+	st := h.AdminSessions
+	h.GetNew()
+	r, _ := http.NewRequest("GET", "https://test.com", nil)
+	h.getSession(r)
+	if st == nil || h.AdminSessions != st {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_GetNewLoginThrottle(t *testing.T) {
+	var sh Six910Handler
+	var l lg.Logger
+	sh.Log = &l
+	h := sh.GetNew().(*Six910Handler)
+	lt := h.LoginThrottle
+	h.GetNew()
+	r, _ := http.NewRequest("GET", "https://test.com", nil)
+	h.getSession(r)
+	if lt == nil || h.LoginThrottle != lt {
+		t.Fail()
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	b64 "encoding/base64"
+	"net/http"
+	"time"
+
+	stock "github.com/Ulbora/Six910-ui/stocksrv"
+	api "github.com/Ulbora/Six910API-Go"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+//StockAlertPage StockAlertPage
+type StockAlertPage struct {
+	Groups []stock.Group
+	Count  int
+}
+
+//StoreAdminViewStockAlerts StoreAdminViewStockAlerts lists the products
+//at or below their stock alert level and back ordered products by
+//distributor
+func (h *Six910Handler) StoreAdminViewStockAlerts(w http.ResponseWriter, r *http.Request) {
+	if h.StockAlertService == nil {
+		http.NotFound(w, r)
+		return
+	}
+	al := h.StockAlertService.Scan(h.getAdminHeader(r))
+	var sp StockAlertPage
+	sp.Groups = stock.GroupByDistributor(al)
+	sp.Count = len(*al)
+	h.Log.Debug("stock alerts in list: ", sp.Count)
+	h.executeAdminTemplate(w, r, adminStockAlertPage, &sp)
+}
+
+//StoreAdminExportStockAlerts StoreAdminExportStockAlerts sends the list
+//page as CSV
+func (h *Six910Handler) StoreAdminExportStockAlerts(w http.ResponseWriter, r *http.Request) {
+	if h.StockAlertService == nil {
+		http.NotFound(w, r)
+		return
+	}
+	al := h.StockAlertService.Scan(h.getAdminHeader(r))
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", "attachment; filename=\"stock-alerts-"+time.Now().Format(auditDateFormat)+".csv\"")
+	if err := stock.WriteCSV(w, al); err != nil {
+		h.Log.Error("stock alert csv export failed: ", err)
+	}
+}
+
+//StartStockAlerts StartStockAlerts runs the stock alert job now and then
+//every interval until Shutdown
+func (h *Six910Handler) StartStockAlerts(interval time.Duration) {
+	h.stockStop = make(chan struct{})
+	go func(stop chan struct{}) {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			h.runStockAlerts()
+			select {
+			case <-t.C:
+			case <-stop:
+				return
+			}
+			select {
+			case <-stop:
+				return
+			default:
+			}
+		}
+	}(h.stockStop)
+}
+
+func (h *Six910Handler) runStockAlerts() {
+	done := h.startJob()
+	defer done()
+	n, suc := h.StockAlertService.Notify(h.stockAlertHeader())
+	h.Log.Info("stock alert job mailed ", n, " products, success: ", suc)
+}
+
+//stockAlertHeader stockAlertHeader calls the backend with a client
+//credentials token or, with Basic auth, the stock alert user since
+//nobody is logged in
+func (h *Six910Handler) stockAlertHeader() *api.Headers {
+	return h.serviceHeader(h.StockAlertUser, h.StockAlertPassword)
+}
+
+//serviceHeader serviceHeader calls the backend with a client credentials
+//token, or as user with Basic auth, for work nobody is logged in for
+func (h *Six910Handler) serviceHeader(user string, password string) *api.Headers {
+	var hd api.Headers
+	if h.OAuth2Enabled {
+		if h.ClientCredentials != nil {
+			if t := h.ClientCredentials.ClientCredentialsToken(); t != nil {
+				hd.Set("Authorization", "Bearer "+t.AccessToken)
+			}
+		}
+	} else {
+		sEnccl := b64.StdEncoding.EncodeToString([]byte(user + ":" + password))
+		hd.Set("Authorization", "Basic "+sEnccl)
+	}
+	return &hd
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	lg "github.com/Ulbora/Level_Logger"
+	stock "github.com/Ulbora/Six910-ui/stocksrv"
+	api "github.com/Ulbora/Six910API-Go"
+	oauth2 "github.com/Ulbora/go-oauth2-client"
+)
+
+type testStockService struct {
+	mu      sync.Mutex
+	alerts  []stock.Alert
+	headers []*api.Headers
+}
+
+func (s *testStockService) Scan(hd *api.Headers) *[]stock.Alert {
+	return &s.alerts
+}
+
+func (s *testStockService) Notify(hd *api.Headers) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.headers = append(s.headers, hd)
+	return len(s.alerts), true
+}
+
+func (s *testStockService) runs() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.headers)
+}
+
+func newTestStockService() *testStockService {
+	var ts testStockService
+	ts.alerts = []stock.Alert{
+		{ProductID: 1, Sku: "s1", Name: "bolt", Stock: 0, StockAlert: 5, Distributor: "Acme"},
+		{ProductID: 2, Sku: "s2", Name: "nut", Stock: 3, StockAlert: 5, BackOrdered: 2, Distributor: "Acme"},
+		{ProductID: 3, Sku: "s3", Name: "axle", Stock: 1, StockAlert: 2, Distributor: stock.NoDistributor},
+	}
+	return &ts
+}
+
+func TestSix910Handler_StoreAdminViewStockAlerts(t *testing.T) {
+	sh, _, dir := testHandler(t)
+	defer os.RemoveAll(dir)
+	sh.StockAlertService = newTestStockService()
+	sh.AdminTemplates = template.Must(template.ParseFiles("testHtmls/test.html"))
+	r, _ := http.NewRequest("GET", "/admin/stockAlertView", nil)
+	w := serveAsAdmin(sh, sh.StoreAdminViewStockAlerts, r)
+	if w.Code != 200 {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAdminExportStockAlerts(t *testing.T) {
+	sh, _, dir := testHandler(t)
+	defer os.RemoveAll(dir)
+	sh.StockAlertService = newTestStockService()
+	sh.AdminTemplates = template.Must(template.ParseFiles("testHtmls/test.html"))
+	r, _ := http.NewRequest("GET", "/admin/stockAlertExport", nil)
+	w := serveAsAdmin(sh, sh.StoreAdminExportStockAlerts, r)
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	fmt.Println("csv: ", w.Body.String())
+	if w.Code != 200 || w.Header().Get("Content-Type") != "text/csv" || len(lines) != 4 || !strings.HasPrefix(lines[1], "Acme,1,s1,bolt,0,5,true,0") {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StockAlertsOff(t *testing.T) {
+	var sh Six910Handler
+	var l lg.Logger
+	sh.Log = &l
+	r, _ := http.NewRequest("GET", "/admin/stockAlertView", nil)
+	if w := serveAsAdmin(&sh, sh.StoreAdminViewStockAlerts, r); w.Code != 404 {
+		t.Fail()
+	}
+	r2, _ := http.NewRequest("GET", "/admin/stockAlertExport", nil)
+	if w := serveAsAdmin(&sh, sh.StoreAdminExportStockAlerts, r2); w.Code != 404 {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StartStockAlerts(t *testing.T) {
+	sh, _, dir := testHandler(t)
+	defer os.RemoveAll(dir)
+	ts := newTestStockService()
+	sh.StockAlertService = ts
+	sh.AdminTemplates = template.Must(template.ParseFiles("testHtmls/test.html"))
+	sh.StockAlertUser = "stock"
+	sh.StockAlertPassword = "pw"
+	sh.StartStockAlerts(10 * time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	if err := sh.Shutdown(context.Background()); err != nil {
+		t.Fail()
+	}
+	n := ts.runs()
+	time.Sleep(30 * time.Millisecond)
+	fmt.Println("stock alert runs: ", n)
+	if n < 2 || ts.runs() != n {
+		t.Fail()
+	}
+	if !strings.Contains(fmt.Sprint(ts.headers[0]), "Basic c3RvY2s6cHc=") {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_stockAlertHeaderOauth(t *testing.T) {
+	sh, _, dir := testHandler(t)
+	defer os.RemoveAll(dir)
+	sh.StockAlertService = newTestStockService()
+	sh.AdminTemplates = template.Must(template.ParseFiles("testHtmls/test.html"))
+	sh.OAuth2Enabled = true
+	var cct oauth2.MockClientCredentialsToken
+	var tk oauth2.Token
+	tk.AccessToken = "cctoken"
+	cct.MockToken = &tk
+	sh.ClientCredentials = cct.GetNew()
+	if hd := sh.stockAlertHeader(); !strings.Contains(fmt.Sprint(hd), "Bearer cctoken") {
+		t.Fail()
+	}
+}
@@ -0,0 +1,626 @@
+package handlers
+
+import (
+	"context"
+	b64 "encoding/base64"
+	"errors"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	cust "github.com/Ulbora/Six910-ui/custsrv"
+	"github.com/Ulbora/Six910-ui/logging"
+	m "github.com/Ulbora/Six910-ui/managers"
+	api "github.com/Ulbora/Six910API-Go"
+	sdbi "github.com/Ulbora/six910-database-interface"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+type storeCtxKey int
+
+const customerSessionKey storeCtxKey = 0
+
+//storeScanChunks limits how many backend pages one product list request
+//reads looking for visible products
+const storeScanChunks = 5
+
+//StoreProduct StoreProduct is a product as customers see it, without
+//cost, MAP, stock alert or distributor
+type StoreProduct struct {
+	ID              int64   `json:"id"`
+	Sku             string  `json:"sku"`
+	Gtin            string  `json:"gtin"`
+	Name            string  `json:"name"`
+	ShortDesc       string  `json:"shortDesc"`
+	Desc            string  `json:"desc"`
+	Msrp            float64 `json:"msrp"`
+	Price           float64 `json:"price"`
+	SalePrice       float64 `json:"salePrice"`
+	Currency        string  `json:"currency"`
+	Manufacturer    string  `json:"manufacturer"`
+	Stock           int64   `json:"stock"`
+	Weight          float64 `json:"weight"`
+	FreeShipping    bool    `json:"freeShipping"`
+	Promoted        bool    `json:"promoted"`
+	Size            string  `json:"size"`
+	Color           string  `json:"color"`
+	Thumbnail       string  `json:"thumbnail"`
+	Image1          string  `json:"image1"`
+	Image2          string  `json:"image2"`
+	Image3          string  `json:"image3"`
+	Image4          string  `json:"image4"`
+	ParentProductID int64   `json:"parentProductId"`
+}
+
+//StoreProductList StoreProductList is a page of visible products. The
+//next page starts at Next while HasMore is true.
+type StoreProductList struct {
+	Data    []StoreProduct `json:"data"`
+	Next    int64          `json:"next"`
+	HasMore bool           `json:"hasMore"`
+}
+
+//StoreLogin StoreLogin
+type StoreLogin struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+//StoreAccount StoreAccount creates a customer who logs in with the
+//customer's email
+type StoreAccount struct {
+	Customer  sdbi.Customer  `json:"customer"`
+	Addresses []sdbi.Address `json:"addresses"`
+	Password  string         `json:"password"`
+}
+
+//StoreToken StoreToken is sent back as a Bearer token on customer calls
+type StoreToken struct {
+	Token      string `json:"token"`
+	CustomerID int64  `json:"customerId"`
+}
+
+//StoreCartItem StoreCartItem
+type StoreCartItem struct {
+	ProductID int64   `json:"productId"`
+	Quantity  int64   `json:"quantity"`
+	Name      string  `json:"name"`
+	Thumbnail string  `json:"thumbnail"`
+	Price     float64 `json:"price"`
+	Total     float64 `json:"total"`
+}
+
+//StoreCart StoreCart prices items at the product's current price
+type StoreCart struct {
+	ID       int64           `json:"id"`
+	Items    []StoreCartItem `json:"items"`
+	Subtotal float64         `json:"subtotal"`
+}
+
+//StoreCartChange StoreCartChange
+type StoreCartChange struct {
+	ProductID int64 `json:"productId"`
+	Quantity  int64 `json:"quantity"`
+}
+
+//StoreCheckout StoreCheckout. A shipping method is needed unless the
+//order is picked up; insurance uses the method's insurance.
+type StoreCheckout struct {
+	ShippingMethodID int64  `json:"shippingMethodId"`
+	Pickup           bool   `json:"pickup"`
+	Insurance        bool   `json:"insurance"`
+	OrderType        string `json:"orderType"`
+	Comment          string `json:"comment"`
+}
+
+//StoreOrder StoreOrder
+type StoreOrder struct {
+	Order    *sdbi.Order          `json:"order"`
+	Items    *[]sdbi.OrderItem    `json:"items"`
+	Comments *[]sdbi.OrderComment `json:"comments"`
+}
+
+//StoreAPIAuth StoreAPIAuth lets a request through with the Bearer token
+//of a logged in customer
+func (h *Six910Handler) StoreAPIAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var cs *cust.Session
+		if bt := bearerToken(r); bt != "" && h.CustomerSessions != nil {
+			cs = h.CustomerSessions.Get(bt)
+		}
+		if cs == nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			h.writeAPIError(w, http.StatusUnauthorized, "customer login required")
+			return
+		}
+		logging.SetUser(r, cs.Username)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), customerSessionKey, cs)))
+	})
+}
+
+//StoreAPIProductList StoreAPIProductList lists visible products, by name
+//(only searchable ones) or category when asked. start is where the
+//backend list is read from.
+func (h *Six910Handler) StoreAPIProductList(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	start, serr := strconv.ParseInt(q.Get("start"), 10, 64)
+	if q.Get("start") == "" {
+		start, serr = 0, nil
+	}
+	p, perr := apiPaging(r)
+	cid, cok := apiQueryID(r, "categoryId")
+	name := strings.TrimSpace(q.Get("name"))
+	switch {
+	case serr != nil || start < 0:
+		h.writeAPIError(w, http.StatusBadRequest, "start must be a number from 0")
+		return
+	case perr != nil:
+		h.writeAPIError(w, http.StatusBadRequest, perr.Error())
+		return
+	case !cok:
+		h.writeAPIError(w, http.StatusBadRequest, errQueryID.Error())
+		return
+	case name != "" && cid != 0:
+		h.writeAPIError(w, http.StatusBadRequest, "use name or categoryId, not both")
+		return
+	}
+	hd := resetHeader(r)
+	get := func(st int64, end int64) *[]sdbi.Product {
+		if name != "" {
+			return h.API.GetProductsByName(url.PathEscape(name), st, end, hd)
+		} else if cid != 0 {
+			return h.API.GetProductsByCaterory(cid, st, end, hd)
+		}
+		return h.API.GetProductList(st, end, hd)
+	}
+	h.writeAPI(w, http.StatusOK, storeProducts(get, start, int64(p.size), name != ""))
+}
+
+//StoreAPIProduct StoreAPIProduct answers 404 for a hidden product
+func (h *Six910Handler) StoreAPIProduct(w http.ResponseWriter, r *http.Request) {
+	id, ok := apiID(r)
+	if ok {
+		p := h.API.GetProductByID(id, resetHeader(r))
+		if p != nil && p.ID == id && p.Visible {
+			h.writeAPI(w, http.StatusOK, storeProductOf(p))
+			return
+		}
+	}
+	h.writeAPIError(w, http.StatusNotFound, "not found")
+}
+
+//StoreAPICategoryList StoreAPICategoryList
+func (h *Six910Handler) StoreAPICategoryList(w http.ResponseWriter, r *http.Request) {
+	p, err := apiPaging(r)
+	if err != nil {
+		h.writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.writeAPI(w, http.StatusOK, p.slice(h.API.GetCategoryList(resetHeader(r))))
+}
+
+//StoreAPILogin StoreAPILogin answers 429 with Retry-After while the
+//username or client IP is locked out
+func (h *Six910Handler) StoreAPILogin(w http.ResponseWriter, r *http.Request) {
+	var sl StoreLogin
+	if err := readAPI(w, r, &sl); err != nil {
+		h.writeAPIError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	if h.LoginThrottle != nil {
+		if wait := h.LoginThrottle.Check(m.CustomerLoginKeys(sl.Username, clientIP(r))...); wait > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			h.writeAPIError(w, http.StatusTooManyRequests, "too many failed logins")
+			return
+		}
+	}
+	st := h.customerLogin(r, sl.Username, sl.Password)
+	if st == nil {
+		h.writeAPIError(w, http.StatusUnauthorized, "invalid username or password")
+		return
+	}
+	h.writeAPI(w, http.StatusOK, st)
+}
+
+//StoreAPILogout StoreAPILogout
+func (h *Six910Handler) StoreAPILogout(w http.ResponseWriter, r *http.Request) {
+	h.CustomerSessions.Delete(getCustomerSession(r).Token)
+	h.writeAPI(w, http.StatusOK, &APIResult{Success: true})
+}
+
+//StoreAPICreateAccount StoreAPICreateAccount creates the customer, their
+//addresses and login, then logs them in. An email that is already a
+//customer gets 409 so nobody can add addresses to another account.
+func (h *Six910Handler) StoreAPICreateAccount(w http.ResponseWriter, r *http.Request) {
+	var sa StoreAccount
+	if err := readAPI(w, r, &sa); err != nil {
+		h.writeAPIError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	fe := validateCustomer(&sa.Customer)
+	if !strings.Contains(sa.Customer.Email, "@") {
+		fe.add("email", "must be an email address")
+	}
+	if len(sa.Password) < minPasswordLength {
+		fe.add("password", "must be at least "+strconv.Itoa(minPasswordLength)+" characters")
+	}
+	if len(fe) > 0 {
+		h.writeAPIFields(w, fe)
+		return
+	}
+	hd := resetHeader(r)
+	if ec := h.API.GetCustomer(sa.Customer.Email, hd); ec != nil && ec.ID != 0 {
+		h.writeAPIError(w, http.StatusConflict, "an account already exists for that email")
+		return
+	}
+	var ca m.CustomerAccount
+	sa.Customer.ID = 0
+	ca.Customer = &sa.Customer
+	if sa.Addresses == nil {
+		sa.Addresses = []sdbi.Address{}
+	}
+	ca.Addresses = &sa.Addresses
+	var u api.User
+	u.Username = sa.Customer.Email
+	u.Password = sa.Password
+	ca.User = &u
+	suc, _ := h.Manager.CreateCustomerAccount(&ca, hd)
+	if !suc {
+		h.writeAPIError(w, http.StatusBadGateway, "account not created")
+		return
+	}
+	h.Log.Info("store api account created for: ", sa.Customer.Email)
+	st := h.customerLogin(r, sa.Customer.Email, sa.Password)
+	if st == nil {
+		h.writeAPIError(w, http.StatusBadGateway, "account created but login failed")
+		return
+	}
+	h.writeAPI(w, http.StatusCreated, st)
+}
+
+//StoreAPIViewCart StoreAPIViewCart
+func (h *Six910Handler) StoreAPIViewCart(w http.ResponseWriter, r *http.Request) {
+	cs := getCustomerSession(r)
+	hd := customerHeader(cs, r)
+	var sc StoreCart
+	sc.Items = []StoreCartItem{}
+	if c := h.API.GetCart(cs.CustomerID, hd); c != nil && c.ID != 0 {
+		sc.ID = c.ID
+		sc.Items, sc.Subtotal, _ = h.storeCartItems(h.API.GetCartItemList(c.ID, cs.CustomerID, hd), hd)
+	}
+	h.writeAPI(w, http.StatusOK, &sc)
+}
+
+//StoreAPIAddToCart StoreAPIAddToCart adds a visible product to the
+//customer's cart, making the cart when there is none
+func (h *Six910Handler) StoreAPIAddToCart(w http.ResponseWriter, r *http.Request) {
+	cs := getCustomerSession(r)
+	hd := customerHeader(cs, r)
+	var cc StoreCartChange
+	if err := readAPI(w, r, &cc); err != nil {
+		h.writeAPIError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	var fe = FieldErrors{}
+	fe.requiredID("productId", cc.ProductID)
+	if cc.Quantity < 1 {
+		fe.add("quantity", "must be at least 1")
+	}
+	if len(fe) > 0 {
+		h.writeAPIFields(w, fe)
+		return
+	}
+	p := h.API.GetProductByID(cc.ProductID, hd)
+	if p == nil || p.ID != cc.ProductID || !p.Visible {
+		h.writeAPIError(w, http.StatusNotFound, "product not found")
+		return
+	}
+	var cp m.CustomerProduct
+	cp.ProductID = cc.ProductID
+	cp.Quantity = cc.Quantity
+	cp.CustomerID = cs.CustomerID
+	cp.StoreID = p.StoreID
+	h.writeStoreCart(w, h.Manager.AddProductToCart(&cp, hd), hd)
+}
+
+//StoreAPIUpdateCart StoreAPIUpdateCart sets the quantity of a product in
+//the cart
+func (h *Six910Handler) StoreAPIUpdateCart(w http.ResponseWriter, r *http.Request) {
+	cs := getCustomerSession(r)
+	hd := customerHeader(cs, r)
+	pid, _ := apiID(r)
+	var cc StoreCartChange
+	if err := readAPI(w, r, &cc); err != nil {
+		h.writeAPIError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	if cc.Quantity < 1 {
+		h.writeAPIFields(w, FieldErrors{"quantity": "must be at least 1"})
+		return
+	}
+	cart := h.API.GetCart(cs.CustomerID, hd)
+	var item *sdbi.CartItem
+	if cart != nil && cart.ID != 0 {
+		if items := h.API.GetCartItemList(cart.ID, cs.CustomerID, hd); items != nil {
+			for i := range *items {
+				if (*items)[i].ProductID == pid {
+					item = &(*items)[i]
+					break
+				}
+			}
+		}
+	}
+	if item == nil {
+		h.writeAPIError(w, http.StatusNotFound, "product is not in the cart")
+		return
+	}
+	item.Quantity = cc.Quantity
+	var cpu m.CustomerProductUpdate
+	cpu.CustomerID = cs.CustomerID
+	cpu.Cart = cart
+	cpu.CartItem = item
+	h.writeStoreCart(w, h.Manager.UpdateProductToCart(&cpu, hd), hd)
+}
+
+//StoreAPICheckOut StoreAPICheckOut orders the customer's cart. Prices,
+//shipping and insurance are worked out here from the backend, never taken
+//from the client.
+func (h *Six910Handler) StoreAPICheckOut(w http.ResponseWriter, r *http.Request) {
+	cs := getCustomerSession(r)
+	hd := customerHeader(cs, r)
+	var co StoreCheckout
+	if err := readAPI(w, r, &co); err != nil {
+		h.writeAPIError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	var cc m.CustomerCart
+	cc.Cart = h.API.GetCart(cs.CustomerID, hd)
+	if cc.Cart == nil || cc.Cart.ID == 0 {
+		h.writeAPIError(w, http.StatusConflict, "the cart is empty")
+		return
+	}
+	cc.Items = h.API.GetCartItemList(cc.Cart.ID, cs.CustomerID, hd)
+	_, sub, err := h.storeCartItems(cc.Items, hd)
+	if err != nil {
+		h.writeAPIError(w, http.StatusConflict, err.Error())
+		return
+	}
+	cc.Subtotal = sub
+	cc.Pickup = co.Pickup
+	cc.OrderType = co.OrderType
+	cc.Comment = co.Comment
+	if !co.Pickup {
+		sm, fe := h.storeShipping(&co, sub, hd)
+		if len(fe) > 0 {
+			h.writeAPIFields(w, fe)
+			return
+		}
+		cc.ShippingHandling = roundCents(sm.Cost + sm.Handling)
+		if co.Insurance && sm.InsuranceID != 0 {
+			if ins := h.API.GetInsurance(sm.InsuranceID, hd); ins != nil {
+				cc.InsuranceCost = roundCents(ins.Cost)
+			}
+		}
+	}
+	//Taxes are left at zero; the backend has no tax rates and the manager
+	//checkout only copies what the cart holds. Admins can add them to the
+	//order afterwards.
+	cc.Total = roundCents(cc.Subtotal + cc.ShippingHandling + cc.InsuranceCost)
+	var ca m.CustomerAccount
+	ca.Customer = h.API.GetCustomerID(cs.CustomerID, hd)
+	ca.Addresses = h.API.GetAddressList(cs.CustomerID, hd)
+	if ca.Customer == nil || ca.Customer.ID != cs.CustomerID || ca.Addresses == nil {
+		h.writeAPIError(w, http.StatusBadGateway, "customer not found")
+		return
+	}
+	var u api.User
+	u.Username = cs.Username
+	u.CustomerID = cs.CustomerID
+	u.Enabled = true
+	ca.User = &u
+	cc.CustomerAccount = &ca
+	ord := h.Manager.CheckOut(&cc, hd)
+	if ord == nil || !ord.Success || ord.Order == nil {
+		h.writeAPIError(w, http.StatusBadGateway, "order not placed")
+		return
+	}
+	h.API.DeleteCart(cc.Cart.ID, cs.CustomerID, hd)
+	h.Log.Info("store api order ", ord.Order.OrderNumber, " placed for: ", cs.Username)
+	h.writeAPI(w, http.StatusCreated, &StoreOrder{Order: ord.Order, Items: ord.Items, Comments: ord.Comments})
+}
+
+//StoreAPIOrderList StoreAPIOrderList
+func (h *Six910Handler) StoreAPIOrderList(w http.ResponseWriter, r *http.Request) {
+	p, err := apiPaging(r)
+	if err != nil {
+		h.writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	cs := getCustomerSession(r)
+	var ol = []StoreOrder{}
+	if cos := h.Manager.ViewCustomerOrderList(cs.CustomerID, customerHeader(cs, r)); cos != nil {
+		for _, co := range *cos {
+			ol = append(ol, StoreOrder{Order: co.Order, Items: co.Items, Comments: co.Comments})
+		}
+	}
+	h.writeAPI(w, http.StatusOK, p.slice(&ol))
+}
+
+//customerLogin logs in through the manager, which throttles, and
+//returns a new token
+func (h *Six910Handler) customerLogin(r *http.Request, username string, password string) *StoreToken {
+	var u api.User
+	u.Username = username
+	u.Password = password
+	suc, lu := h.Manager.CustomerLogin(&u, clientIP(r), resetHeader(r))
+	if !suc || lu.CustomerID == 0 || h.CustomerSessions == nil {
+		h.Log.Info("store api login failed for: ", username)
+		return nil
+	}
+	var cs cust.Session
+	cs.Username = lu.Username
+	cs.Password = password
+	cs.CustomerID = lu.CustomerID
+	ncs := h.CustomerSessions.Create(&cs)
+	return &StoreToken{Token: ncs.Token, CustomerID: ncs.CustomerID}
+}
+
+func (h *Six910Handler) writeStoreCart(w http.ResponseWriter, cc *m.CustomerCart, hd *api.Headers) {
+	if cc == nil || cc.Cart == nil {
+		h.writeAPIError(w, http.StatusBadGateway, "cart not changed")
+		return
+	}
+	var sc StoreCart
+	sc.ID = cc.Cart.ID
+	sc.Items, sc.Subtotal, _ = h.storeCartItems(cc.Items, hd)
+	h.writeAPI(w, http.StatusOK, &sc)
+}
+
+//storeCartItems prices the cart at the current sale or list price. The
+//error names the first product that can no longer be bought.
+func (h *Six910Handler) storeCartItems(items *[]sdbi.CartItem, hd *api.Headers) ([]StoreCartItem, float64, error) {
+	var rtn = []StoreCartItem{}
+	var sub float64
+	var err error
+	if items == nil || len(*items) == 0 {
+		return rtn, 0, errors.New("the cart is empty")
+	}
+	for _, ci := range *items {
+		var sci StoreCartItem
+		sci.ProductID = ci.ProductID
+		sci.Quantity = ci.Quantity
+		p := h.API.GetProductByID(ci.ProductID, hd)
+		if p == nil || p.ID != ci.ProductID || !p.Visible {
+			if err == nil {
+				err = errors.New("product " + strconv.FormatInt(ci.ProductID, 10) + " is no longer available")
+			}
+		} else {
+			sci.Name = p.Name
+			sci.Thumbnail = p.Thumbnail
+			sci.Price = p.Price
+			if p.SalePrice > 0 && p.SalePrice < p.Price {
+				sci.Price = p.SalePrice
+			}
+			sci.Total = roundCents(sci.Price * float64(ci.Quantity))
+			sub += sci.Total
+		}
+		rtn = append(rtn, sci)
+	}
+	return rtn, roundCents(sub), err
+}
+
+//storeShipping checks the shipping method can ship an order of sub
+func (h *Six910Handler) storeShipping(co *StoreCheckout, sub float64, hd *api.Headers) (*sdbi.ShippingMethod, FieldErrors) {
+	var fe = FieldErrors{}
+	fe.requiredID("shippingMethodId", co.ShippingMethodID)
+	if len(fe) > 0 {
+		return nil, fe
+	}
+	sm := h.API.GetShippingMethod(co.ShippingMethodID, hd)
+	if sm == nil || sm.ID != co.ShippingMethodID {
+		fe.add("shippingMethodId", "is not a shipping method")
+	} else if sub < sm.MinOrderAmount || (sm.MaxOrderAmount > 0 && sub > sm.MaxOrderAmount) {
+		fe.add("shippingMethodId", "does not ship an order of this amount")
+	}
+	return sm, fe
+}
+
+//storeProducts reads backend pages from start until size visible
+//products are found, the list ends or storeScanChunks pages were read.
+//Next is the backend position after the last product looked at.
+func storeProducts(get func(st int64, end int64) *[]sdbi.Product, start int64, size int64, search bool) *StoreProductList {
+	var rtn StoreProductList
+	rtn.Data = []StoreProduct{}
+	pos := start
+	for i := 0; i < storeScanChunks; i++ {
+		chunk := size * 2
+		pl := get(pos, pos+chunk)
+		if pl == nil || len(*pl) == 0 {
+			rtn.Next = pos
+			return &rtn
+		}
+		for j := range *pl {
+			p := &(*pl)[j]
+			if !p.Visible || (search && !p.Searchable) {
+				continue
+			}
+			if int64(len(rtn.Data)) == size {
+				rtn.Next = pos + int64(j)
+				rtn.HasMore = true
+				return &rtn
+			}
+			rtn.Data = append(rtn.Data, storeProductOf(p))
+		}
+		pos += int64(len(*pl))
+		if int64(len(*pl)) < chunk {
+			rtn.Next = pos
+			return &rtn
+		}
+	}
+	rtn.Next = pos
+	rtn.HasMore = true
+	return &rtn
+}
+
+func storeProductOf(p *sdbi.Product) StoreProduct {
+	var sp StoreProduct
+	sp.ID = p.ID
+	sp.Sku = p.Sku
+	sp.Gtin = p.Gtin
+	sp.Name = p.Name
+	sp.ShortDesc = p.ShortDesc
+	sp.Desc = p.Desc
+	sp.Msrp = p.Msrp
+	sp.Price = p.Price
+	sp.SalePrice = p.SalePrice
+	sp.Currency = p.Currency
+	sp.Manufacturer = p.Manufacturer
+	sp.Stock = p.Stock
+	sp.Weight = p.Weight
+	sp.FreeShipping = p.FreeShipping
+	sp.Promoted = p.Promoted
+	sp.Size = p.Size
+	sp.Color = p.Color
+	sp.Thumbnail = p.Thumbnail
+	sp.Image1 = p.Image1
+	sp.Image2 = p.Image2
+	sp.Image3 = p.Image3
+	sp.Image4 = p.Image4
+	sp.ParentProductID = p.ParentProductID
+	return sp
+}
+
+func getCustomerSession(r *http.Request) *cust.Session {
+	cs, _ := r.Context().Value(customerSessionKey).(*cust.Session)
+	return cs
+}
+
+//customerHeader calls the backend as the logged in customer
+func customerHeader(cs *cust.Session, r *http.Request) *api.Headers {
+	hd := resetHeader(r)
+	hd.Set("Authorization", "Basic "+b64.StdEncoding.EncodeToString([]byte(cs.Username+":"+cs.Password)))
+	return hd
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,307 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	cust "github.com/Ulbora/Six910-ui/custsrv"
+	m "github.com/Ulbora/Six910-ui/managers"
+	mapi "github.com/Ulbora/Six910-ui/mockapi"
+	thr "github.com/Ulbora/Six910-ui/throtsrv"
+	api "github.com/Ulbora/Six910API-Go"
+	sdbi "github.com/Ulbora/six910-database-interface"
+)
+
+func storeTestLogin(sh *Six910Handler) string {
+	var s cust.Session
+	s.Username = "bob@bob.com"
+	s.Password = "secret123"
+	s.CustomerID = 18
+	return sh.CustomerSessions.Create(&s).Token
+}
+
+func storeTestCart(sapi *mapi.MockAPI) {
+	sapi.MockCart = &sdbi.Cart{ID: 4, CustomerID: 18}
+	sapi.MockCartItemList = &[]sdbi.CartItem{{ID: 1, CartID: 4, ProductID: 7, Quantity: 3}}
+	sapi.MockProduct = &sdbi.Product{ID: 7, Name: "hat", Price: 10, SalePrice: 8.5, Cost: 2, Visible: true}
+}
+
+func TestSix910Handler_StoreAPIProductList(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	useStoreAPI(sh, sapi)
+	defer os.RemoveAll(dir)
+	sapi.MockProductList = &[]sdbi.Product{{ID: 1, Visible: true, Cost: 3}, {ID: 2}, {ID: 3, Visible: true}}
+	sapi.MockProductNameList = &[]sdbi.Product{{ID: 1, Visible: true, Searchable: true}, {ID: 3, Visible: true}}
+
+	w := serveStore(sh, "", "GET", "/api/v1/products", "")
+	var pl StoreProductList
+	json.NewDecoder(w.Body).Decode(&pl)
+	if w.Code != 200 || len(pl.Data) != 2 || pl.Data[1].ID != 3 || pl.HasMore || pl.Next != 3 ||
+		strings.Contains(w.Body.String(), "cost") {
+		fmt.Println("store list: ", w.Code, pl)
+		t.Fail()
+	}
+	w = serveStore(sh, "", "GET", "/api/v1/products?name=hat", "")
+	pl = StoreProductList{}
+	json.NewDecoder(w.Body).Decode(&pl)
+	if w.Code != 200 || len(pl.Data) != 1 || pl.Data[0].ID != 1 {
+		fmt.Println("store search: ", w.Code, pl)
+		t.Fail()
+	}
+	if w = serveStore(sh, "", "GET", "/api/v1/products?name=hat&categoryId=2", ""); w.Code != 400 {
+		t.Fail()
+	}
+	if w = serveStore(sh, "", "GET", "/api/v1/products?start=-1", ""); w.Code != 400 {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_storeProducts(t *testing.T) {
+	var all []sdbi.Product
+	for i := int64(0); i < 30; i++ {
+		all = append(all, sdbi.Product{ID: i, Visible: i%3 != 0})
+	}
+	get := func(st int64, end int64) *[]sdbi.Product {
+		if end > int64(len(all)) {
+			end = int64(len(all))
+		}
+		pl := all[st:end]
+		return &pl
+	}
+	pl := storeProducts(get, 0, 4, false)
+	if len(pl.Data) != 4 || pl.Data[3].ID != 5 || !pl.HasMore || pl.Next != 7 {
+		fmt.Println("first page: ", pl)
+		t.Fail()
+	}
+	pl = storeProducts(get, 27, 4, false)
+	if len(pl.Data) != 2 || pl.HasMore || pl.Next != 30 {
+		fmt.Println("last page: ", pl)
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAPIProduct(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	useStoreAPI(sh, sapi)
+	defer os.RemoveAll(dir)
+	sapi.MockProduct = &sdbi.Product{ID: 7, Name: "hat"}
+	if w := serveStore(sh, "", "GET", "/api/v1/products/7", ""); w.Code != 404 {
+		t.Fail()
+	}
+	sapi.MockProduct.Visible = true
+	if w := serveStore(sh, "", "GET", "/api/v1/products/7", ""); w.Code != 200 || !strings.Contains(w.Body.String(), `"name":"hat"`) {
+		fmt.Println("product: ", w.Code, w.Body.String())
+		t.Fail()
+	}
+	sapi.MockCategoryList = &[]sdbi.Category{{ID: 1, Name: "hats"}}
+	if w := serveStore(sh, "", "GET", "/api/v1/categories", ""); w.Code != 200 || !strings.Contains(w.Body.String(), "hats") {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAPILogin(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	useStoreAPI(sh, sapi)
+	defer os.RemoveAll(dir)
+	sapi.MockUser = &api.UserResponse{Username: "bob@bob.com", Role: customerRole, CustomerID: 18, Enabled: true}
+
+	w := serveStore(sh, "", "POST", "/api/v1/login", `{"username":"bob@bob.com","password":"secret123"}`)
+	var st StoreToken
+	json.NewDecoder(w.Body).Decode(&st)
+	if w.Code != 200 || st.Token == "" || st.CustomerID != 18 {
+		fmt.Println("login: ", w.Code, st)
+		t.Fail()
+	}
+	cs := sh.CustomerSessions.Get(st.Token)
+	if cs == nil || cs.Password != "secret123" {
+		t.Fail()
+	}
+	if w = serveStore(sh, st.Token, "POST", "/api/v1/logout", ""); w.Code != 200 || sh.CustomerSessions.Get(st.Token) != nil {
+		t.Fail()
+	}
+	if w = serveStore(sh, st.Token, "GET", "/api/v1/cart", ""); w.Code != 401 {
+		t.Fail()
+	}
+	sapi.MockUser = &api.UserResponse{Username: "bob@bob.com", Role: "admin", Enabled: true}
+	if w = serveStore(sh, "", "POST", "/api/v1/login", `{"username":"bob@bob.com","password":"secret123"}`); w.Code != 401 {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAPILoginIPThrottled(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	useStoreAPI(sh, sapi)
+	defer os.RemoveAll(dir)
+	sapi.MockUser = &api.UserResponse{Username: "bob@bob.com", Role: customerRole, CustomerID: 18, Enabled: true}
+	var ms thr.MemoryStore
+	var th thr.Six910Throttle
+	th.Store = ms.GetNew()
+	th.UserPolicy = thr.DefaultUserPolicy()
+	th.IPPolicy = thr.DefaultIPPolicy()
+	th.Log = sh.Log
+	sh.LoginThrottle = th.GetNew()
+	sh.Manager.(*m.Six910Manager).Throttle = sh.LoginThrottle
+	for i := 0; i < 20; i++ {
+		sh.LoginThrottle.Fail(thr.IPKey("10.0.0.9"))
+	}
+	login := func(addr string) *httptest.ResponseRecorder {
+		r, _ := http.NewRequest("POST", "/api/v1/login", strings.NewReader(`{"username":"bob@bob.com","password":"secret123"}`))
+		r.Header.Set("Content-Type", "application/json")
+		r.RemoteAddr = addr
+		w := httptest.NewRecorder()
+		sh.StoreAPILogin(w, r)
+		return w
+	}
+	if w := login("10.0.0.9:4000"); w.Code != 429 || w.Header().Get("Retry-After") == "" {
+		fmt.Println("throttled ip: ", w.Code)
+		t.Fail()
+	}
+	if w := login("10.0.0.10:4000"); w.Code != 200 {
+		fmt.Println("other ip: ", w.Code)
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAPICreateAccount(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	useStoreAPI(sh, sapi)
+	defer os.RemoveAll(dir)
+	if w := serveStore(sh, "", "POST", "/api/v1/customers", `{"customer":{"email":"bob"},"password":"short"}`); w.Code != 422 ||
+		!strings.Contains(w.Body.String(), "password") {
+		fmt.Println("create bad: ", w.Code, w.Body.String())
+		t.Fail()
+	}
+	sapi.MockCustomer = &sdbi.Customer{ID: 3, Email: "bob@bob.com"}
+	body := `{"customer":{"email":"bob@bob.com","firstName":"Bob"},"addresses":[{"address":"1 Main","type":"Billing"}],"password":"secret123"}`
+	if w := serveStore(sh, "", "POST", "/api/v1/customers", body); w.Code != 409 {
+		t.Fail()
+	}
+	sapi.MockCustomer = &sdbi.Customer{}
+	sapi.MockAddCustomerResp = &api.ResponseID{ID: 18, Success: true}
+	sapi.MockAddAddressRes = &api.ResponseID{ID: 2, Success: true}
+	sapi.MockAddCustomerUserRes = &api.Response{Success: true}
+	sapi.MockUser = &api.UserResponse{Username: "bob@bob.com", Role: customerRole, CustomerID: 18, Enabled: true}
+	w := serveStore(sh, "", "POST", "/api/v1/customers", body)
+	var st StoreToken
+	json.NewDecoder(w.Body).Decode(&st)
+	if w.Code != 201 || st.CustomerID != 18 || sh.CustomerSessions.Get(st.Token) == nil {
+		fmt.Println("create: ", w.Code, w.Body.String())
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAPICart(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	useStoreAPI(sh, sapi)
+	defer os.RemoveAll(dir)
+	storeTestCart(sapi)
+	sapi.MockCartItemAddResp = &api.ResponseID{ID: 1, Success: true}
+	sapi.MockCartItemUpdateResp = &api.Response{Success: true}
+	tok := storeTestLogin(sh)
+
+	w := serveStore(sh, tok, "GET", "/api/v1/cart", "")
+	var sc StoreCart
+	json.NewDecoder(w.Body).Decode(&sc)
+	if w.Code != 200 || sc.ID != 4 || len(sc.Items) != 1 || sc.Items[0].Price != 8.5 || sc.Subtotal != 25.5 {
+		fmt.Println("cart: ", w.Code, sc)
+		t.Fail()
+	}
+	if w = serveStore(sh, tok, "POST", "/api/v1/cart/items", `{"productId":7,"quantity":1}`); w.Code != 200 {
+		fmt.Println("add: ", w.Code, w.Body.String())
+		t.Fail()
+	}
+	if w = serveStore(sh, tok, "POST", "/api/v1/cart/items", `{"productId":7}`); w.Code != 422 {
+		t.Fail()
+	}
+	if w = serveStore(sh, tok, "PUT", "/api/v1/cart/items/7", `{"quantity":5}`); w.Code != 200 {
+		fmt.Println("update: ", w.Code, w.Body.String())
+		t.Fail()
+	}
+	if w = serveStore(sh, tok, "PUT", "/api/v1/cart/items/9", `{"quantity":5}`); w.Code != 404 {
+		t.Fail()
+	}
+	sapi.MockProduct.Visible = false
+	if w = serveStore(sh, tok, "POST", "/api/v1/cart/items", `{"productId":7,"quantity":1}`); w.Code != 404 {
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAPICheckOut(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	useStoreAPI(sh, sapi)
+	defer os.RemoveAll(dir)
+	storeTestCart(sapi)
+	sapi.MockShippingMethod = &sdbi.ShippingMethod{ID: 2, Cost: 5, Handling: 1.25, MaxOrderAmount: 100, InsuranceID: 6}
+	sapi.MockInsurance = &sdbi.Insurance{ID: 6, Cost: 2}
+	sapi.MockCustomer = &sdbi.Customer{ID: 18, FirstName: "Bob"}
+	sapi.MockAddressList1 = &[]sdbi.Address{{ID: 2, Type: "Billing"}}
+	sapi.MockAddOrderResp = &api.ResponseID{ID: 30, Success: true}
+	sapi.MockAddOrderItemResp = &api.ResponseID{ID: 31, Success: true}
+	tok := storeTestLogin(sh)
+
+	if w := serveStore(sh, tok, "POST", "/api/v1/checkout", `{}`); w.Code != 422 {
+		t.Fail()
+	}
+	w := serveStore(sh, tok, "POST", "/api/v1/checkout", `{"shippingMethodId":2,"insurance":true}`)
+	var so StoreOrder
+	json.NewDecoder(w.Body).Decode(&so)
+	if w.Code != 201 || so.Order == nil || so.Order.Subtotal != 25.5 || so.Order.ShippingHandling != 6.25 ||
+		so.Order.Insurance != 2 || so.Order.Total != 33.75 || so.Order.Username != "bob@bob.com" {
+		fmt.Println("checkout: ", w.Code, w.Body.String())
+		t.Fail()
+	}
+	sapi.MockShippingMethod.MinOrderAmount = 50
+	if w = serveStore(sh, tok, "POST", "/api/v1/checkout", `{"shippingMethodId":2}`); w.Code != 422 {
+		t.Fail()
+	}
+	sapi.MockProduct.Visible = false
+	if w = serveStore(sh, tok, "POST", "/api/v1/checkout", `{"pickup":true}`); w.Code != 409 {
+		t.Fail()
+	}
+}
+
+//noOrderManager reports a checkout as done without the order
+type noOrderManager struct {
+	m.Manager
+}
+
+func (n *noOrderManager) CheckOut(cart *m.CustomerCart, hd *api.Headers) *m.CustomerOrder {
+	return &m.CustomerOrder{Success: true}
+}
+
+func TestSix910Handler_StoreAPICheckOutNoOrder(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	useStoreAPI(sh, sapi)
+	defer os.RemoveAll(dir)
+	storeTestCart(sapi)
+	sapi.MockCustomer = &sdbi.Customer{ID: 18}
+	sapi.MockAddressList1 = &[]sdbi.Address{{ID: 2, Type: "Billing"}}
+	tok := storeTestLogin(sh)
+	sh.Manager = &noOrderManager{Manager: sh.Manager}
+	if w := serveStore(sh, tok, "POST", "/api/v1/checkout", `{"pickup":true}`); w.Code != 502 {
+		fmt.Println("checkout: ", w.Code, w.Body.String())
+		t.Fail()
+	}
+}
+
+func TestSix910Handler_StoreAPIOrderList(t *testing.T) {
+	sh, sapi, dir := testHandler(t)
+	useStoreAPI(sh, sapi)
+	defer os.RemoveAll(dir)
+	sapi.MockCustomer = &sdbi.Customer{ID: 18}
+	sapi.MockAddressList1 = &[]sdbi.Address{}
+	sapi.MockOrderList = &[]sdbi.Order{{ID: 30, CustomerID: 18}}
+	w := serveStore(sh, storeTestLogin(sh), "GET", "/api/v1/orders", "")
+	if w.Code != 200 || !strings.Contains(w.Body.String(), `"total":1`) {
+		fmt.Println("orders: ", w.Code, w.Body.String())
+		t.Fail()
+	}
+	if w = serveStore(sh, "", "GET", "/api/v1/orders", ""); w.Code != 401 {
+		t.Fail()
+	}
+}
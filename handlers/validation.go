@@ -0,0 +1,300 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	sdbi "github.com/Ulbora/six910-database-interface"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+//invalidForm is the page error shown over a form with bad fields
+const invalidForm = "Please correct the marked fields"
+
+//FieldErrors FieldErrors maps a field to what is wrong with it
+type FieldErrors map[string]string
+
+//FormErrors FormErrors is part of each admin form page. When a save is
+//refused Fields has a message for each bad field and Form the values as
+//they were typed, so the form can be shown again.
+type FormErrors struct {
+	Fields FieldErrors
+	Form   url.Values
+}
+
+func formErrors(r *http.Request, fe FieldErrors) FormErrors {
+	return FormErrors{Fields: fe, Form: r.Form}
+}
+
+//formReader reads typed form values. A value that is sent but does not
+//parse gets a message instead of being saved as zero.
+type formReader struct {
+	r  *http.Request
+	fe FieldErrors
+}
+
+func newFormReader(r *http.Request) *formReader {
+	return &formReader{r: r, fe: FieldErrors{}}
+}
+
+func (f *formReader) str(name string) string {
+	return f.r.FormValue(name)
+}
+
+func (f *formReader) int(name string) int64 {
+	var rtn int64
+	if v := strings.TrimSpace(f.r.FormValue(name)); v != "" {
+		var err error
+		if rtn, err = strconv.ParseInt(v, 10, 64); err != nil {
+			f.fe.add(name, "must be a whole number")
+		}
+	}
+	return rtn
+}
+
+func (f *formReader) float(name string) float64 {
+	var rtn float64
+	if v := strings.TrimSpace(f.r.FormValue(name)); v != "" {
+		var err error
+		if rtn, err = strconv.ParseFloat(v, 64); err != nil {
+			f.fe.add(name, "must be a number like 12.99")
+		}
+	}
+	return rtn
+}
+
+//bool takes "on" from a checkbox without a value as true
+func (f *formReader) bool(name string) bool {
+	var rtn bool
+	if v := strings.TrimSpace(f.r.FormValue(name)); v == "on" {
+		rtn = true
+	} else if v != "" {
+		var err error
+		if rtn, err = strconv.ParseBool(v); err != nil {
+			f.fe.add(name, "must be true or false")
+		}
+	}
+	return rtn
+}
+
+func (f *formReader) time(name string, layout string) time.Time {
+	var rtn time.Time
+	if v := strings.TrimSpace(f.r.FormValue(name)); v != "" {
+		var err error
+		if rtn, err = time.Parse(layout, v); err != nil {
+			f.fe.add(name, "must be a date like "+layout)
+		}
+	}
+	return rtn
+}
+
+//check adds the errors of a validator to the parse errors. renames are
+//pairs of validator and form field names for fields the form names
+//differently.
+func (f *formReader) check(fe FieldErrors, renames ...string) FieldErrors {
+	for i := 0; i+1 < len(renames); i += 2 {
+		if msg, ok := fe[renames[i]]; ok {
+			delete(fe, renames[i])
+			fe[renames[i+1]] = msg
+		}
+	}
+	for k, v := range fe {
+		f.fe.add(k, v)
+	}
+	return f.fe
+}
+
+//add keeps the first message for a field
+func (fe FieldErrors) add(field string, msg string) {
+	if _, ok := fe[field]; !ok {
+		fe[field] = msg
+	}
+}
+
+func (fe FieldErrors) required(field string, val string) {
+	if strings.TrimSpace(val) == "" {
+		fe.add(field, "is required")
+	}
+}
+
+func (fe FieldErrors) requiredID(field string, val int64) {
+	if val <= 0 {
+		fe.add(field, "is required")
+	}
+}
+
+func (fe FieldErrors) notNegative(field string, val float64) {
+	if val < 0 {
+		fe.add(field, "must not be negative")
+	}
+}
+
+//url checks an optional absolute http or https URL
+func (fe FieldErrors) url(field string, val string) {
+	if val == "" {
+		return
+	}
+	u, err := url.Parse(val)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		fe.add(field, "must be a full http or https URL")
+	}
+}
+
+//orderRange checks min and max order amounts; a zero max has no limit
+func (fe FieldErrors) orderRange(minField string, min float64, maxField string, max float64) {
+	fe.notNegative(minField, min)
+	fe.notNegative(maxField, max)
+	if max > 0 && min > max {
+		fe.add(minField, "must not be more than "+maxField)
+	}
+}
+
+func validateProduct(p *sdbi.Product) FieldErrors {
+	var fe = FieldErrors{}
+	fe.required("sku", p.Sku)
+	fe.required("name", p.Name)
+	fe.notNegative("price", p.Price)
+	fe.notNegative("salePrice", p.SalePrice)
+	fe.notNegative("cost", p.Cost)
+	return fe
+}
+
+func validateCategory(c *sdbi.Category) FieldErrors {
+	var fe = FieldErrors{}
+	fe.required("name", c.Name)
+	return fe
+}
+
+func validateDistributor(d *sdbi.Distributor) FieldErrors {
+	var fe = FieldErrors{}
+	fe.required("company", d.Company)
+	return fe
+}
+
+func validateInsurance(i *sdbi.Insurance) FieldErrors {
+	var fe = FieldErrors{}
+	fe.notNegative("cost", i.Cost)
+	fe.orderRange("minOrderAmount", i.MinOrderAmount, "maxOrderAmount", i.MaxOrderAmount)
+	return fe
+}
+
+func validateShippingMethod(s *sdbi.ShippingMethod) FieldErrors {
+	var fe = FieldErrors{}
+	fe.required("name", s.Name)
+	fe.requiredID("regionId", s.RegionID)
+	fe.requiredID("shippingCarrierId", s.ShippingCarrierID)
+	fe.notNegative("cost", s.Cost)
+	fe.notNegative("handling", s.Handling)
+	fe.orderRange("minOrderAmount", s.MinOrderAmount, "maxOrderAmount", s.MaxOrderAmount)
+	return fe
+}
+
+func validateShippingCarrier(c *sdbi.ShippingCarrier) FieldErrors {
+	var fe = FieldErrors{}
+	fe.required("carrier", c.Carrier)
+	return fe
+}
+
+func validateRegion(r *sdbi.Region) FieldErrors {
+	var fe = FieldErrors{}
+	fe.required("regionCode", r.RegionCode)
+	fe.required("name", r.Name)
+	return fe
+}
+
+func validateSubRegion(s *sdbi.SubRegion) FieldErrors {
+	var fe = FieldErrors{}
+	fe.required("subRegionCode", s.SubRegionCode)
+	fe.required("name", s.Name)
+	fe.requiredID("regionId", s.RegionID)
+	return fe
+}
+
+func validatePlugin(p *sdbi.Plugins) FieldErrors {
+	var fe = FieldErrors{}
+	fe.required("pluginName", p.PluginName)
+	fe.notNegative("fee", p.Fee)
+	fe.url("activateUrl", p.ActivateURL)
+	fe.url("oauthRedirectUrl", p.OauthRedirectURL)
+	return fe
+}
+
+func validatePaymentGateway(p *sdbi.PaymentGateway) FieldErrors {
+	var fe = FieldErrors{}
+	fe.requiredID("storePluginsId", p.StorePluginsID)
+	fe.url("checkoutUrl", p.CheckoutURL)
+	fe.url("postOrderUrl", p.PostOrderURL)
+	return fe
+}
+
+func validateStorePlugin(p *sdbi.StorePlugins) FieldErrors {
+	var fe = FieldErrors{}
+	fe.required("pluginName", p.PluginName)
+	fe.notNegative("rekeyTryCount", float64(p.RekeyTryCount))
+	fe.url("iframeUrl", p.IframeURL)
+	fe.url("activateUrl", p.ActivateURL)
+	fe.url("oauthRedirectUrl", p.OauthRedirectURL)
+	return fe
+}
+
+func validateExSubRegion(s *sdbi.ExcludedSubRegion) FieldErrors {
+	var fe = FieldErrors{}
+	fe.requiredID("regionId", s.RegionID)
+	fe.requiredID("subRegionId", s.SubRegionID)
+	fe.requiredID("shippingMethodId", s.ShippingMethodID)
+	return fe
+}
+
+func validateIncSubRegion(s *sdbi.IncludedSubRegion) FieldErrors {
+	var fe = FieldErrors{}
+	fe.requiredID("regionId", s.RegionID)
+	fe.requiredID("subRegionId", s.SubRegionID)
+	fe.requiredID("shippingMethodId", s.ShippingMethodID)
+	return fe
+}
+
+func validateOrder(o *sdbi.Order) FieldErrors {
+	var fe = FieldErrors{}
+	fe.required("status", o.Status)
+	fe.notNegative("subTotal", o.Subtotal)
+	fe.notNegative("shippingHandling", o.ShippingHandling)
+	fe.notNegative("insurance", o.Insurance)
+	fe.notNegative("taxes", o.Taxes)
+	fe.notNegative("total", o.Total)
+	return fe
+}
+
+func validateShipment(s *sdbi.Shipment) FieldErrors {
+	var fe = FieldErrors{}
+	fe.requiredID("orderId", s.OrderID)
+	fe.notNegative("boxes", float64(s.Boxes))
+	fe.notNegative("shippingHandling", s.ShippingHandling)
+	fe.notNegative("insurance", s.Insurance)
+	return fe
+}
+
+func validateCustomer(c *sdbi.Customer) FieldErrors {
+	var fe = FieldErrors{}
+	fe.required("email", c.Email)
+	return fe
+}
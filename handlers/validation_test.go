@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	sdbi "github.com/Ulbora/six910-database-interface"
+)
+
+func TestValidateInsurance(t *testing.T) {
+	var i sdbi.Insurance
+	i.MinOrderAmount = 100
+	i.MaxOrderAmount = 50
+	fe := validateInsurance(&i)
+	fmt.Println("fe: ", fe)
+	if fe["minOrderAmount"] == "" || len(fe) != 1 {
+		t.Fail()
+	}
+	i.MaxOrderAmount = 0
+	if len(validateInsurance(&i)) != 0 {
+		t.Fail()
+	}
+}
+
+func TestValidateProduct(t *testing.T) {
+	var p sdbi.Product
+	p.Price = -1
+	fe := validateProduct(&p)
+	fmt.Println("fe: ", fe)
+	if fe["sku"] == "" || fe["name"] == "" || fe["price"] == "" {
+		t.Fail()
+	}
+}
+
+func TestFormReader(t *testing.T) {
+	r, _ := http.NewRequest("POST", "https://test.com", strings.NewReader(
+		"id=abc&price=12,99&stock=&visible=on&promoted=yes&rekeyDate=2020-01-02 03:04:05"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	f := newFormReader(r)
+	f.int("id")
+	f.float("price")
+	if f.int("stock") != 0 || !f.bool("visible") || f.bool("promoted") || f.time("rekeyDate", timeFormat).Year() != 2020 {
+		t.Fail()
+	}
+	fe := f.check(FieldErrors{"storePluginsId": "is required", "price": "must not be negative"}, "storePluginsId", "storePluginId")
+	fmt.Println("fe: ", fe)
+	if len(fe) != 4 || fe["id"] == "" || fe["price"] != "must be a number like 12.99" || fe["promoted"] == "" ||
+		fe["storePluginId"] == "" {
+		t.Fail()
+	}
+}
+
+func TestValidatePaymentGateway(t *testing.T) {
+	var p sdbi.PaymentGateway
+	p.StorePluginsID = 2
+	p.CheckoutURL = "pay.test.com/checkout"
+	fe := validatePaymentGateway(&p)
+	fmt.Println("fe: ", fe)
+	if fe["checkoutUrl"] == "" || len(fe) != 1 {
+		t.Fail()
+	}
+	p.CheckoutURL = "https://pay.test.com/checkout"
+	if len(validatePaymentGateway(&p)) != 0 {
+		t.Fail()
+	}
+	var sp sdbi.StorePlugins
+	sp.PluginName = "pay"
+	sp.IframeURL = "javascript:alert(1)"
+	if validateStorePlugin(&sp)["iframeUrl"] == "" {
+		t.Fail()
+	}
+}
@@ -0,0 +1,85 @@
+//Package logging ...
+package logging
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+var levelRe = regexp.MustCompile(`^(INFO|DEBUG|ERROR):\s+\[(.*)\]$`)
+
+//JSONLogger JSONLogger writes one JSON object per line with every string
+//passed through Redact. Set it as the output of the standard log package
+//so Level_Logger lines are converted too.
+type JSONLogger struct {
+	Out io.Writer
+	mu  sync.Mutex
+}
+
+//Install Install sends the standard log package, and so every
+//Level_Logger, through l
+func (l *JSONLogger) Install() {
+	log.SetFlags(0)
+	log.SetOutput(l)
+}
+
+//Write Write converts a standard log line into a JSON entry
+func (l *JSONLogger) Write(p []byte) (int, error) {
+	for _, ln := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		level := "INFO"
+		msg := ln
+		if m := levelRe.FindStringSubmatch(ln); m != nil {
+			level = m[1]
+			msg = m[2]
+		}
+		l.Entry(level, msg, nil)
+	}
+	return len(p), nil
+}
+
+//Entry Entry writes a log entry with extra fields
+func (l *JSONLogger) Entry(level string, msg string, fields map[string]interface{}) {
+	var ent = make(map[string]interface{})
+	for k, v := range fields {
+		if s, ok := v.(string); ok {
+			v = Redact(s)
+		}
+		ent[k] = v
+	}
+	ent["time"] = time.Now().UTC().Format(time.RFC3339Nano)
+	ent["level"] = level
+	ent["msg"] = Redact(msg)
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(ent); err != nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.Out.Write(buf.Bytes())
+}
@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	lg "github.com/Ulbora/Level_Logger"
+)
+
+func TestJSONLogger_Install(t *testing.T) {
+	var buf bytes.Buffer
+	var jl JSONLogger
+	jl.Out = &buf
+	jl.Install()
+	defer log.SetOutput(os.Stderr)
+	defer log.SetFlags(log.LstdFlags)
+
+	var l lg.Logger
+	l.LogLevel = lg.AllLevel
+	l.Debug("sEnc: ", "YWRtaW46YWRtaW4=")
+	l.Error("failed")
+	fmt.Println(buf.String())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.FailNow()
+	}
+	var ent map[string]interface{}
+	json.Unmarshal([]byte(lines[0]), &ent)
+	if ent["level"] != "DEBUG" || strings.Contains(lines[0], "YWRtaW46YWRtaW4=") ||
+		!strings.Contains(ent["msg"].(string), redacted) || ent["time"] == nil {
+		t.Fail()
+	}
+	var ent2 map[string]interface{}
+	json.Unmarshal([]byte(lines[1]), &ent2)
+	if ent2["level"] != "ERROR" || ent2["msg"] != "failed" {
+		t.Fail()
+	}
+}
+
+func TestJSONLogger_Entry(t *testing.T) {
+	var buf bytes.Buffer
+	var jl JSONLogger
+	jl.Out = &buf
+	jl.Entry("INFO", "login", map[string]interface{}{"auth": "Bearer abc123", "status": 200})
+	var ent map[string]interface{}
+	json.Unmarshal(buf.Bytes(), &ent)
+	fmt.Println(ent)
+	if ent["auth"] != "Bearer "+redacted || ent["status"] != float64(200) {
+		t.Fail()
+	}
+}
@@ -0,0 +1,117 @@
+package logging
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+//RequestIDHeader RequestIDHeader is read from incoming requests, returned
+//to the client and sent on every backend call
+const RequestIDHeader = "X-Request-ID"
+
+type ctxKey int
+
+const requestInfoKey ctxKey = 0
+
+var requestIDRe = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+type requestInfo struct {
+	id   string
+	mu   sync.Mutex
+	user string
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	code int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	s.code = code
+	s.ResponseWriter.WriteHeader(code)
+}
+
+//RequestID RequestID returns the ID that Middleware assigned to r
+func RequestID(r *http.Request) string {
+	var rtn string
+	if ri, ok := r.Context().Value(requestInfoKey).(*requestInfo); ok {
+		rtn = ri.id
+	}
+	return rtn
+}
+
+//SetUser SetUser records the logged in user for the access log entry of r
+func SetUser(r *http.Request, user string) {
+	if ri, ok := r.Context().Value(requestInfoKey).(*requestInfo); ok {
+		ri.mu.Lock()
+		ri.user = user
+		ri.mu.Unlock()
+	}
+}
+
+//Middleware Middleware gives each request an ID, unless the caller sent
+//a usable one, and writes an access log entry when the request completes
+func Middleware(l *JSONLogger) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var ri requestInfo
+			ri.id = r.Header.Get(RequestIDHeader)
+			if !requestIDRe.MatchString(ri.id) {
+				ri.id = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, ri.id)
+			route := "unknown"
+			if cr := mux.CurrentRoute(r); cr != nil {
+				if tpl, err := cr.GetPathTemplate(); err == nil {
+					route = tpl
+				}
+			}
+			sr := &statusRecorder{ResponseWriter: w, code: http.StatusOK}
+			st := time.Now()
+			next.ServeHTTP(sr, r.WithContext(context.WithValue(r.Context(), requestInfoKey, &ri)))
+			ri.mu.Lock()
+			user := ri.user
+			ri.mu.Unlock()
+			l.Entry("INFO", "request", map[string]interface{}{
+				"requestId":  ri.id,
+				"method":     r.Method,
+				"route":      route,
+				"path":       r.URL.Path,
+				"user":       user,
+				"status":     sr.code,
+				"durationMs": float64(time.Since(st).Microseconds()) / 1000,
+			})
+		})
+	}
+}
+
+func newRequestID() string {
+	var b = make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
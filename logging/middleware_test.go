@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	var jl JSONLogger
+	jl.Out = &buf
+	var seen string
+	router := mux.NewRouter()
+	router.HandleFunc("/admin/editProductView/{id}", func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestID(r)
+		SetUser(r, "admin")
+		w.WriteHeader(http.StatusNotFound)
+	}).Methods("GET")
+	router.Use(Middleware(&jl))
+
+	r, _ := http.NewRequest("GET", "/admin/editProductView/5", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	var ent map[string]interface{}
+	json.Unmarshal(buf.Bytes(), &ent)
+	fmt.Println(ent)
+	if seen == "" || len(seen) != 32 || w.Header().Get(RequestIDHeader) != seen || ent["requestId"] != seen {
+		t.Fail()
+	}
+	if ent["route"] != "/admin/editProductView/{id}" || ent["user"] != "admin" ||
+		ent["status"] != float64(404) || ent["durationMs"] == nil {
+		t.Fail()
+	}
+}
+
+func TestMiddlewareIncomingID(t *testing.T) {
+	var buf bytes.Buffer
+	var jl JSONLogger
+	jl.Out = &buf
+	var seen string
+	router := mux.NewRouter()
+	router.HandleFunc("/admin", func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestID(r)
+	})
+	router.Use(Middleware(&jl))
+
+	r, _ := http.NewRequest("GET", "/admin", nil)
+	r.Header.Set(RequestIDHeader, "abc-123")
+	router.ServeHTTP(httptest.NewRecorder(), r)
+	if seen != "abc-123" {
+		t.Fail()
+	}
+
+	r2, _ := http.NewRequest("GET", "/admin", nil)
+	r2.Header.Set(RequestIDHeader, "bad id\nwith newline")
+	router.ServeHTTP(httptest.NewRecorder(), r2)
+	if seen == "bad id\nwith newline" || seen == "" {
+		t.Fail()
+	}
+}
+
+func TestRequestIDNoMiddleware(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/admin", nil)
+	SetUser(r, "admin")
+	if RequestID(r) != "" {
+		t.Fail()
+	}
+}
@@ -0,0 +1,44 @@
+package logging
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"regexp"
+)
+
+const redacted = "[REDACTED]"
+
+var (
+	authSchemeRe = regexp.MustCompile(`(?i)\b(basic|bearer)(\s+)[A-Za-z0-9._~+/=-]+`)
+	secretKeyRe  = regexp.MustCompile(`(?i)(\b(?:old)?(?:password|passwd|pw)|\bsecret|\w+_secret|\w*Secret|\bapi_?key|\bapiKey|\b(?:access|refresh)_?token|\btoken|\bsEnc\w*)` +
+		`("?\s*[:= ]\s*"?)([^\s",&\]}]+)`)
+)
+
+//Redact Redact replaces passwords, Basic and Bearer credentials, API
+//keys, OAuth secrets and tokens in s
+func Redact(s string) string {
+	s = authSchemeRe.ReplaceAllString(s, "${1}${2}"+redacted)
+	return secretKeyRe.ReplaceAllStringFunc(s, func(m string) string {
+		sm := secretKeyRe.FindStringSubmatch(m)
+		if sm[3] == redacted {
+			return m
+		}
+		return sm[1] + sm[2] + redacted
+	})
+}
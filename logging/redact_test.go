@@ -0,0 +1,37 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	var tests = []struct {
+		in     string
+		secret string
+	}{
+		{"DEBUG:  [sEnc:  YWRtaW46YWRtaW4=]", "YWRtaW46YWRtaW4="},
+		{"header: Authorization Basic YWRtaW46YWRtaW4=", "YWRtaW46YWRtaW4="},
+		{"Authorization: Bearer eyJhbGciOi.abc.def", "eyJhbGciOi.abc.def"},
+		{"DEBUG:  [password admin123]", "admin123"},
+		{`{"username":"bob","password":"hunter2"}`, "hunter2"},
+		{"oldPassword=pass1&x=1", "pass1"},
+		{"apiKey: 557444414141", "557444414141"},
+		{"url: /oauth/token?client_secret=abcd&grant_type=code", "abcd"},
+		{"AuthCodeSecret:sssss", "sssss"},
+		{"access_token=tok123", "tok123"},
+		{"refreshToken: rrr999", "rrr999"},
+	}
+	for _, tt := range tests {
+		out := Redact(tt.in)
+		fmt.Println(out)
+		if strings.Contains(out, tt.secret) || !strings.Contains(out, redacted) {
+			fmt.Println("not redacted: ", tt.in)
+			t.Fail()
+		}
+	}
+	if Redact("GET /admin/productListView 200") != "GET /admin/productListView 200" {
+		t.Fail()
+	}
+}
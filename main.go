@@ -22,18 +22,583 @@ package main
 */
 
 import (
+	"context"
+	"flag"
 	"fmt"
+	"html/template"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	px "github.com/Ulbora/GoProxy"
+	lg "github.com/Ulbora/Level_Logger"
+	apitok "github.com/Ulbora/Six910-ui/apitoksrv"
+	audit "github.com/Ulbora/Six910-ui/auditsrv"
+	bks "github.com/Ulbora/Six910-ui/bkupsrv"
+	"github.com/Ulbora/Six910-ui/config"
+	conts "github.com/Ulbora/Six910-ui/contsrv"
+	cust "github.com/Ulbora/Six910-ui/custsrv"
+	hand "github.com/Ulbora/Six910-ui/handlers"
+	imgs "github.com/Ulbora/Six910-ui/imgsrv"
+	"github.com/Ulbora/Six910-ui/logging"
+	mails "github.com/Ulbora/Six910-ui/mailsrv"
+	m "github.com/Ulbora/Six910-ui/managers"
+	met "github.com/Ulbora/Six910-ui/metrics"
+	perms "github.com/Ulbora/Six910-ui/permsrv"
+	rsts "github.com/Ulbora/Six910-ui/resetsrv"
+	ss "github.com/Ulbora/Six910-ui/sessrv"
+	stock "github.com/Ulbora/Six910-ui/stocksrv"
+	thr "github.com/Ulbora/Six910-ui/throtsrv"
+	tmpts "github.com/Ulbora/Six910-ui/tmptsrv"
+	totps "github.com/Ulbora/Six910-ui/totpsrv"
+	users "github.com/Ulbora/Six910-ui/usersrv"
+	api "github.com/Ulbora/Six910API-Go"
+	ml "github.com/Ulbora/go-mail-sender"
+	oauth2 "github.com/Ulbora/go-oauth2-client"
+	ds "github.com/Ulbora/json-datastore"
 	"github.com/gorilla/mux"
 )
 
 func main() {
-	// just the start of Six910-ui Server
-	// This is the storefront for Six910-ui.
-	fmt.Println("Six910 (six nine ten) UI is running on port 3001!")
+	var cfgPath string
+	flag.StringVar(&cfgPath, "config", os.Getenv("SIX910_CONFIG"), "path to a .json or .yaml config file")
+	flag.Parse()
+
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	var jl logging.JSONLogger
+	jl.Out = os.Stderr
+	jl.Install()
+
+	var l lg.Logger
+	l.LogLevel = cfg.LogLevel
+
+	var mt met.Six910Metrics
+	mts := mt.GetNew()
+
+	sh := buildHandler(cfg, &l, mts)
+	if sh.StockAlertService != nil {
+		sh.StartStockAlerts(time.Duration(cfg.StockAlertInterval) * time.Second)
+	}
+
+	router := buildRouter(sh.GetNew())
+	router.Handle("/metrics", mts).Methods("GET")
+	router.Use(logging.Middleware(&jl), met.Middleware(mts))
+	router.PathPrefix("/static/").Handler(http.StripPrefix("/static/", http.FileServer(http.Dir("./static/"))))
+
+	srv := &http.Server{Addr: ":" + cfg.Port, Handler: router}
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+
+	fmt.Println("Six910 (six nine ten) UI is running on port " + cfg.Port + "!")
+	if err := serve(srv, sh, stop, time.Duration(cfg.ShutdownTimeout)*time.Second); err != nil {
+		l.Error(err)
+		os.Exit(1)
+	}
+}
+
+//serve runs srv until a signal arrives on stop, then stops taking new
+//requests and gives in-flight requests and handler jobs until timeout to finish
+func serve(srv *http.Server, sh *hand.Six910Handler, stop <-chan os.Signal, timeout time.Duration) error {
+	var srvErr = make(chan error, 1)
+	go func() {
+		srvErr <- srv.ListenAndServe()
+	}()
+	select {
+	case err := <-srvErr:
+		return err
+	case sig := <-stop:
+		sh.Log.Info("received ", sig, ", shutting down")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	err := srv.Shutdown(ctx)
+	if err != nil {
+		sh.Log.Error("http server shutdown: ", err)
+	}
+	if herr := sh.Shutdown(ctx); herr != nil && err == nil {
+		err = herr
+	}
+	return err
+}
+
+func buildHandler(cfg *config.Config, l *lg.Logger, mts met.Metrics) *hand.Six910Handler {
+	var sh hand.Six910Handler
+	sh.Log = l
+
+	sh.BackendURL = cfg.BackendURL
+	sh.StoreName = cfg.StoreName
+	sh.LocalDomain = cfg.LocalDomain
+	sh.APIKey = cfg.APIKey
+	sh.OAuth2Enabled = cfg.OAuth2Enabled
+	sh.ContentStorePath = cfg.ContentStorePath
+	sh.TemplateStorePath = cfg.TemplateStorePath
+	sh.TemplateFilePath = cfg.TemplateFilePath
+	sh.ImagePath = cfg.ImagePath
+	sh.OauthHost = cfg.OauthHost
+	sh.UserHost = cfg.UserHost
+	sh.SchemeDefault = cfg.SchemeDefault
+
+	var cc hand.ClientCreds
+	cc.AuthCodeClient = cfg.AuthCodeClient
+	cc.AuthCodeSecret = cfg.AuthCodeSecret
+	cc.AuthCodeState = cfg.AuthCodeState
+	sh.ClientCreds = &cc
+
+	var at oauth2.AuthCodeToken
+	sh.Auth = &at
+
+	var cct oauth2.ClientCredentialsToken
+	cct.OauthHost = cfg.OauthHost
+	cct.ClientID = cfg.AuthCodeClient
+	cct.Secret = cfg.AuthCodeSecret
+	sh.ClientCredentials = cct.GetNew()
+
+	sh.Session.SessionKey = cfg.SessionKey
+	sh.Session.Secure = cfg.SessionSecure
+	sh.Session.MaxAge = cfg.AdminSessionMaxAge
+	sh.AdminSessions = buildAdminSessions(cfg, l)
+	var cus cust.MemoryStore
+	sh.CustomerSessions = cus.GetNew()
+	sh.SuperAdmins = cfg.SuperAdminList()
+	sh.PermissionService = buildPermissions(cfg, l)
+	sh.PublicURL = cfg.PublicURL
+	sh.MailFrom = cfg.MailFrom
+	sh.OrderStatuses = cfg.OrderStatusList()
+	sh.LoginThrottle = buildLoginThrottle(cfg, l)
+	if cfg.TwoFactorStorePath != "" {
+		var tfds ds.DataStore
+		tfds.Path = cfg.TwoFactorStorePath
+		var tfs totps.Six910TotpService
+		tfs.Store = tfds.GetNew()
+		tfs.Issuer = cfg.StoreName
+		tfs.Log = l
+		sh.TwoFactorService = tfs.GetNew()
+	}
+	if cfg.AuditStorePath != "" {
+		var as audit.Six910AuditService
+		as.Path = cfg.AuditStorePath
+		as.Log = l
+		sh.AuditService = as.GetNew()
+	}
+	if cfg.APITokenStorePath != "" {
+		var tds ds.DataStore
+		tds.Path = cfg.APITokenStorePath
+		var ts apitok.Six910TokenService
+		ts.Store = tds.GetNew()
+		ts.Log = l
+		sh.APITokenService = ts.GetNew()
+	}
+	if cfg.PasswordResetStorePath != "" {
+		var rds ds.DataStore
+		rds.Path = cfg.PasswordResetStorePath
+		var rs rsts.Six910ResetService
+		rs.Store = rds.GetNew()
+		rs.Key = []byte(cfg.PasswordResetKey)
+		rs.Timeout = time.Duration(cfg.PasswordResetTimeout) * time.Second
+		rs.Log = l
+		sh.ResetService = rs.GetNew()
+		sh.ResetUser = cfg.PasswordResetUser
+		sh.ResetPassword = cfg.PasswordResetPassword
+	}
+
+	var sapi api.Six910API
+	sapi.SetRestURL(sh.BackendURL)
+	sapi.SetStore(sh.StoreName, sh.LocalDomain)
+	sapi.SetAPIKey(sh.APIKey)
+	var mapi met.API
+	mapi.API = sapi.GetNew()
+	mapi.Metrics = mts
+	sh.API = mapi.GetNew()
+
+	var sm m.Six910Manager
+	sm.API = sh.API
+	sm.Log = l
+	sm.Throttle = sh.LoginThrottle
+	var mm met.Manager
+	mm.Manager = sm.GetNew()
+	mm.Metrics = mts
+	sh.Manager = mm.GetNew()
+
+	var cds ds.DataStore
+	cds.Path = cfg.ContentStorePath
+	contentStore := cds.GetNew()
+
+	var tds ds.DataStore
+	tds.Path = cfg.TemplateStorePath
+	templateStore := tds.GetNew()
+
+	var cs conts.CmsService
+	cs.Store = contentStore
+	cs.ContentStorePath = cfg.ContentStorePath
+	cs.CaptchaHost = cfg.CaptchaHost
+	cs.HitLimit = cfg.HitLimit
+	cs.Log = l
+	var mcs met.ContentService
+	mcs.Service = cs.GetNew()
+	mcs.Metrics = mts
+	sh.ContentService = mcs.GetNew()
+
+	var bs bks.Six910BackupService
+	bs.Store = contentStore
+	bs.TemplateStore = templateStore
+	bs.ContentStorePath = cfg.ContentStorePath
+	bs.TemplateStorePath = cfg.TemplateStorePath
+	bs.TemplateFilePath = cfg.TemplateFilePath
+	bs.ImagePath = cfg.ImagePath
+	bs.Log = l
+	var mbs met.BackupService
+	mbs.BackupService = bs.GetNew()
+	mbs.Metrics = mts
+	sh.BackupService = mbs.GetNew()
+
+	var ts tmpts.Six910TemplateService
+	ts.Store = contentStore
+	ts.TemplateStore = templateStore
+	ts.ContentStorePath = cfg.ContentStorePath
+	ts.TemplateStorePath = cfg.TemplateStorePath
+	ts.TemplateFilePath = cfg.TemplateFilePath
+	ts.Log = l
+	sh.TemplateService = ts.GetNew()
+
+	var is imgs.Six910ImageService
+	is.ImagePath = cfg.ImagePath
+	is.Log = l
+	sh.ImageService = is.GetNew()
+
+	var mss ml.SecureSender
+	mss.User = cfg.MailUser
+	mss.Password = cfg.MailPassword
+	mss.MailHost = cfg.MailHost
+	mss.Port = cfg.MailPort
+	var ms mails.Six910MailService
+	ms.MailSender = &mss
+	ms.Log = l
+	sh.MailService = ms.GetNew()
+
+	if cfg.StockAlertStorePath != "" {
+		var sds ds.DataStore
+		sds.Path = cfg.StockAlertStorePath
+		var sas stock.Six910StockService
+		sas.API = sh.API
+		sas.Store = sds.GetNew()
+		sas.MailService = sh.MailService
+		sas.MailFrom = cfg.MailFrom
+		sas.Recipients = cfg.StockAlertEmailList()
+		sas.StoreName = cfg.StoreName
+		sas.Log = l
+		sh.StockAlertService = sas.GetNew()
+		sh.StockAlertUser = cfg.StockAlertUser
+		sh.StockAlertPassword = cfg.StockAlertPassword
+	}
+
+	var us users.Oauth2UserService
+	us.ClientID = cfg.AuthCodeClient
+	us.Host = cfg.OauthHost
+	us.UserHost = cfg.UserHost
+	var gp px.GoProxy
+	us.Proxy = gp.GetNewProxy()
+	us.Log = l
+	sh.UserService = us.GetNew()
+
+	sh.AdminTemplates = template.Must(template.New("admin").Funcs(hand.AdminTemplateFuncs()).ParseGlob(cfg.AdminTemplates))
+
+	return &sh
+}
+
+//buildAdminSessions keeps admin sessions on disk when a store path is set
+//so admins stay logged in across restarts
+func buildAdminSessions(cfg *config.Config, l *lg.Logger) ss.Store {
+	var ex ss.Expiry
+	ex.IdleTimeout = time.Duration(cfg.AdminSessionIdleTimeout) * time.Second
+	ex.MaxAge = time.Duration(cfg.AdminSessionMaxAge) * time.Second
+	if cfg.AdminSessionStorePath != "" {
+		var fs ss.FileStore
+		fs.Expiry = ex
+		fs.Path = cfg.AdminSessionStorePath
+		fs.Key = []byte(cfg.SessionKey)
+		fs.Log = l
+		return fs.GetNew()
+	}
+	var ms ss.MemoryStore
+	ms.Expiry = ex
+	return ms.GetNew()
+}
+
+//buildPermissions maps admins to permission sets; config.Validate has
+//already rejected unknown set names
+func buildPermissions(cfg *config.Config, l *lg.Logger) perms.Service {
+	var ps perms.Six910PermissionService
+	ps.Users, _ = perms.ParseUsers(cfg.AdminPermissions)
+	ps.Default = cfg.DefaultPermissionList()
+	ps.SuperAdmins = cfg.SuperAdminList()
+	ps.Log = l
+	return ps.GetNew()
+}
+
+//buildLoginThrottle builds the throttle shared by admin and customer
+//logins. A client IP gets five times the failures of a username before
+//it is locked out since many users can be behind one address.
+func buildLoginThrottle(cfg *config.Config, l *lg.Logger) thr.Throttle {
+	var ms thr.MemoryStore
+	var lt thr.Six910Throttle
+	lt.Store = ms.GetNew()
+	lt.UserPolicy = thr.DefaultUserPolicy()
+	lt.UserPolicy.LockoutAfter = cfg.LoginLockoutAfter
+	lt.UserPolicy.LockoutTime = time.Duration(cfg.LoginLockoutTime) * time.Second
+	lt.IPPolicy = thr.DefaultIPPolicy()
+	lt.IPPolicy.LockoutAfter = cfg.LoginLockoutAfter * 5
+	lt.IPPolicy.LockoutTime = lt.UserPolicy.LockoutTime
+	lt.Log = l
+	return lt.GetNew()
+}
+
+func buildRouter(h hand.Handler) *mux.Router {
 	router := mux.NewRouter()
-	http.ListenAndServe(":3001", router)
+
+	router.HandleFunc("/healthz", h.Healthz).Methods("GET")
+	router.HandleFunc("/readyz", h.Readyz).Methods("GET")
+
+	//login
+	router.HandleFunc("/admin/login", h.StoreAdminLogin).Methods("GET")
+	router.HandleFunc("/admin/login", h.StoreAdminLoginNonOAuthUser).Methods("POST")
+	router.HandleFunc("/admin/loginTwoFactor", h.StoreAdminLoginTwoFactorPage).Methods("GET")
+	router.HandleFunc("/admin/loginTwoFactor", h.StoreAdminLoginTwoFactor).Methods("POST")
+	router.HandleFunc("/tokenHandler", h.StoreAdminHandleToken).Methods("GET")
+	router.HandleFunc("/admin/logout", h.StoreAdminLogout).Methods("GET")
+
+	//password reset
+	router.HandleFunc("/admin/forgotPassword", h.StoreAdminForgotPasswordPage).Methods("GET")
+	router.HandleFunc("/admin/forgotPassword", h.StoreAdminForgotPassword).Methods("POST")
+	router.HandleFunc("/admin/resetPassword", h.StoreAdminResetPasswordPage).Methods("GET")
+	router.HandleFunc("/admin/resetPassword", h.StoreAdminResetPassword).Methods("POST")
+	router.HandleFunc("/forgotPassword", h.CustomerForgotPasswordPage).Methods("GET")
+	router.HandleFunc("/forgotPassword", h.CustomerForgotPassword).Methods("POST")
+	router.HandleFunc("/resetPassword", h.CustomerResetPasswordPage).Methods("GET")
+	router.HandleFunc("/resetPassword", h.CustomerResetPassword).Methods("POST")
+
+	//JSON admin API; it is added before /admin so a script gets JSON
+	//errors instead of the login page
+	adminAPI := router.PathPrefix("/admin/api/v1").Subrouter()
+	adminAPI.NotFoundHandler = http.HandlerFunc(h.AdminAPINotFound)
+	adminAPI.Use(h.AdminAPIAuth)
+	adminAPI.HandleFunc("/{resource}", h.AdminAPIList).Methods("GET")
+	adminAPI.HandleFunc("/{resource}", h.AdminAPIAdd).Methods("POST")
+	adminAPI.HandleFunc("/{resource}/{id:[0-9]+}", h.AdminAPIGet).Methods("GET")
+	adminAPI.HandleFunc("/{resource}/{id:[0-9]+}", h.AdminAPIUpdate).Methods("PUT")
+	adminAPI.HandleFunc("/{resource}/{id:[0-9]+}", h.AdminAPIDelete).Methods("DELETE")
+
+	//JSON store API for headless front ends; cart, checkout and orders
+	//need a customer token from /login
+	storeAPI := router.PathPrefix("/api/v1").Subrouter()
+	storeAPI.NotFoundHandler = http.HandlerFunc(h.AdminAPINotFound)
+	storeAPI.HandleFunc("/products", h.StoreAPIProductList).Methods("GET")
+	storeAPI.HandleFunc("/products/{id:[0-9]+}", h.StoreAPIProduct).Methods("GET")
+	storeAPI.HandleFunc("/categories", h.StoreAPICategoryList).Methods("GET")
+	storeAPI.HandleFunc("/customers", h.StoreAPICreateAccount).Methods("POST")
+	storeAPI.HandleFunc("/login", h.StoreAPILogin).Methods("POST")
+	storeAPI.Handle("/logout", h.StoreAPIAuth(http.HandlerFunc(h.StoreAPILogout))).Methods("POST")
+	storeAPI.Handle("/cart", h.StoreAPIAuth(http.HandlerFunc(h.StoreAPIViewCart))).Methods("GET")
+	storeAPI.Handle("/cart/items", h.StoreAPIAuth(http.HandlerFunc(h.StoreAPIAddToCart))).Methods("POST")
+	storeAPI.Handle("/cart/items/{id:[0-9]+}", h.StoreAPIAuth(http.HandlerFunc(h.StoreAPIUpdateCart))).Methods("PUT")
+	storeAPI.Handle("/checkout", h.StoreAPIAuth(http.HandlerFunc(h.StoreAPICheckOut))).Methods("POST")
+	storeAPI.Handle("/orders", h.StoreAPIAuth(http.HandlerFunc(h.StoreAPIOrderList))).Methods("GET")
+
+	//everything else under /admin needs a logged in store admin
+	router.Handle("/admin", h.AdminAuth(h.AdminCSRF(http.HandlerFunc(h.StoreAdminIndex)))).Methods("GET")
+	admin := router.PathPrefix("/admin").Subrouter()
+	admin.Use(h.AdminAuth, h.AdminCSRF)
+
+	admin.HandleFunc("/changePassword", h.StoreAdminChangePassword).Methods("GET")
+	admin.HandleFunc("/changePassword", h.StoreAdminChangeUserPassword).Methods("POST")
+	admin.HandleFunc("/twoFactorView", h.StoreAdminTwoFactorPage).Methods("GET")
+	admin.HandleFunc("/twoFactorEnroll", h.StoreAdminStartTwoFactor).Methods("POST")
+	admin.HandleFunc("/twoFactorConfirm", h.StoreAdminConfirmTwoFactor).Methods("POST")
+	admin.HandleFunc("/twoFactorDisable", h.StoreAdminDisableTwoFactor).Methods("POST")
+	admin.HandleFunc("/apiTokenListView", h.StoreAdminViewAPITokenList).Methods("GET")
+	admin.HandleFunc("/addApiToken", h.StoreAdminAddAPIToken).Methods("POST")
+	admin.HandleFunc("/deleteApiToken/{id}", h.StoreAdminDeleteAPIToken).Methods("POST")
+
+	//admin users
+	users := admin.NewRoute().Subrouter()
+	users.Use(h.AdminPermission(perms.Users))
+	users.HandleFunc("/twoFactorUsersView", h.StoreAdminViewTwoFactorUsers).Methods("GET")
+	users.HandleFunc("/twoFactorReset/{username}", h.StoreAdminResetTwoFactor).Methods("POST")
+	users.HandleFunc("/sessionListView", h.StoreAdminViewSessionList).Methods("GET")
+	users.HandleFunc("/revokeSession/{ref}", h.StoreAdminRevokeSession).Methods("POST")
+	users.HandleFunc("/auditLogView", h.StoreAdminViewAuditLog).Methods("GET")
+	users.HandleFunc("/auditLogExport", h.StoreAdminExportAuditLog).Methods("GET")
+	users.HandleFunc("/adminUserListView", h.StoreAdminViewAdminUserList).Methods("GET")
+	users.HandleFunc("/addAdminUserView", h.StoreAdminAddAdminUserPage).Methods("GET")
+	users.HandleFunc("/addAdminUser", h.StoreAdminAddAdminUser).Methods("POST")
+	users.HandleFunc("/editAdminUserView/{username}", h.StoreAdminEditAdminUserPage).Methods("GET")
+	users.HandleFunc("/editAdminUser", h.StoreAdminEditAdminUser).Methods("POST")
+	users.HandleFunc("/enableAdminUser/{username}", h.StoreAdminEnableAdminUser).Methods("POST")
+	users.HandleFunc("/disableAdminUser/{username}", h.StoreAdminDisableAdminUser).Methods("POST")
+	users.HandleFunc("/resetAdminUserPassword/{username}", h.StoreAdminResetAdminUserPassword).Methods("POST")
+	users.HandleFunc("/deleteAdminUser/{username}", h.StoreAdminDeleteAdminUser).Methods("POST")
+
+	admin.HandleFunc("/index", h.StoreAdminIndex).Methods("GET")
+	admin.HandleFunc("/search", h.StoreAdminSearch).Methods("GET")
+
+	//product upload
+	products := admin.NewRoute().Subrouter()
+	products.Use(h.AdminPermission(perms.Products))
+	products.HandleFunc("/productUploadView", h.StoreAdminUploadProductFilePage).Methods("GET")
+	products.HandleFunc("/productUpload", h.StoreAdminUploadProductFile).Methods("POST")
+
+	//products
+	products.HandleFunc("/addProdView", h.StoreAdminAddProductPage).Methods("GET")
+	products.HandleFunc("/addProduct", h.StoreAdminAddProduct).Methods("POST")
+	products.HandleFunc("/editProductView/{id}", h.StoreAdminEditProductPage).Methods("GET")
+	products.HandleFunc("/editProduct", h.StoreAdminEditProduct).Methods("POST")
+	products.HandleFunc("/productListView", h.StoreAdminViewProductList).Methods("GET")
+	products.HandleFunc("/productListView/{start}/{end}", h.StoreAdminViewProductList).Methods("GET")
+	products.HandleFunc("/deleteProduct/{id}", h.StoreAdminDeleteProduct).Methods("POST")
+	products.HandleFunc("/productBulk", h.StoreAdminProductBulk).Methods("POST")
+
+	//stock alerts
+	products.HandleFunc("/stockAlertView", h.StoreAdminViewStockAlerts).Methods("GET")
+	products.HandleFunc("/stockAlertExport", h.StoreAdminExportStockAlerts).Methods("GET")
+
+	//orders
+	orders := admin.NewRoute().Subrouter()
+	orders.Use(h.AdminPermission(perms.Orders))
+	orders.HandleFunc("/editOrderView/{id}", h.StoreAdminEditOrderPage).Methods("GET")
+	orders.HandleFunc("/editOrder", h.StoreAdminEditOrder).Methods("POST")
+	orders.HandleFunc("/orderListView", h.StoreAdminViewOrderList).Methods("GET")
+	orders.HandleFunc("/orderListView/{status}", h.StoreAdminViewOrderList).Methods("GET")
+
+	//shipments
+	shipments := admin.NewRoute().Subrouter()
+	shipments.Use(h.AdminPermission(perms.Shipments))
+	shipments.HandleFunc("/addShipmentView/{id}", h.StoreAdminAddShipmentPage).Methods("GET")
+	shipments.HandleFunc("/addShipment", h.StoreAdminAddShipment).Methods("POST")
+	shipments.HandleFunc("/editShipmentView/{id}", h.StoreAdminEditShipmentPage).Methods("GET")
+	shipments.HandleFunc("/editShipment", h.StoreAdminEditShipment).Methods("POST")
+	shipments.HandleFunc("/shipmentListView/{oid}", h.StoreAdminViewShipmentList).Methods("GET")
+	shipments.HandleFunc("/deleteShipment/{id}", h.StoreAdminDeleteShipment).Methods("POST")
+
+	//customers
+	customers := admin.NewRoute().Subrouter()
+	customers.Use(h.AdminPermission(perms.Customers))
+	customers.HandleFunc("/editCustomerView/{id}", h.StoreAdminEditCustomerPage).Methods("GET")
+	customers.HandleFunc("/editCustomer", h.StoreAdminEditCustomer).Methods("POST")
+	customers.HandleFunc("/editCustomerUserView/{username}/{cid}", h.StoreAdminEditCustomerUserPage).Methods("GET")
+	customers.HandleFunc("/editCustomerUser", h.StoreAdminEditCustomerUser).Methods("POST")
+	customers.HandleFunc("/customerListView", h.StoreAdminViewCustomerList).Methods("GET")
+
+	//categories
+	products.HandleFunc("/addCategoryView", h.StoreAdminAddCategoryPage).Methods("GET")
+	products.HandleFunc("/addCategory", h.StoreAdminAddCategory).Methods("POST")
+	products.HandleFunc("/editCategoryView/{id}", h.StoreAdminEditCategoryPage).Methods("GET")
+	products.HandleFunc("/editCategory", h.StoreAdminEditCategory).Methods("POST")
+	products.HandleFunc("/categoryListView", h.StoreAdminViewCategoryList).Methods("GET")
+	products.HandleFunc("/deleteCategory/{id}", h.StoreAdminDeleteCategory).Methods("POST")
+
+	//distributors
+	products.HandleFunc("/addDistributorView", h.StoreAdminAddDistributorPage).Methods("GET")
+	products.HandleFunc("/addDistributor", h.StoreAdminAddDistributor).Methods("POST")
+	products.HandleFunc("/editDistributorView/{id}", h.StoreAdminEditDistributorPage).Methods("GET")
+	products.HandleFunc("/editDistributor", h.StoreAdminEditDistributor).Methods("POST")
+	products.HandleFunc("/distributorListView", h.StoreAdminViewDistributorList).Methods("GET")
+	products.HandleFunc("/deleteDistributor/{id}", h.StoreAdminDeleteDistributor).Methods("POST")
+
+	//insurance
+	payments := admin.NewRoute().Subrouter()
+	payments.Use(h.AdminPermission(perms.Payments))
+	payments.HandleFunc("/addInsuranceView", h.StoreAdminAddInsurancePage).Methods("GET")
+	payments.HandleFunc("/addInsurance", h.StoreAdminAddInsurance).Methods("POST")
+	payments.HandleFunc("/editInsuranceView/{id}", h.StoreAdminEditInsurancePage).Methods("GET")
+	payments.HandleFunc("/editInsurance", h.StoreAdminEditInsurance).Methods("POST")
+	payments.HandleFunc("/insuranceListView", h.StoreAdminViewInsuranceList).Methods("GET")
+	payments.HandleFunc("/deleteInsurance/{id}", h.StoreAdminDeleteInsurance).Methods("POST")
+
+	//payment gateways
+	payments.HandleFunc("/addPaymentGatewayView", h.StoreAdminAddPaymentGatewayPage).Methods("GET")
+	payments.HandleFunc("/addPaymentGateway", h.StoreAdminAddPaymentGateway).Methods("POST")
+	payments.HandleFunc("/editPaymentGatewayView/{id}", h.StoreAdminEditPaymentGatewayPage).Methods("GET")
+	payments.HandleFunc("/editPaymentGateway", h.StoreAdminEditPaymentGateway).Methods("POST")
+	payments.HandleFunc("/paymentGatewayListView", h.StoreAdminViewPaymentGatewayList).Methods("GET")
+	payments.HandleFunc("/deletePaymentGateway/{id}", h.StoreAdminDeletePaymentGateway).Methods("POST")
+
+	//plugins
+	plugins := admin.NewRoute().Subrouter()
+	plugins.Use(h.AdminPermission(perms.Plugins))
+	plugins.HandleFunc("/addPluginView", h.StoreAdminAddPluginPage).Methods("GET")
+	plugins.HandleFunc("/addPlugin", h.StoreAdminAddPlugin).Methods("POST")
+	plugins.HandleFunc("/editPluginView/{id}", h.StoreAdminEditPluginPage).Methods("GET")
+	plugins.HandleFunc("/editPlugin", h.StoreAdminEditPlugin).Methods("POST")
+	plugins.HandleFunc("/pluginListView", h.StoreAdminViewPluginList).Methods("GET")
+	plugins.HandleFunc("/pluginListView/{start}/{end}", h.StoreAdminViewPluginList).Methods("GET")
+	plugins.HandleFunc("/deletePlugin/{id}", h.StoreAdminDeletePlugin).Methods("POST")
+
+	//store plugins
+	plugins.HandleFunc("/addStorePluginView", h.StoreAdminAddStorePluginPage).Methods("GET")
+	plugins.HandleFunc("/addStorePlugin", h.StoreAdminAddStorePlugin).Methods("POST")
+	plugins.HandleFunc("/editStorePluginView/{id}", h.StoreAdminEditStorePluginPage).Methods("GET")
+	plugins.HandleFunc("/editStorePlugin", h.StoreAdminEditStorePlugin).Methods("POST")
+	plugins.HandleFunc("/storePluginListView", h.StoreAdminViewStorePluginList).Methods("GET")
+	plugins.HandleFunc("/deleteStorePlugin/{id}", h.StoreAdminDeleteStorePlugin).Methods("POST")
+
+	//shipping carriers
+	shipping := admin.NewRoute().Subrouter()
+	shipping.Use(h.AdminPermission(perms.Shipping))
+	shipping.HandleFunc("/addShippingCarrierView", h.StoreAdminAddCarrierPage).Methods("GET")
+	shipping.HandleFunc("/addShippingCarrier", h.StoreAdminAddCarrier).Methods("POST")
+	shipping.HandleFunc("/editShippingCarrierView/{id}", h.StoreAdminEditCarrierPage).Methods("GET")
+	shipping.HandleFunc("/editShippingCarrier", h.StoreAdminEditCarrier).Methods("POST")
+	shipping.HandleFunc("/shippingCarrierListView", h.StoreAdminViewCarrierList).Methods("GET")
+	shipping.HandleFunc("/deleteShippingCarrier/{id}", h.StoreAdminDeleteCarrier).Methods("POST")
+
+	//shipping methods
+	shipping.HandleFunc("/addShippingMethodView", h.StoreAdminAddShippingMethodPage).Methods("GET")
+	shipping.HandleFunc("/addShippingMethod", h.StoreAdminAddShippingMethod).Methods("POST")
+	shipping.HandleFunc("/editShippingMethodView/{id}", h.StoreAdminEditShippingMethodPage).Methods("GET")
+	shipping.HandleFunc("/editShippingMethod", h.StoreAdminEditShippingMethod).Methods("POST")
+	shipping.HandleFunc("/shippingMethodListView", h.StoreAdminViewShippingMethodList).Methods("GET")
+	shipping.HandleFunc("/deleteShippingMethod/{id}", h.StoreAdminDeleteShippingMethod).Methods("POST")
+
+	//regions
+	regions := admin.NewRoute().Subrouter()
+	regions.Use(h.AdminPermission(perms.Regions))
+	regions.HandleFunc("/addRegionView", h.StoreAdminAddRegionPage).Methods("GET")
+	regions.HandleFunc("/addRegion", h.StoreAdminAddRegion).Methods("POST")
+	regions.HandleFunc("/editRegionView/{id}", h.StoreAdminEditRegionPage).Methods("GET")
+	regions.HandleFunc("/editRegion", h.StoreAdminEditRegion).Methods("POST")
+	regions.HandleFunc("/regionView", h.StoreAdminViewRegionList).Methods("GET")
+	regions.HandleFunc("/deleteRegion/{id}", h.StoreAdminDeleteRegion).Methods("POST")
+
+	//sub regions
+	regions.HandleFunc("/addSubRegionView/{regionId}", h.StoreAdminAddSubRegionPage).Methods("GET")
+	regions.HandleFunc("/addSubRegion", h.StoreAdminAddSubRegion).Methods("POST")
+	regions.HandleFunc("/editSubRegionView/{id}/{regionId}", h.StoreAdminEditSubRegionPage).Methods("GET")
+	regions.HandleFunc("/editSubRegion", h.StoreAdminEditSubRegion).Methods("POST")
+	regions.HandleFunc("/subRegionView", h.StoreAdminViewSubRegionList).Methods("GET")
+	regions.HandleFunc("/subRegionView/{regionId}", h.StoreAdminViewSubRegionList).Methods("GET")
+	regions.HandleFunc("/deleteSubRegion/{id}", h.StoreAdminDeleteSubRegion).Methods("POST")
+
+	//excluded sub regions
+	regions.HandleFunc("/addExcludedSubRegionView/{regionId}/{subRegionId}", h.StoreAdminAddExcludedSubRegionPage).Methods("GET")
+	regions.HandleFunc("/addExcludedSubRegion", h.StoreAdminAddExcludedSubRegion).Methods("POST")
+	regions.HandleFunc("/excludedSubRegionView", h.StoreAdminViewExcludedSubRegionList).Methods("GET")
+	regions.HandleFunc("/excludedSubRegionView/{regionId}", h.StoreAdminViewExcludedSubRegionList).Methods("GET")
+	regions.HandleFunc("/deleteExcludedSubRegion/{id}/{regionId}", h.StoreAdminDeleteExcludedSubRegion).Methods("POST")
+
+	//included sub regions
+	regions.HandleFunc("/addIncludedSubRegionView/{regionId}/{subRegionId}", h.StoreAdminAddIncludedSubRegionPage).Methods("GET")
+	regions.HandleFunc("/addIncludedSubRegion", h.StoreAdminAddIncludedSubRegion).Methods("POST")
+	regions.HandleFunc("/includedSubRegionView", h.StoreAdminViewIncludedSubRegionList).Methods("GET")
+	regions.HandleFunc("/includedSubRegionView/{regionId}", h.StoreAdminViewIncludedSubRegionList).Methods("GET")
+	regions.HandleFunc("/deleteIncludedSubRegion/{id}/{regionId}", h.StoreAdminDeleteIncludedSubRegion).Methods("POST")
+
+	return router
 }
 
 // go mod init github.com/Ulbora/Six910-ui
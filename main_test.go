@@ -0,0 +1,222 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	lg "github.com/Ulbora/Level_Logger"
+	"github.com/Ulbora/Six910-ui/config"
+	hand "github.com/Ulbora/Six910-ui/handlers"
+	perms "github.com/Ulbora/Six910-ui/permsrv"
+	ss "github.com/Ulbora/Six910-ui/sessrv"
+	thr "github.com/Ulbora/Six910-ui/throtsrv"
+	"github.com/gorilla/mux"
+)
+
+func TestMain_buildRouter(t *testing.T) {
+	var sh hand.Six910Handler
+	var l lg.Logger
+	l.LogLevel = lg.AllLevel
+	sh.Log = &l
+	router := buildRouter(sh.GetNew())
+
+	var tests = []struct {
+		method string
+		url    string
+		vars   map[string]string
+	}{
+		{"GET", "/healthz", nil},
+		{"GET", "/readyz", nil},
+		{"GET", "/admin/login", nil},
+		{"POST", "/admin/login", nil},
+		{"GET", "/admin/index", nil},
+		{"GET", "/admin/editProductView/5", map[string]string{"id": "5"}},
+		{"GET", "/admin/productListView/0/100", map[string]string{"start": "0", "end": "100"}},
+		{"POST", "/admin/productBulk", nil},
+		{"GET", "/admin/orderListView/processing", map[string]string{"status": "processing"}},
+		{"GET", "/admin/shipmentListView/12", map[string]string{"oid": "12"}},
+		{"GET", "/admin/editCustomerUserView/tester/3", map[string]string{"username": "tester", "cid": "3"}},
+		{"GET", "/admin/editSubRegionView/4/2", map[string]string{"id": "4", "regionId": "2"}},
+		{"GET", "/admin/addExcludedSubRegionView/2/4", map[string]string{"regionId": "2", "subRegionId": "4"}},
+		{"POST", "/admin/deleteIncludedSubRegion/7/2", map[string]string{"id": "7", "regionId": "2"}},
+		{"POST", "/admin/addPaymentGateway", nil},
+		{"GET", "/admin/sessionListView", nil},
+		{"POST", "/admin/loginTwoFactor", nil},
+		{"POST", "/admin/twoFactorReset/tester", map[string]string{"username": "tester"}},
+		{"POST", "/admin/revokeSession/ab12", map[string]string{"ref": "ab12"}},
+		{"GET", "/admin/auditLogView", nil},
+		{"GET", "/admin/auditLogExport", nil},
+		{"GET", "/admin/stockAlertView", nil},
+		{"GET", "/admin/search", nil},
+		{"GET", "/admin/stockAlertExport", nil},
+		{"GET", "/admin/apiTokenListView", nil},
+		{"POST", "/admin/deleteApiToken/ab12", map[string]string{"id": "ab12"}},
+		{"GET", "/admin/api/v1/products", map[string]string{"resource": "products"}},
+		{"POST", "/admin/api/v1/categories", map[string]string{"resource": "categories"}},
+		{"PUT", "/admin/api/v1/orders/9", map[string]string{"resource": "orders", "id": "9"}},
+		{"DELETE", "/admin/api/v1/subRegions/4", map[string]string{"resource": "subRegions", "id": "4"}},
+		{"GET", "/admin/editAdminUserView/jane@test.com", map[string]string{"username": "jane@test.com"}},
+		{"POST", "/admin/disableAdminUser/jane", map[string]string{"username": "jane"}},
+		{"POST", "/admin/resetAdminUserPassword/jane", map[string]string{"username": "jane"}},
+		{"POST", "/admin/forgotPassword", nil},
+		{"GET", "/admin/resetPassword", nil},
+		{"POST", "/resetPassword", nil},
+		{"GET", "/api/v1/products", nil},
+		{"GET", "/api/v1/products/7", map[string]string{"id": "7"}},
+		{"PUT", "/api/v1/cart/items/7", map[string]string{"id": "7"}},
+		{"POST", "/api/v1/checkout", nil},
+	}
+	for _, tt := range tests {
+		r, _ := http.NewRequest(tt.method, tt.url, nil)
+		var match mux.RouteMatch
+		if !router.Match(r, &match) || match.MatchErr != nil {
+			fmt.Println("no route for: ", tt.method, tt.url)
+			t.Fail()
+			continue
+		}
+		for k, v := range tt.vars {
+			if match.Vars[k] != v {
+				fmt.Println("bad var for: ", tt.url, k, match.Vars[k])
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestMain_buildRouterWrongMethod(t *testing.T) {
+	var sh hand.Six910Handler
+	var l lg.Logger
+	sh.Log = &l
+	router := buildRouter(sh.GetNew())
+	for _, u := range []string{"/admin/addProduct", "/admin/deleteProduct/3", "/admin/productBulk"} {
+		r, _ := http.NewRequest("GET", u, nil)
+		var match mux.RouteMatch
+		if router.Match(r, &match) && match.MatchErr == nil {
+			fmt.Println("GET should not match: ", u)
+			t.Fail()
+		}
+	}
+}
+
+func TestMain_serveStopsOnSignal(t *testing.T) {
+	var sh hand.Six910Handler
+	var l lg.Logger
+	sh.Log = &l
+	srv := &http.Server{Addr: "127.0.0.1:0", Handler: buildRouter(sh.GetNew())}
+	stop := make(chan os.Signal, 1)
+	stop <- syscall.SIGTERM
+	err := serve(srv, &sh, stop, time.Second)
+	fmt.Println("serve err: ", err)
+	if err != nil {
+		t.Fail()
+	}
+}
+
+func TestMain_serveListenError(t *testing.T) {
+	var sh hand.Six910Handler
+	var l lg.Logger
+	sh.Log = &l
+	srv := &http.Server{Addr: "127.0.0.1:notaport"}
+	stop := make(chan os.Signal, 1)
+	err := serve(srv, &sh, stop, time.Second)
+	if err == nil {
+		t.Fail()
+	}
+}
+
+func TestMain_buildRouterAdminAuth(t *testing.T) {
+	var sh hand.Six910Handler
+	var l lg.Logger
+	sh.Log = &l
+	router := buildRouter(sh.GetNew())
+	for _, u := range []string{"GET /admin", "GET /admin/index", "GET /admin/productListView", "POST /admin/deleteProduct/3"} {
+		mu := strings.Split(u, " ")
+		r, _ := http.NewRequest(mu[0], mu[1], nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != http.StatusFound || w.Header().Get("Location") != "/admin/login" {
+			fmt.Println("not protected: ", u, w.Code, w.Header().Get("Location"))
+			t.Fail()
+		}
+	}
+}
+
+func TestMain_buildRouterAdminAPI(t *testing.T) {
+	var sh hand.Six910Handler
+	var l lg.Logger
+	sh.Log = &l
+	router := buildRouter(sh.GetNew())
+	for _, u := range []string{"GET /admin/api/v1/products 401", "DELETE /admin/api/v1/products/3 401",
+		"GET /admin/api/v1/products/abc 404", "GET /admin/api/v1 404"} {
+		mu := strings.Split(u, " ")
+		r, _ := http.NewRequest(mu[0], mu[1], nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if fmt.Sprint(w.Code) != mu[2] || w.Header().Get("Content-Type") != "application/json" {
+			fmt.Println("bad api answer: ", u, w.Code, w.Header().Get("Content-Type"))
+			t.Fail()
+		}
+	}
+}
+
+func TestMain_buildRouterStoreAPI(t *testing.T) {
+	var sh hand.Six910Handler
+	var l lg.Logger
+	sh.Log = &l
+	router := buildRouter(sh.GetNew())
+	for _, u := range []string{"GET /api/v1/cart 401", "POST /api/v1/checkout 401",
+		"GET /api/v1/orders 401", "GET /api/v1/products/abc 404", "GET /api/v1 404"} {
+		mu := strings.Split(u, " ")
+		r, _ := http.NewRequest(mu[0], mu[1], nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if fmt.Sprint(w.Code) != mu[2] || w.Header().Get("Content-Type") != "application/json" {
+			fmt.Println("bad store api answer: ", u, w.Code, w.Header().Get("Content-Type"))
+			t.Fail()
+		}
+	}
+}
+
+func TestMain_buildAdminSessions(t *testing.T) {
+	var l lg.Logger
+	cfg := config.Default()
+	if _, ok := buildAdminSessions(cfg, &l).(*ss.MemoryStore); !ok {
+		t.Fail()
+	}
+	dir, _ := ioutil.TempDir("", "six910sess")
+	defer os.RemoveAll(dir)
+	cfg.AdminSessionStorePath = dir
+	fs, ok := buildAdminSessions(cfg, &l).(*ss.FileStore)
+	if !ok || fs.IdleTimeout != 30*time.Minute || fs.MaxAge != 12*time.Hour || string(fs.Key) != cfg.SessionKey {
+		t.Fail()
+	}
+}
+
+func TestMain_buildLoginThrottle(t *testing.T) {
+	var l lg.Logger
+	cfg := config.Default()
+	cfg.LoginLockoutAfter = 4
+	lt, ok := buildLoginThrottle(cfg, &l).(*thr.Six910Throttle)
+	if !ok || lt.UserPolicy.LockoutAfter != 4 || lt.IPPolicy.LockoutAfter != 20 || lt.IPPolicy.LockoutTime != 15*time.Minute {
+		t.Fail()
+	}
+}
+
+func TestMain_buildPermissions(t *testing.T) {
+	var l lg.Logger
+	cfg := config.Default()
+	cfg.AdminPermissions = "packer:fulfillment"
+	cfg.SuperAdmins = "boss"
+	ps := buildPermissions(cfg, &l)
+	if ps.Allowed("packer", perms.Payments, false) || !ps.Allowed("packer", perms.Shipments, true) ||
+		!ps.Allowed("other", perms.Payments, true) || ps.SetsFor("boss")[0] != perms.FullSet {
+		t.Fail()
+	}
+}
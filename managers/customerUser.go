@@ -3,6 +3,7 @@ package managers
 import (
 	b64 "encoding/base64"
 
+	thr "github.com/Ulbora/Six910-ui/throtsrv"
 	api "github.com/Ulbora/Six910API-Go"
 )
 
@@ -24,12 +25,21 @@ import (
  along with this program.  If not, see <http://www.gnu.org/licenses/>.
 */
 
-//CustomerLogin CustomerLogin
-func (m *Six910Manager) CustomerLogin(u *api.User, hd *api.Headers) (bool, *api.User) {
+//CustomerLogin CustomerLogin fails without calling the API while the
+//username or clientIP is throttled; callers can ask Throttle how long to
+//wait with CustomerLoginKeys
+func (m *Six910Manager) CustomerLogin(u *api.User, clientIP string, hd *api.Headers) (bool, *api.User) {
 	var succl bool
 	var rtncl api.User
+	ukey := thr.UserKey(customerRole, u.Username)
+	keys := CustomerLoginKeys(u.Username, clientIP)
+	if m.Throttle != nil {
+		if wait := m.Throttle.Check(keys...); wait > 0 {
+			m.Log.Info("customer login throttled for: ", u.Username, " from: ", clientIP)
+			return succl, &rtncl
+		}
+	}
 	sEnccl := b64.StdEncoding.EncodeToString([]byte(u.Username + ":" + u.Password))
-	m.Log.Debug("sEnc: ", sEnccl)
 
 	hd.Set("Authorization", "Basic "+sEnccl)
 
@@ -41,17 +51,34 @@ func (m *Six910Manager) CustomerLogin(u *api.User, hd *api.Headers) (bool, *api.
 		rtncl.Role = usrcl.Role
 		rtncl.StoreID = usrcl.StoreID
 		rtncl.Username = usrcl.Username
+		rtncl.CustomerID = usrcl.CustomerID
+	}
+	if m.Throttle != nil {
+		if succl {
+			m.Throttle.Reset(ukey)
+		} else {
+			m.Throttle.Fail(keys...)
+		}
 	}
 	m.Log.Debug("rtn: ", rtncl)
 	return succl, &rtncl
 }
 
+//CustomerLoginKeys CustomerLoginKeys are the throttle keys of a customer
+//login: the username and, when known, the client IP
+func CustomerLoginKeys(username string, clientIP string) []string {
+	var rtn = []string{thr.UserKey(customerRole, username)}
+	if clientIP != "" {
+		rtn = append(rtn, thr.IPKey(clientIP))
+	}
+	return rtn
+}
+
 //CustomerChangePassword CustomerChangePassword
 func (m *Six910Manager) CustomerChangePassword(u *api.User, hd *api.Headers) (bool, *api.User) {
 	var succc bool
 	var rtncc api.User
 	sEnccc := b64.StdEncoding.EncodeToString([]byte(u.Username + ":" + u.OldPassword))
-	m.Log.Debug("sEnc: ", sEnccc)
 
 	hd.Set("Authorization", "Basic "+sEnccc)
 
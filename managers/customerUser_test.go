@@ -6,10 +6,12 @@ import (
 	"io/ioutil"
 	"net/http"
 	"testing"
+	"time"
 
 	px "github.com/Ulbora/GoProxy"
 	lg "github.com/Ulbora/Level_Logger"
 	mapi "github.com/Ulbora/Six910-ui/mockapi"
+	thr "github.com/Ulbora/Six910-ui/throtsrv"
 	api "github.com/Ulbora/Six910API-Go"
 )
 
@@ -33,7 +35,7 @@ func TestSix910Manager_CustomerLogin(t *testing.T) {
 	//---mock out the call
 	var gp px.MockGoProxy
 	var mres http.Response
-	mres.Body = ioutil.NopCloser(bytes.NewBufferString(`{"username": "tester123", "enabled": true, "role": "customer" }`))
+	mres.Body = ioutil.NopCloser(bytes.NewBufferString(`{"username": "tester123", "enabled": true, "role": "customer", "customerId": 18 }`))
 	gp.MockResp = &mres
 	gp.MockDoSuccess1 = true
 	gp.MockRespCode = 200
@@ -54,10 +56,100 @@ func TestSix910Manager_CustomerLogin(t *testing.T) {
 	//head.Set("Authorization", "Basic YWRtaW46YWRtaW4=")
 
 	m := sm.GetNew()
-	suc, us := m.CustomerLogin(&u, &head)
+	suc, us := m.CustomerLogin(&u, "10.0.0.1", &head)
 	fmt.Println("suc customer: ", suc)
 	fmt.Println("us customer: ", us)
-	if !suc || us.Username != "tester123" {
+	if !suc || us.Username != "tester123" || us.CustomerID != 18 {
+		t.Fail()
+	}
+}
+
+func TestSix910Manager_CustomerLoginThrottled(t *testing.T) {
+	var sm Six910Manager
+	var sapi mapi.MockAPI
+
+	//-----------start mocking------------------
+	var user api.UserResponse
+	user.Username = "tester123"
+	user.Role = customerRole
+	user.Enabled = false
+
+	sapi.MockUser = &user
+	//-----------end mocking --------
+
+	var l lg.Logger
+	l.LogLevel = lg.AllLevel
+	sm.API = sapi.GetNew()
+	sm.Log = &l
+	var ms thr.MemoryStore
+	var th thr.Six910Throttle
+	th.Store = ms.GetNew()
+	th.UserPolicy = thr.DefaultUserPolicy()
+	th.Log = &l
+	sm.Throttle = th.GetNew()
+
+	m := sm.GetNew()
+	var u api.User
+	u.Username = "tester123"
+	u.Password = "bad"
+	for i := 0; i < 4; i++ {
+		var head api.Headers
+		if suc, _ := m.CustomerLogin(&u, "10.0.0.1", &head); suc {
+			t.Fail()
+		}
+	}
+	if sm.Throttle.Check(thr.UserKey(customerRole, "tester123")) <= 0 {
+		t.Fail()
+	}
+	user.Enabled = true
+	var head api.Headers
+	suc, _ := m.CustomerLogin(&u, "10.0.0.1", &head)
+	fmt.Println("suc while throttled: ", suc)
+	if suc {
+		t.Fail()
+	}
+}
+
+func TestSix910Manager_CustomerLoginIPThrottled(t *testing.T) {
+	var sm Six910Manager
+	var sapi mapi.MockAPI
+	var user api.UserResponse
+	user.Role = customerRole
+	user.Enabled = true
+	sapi.MockUser = &user
+	var l lg.Logger
+	sm.API = sapi.GetNew()
+	sm.Log = &l
+	var ms thr.MemoryStore
+	var th thr.Six910Throttle
+	th.Store = ms.GetNew()
+	th.UserPolicy = thr.DefaultUserPolicy()
+	th.IPPolicy = thr.Policy{FreeAttempts: 2, BaseDelay: time.Minute, MaxDelay: time.Minute, LockoutTime: time.Hour}
+	th.Log = &l
+	sm.Throttle = th.GetNew()
+
+	m := sm.GetNew()
+	for i := 0; i < 3; i++ {
+		var u api.User
+		u.Username = fmt.Sprint("sprayed", i, "@bob.com")
+		u.Password = "bad"
+		var head api.Headers
+		if suc, _ := m.CustomerLogin(&u, "10.0.0.9", &head); suc {
+			t.Fail()
+		}
+	}
+	if sm.Throttle.Check(thr.IPKey("10.0.0.9")) <= 0 {
+		t.Fail()
+	}
+	var u api.User
+	u.Username = "bob@bob.com"
+	user.Username = u.Username
+	var head api.Headers
+	if suc, _ := m.CustomerLogin(&u, "10.0.0.9", &head); suc {
+		fmt.Println("logged in from a throttled IP")
+		t.Fail()
+	}
+	if suc, _ := m.CustomerLogin(&u, "10.0.0.10", &head); !suc {
 		t.Fail()
 	}
 }
@@ -159,7 +159,7 @@ type Manager interface {
 	ViewCustomerOrder(orderID int64, cid int64, hd *api.Headers) *CustomerOrder
 	ViewCustomerOrderList(cid int64, hd *api.Headers) *[]CustomerOrder
 
-	CustomerLogin(u *api.User, hd *api.Headers) (bool, *api.User)
+	CustomerLogin(u *api.User, clientIP string, hd *api.Headers) (bool, *api.User)
 	CustomerChangePassword(u *api.User, hd *api.Headers) (bool, *api.User)
 
 	//--------------------end ---new------------
@@ -23,6 +23,7 @@ import (
 	"sync"
 
 	lg "github.com/Ulbora/Level_Logger"
+	thr "github.com/Ulbora/Six910-ui/throtsrv"
 	api "github.com/Ulbora/Six910API-Go"
 )
 
@@ -31,7 +32,9 @@ type Six910Manager struct {
 	API api.API
 	//StoreID int64
 	Log *lg.Logger
-	mu  sync.Mutex
+	//Throttle slows down repeated failed customer logins; nil turns it off
+	Throttle thr.Throttle
+	mu       sync.Mutex
 }
 
 //GetNew GetNew
@@ -30,7 +30,6 @@ func (m *Six910Manager) StoreAdminLogin(u *api.User, hd *api.Headers) (bool, *ap
 	var rtn api.User
 
 	sEnca := b64.StdEncoding.EncodeToString([]byte(u.Username + ":" + u.Password))
-	m.Log.Debug("sEnc: ", sEnca)
 
 	hd.Set("Authorization", "Basic "+sEnca)
 
@@ -54,7 +53,6 @@ func (m *Six910Manager) StoreAdminChangePassword(u *api.User, hd *api.Headers) (
 	var suc bool
 	var rtnac api.User
 	sEncac := b64.StdEncoding.EncodeToString([]byte(u.Username + ":" + u.OldPassword))
-	m.Log.Debug("sEnc: ", sEncac)
 
 	hd.Set("Authorization", "Basic "+sEncac)
 
@@ -0,0 +1,1175 @@
+package metrics
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"time"
+
+	api "github.com/Ulbora/Six910API-Go"
+	sdbi "github.com/Ulbora/six910-database-interface"
+)
+
+//API API decorates an api.API and records the count and duration of every
+//call. Calls returning a Response or ResponseID succeed when Success is set;
+//all other calls succeed when the backend returned a result.
+type API struct {
+	API     api.API
+	Metrics Metrics
+}
+
+//GetNew GetNew
+func (ia *API) GetNew() api.API {
+	return ia
+}
+
+//AddAddress AddAddress
+func (ia *API) AddAddress(a *sdbi.Address, headers *api.Headers) *api.ResponseID {
+	st := time.Now()
+	rtn := ia.API.AddAddress(a, headers)
+	ia.Metrics.ObserveAPICall("AddAddress", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//UpdateAddress UpdateAddress
+func (ia *API) UpdateAddress(a *sdbi.Address, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.UpdateAddress(a, headers)
+	ia.Metrics.ObserveAPICall("UpdateAddress", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetAddress GetAddress
+func (ia *API) GetAddress(id int64, cid int64, headers *api.Headers) *sdbi.Address {
+	st := time.Now()
+	rtn := ia.API.GetAddress(id, cid, headers)
+	ia.Metrics.ObserveAPICall("GetAddress", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetAddressList GetAddressList
+func (ia *API) GetAddressList(cid int64, headers *api.Headers) *[]sdbi.Address {
+	st := time.Now()
+	rtn := ia.API.GetAddressList(cid, headers)
+	ia.Metrics.ObserveAPICall("GetAddressList", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//DeleteAddress DeleteAddress
+func (ia *API) DeleteAddress(id int64, cid int64, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.DeleteAddress(id, cid, headers)
+	ia.Metrics.ObserveAPICall("DeleteAddress", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//AddCart AddCart
+func (ia *API) AddCart(c *sdbi.Cart, headers *api.Headers) *api.ResponseID {
+	st := time.Now()
+	rtn := ia.API.AddCart(c, headers)
+	ia.Metrics.ObserveAPICall("AddCart", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//UpdateCart UpdateCart
+func (ia *API) UpdateCart(c *sdbi.Cart, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.UpdateCart(c, headers)
+	ia.Metrics.ObserveAPICall("UpdateCart", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetCart GetCart
+func (ia *API) GetCart(cid int64, headers *api.Headers) *sdbi.Cart {
+	st := time.Now()
+	rtn := ia.API.GetCart(cid, headers)
+	ia.Metrics.ObserveAPICall("GetCart", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//DeleteCart DeleteCart
+func (ia *API) DeleteCart(id int64, cid int64, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.DeleteCart(id, cid, headers)
+	ia.Metrics.ObserveAPICall("DeleteCart", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//AddCartItem AddCartItem
+func (ia *API) AddCartItem(ci *sdbi.CartItem, cid int64, headers *api.Headers) *api.ResponseID {
+	st := time.Now()
+	rtn := ia.API.AddCartItem(ci, cid, headers)
+	ia.Metrics.ObserveAPICall("AddCartItem", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//UpdateCartItem UpdateCartItem
+func (ia *API) UpdateCartItem(ci *sdbi.CartItem, cid int64, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.UpdateCartItem(ci, cid, headers)
+	ia.Metrics.ObserveAPICall("UpdateCartItem", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetCartItem GetCartItem
+func (ia *API) GetCartItem(cid int64, prodID int64, headers *api.Headers) *sdbi.CartItem {
+	st := time.Now()
+	rtn := ia.API.GetCartItem(cid, prodID, headers)
+	ia.Metrics.ObserveAPICall("GetCartItem", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetCartItemList GetCartItemList
+func (ia *API) GetCartItemList(cartID int64, cid int64, headers *api.Headers) *[]sdbi.CartItem {
+	st := time.Now()
+	rtn := ia.API.GetCartItemList(cartID, cid, headers)
+	ia.Metrics.ObserveAPICall("GetCartItemList", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//DeleteCartItem DeleteCartItem
+func (ia *API) DeleteCartItem(id int64, prodID int64, cartID int64, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.DeleteCartItem(id, prodID, cartID, headers)
+	ia.Metrics.ObserveAPICall("DeleteCartItem", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//AddCategory AddCategory
+func (ia *API) AddCategory(c *sdbi.Category, headers *api.Headers) *api.ResponseID {
+	st := time.Now()
+	rtn := ia.API.AddCategory(c, headers)
+	ia.Metrics.ObserveAPICall("AddCategory", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//UpdateCategory UpdateCategory
+func (ia *API) UpdateCategory(c *sdbi.Category, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.UpdateCategory(c, headers)
+	ia.Metrics.ObserveAPICall("UpdateCategory", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetCategory GetCategory
+func (ia *API) GetCategory(id int64, headers *api.Headers) *sdbi.Category {
+	st := time.Now()
+	rtn := ia.API.GetCategory(id, headers)
+	ia.Metrics.ObserveAPICall("GetCategory", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetCategoryList GetCategoryList
+func (ia *API) GetCategoryList(headers *api.Headers) *[]sdbi.Category {
+	st := time.Now()
+	rtn := ia.API.GetCategoryList(headers)
+	ia.Metrics.ObserveAPICall("GetCategoryList", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetSubCategoryList GetSubCategoryList
+func (ia *API) GetSubCategoryList(catID int64, headers *api.Headers) *[]sdbi.Category {
+	st := time.Now()
+	rtn := ia.API.GetSubCategoryList(catID, headers)
+	ia.Metrics.ObserveAPICall("GetSubCategoryList", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//DeleteCategory DeleteCategory
+func (ia *API) DeleteCategory(id int64, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.DeleteCategory(id, headers)
+	ia.Metrics.ObserveAPICall("DeleteCategory", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//AddCustomer AddCustomer
+func (ia *API) AddCustomer(c *sdbi.Customer, headers *api.Headers) *api.ResponseID {
+	st := time.Now()
+	rtn := ia.API.AddCustomer(c, headers)
+	ia.Metrics.ObserveAPICall("AddCustomer", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//UpdateCustomer UpdateCustomer
+func (ia *API) UpdateCustomer(c *sdbi.Customer, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.UpdateCustomer(c, headers)
+	ia.Metrics.ObserveAPICall("UpdateCustomer", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetCustomer GetCustomer
+func (ia *API) GetCustomer(email string, headers *api.Headers) *sdbi.Customer {
+	st := time.Now()
+	rtn := ia.API.GetCustomer(email, headers)
+	ia.Metrics.ObserveAPICall("GetCustomer", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetCustomerID GetCustomerID
+func (ia *API) GetCustomerID(id int64, headers *api.Headers) *sdbi.Customer {
+	st := time.Now()
+	rtn := ia.API.GetCustomerID(id, headers)
+	ia.Metrics.ObserveAPICall("GetCustomerID", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetCustomerList GetCustomerList
+func (ia *API) GetCustomerList(headers *api.Headers) *[]sdbi.Customer {
+	st := time.Now()
+	rtn := ia.API.GetCustomerList(headers)
+	ia.Metrics.ObserveAPICall("GetCustomerList", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//DeleteCustomer DeleteCustomer
+func (ia *API) DeleteCustomer(id int64, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.DeleteCustomer(id, headers)
+	ia.Metrics.ObserveAPICall("DeleteCustomer", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//AddDataStoreWriteLock AddDataStoreWriteLock
+func (ia *API) AddDataStoreWriteLock(w *sdbi.DataStoreWriteLock, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.AddDataStoreWriteLock(w, headers)
+	ia.Metrics.ObserveAPICall("AddDataStoreWriteLock", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//UpdateDataStoreWriteLock UpdateDataStoreWriteLock
+func (ia *API) UpdateDataStoreWriteLock(w *sdbi.DataStoreWriteLock, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.UpdateDataStoreWriteLock(w, headers)
+	ia.Metrics.ObserveAPICall("UpdateDataStoreWriteLock", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetDataStoreWriteLock GetDataStoreWriteLock
+func (ia *API) GetDataStoreWriteLock(dataStore string, headers *api.Headers) *sdbi.DataStoreWriteLock {
+	st := time.Now()
+	rtn := ia.API.GetDataStoreWriteLock(dataStore, headers)
+	ia.Metrics.ObserveAPICall("GetDataStoreWriteLock", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//AddLocalDatastore AddLocalDatastore
+func (ia *API) AddLocalDatastore(d *sdbi.LocalDataStore, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.AddLocalDatastore(d, headers)
+	ia.Metrics.ObserveAPICall("AddLocalDatastore", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//UpdateLocalDatastore UpdateLocalDatastore
+func (ia *API) UpdateLocalDatastore(d *sdbi.LocalDataStore, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.UpdateLocalDatastore(d, headers)
+	ia.Metrics.ObserveAPICall("UpdateLocalDatastore", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetLocalDatastore GetLocalDatastore
+func (ia *API) GetLocalDatastore(dataStoreName string, headers *api.Headers) *sdbi.LocalDataStore {
+	st := time.Now()
+	rtn := ia.API.GetLocalDatastore(dataStoreName, headers)
+	ia.Metrics.ObserveAPICall("GetLocalDatastore", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//AddDistributor AddDistributor
+func (ia *API) AddDistributor(d *sdbi.Distributor, headers *api.Headers) *api.ResponseID {
+	st := time.Now()
+	rtn := ia.API.AddDistributor(d, headers)
+	ia.Metrics.ObserveAPICall("AddDistributor", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//UpdateDistributor UpdateDistributor
+func (ia *API) UpdateDistributor(d *sdbi.Distributor, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.UpdateDistributor(d, headers)
+	ia.Metrics.ObserveAPICall("UpdateDistributor", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetDistributor GetDistributor
+func (ia *API) GetDistributor(id int64, headers *api.Headers) *sdbi.Distributor {
+	st := time.Now()
+	rtn := ia.API.GetDistributor(id, headers)
+	ia.Metrics.ObserveAPICall("GetDistributor", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetDistributorList GetDistributorList
+func (ia *API) GetDistributorList(headers *api.Headers) *[]sdbi.Distributor {
+	st := time.Now()
+	rtn := ia.API.GetDistributorList(headers)
+	ia.Metrics.ObserveAPICall("GetDistributorList", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//DeleteDistributor DeleteDistributor
+func (ia *API) DeleteDistributor(id int64, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.DeleteDistributor(id, headers)
+	ia.Metrics.ObserveAPICall("DeleteDistributor", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//AddExcludedSubRegion AddExcludedSubRegion
+func (ia *API) AddExcludedSubRegion(e *sdbi.ExcludedSubRegion, headers *api.Headers) *api.ResponseID {
+	st := time.Now()
+	rtn := ia.API.AddExcludedSubRegion(e, headers)
+	ia.Metrics.ObserveAPICall("AddExcludedSubRegion", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetExcludedSubRegionList GetExcludedSubRegionList
+func (ia *API) GetExcludedSubRegionList(regionID int64, headers *api.Headers) *[]sdbi.ExcludedSubRegion {
+	st := time.Now()
+	rtn := ia.API.GetExcludedSubRegionList(regionID, headers)
+	ia.Metrics.ObserveAPICall("GetExcludedSubRegionList", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//DeleteExcludedSubRegion DeleteExcludedSubRegion
+func (ia *API) DeleteExcludedSubRegion(id int64, regionID int64, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.DeleteExcludedSubRegion(id, regionID, headers)
+	ia.Metrics.ObserveAPICall("DeleteExcludedSubRegion", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//AddIncludedSubRegion AddIncludedSubRegion
+func (ia *API) AddIncludedSubRegion(e *sdbi.IncludedSubRegion, headers *api.Headers) *api.ResponseID {
+	st := time.Now()
+	rtn := ia.API.AddIncludedSubRegion(e, headers)
+	ia.Metrics.ObserveAPICall("AddIncludedSubRegion", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetIncludedSubRegionList GetIncludedSubRegionList
+func (ia *API) GetIncludedSubRegionList(regionID int64, headers *api.Headers) *[]sdbi.IncludedSubRegion {
+	st := time.Now()
+	rtn := ia.API.GetIncludedSubRegionList(regionID, headers)
+	ia.Metrics.ObserveAPICall("GetIncludedSubRegionList", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//DeleteIncludedSubRegion DeleteIncludedSubRegion
+func (ia *API) DeleteIncludedSubRegion(id int64, regionID int64, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.DeleteIncludedSubRegion(id, regionID, headers)
+	ia.Metrics.ObserveAPICall("DeleteIncludedSubRegion", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//AddInstance AddInstance
+func (ia *API) AddInstance(i *sdbi.Instances, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.AddInstance(i, headers)
+	ia.Metrics.ObserveAPICall("AddInstance", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//UpdateInstance UpdateInstance
+func (ia *API) UpdateInstance(i *sdbi.Instances, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.UpdateInstance(i, headers)
+	ia.Metrics.ObserveAPICall("UpdateInstance", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetInstance GetInstance
+func (ia *API) GetInstance(name string, dataStoreName string, headers *api.Headers) *sdbi.Instances {
+	st := time.Now()
+	rtn := ia.API.GetInstance(name, dataStoreName, headers)
+	ia.Metrics.ObserveAPICall("GetInstance", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetInstanceList GetInstanceList
+func (ia *API) GetInstanceList(dataStoreName string, headers *api.Headers) *[]sdbi.Instances {
+	st := time.Now()
+	rtn := ia.API.GetInstanceList(dataStoreName, headers)
+	ia.Metrics.ObserveAPICall("GetInstanceList", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//AddInsurance AddInsurance
+func (ia *API) AddInsurance(i *sdbi.Insurance, headers *api.Headers) *api.ResponseID {
+	st := time.Now()
+	rtn := ia.API.AddInsurance(i, headers)
+	ia.Metrics.ObserveAPICall("AddInsurance", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//UpdateInsurance UpdateInsurance
+func (ia *API) UpdateInsurance(i *sdbi.Insurance, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.UpdateInsurance(i, headers)
+	ia.Metrics.ObserveAPICall("UpdateInsurance", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetInsurance GetInsurance
+func (ia *API) GetInsurance(id int64, headers *api.Headers) *sdbi.Insurance {
+	st := time.Now()
+	rtn := ia.API.GetInsurance(id, headers)
+	ia.Metrics.ObserveAPICall("GetInsurance", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetInsuranceList GetInsuranceList
+func (ia *API) GetInsuranceList(headers *api.Headers) *[]sdbi.Insurance {
+	st := time.Now()
+	rtn := ia.API.GetInsuranceList(headers)
+	ia.Metrics.ObserveAPICall("GetInsuranceList", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//DeleteInsurance DeleteInsurance
+func (ia *API) DeleteInsurance(id int64, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.DeleteInsurance(id, headers)
+	ia.Metrics.ObserveAPICall("DeleteInsurance", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//AddOrder AddOrder
+func (ia *API) AddOrder(o *sdbi.Order, headers *api.Headers) *api.ResponseID {
+	st := time.Now()
+	rtn := ia.API.AddOrder(o, headers)
+	ia.Metrics.ObserveAPICall("AddOrder", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//UpdateOrder UpdateOrder
+func (ia *API) UpdateOrder(o *sdbi.Order, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.UpdateOrder(o, headers)
+	ia.Metrics.ObserveAPICall("UpdateOrder", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetOrder GetOrder
+func (ia *API) GetOrder(id int64, headers *api.Headers) *sdbi.Order {
+	st := time.Now()
+	rtn := ia.API.GetOrder(id, headers)
+	ia.Metrics.ObserveAPICall("GetOrder", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetOrderList GetOrderList
+func (ia *API) GetOrderList(cid int64, headers *api.Headers) *[]sdbi.Order {
+	st := time.Now()
+	rtn := ia.API.GetOrderList(cid, headers)
+	ia.Metrics.ObserveAPICall("GetOrderList", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetStoreOrderList GetStoreOrderList
+func (ia *API) GetStoreOrderList(headers *api.Headers) *[]sdbi.Order {
+	st := time.Now()
+	rtn := ia.API.GetStoreOrderList(headers)
+	ia.Metrics.ObserveAPICall("GetStoreOrderList", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetStoreOrderListByStatus GetStoreOrderListByStatus
+func (ia *API) GetStoreOrderListByStatus(status string, headers *api.Headers) *[]sdbi.Order {
+	st := time.Now()
+	rtn := ia.API.GetStoreOrderListByStatus(status, headers)
+	ia.Metrics.ObserveAPICall("GetStoreOrderListByStatus", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//DeleteOrder DeleteOrder
+func (ia *API) DeleteOrder(id int64, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.DeleteOrder(id, headers)
+	ia.Metrics.ObserveAPICall("DeleteOrder", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//AddOrderComments AddOrderComments
+func (ia *API) AddOrderComments(c *sdbi.OrderComment, headers *api.Headers) *api.ResponseID {
+	st := time.Now()
+	rtn := ia.API.AddOrderComments(c, headers)
+	ia.Metrics.ObserveAPICall("AddOrderComments", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetOrderCommentList GetOrderCommentList
+func (ia *API) GetOrderCommentList(orderID int64, headers *api.Headers) *[]sdbi.OrderComment {
+	st := time.Now()
+	rtn := ia.API.GetOrderCommentList(orderID, headers)
+	ia.Metrics.ObserveAPICall("GetOrderCommentList", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//AddOrderItem AddOrderItem
+func (ia *API) AddOrderItem(i *sdbi.OrderItem, headers *api.Headers) *api.ResponseID {
+	st := time.Now()
+	rtn := ia.API.AddOrderItem(i, headers)
+	ia.Metrics.ObserveAPICall("AddOrderItem", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//UpdateOrderItem UpdateOrderItem
+func (ia *API) UpdateOrderItem(i *sdbi.OrderItem, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.UpdateOrderItem(i, headers)
+	ia.Metrics.ObserveAPICall("UpdateOrderItem", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetOrderItem GetOrderItem
+func (ia *API) GetOrderItem(id int64, headers *api.Headers) *sdbi.OrderItem {
+	st := time.Now()
+	rtn := ia.API.GetOrderItem(id, headers)
+	ia.Metrics.ObserveAPICall("GetOrderItem", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetOrderItemList GetOrderItemList
+func (ia *API) GetOrderItemList(orderID int64, headers *api.Headers) *[]sdbi.OrderItem {
+	st := time.Now()
+	rtn := ia.API.GetOrderItemList(orderID, headers)
+	ia.Metrics.ObserveAPICall("GetOrderItemList", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//DeleteOrderItem DeleteOrderItem
+func (ia *API) DeleteOrderItem(id int64, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.DeleteOrderItem(id, headers)
+	ia.Metrics.ObserveAPICall("DeleteOrderItem", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//AddOrderTransaction AddOrderTransaction
+func (ia *API) AddOrderTransaction(t *sdbi.OrderTransaction, headers *api.Headers) *api.ResponseID {
+	st := time.Now()
+	rtn := ia.API.AddOrderTransaction(t, headers)
+	ia.Metrics.ObserveAPICall("AddOrderTransaction", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetOrderTransactionList GetOrderTransactionList
+func (ia *API) GetOrderTransactionList(orderID int64, headers *api.Headers) *[]sdbi.OrderTransaction {
+	st := time.Now()
+	rtn := ia.API.GetOrderTransactionList(orderID, headers)
+	ia.Metrics.ObserveAPICall("GetOrderTransactionList", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//AddPaymentGateway AddPaymentGateway
+func (ia *API) AddPaymentGateway(pgw *sdbi.PaymentGateway, headers *api.Headers) *api.ResponseID {
+	st := time.Now()
+	rtn := ia.API.AddPaymentGateway(pgw, headers)
+	ia.Metrics.ObserveAPICall("AddPaymentGateway", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//UpdatePaymentGateway UpdatePaymentGateway
+func (ia *API) UpdatePaymentGateway(pgw *sdbi.PaymentGateway, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.UpdatePaymentGateway(pgw, headers)
+	ia.Metrics.ObserveAPICall("UpdatePaymentGateway", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetPaymentGateway GetPaymentGateway
+func (ia *API) GetPaymentGateway(id int64, headers *api.Headers) *sdbi.PaymentGateway {
+	st := time.Now()
+	rtn := ia.API.GetPaymentGateway(id, headers)
+	ia.Metrics.ObserveAPICall("GetPaymentGateway", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetPaymentGateways GetPaymentGateways
+func (ia *API) GetPaymentGateways(headers *api.Headers) *[]sdbi.PaymentGateway {
+	st := time.Now()
+	rtn := ia.API.GetPaymentGateways(headers)
+	ia.Metrics.ObserveAPICall("GetPaymentGateways", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//DeletePaymentGateway DeletePaymentGateway
+func (ia *API) DeletePaymentGateway(id int64, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.DeletePaymentGateway(id, headers)
+	ia.Metrics.ObserveAPICall("DeletePaymentGateway", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//AddPlugin AddPlugin
+func (ia *API) AddPlugin(p *sdbi.Plugins, headers *api.Headers) *api.ResponseID {
+	st := time.Now()
+	rtn := ia.API.AddPlugin(p, headers)
+	ia.Metrics.ObserveAPICall("AddPlugin", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//UpdatePlugin UpdatePlugin
+func (ia *API) UpdatePlugin(p *sdbi.Plugins, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.UpdatePlugin(p, headers)
+	ia.Metrics.ObserveAPICall("UpdatePlugin", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetPlugin GetPlugin
+func (ia *API) GetPlugin(id int64, headers *api.Headers) *sdbi.Plugins {
+	st := time.Now()
+	rtn := ia.API.GetPlugin(id, headers)
+	ia.Metrics.ObserveAPICall("GetPlugin", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetPluginList GetPluginList
+func (ia *API) GetPluginList(start int64, end int64, headers *api.Headers) *[]sdbi.Plugins {
+	st := time.Now()
+	rtn := ia.API.GetPluginList(start, end, headers)
+	ia.Metrics.ObserveAPICall("GetPluginList", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//DeletePlugin DeletePlugin
+func (ia *API) DeletePlugin(id int64, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.DeletePlugin(id, headers)
+	ia.Metrics.ObserveAPICall("DeletePlugin", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//AddProduct AddProduct
+func (ia *API) AddProduct(p *sdbi.Product, headers *api.Headers) *api.ResponseID {
+	st := time.Now()
+	rtn := ia.API.AddProduct(p, headers)
+	ia.Metrics.ObserveAPICall("AddProduct", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//UpdateProduct UpdateProduct
+func (ia *API) UpdateProduct(p *sdbi.Product, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.UpdateProduct(p, headers)
+	ia.Metrics.ObserveAPICall("UpdateProduct", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetProductByID GetProductByID
+func (ia *API) GetProductByID(id int64, headers *api.Headers) *sdbi.Product {
+	st := time.Now()
+	rtn := ia.API.GetProductByID(id, headers)
+	ia.Metrics.ObserveAPICall("GetProductByID", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetProductBySku GetProductBySku
+func (ia *API) GetProductBySku(sku string, did int64, headers *api.Headers) *sdbi.Product {
+	st := time.Now()
+	rtn := ia.API.GetProductBySku(sku, did, headers)
+	ia.Metrics.ObserveAPICall("GetProductBySku", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetProductsByName GetProductsByName
+func (ia *API) GetProductsByName(name string, start int64, end int64, headers *api.Headers) *[]sdbi.Product {
+	st := time.Now()
+	rtn := ia.API.GetProductsByName(name, start, end, headers)
+	ia.Metrics.ObserveAPICall("GetProductsByName", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetProductsByCaterory GetProductsByCaterory
+func (ia *API) GetProductsByCaterory(catID int64, start int64, end int64, headers *api.Headers) *[]sdbi.Product {
+	st := time.Now()
+	rtn := ia.API.GetProductsByCaterory(catID, start, end, headers)
+	ia.Metrics.ObserveAPICall("GetProductsByCaterory", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetProductList GetProductList
+func (ia *API) GetProductList(start int64, end int64, headers *api.Headers) *[]sdbi.Product {
+	st := time.Now()
+	rtn := ia.API.GetProductList(start, end, headers)
+	ia.Metrics.ObserveAPICall("GetProductList", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//DeleteProduct DeleteProduct
+func (ia *API) DeleteProduct(id int64, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.DeleteProduct(id, headers)
+	ia.Metrics.ObserveAPICall("DeleteProduct", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//AddProductCategory AddProductCategory
+func (ia *API) AddProductCategory(pc *sdbi.ProductCategory, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.AddProductCategory(pc, headers)
+	ia.Metrics.ObserveAPICall("AddProductCategory", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//DeleteProductCategory DeleteProductCategory
+func (ia *API) DeleteProductCategory(pc *sdbi.ProductCategory, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.DeleteProductCategory(pc, headers)
+	ia.Metrics.ObserveAPICall("DeleteProductCategory", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//AddRegion AddRegion
+func (ia *API) AddRegion(r *sdbi.Region, headers *api.Headers) *api.ResponseID {
+	st := time.Now()
+	rtn := ia.API.AddRegion(r, headers)
+	ia.Metrics.ObserveAPICall("AddRegion", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//UpdateRegion UpdateRegion
+func (ia *API) UpdateRegion(r *sdbi.Region, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.UpdateRegion(r, headers)
+	ia.Metrics.ObserveAPICall("UpdateRegion", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetRegion GetRegion
+func (ia *API) GetRegion(id int64, headers *api.Headers) *sdbi.Region {
+	st := time.Now()
+	rtn := ia.API.GetRegion(id, headers)
+	ia.Metrics.ObserveAPICall("GetRegion", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetRegionList GetRegionList
+func (ia *API) GetRegionList(headers *api.Headers) *[]sdbi.Region {
+	st := time.Now()
+	rtn := ia.API.GetRegionList(headers)
+	ia.Metrics.ObserveAPICall("GetRegionList", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//DeleteRegion DeleteRegion
+func (ia *API) DeleteRegion(id int64, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.DeleteRegion(id, headers)
+	ia.Metrics.ObserveAPICall("DeleteRegion", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//AddShipment AddShipment
+func (ia *API) AddShipment(s *sdbi.Shipment, headers *api.Headers) *api.ResponseID {
+	st := time.Now()
+	rtn := ia.API.AddShipment(s, headers)
+	ia.Metrics.ObserveAPICall("AddShipment", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//UpdateShipment UpdateShipment
+func (ia *API) UpdateShipment(s *sdbi.Shipment, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.UpdateShipment(s, headers)
+	ia.Metrics.ObserveAPICall("UpdateShipment", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetShipment GetShipment
+func (ia *API) GetShipment(id int64, headers *api.Headers) *sdbi.Shipment {
+	st := time.Now()
+	rtn := ia.API.GetShipment(id, headers)
+	ia.Metrics.ObserveAPICall("GetShipment", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetShipmentList GetShipmentList
+func (ia *API) GetShipmentList(orderID int64, headers *api.Headers) *[]sdbi.Shipment {
+	st := time.Now()
+	rtn := ia.API.GetShipmentList(orderID, headers)
+	ia.Metrics.ObserveAPICall("GetShipmentList", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//DeleteShipment DeleteShipment
+func (ia *API) DeleteShipment(id int64, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.DeleteShipment(id, headers)
+	ia.Metrics.ObserveAPICall("DeleteShipment", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//AddShipmentBox AddShipmentBox
+func (ia *API) AddShipmentBox(sb *sdbi.ShipmentBox, headers *api.Headers) *api.ResponseID {
+	st := time.Now()
+	rtn := ia.API.AddShipmentBox(sb, headers)
+	ia.Metrics.ObserveAPICall("AddShipmentBox", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//UpdateShipmentBox UpdateShipmentBox
+func (ia *API) UpdateShipmentBox(sb *sdbi.ShipmentBox, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.UpdateShipmentBox(sb, headers)
+	ia.Metrics.ObserveAPICall("UpdateShipmentBox", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetShipmentBox GetShipmentBox
+func (ia *API) GetShipmentBox(id int64, headers *api.Headers) *sdbi.ShipmentBox {
+	st := time.Now()
+	rtn := ia.API.GetShipmentBox(id, headers)
+	ia.Metrics.ObserveAPICall("GetShipmentBox", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetShipmentBoxList GetShipmentBoxList
+func (ia *API) GetShipmentBoxList(shipmentID int64, headers *api.Headers) *[]sdbi.ShipmentBox {
+	st := time.Now()
+	rtn := ia.API.GetShipmentBoxList(shipmentID, headers)
+	ia.Metrics.ObserveAPICall("GetShipmentBoxList", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//DeleteShipmentBox DeleteShipmentBox
+func (ia *API) DeleteShipmentBox(id int64, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.DeleteShipmentBox(id, headers)
+	ia.Metrics.ObserveAPICall("DeleteShipmentBox", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//AddShipmentItem AddShipmentItem
+func (ia *API) AddShipmentItem(si *sdbi.ShipmentItem, headers *api.Headers) *api.ResponseID {
+	st := time.Now()
+	rtn := ia.API.AddShipmentItem(si, headers)
+	ia.Metrics.ObserveAPICall("AddShipmentItem", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//UpdateShipmentItem UpdateShipmentItem
+func (ia *API) UpdateShipmentItem(si *sdbi.ShipmentItem, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.UpdateShipmentItem(si, headers)
+	ia.Metrics.ObserveAPICall("UpdateShipmentItem", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetShipmentItem GetShipmentItem
+func (ia *API) GetShipmentItem(id int64, headers *api.Headers) *sdbi.ShipmentItem {
+	st := time.Now()
+	rtn := ia.API.GetShipmentItem(id, headers)
+	ia.Metrics.ObserveAPICall("GetShipmentItem", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetShipmentItemList GetShipmentItemList
+func (ia *API) GetShipmentItemList(shipmentID int64, headers *api.Headers) *[]sdbi.ShipmentItem {
+	st := time.Now()
+	rtn := ia.API.GetShipmentItemList(shipmentID, headers)
+	ia.Metrics.ObserveAPICall("GetShipmentItemList", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetShipmentItemListByBox GetShipmentItemListByBox
+func (ia *API) GetShipmentItemListByBox(boxNumber int64, shipmentID int64, headers *api.Headers) *[]sdbi.ShipmentItem {
+	st := time.Now()
+	rtn := ia.API.GetShipmentItemListByBox(boxNumber, shipmentID, headers)
+	ia.Metrics.ObserveAPICall("GetShipmentItemListByBox", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//DeleteShipmentItem DeleteShipmentItem
+func (ia *API) DeleteShipmentItem(id int64, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.DeleteShipmentItem(id, headers)
+	ia.Metrics.ObserveAPICall("DeleteShipmentItem", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//AddShippingCarrier AddShippingCarrier
+func (ia *API) AddShippingCarrier(c *sdbi.ShippingCarrier, headers *api.Headers) *api.ResponseID {
+	st := time.Now()
+	rtn := ia.API.AddShippingCarrier(c, headers)
+	ia.Metrics.ObserveAPICall("AddShippingCarrier", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//UpdateShippingCarrier UpdateShippingCarrier
+func (ia *API) UpdateShippingCarrier(c *sdbi.ShippingCarrier, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.UpdateShippingCarrier(c, headers)
+	ia.Metrics.ObserveAPICall("UpdateShippingCarrier", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetShippingCarrier GetShippingCarrier
+func (ia *API) GetShippingCarrier(id int64, headers *api.Headers) *sdbi.ShippingCarrier {
+	st := time.Now()
+	rtn := ia.API.GetShippingCarrier(id, headers)
+	ia.Metrics.ObserveAPICall("GetShippingCarrier", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetShippingCarrierList GetShippingCarrierList
+func (ia *API) GetShippingCarrierList(headers *api.Headers) *[]sdbi.ShippingCarrier {
+	st := time.Now()
+	rtn := ia.API.GetShippingCarrierList(headers)
+	ia.Metrics.ObserveAPICall("GetShippingCarrierList", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//DeleteShippingCarrier DeleteShippingCarrier
+func (ia *API) DeleteShippingCarrier(id int64, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.DeleteShippingCarrier(id, headers)
+	ia.Metrics.ObserveAPICall("DeleteShippingCarrier", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//AddShippingMethod AddShippingMethod
+func (ia *API) AddShippingMethod(s *sdbi.ShippingMethod, headers *api.Headers) *api.ResponseID {
+	st := time.Now()
+	rtn := ia.API.AddShippingMethod(s, headers)
+	ia.Metrics.ObserveAPICall("AddShippingMethod", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//UpdateShippingMethod UpdateShippingMethod
+func (ia *API) UpdateShippingMethod(s *sdbi.ShippingMethod, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.UpdateShippingMethod(s, headers)
+	ia.Metrics.ObserveAPICall("UpdateShippingMethod", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetShippingMethod GetShippingMethod
+func (ia *API) GetShippingMethod(id int64, headers *api.Headers) *sdbi.ShippingMethod {
+	st := time.Now()
+	rtn := ia.API.GetShippingMethod(id, headers)
+	ia.Metrics.ObserveAPICall("GetShippingMethod", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetShippingMethodList GetShippingMethodList
+func (ia *API) GetShippingMethodList(headers *api.Headers) *[]sdbi.ShippingMethod {
+	st := time.Now()
+	rtn := ia.API.GetShippingMethodList(headers)
+	ia.Metrics.ObserveAPICall("GetShippingMethodList", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//DeleteShippingMethod DeleteShippingMethod
+func (ia *API) DeleteShippingMethod(id int64, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.DeleteShippingMethod(id, headers)
+	ia.Metrics.ObserveAPICall("DeleteShippingMethod", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//AddStore AddStore
+func (ia *API) AddStore(s *sdbi.Store, headers *api.Headers) *api.ResponseID {
+	st := time.Now()
+	rtn := ia.API.AddStore(s, headers)
+	ia.Metrics.ObserveAPICall("AddStore", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//UpdateStore UpdateStore
+func (ia *API) UpdateStore(s *sdbi.Store, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.UpdateStore(s, headers)
+	ia.Metrics.ObserveAPICall("UpdateStore", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetStore GetStore
+func (ia *API) GetStore(sname string, localDomain string, headers *api.Headers) *sdbi.Store {
+	st := time.Now()
+	rtn := ia.API.GetStore(sname, localDomain, headers)
+	ia.Metrics.ObserveAPICall("GetStore", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//DeleteStore DeleteStore
+func (ia *API) DeleteStore(sname string, localDomain string, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.DeleteStore(sname, localDomain, headers)
+	ia.Metrics.ObserveAPICall("DeleteStore", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//AddStorePlugin AddStorePlugin
+func (ia *API) AddStorePlugin(sp *sdbi.StorePlugins, headers *api.Headers) *api.ResponseID {
+	st := time.Now()
+	rtn := ia.API.AddStorePlugin(sp, headers)
+	ia.Metrics.ObserveAPICall("AddStorePlugin", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//UpdateStorePlugin UpdateStorePlugin
+func (ia *API) UpdateStorePlugin(sp *sdbi.StorePlugins, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.UpdateStorePlugin(sp, headers)
+	ia.Metrics.ObserveAPICall("UpdateStorePlugin", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetStorePlugin GetStorePlugin
+func (ia *API) GetStorePlugin(id int64, headers *api.Headers) *sdbi.StorePlugins {
+	st := time.Now()
+	rtn := ia.API.GetStorePlugin(id, headers)
+	ia.Metrics.ObserveAPICall("GetStorePlugin", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetStorePluginList GetStorePluginList
+func (ia *API) GetStorePluginList(headers *api.Headers) *[]sdbi.StorePlugins {
+	st := time.Now()
+	rtn := ia.API.GetStorePluginList(headers)
+	ia.Metrics.ObserveAPICall("GetStorePluginList", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//DeleteStorePlugin DeleteStorePlugin
+func (ia *API) DeleteStorePlugin(id int64, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.DeleteStorePlugin(id, headers)
+	ia.Metrics.ObserveAPICall("DeleteStorePlugin", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//AddSubRegion AddSubRegion
+func (ia *API) AddSubRegion(s *sdbi.SubRegion, headers *api.Headers) *api.ResponseID {
+	st := time.Now()
+	rtn := ia.API.AddSubRegion(s, headers)
+	ia.Metrics.ObserveAPICall("AddSubRegion", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//UpdateSubRegion UpdateSubRegion
+func (ia *API) UpdateSubRegion(s *sdbi.SubRegion, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.UpdateSubRegion(s, headers)
+	ia.Metrics.ObserveAPICall("UpdateSubRegion", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetSubRegion GetSubRegion
+func (ia *API) GetSubRegion(id int64, headers *api.Headers) *sdbi.SubRegion {
+	st := time.Now()
+	rtn := ia.API.GetSubRegion(id, headers)
+	ia.Metrics.ObserveAPICall("GetSubRegion", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetSubRegionList GetSubRegionList
+func (ia *API) GetSubRegionList(regionID int64, headers *api.Headers) *[]sdbi.SubRegion {
+	st := time.Now()
+	rtn := ia.API.GetSubRegionList(regionID, headers)
+	ia.Metrics.ObserveAPICall("GetSubRegionList", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//DeleteSubRegion DeleteSubRegion
+func (ia *API) DeleteSubRegion(id int64, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.DeleteSubRegion(id, headers)
+	ia.Metrics.ObserveAPICall("DeleteSubRegion", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//AddCustomerUser AddCustomerUser
+func (ia *API) AddCustomerUser(u *api.User, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.AddCustomerUser(u, headers)
+	ia.Metrics.ObserveAPICall("AddCustomerUser", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//UpdateUser UpdateUser
+func (ia *API) UpdateUser(u *api.User, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.UpdateUser(u, headers)
+	ia.Metrics.ObserveAPICall("UpdateUser", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetUser GetUser
+func (ia *API) GetUser(u *api.User, headers *api.Headers) *api.UserResponse {
+	st := time.Now()
+	rtn := ia.API.GetUser(u, headers)
+	ia.Metrics.ObserveAPICall("GetUser", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetAdminUsers GetAdminUsers
+func (ia *API) GetAdminUsers(headers *api.Headers) *[]api.UserResponse {
+	st := time.Now()
+	rtn := ia.API.GetAdminUsers(headers)
+	ia.Metrics.ObserveAPICall("GetAdminUsers", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetCustomerUsers GetCustomerUsers
+func (ia *API) GetCustomerUsers(headers *api.Headers) *[]api.UserResponse {
+	st := time.Now()
+	rtn := ia.API.GetCustomerUsers(headers)
+	ia.Metrics.ObserveAPICall("GetCustomerUsers", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//AddZoneZip AddZoneZip
+func (ia *API) AddZoneZip(z *sdbi.ZoneZip, headers *api.Headers) *api.ResponseID {
+	st := time.Now()
+	rtn := ia.API.AddZoneZip(z, headers)
+	ia.Metrics.ObserveAPICall("AddZoneZip", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
+
+//GetZoneZipListByExclusion GetZoneZipListByExclusion
+func (ia *API) GetZoneZipListByExclusion(exID int64, headers *api.Headers) *[]sdbi.ZoneZip {
+	st := time.Now()
+	rtn := ia.API.GetZoneZipListByExclusion(exID, headers)
+	ia.Metrics.ObserveAPICall("GetZoneZipListByExclusion", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//GetZoneZipListByInclusion GetZoneZipListByInclusion
+func (ia *API) GetZoneZipListByInclusion(incID int64, headers *api.Headers) *[]sdbi.ZoneZip {
+	st := time.Now()
+	rtn := ia.API.GetZoneZipListByInclusion(incID, headers)
+	ia.Metrics.ObserveAPICall("GetZoneZipListByInclusion", rtn != nil, time.Since(st))
+	return rtn
+}
+
+//DeleteZoneZip DeleteZoneZip
+func (ia *API) DeleteZoneZip(id int64, incID int64, exID int64, headers *api.Headers) *api.Response {
+	st := time.Now()
+	rtn := ia.API.DeleteZoneZip(id, incID, exID, headers)
+	ia.Metrics.ObserveAPICall("DeleteZoneZip", rtn != nil && rtn.Success, time.Since(st))
+	return rtn
+}
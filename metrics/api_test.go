@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	mapi "github.com/Ulbora/Six910-ui/mockapi"
+	api "github.com/Ulbora/Six910API-Go"
+	sdbi "github.com/Ulbora/six910-database-interface"
+)
+
+func TestAPI_Calls(t *testing.T) {
+	var mt Six910Metrics
+	m := mt.GetNew()
+
+	var sapi mapi.MockAPI
+	var pr api.ResponseID
+	pr.Success = true
+	pr.ID = 5
+	sapi.MockAddProductResp = &pr
+	var dr api.Response
+	sapi.MockDeleteProductResp = &dr
+
+	var ia API
+	ia.API = sapi.GetNew()
+	ia.Metrics = m
+	a := ia.GetNew()
+
+	var hd api.Headers
+	var p sdbi.Product
+	res := a.AddProduct(&p, &hd)
+	a.DeleteProduct(5, &hd)
+	a.GetStore("teststore", "test.com", &hd)
+	if res.ID != 5 {
+		t.Fail()
+	}
+
+	r, _ := http.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, r)
+	body := w.Body.String()
+	fmt.Println(body)
+	var want = []string{
+		`six910_api_calls_total{method="AddProduct",success="true"} 1`,
+		`six910_api_calls_total{method="DeleteProduct",success="false"} 1`,
+		`six910_api_calls_total{method="GetStore",success="false"} 1`,
+		`six910_api_call_duration_seconds_count{method="AddProduct",success="true"} 1`,
+	}
+	for _, s := range want {
+		if !strings.Contains(body, s) {
+			fmt.Println("missing: ", s)
+			t.Fail()
+		}
+	}
+}
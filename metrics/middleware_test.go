@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestMiddleware(t *testing.T) {
+	var mt Six910Metrics
+	m := mt.GetNew()
+	router := mux.NewRouter()
+	router.HandleFunc("/admin/deleteProduct/{id}", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/admin/productListView", http.StatusFound)
+	}).Methods("GET")
+	router.Use(Middleware(m))
+
+	for _, id := range []string{"1", "2"} {
+		r, _ := http.NewRequest("GET", "/admin/deleteProduct/"+id, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+	}
+	r, _ := http.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, r)
+	body := w.Body.String()
+	fmt.Println(body)
+	if !strings.Contains(body, `six910_http_requests_total{route="/admin/deleteProduct/{id}",method="GET",code="302"} 2`) {
+		t.Fail()
+	}
+}
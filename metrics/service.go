@@ -0,0 +1,122 @@
+//Package metrics ...
+package metrics
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+	sizeBuckets     = []float64{1 << 10, 1 << 14, 1 << 17, 1 << 20, 1 << 23, 1 << 26, 1 << 29}
+)
+
+//Metrics Metrics
+type Metrics interface {
+	ObserveRequest(route string, method string, code int, d time.Duration)
+	ObserveAPICall(method string, success bool, d time.Duration)
+	AddContentHit(name string)
+	ObserveProductImport(notImported int, success bool)
+	ObserveBackup(direction string, size int, success bool)
+
+	ServeHTTP(w http.ResponseWriter, r *http.Request)
+}
+
+//Six910Metrics Six910Metrics
+type Six910Metrics struct {
+	families []*family
+
+	requests        *family
+	requestDuration *family
+	apiCalls        *family
+	apiDuration     *family
+	contentHits     *family
+	importRows      *family
+	imports         *family
+	backups         *family
+	backupSize      *family
+}
+
+//GetNew GetNew
+func (m *Six910Metrics) GetNew() Metrics {
+	m.requests = newFamily("six910_http_requests_total",
+		"HTTP requests by route template, method and status code.", counterType, nil, "route", "method", "code")
+	m.requestDuration = newFamily("six910_http_request_duration_seconds",
+		"HTTP request duration by route template and method.", histogramType, durationBuckets, "route", "method")
+	m.apiCalls = newFamily("six910_api_calls_total",
+		"Six910 backend API calls by method and success.", counterType, nil, "method", "success")
+	m.apiDuration = newFamily("six910_api_call_duration_seconds",
+		"Six910 backend API call duration by method and success.", histogramType, durationBuckets, "method", "success")
+	m.contentHits = newFamily("six910_content_hits_total",
+		"Content page hits counted by CmsService.", counterType, nil, "name")
+	m.importRows = newFamily("six910_product_rows_not_imported_total",
+		"Product rows in uploaded product files that could not be imported.", counterType, nil)
+	m.imports = newFamily("six910_product_imports_total",
+		"Product file imports by success.", counterType, nil, "success")
+	m.backups = newFamily("six910_backups_total",
+		"Backup uploads and downloads by direction and success.", counterType, nil, "direction", "success")
+	m.backupSize = newFamily("six910_backup_size_bytes",
+		"Size of uploaded and downloaded backups.", histogramType, sizeBuckets, "direction")
+	m.families = []*family{m.requests, m.requestDuration, m.apiCalls, m.apiDuration, m.contentHits,
+		m.importRows, m.imports, m.backups, m.backupSize}
+	return m
+}
+
+//ObserveRequest ObserveRequest
+func (m *Six910Metrics) ObserveRequest(route string, method string, code int, d time.Duration) {
+	m.requests.add(1, route, method, strconv.Itoa(code))
+	m.requestDuration.observe(d.Seconds(), route, method)
+}
+
+//ObserveAPICall ObserveAPICall
+func (m *Six910Metrics) ObserveAPICall(method string, success bool, d time.Duration) {
+	suc := strconv.FormatBool(success)
+	m.apiCalls.add(1, method, suc)
+	m.apiDuration.observe(d.Seconds(), method, suc)
+}
+
+//AddContentHit AddContentHit
+func (m *Six910Metrics) AddContentHit(name string) {
+	m.contentHits.add(1, name)
+}
+
+//ObserveProductImport ObserveProductImport
+func (m *Six910Metrics) ObserveProductImport(notImported int, success bool) {
+	m.imports.add(1, strconv.FormatBool(success))
+	m.importRows.add(float64(notImported))
+}
+
+//ObserveBackup ObserveBackup
+func (m *Six910Metrics) ObserveBackup(direction string, size int, success bool) {
+	m.backups.add(1, direction, strconv.FormatBool(success))
+	if success {
+		m.backupSize.observe(float64(size), direction)
+	}
+}
+
+//ServeHTTP ServeHTTP writes every metric in the Prometheus text format
+func (m *Six910Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	for _, f := range m.families {
+		f.write(w)
+	}
+}
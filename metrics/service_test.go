@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSix910Metrics_ServeHTTP(t *testing.T) {
+	var mt Six910Metrics
+	m := mt.GetNew()
+	m.ObserveRequest("/admin/editProductView/{id}", "GET", 200, 30*time.Millisecond)
+	m.ObserveRequest("/admin/editProductView/{id}", "GET", 200, 2*time.Second)
+	m.ObserveAPICall("GetProductByID", true, time.Millisecond)
+	m.ObserveAPICall("GetProductByID", false, time.Millisecond)
+	m.AddContentHit("about\"us")
+	m.ObserveProductImport(2, true)
+	m.ObserveBackup("download", 2048, true)
+
+	r, _ := http.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, r)
+	body := w.Body.String()
+	fmt.Println(body)
+	var want = []string{
+		"# TYPE six910_http_requests_total counter",
+		`six910_http_requests_total{route="/admin/editProductView/{id}",method="GET",code="200"} 2`,
+		`six910_http_request_duration_seconds_bucket{route="/admin/editProductView/{id}",method="GET",le="0.05"} 1`,
+		`six910_http_request_duration_seconds_bucket{route="/admin/editProductView/{id}",method="GET",le="+Inf"} 2`,
+		`six910_http_request_duration_seconds_count{route="/admin/editProductView/{id}",method="GET"} 2`,
+		`six910_api_calls_total{method="GetProductByID",success="true"} 1`,
+		`six910_api_calls_total{method="GetProductByID",success="false"} 1`,
+		`six910_content_hits_total{name="about\"us"} 1`,
+		`six910_product_rows_not_imported_total 2`,
+		`six910_backup_size_bytes_sum{direction="download"} 2048`,
+	}
+	for _, s := range want {
+		if !strings.Contains(body, s) {
+			fmt.Println("missing: ", s)
+			t.Fail()
+		}
+	}
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain") {
+		t.Fail()
+	}
+}
@@ -0,0 +1,100 @@
+package metrics
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	bks "github.com/Ulbora/Six910-ui/bkupsrv"
+	conts "github.com/Ulbora/Six910-ui/contsrv"
+	m "github.com/Ulbora/Six910-ui/managers"
+	api "github.com/Ulbora/Six910API-Go"
+)
+
+//Manager Manager decorates a managers.Manager and records product
+//imports and rows not imported; every other method goes straight to Manager
+type Manager struct {
+	m.Manager
+	Metrics Metrics
+}
+
+//GetNew GetNew
+func (mm *Manager) GetNew() m.Manager {
+	return mm
+}
+
+//UploadProductFile UploadProductFile
+func (mm *Manager) UploadProductFile(file []byte, hd *api.Headers) (success bool, productNotImported int) {
+	success, productNotImported = mm.Manager.UploadProductFile(file, hd)
+	mm.Metrics.ObserveProductImport(productNotImported, success)
+	return
+}
+
+//BackupService BackupService decorates a bkupsrv.BackupService and
+//records backup sizes
+type BackupService struct {
+	BackupService bks.BackupService
+	Metrics       Metrics
+}
+
+//GetNew GetNew
+func (b *BackupService) GetNew() bks.BackupService {
+	return b
+}
+
+//UploadBackups UploadBackups
+func (b *BackupService) UploadBackups(bk *[]byte) bool {
+	rtn := b.BackupService.UploadBackups(bk)
+	var size int
+	if bk != nil {
+		size = len(*bk)
+	}
+	b.Metrics.ObserveBackup("upload", size, rtn)
+	return rtn
+}
+
+//DownloadBackups DownloadBackups
+func (b *BackupService) DownloadBackups() (bool, *[]byte) {
+	suc, bk := b.BackupService.DownloadBackups()
+	var size int
+	if bk != nil {
+		size = len(*bk)
+	}
+	b.Metrics.ObserveBackup("download", size, suc)
+	return suc, bk
+}
+
+//ContentService ContentService decorates a contsrv.Service and counts
+//the content hits that CmsService records
+type ContentService struct {
+	conts.Service
+	Metrics Metrics
+}
+
+//GetNew GetNew
+func (c *ContentService) GetNew() conts.Service {
+	return c
+}
+
+//GetContent GetContent
+func (c *ContentService) GetContent(name string) (bool, *conts.Content) {
+	suc, ct := c.Service.GetContent(name)
+	if suc {
+		c.Metrics.AddContentHit(name)
+	}
+	return suc, ct
+}
@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	lg "github.com/Ulbora/Level_Logger"
+	conts "github.com/Ulbora/Six910-ui/contsrv"
+	m "github.com/Ulbora/Six910-ui/managers"
+	api "github.com/Ulbora/Six910API-Go"
+	ds "github.com/Ulbora/json-datastore"
+)
+
+type testManager struct {
+	m.Manager
+}
+
+func (t *testManager) UploadProductFile(file []byte, hd *api.Headers) (bool, int) {
+	return true, 1
+}
+
+type testBackupService struct {
+}
+
+func (t *testBackupService) UploadBackups(bk *[]byte) bool {
+	return true
+}
+
+func (t *testBackupService) DownloadBackups() (bool, *[]byte) {
+	var bk = []byte("some backup")
+	return true, &bk
+}
+
+func scrape(mts Metrics) string {
+	r, _ := http.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	mts.ServeHTTP(w, r)
+	return w.Body.String()
+}
+
+func TestManager_UploadProductFile(t *testing.T) {
+	var mt Six910Metrics
+	mts := mt.GetNew()
+	var mm Manager
+	mm.Manager = &testManager{}
+	mm.Metrics = mts
+	man := mm.GetNew()
+	var hd api.Headers
+	suc, notImp := man.UploadProductFile([]byte("name,sku\nbook,1\npen,2\ncup,3\n"), &hd)
+	body := scrape(mts)
+	fmt.Println(body)
+	if !suc || notImp != 1 {
+		t.Fail()
+	}
+	if !strings.Contains(body, `six910_product_rows_not_imported_total 1`) ||
+		!strings.Contains(body, `six910_product_imports_total{success="true"} 1`) {
+		t.Fail()
+	}
+}
+
+func TestBackupService(t *testing.T) {
+	var mt Six910Metrics
+	mts := mt.GetNew()
+	var mb BackupService
+	mb.BackupService = &testBackupService{}
+	mb.Metrics = mts
+	bs := mb.GetNew()
+	bs.DownloadBackups()
+	var bk = []byte("1234")
+	bs.UploadBackups(&bk)
+	body := scrape(mts)
+	if !strings.Contains(body, `six910_backup_size_bytes_sum{direction="download"} 11`) ||
+		!strings.Contains(body, `six910_backup_size_bytes_sum{direction="upload"} 4`) {
+		t.Fail()
+	}
+}
+
+func TestContentService_GetContent(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "six910metrics")
+	defer os.RemoveAll(dir)
+	var l lg.Logger
+	var cds ds.DataStore
+	cds.Path = dir
+	var ci conts.CmsService
+	ci.Store = cds.GetNew()
+	ci.Log = &l
+	var mt Six910Metrics
+	mts := mt.GetNew()
+	var mc ContentService
+	mc.Service = ci.GetNew()
+	mc.Metrics = mts
+	cs := mc.GetNew()
+	var ct conts.Content
+	ct.Name = "about"
+	ct.Text = "about us"
+	cs.AddContent(&ct)
+	cs.GetContent("about")
+	cs.GetContent("about")
+	cs.GetContent("missing")
+	body := scrape(mts)
+	if !strings.Contains(body, `six910_content_hits_total{name="about"} 2`) ||
+		strings.Contains(body, `name="missing"`) || ci.HitTotal != 2 {
+		t.Fail()
+	}
+}
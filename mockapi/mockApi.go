@@ -30,6 +30,8 @@ type MockAPI struct {
 	MockAddCustomerUserRes *api.Response
 	MockUpdateUserResp     *api.Response
 
+	MockAdminUsers *[]api.UserResponse
+
 	MockCart        *sdbi.Cart
 	MockAddCartResp *api.ResponseID
 
@@ -52,8 +54,11 @@ type MockAPI struct {
 	MockAddProductResp    *api.ResponseID
 	MockUpdateProductResp *api.Response
 	MockProductList       *[]sdbi.Product
+	MockProductNameList   *[]sdbi.Product
 	MockDeleteProductResp *api.Response
 
+	MockProductCategoryList *[]sdbi.Product
+
 	MockAddOrderResp    *api.ResponseID
 	MockUpdateOrderResp *api.Response
 	MockOrder           *sdbi.Order
@@ -89,6 +94,8 @@ type MockAPI struct {
 
 	MockAddProductCategoryResp *api.Response
 
+	MockDeleteProductCategoryResp *api.Response
+
 	MockAddShipmentResp    *api.ResponseID
 	MockUpdateShipmentResp *api.Response
 	MockShipment           *sdbi.Shipment
@@ -124,6 +131,8 @@ type MockAPI struct {
 	MockStorePluginList       *[]sdbi.StorePlugins
 	MockDeleteStorePluginResp *api.Response
 
+	MockStore *sdbi.Store
+
 	MockAddShippingCarrierResp    *api.ResponseID
 	MockUpdateShippingCarrierResp *api.Response
 	MockShippingCarrier           *sdbi.ShippingCarrier
@@ -662,12 +671,12 @@ func (a *MockAPI) GetProductBySku(sku string, did int64, headers *api.Headers) *
 
 //GetProductsByName GetProductsByName
 func (a *MockAPI) GetProductsByName(name string, start int64, end int64, headers *api.Headers) *[]sdbi.Product {
-	return nil
+	return a.MockProductNameList
 }
 
 //GetProductsByCaterory GetProductsByCaterory
 func (a *MockAPI) GetProductsByCaterory(catID int64, start int64, end int64, headers *api.Headers) *[]sdbi.Product {
-	return nil
+	return a.MockProductCategoryList
 }
 
 //GetProductList GetProductList
@@ -689,7 +698,7 @@ func (a *MockAPI) AddProductCategory(pc *sdbi.ProductCategory, headers *api.Head
 
 //DeleteProductCategory DeleteProductCategory
 func (a *MockAPI) DeleteProductCategory(pc *sdbi.ProductCategory, headers *api.Headers) *api.Response {
-	return nil
+	return a.MockDeleteProductCategoryResp
 }
 
 //region
@@ -873,7 +882,7 @@ func (a *MockAPI) UpdateStore(s *sdbi.Store, headers *api.Headers) *api.Response
 
 //GetStore GetStore
 func (a *MockAPI) GetStore(sname string, localDomain string, headers *api.Headers) *sdbi.Store {
-	return nil
+	return a.MockStore
 }
 
 //DeleteStore DeleteStore
@@ -954,7 +963,7 @@ func (a *MockAPI) GetUser(u *api.User, headers *api.Headers) *api.UserResponse {
 
 //GetAdminUsers GetAdminUsers
 func (a *MockAPI) GetAdminUsers(headers *api.Headers) *[]api.UserResponse {
-	return nil
+	return a.MockAdminUsers
 }
 
 //GetCustomerUsers GetCustomerUsers
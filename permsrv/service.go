@@ -0,0 +1,173 @@
+//Package permsrv ...
+package permsrv
+
+import (
+	"sort"
+	"strings"
+
+	lg "github.com/Ulbora/Level_Logger"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+//Areas are the admin route groups a permission set can reach
+const (
+	Products  = "products"
+	Orders    = "orders"
+	Shipments = "shipments"
+	Customers = "customers"
+	Payments  = "payments"
+	Plugins   = "plugins"
+	Shipping  = "shipping"
+	Regions   = "regions"
+	Users     = "users"
+)
+
+//Access Access
+type Access int
+
+//Access levels; Write includes Read
+const (
+	None Access = iota
+	Read
+	Write
+)
+
+//FullSet FullSet can do everything; super admins always have it
+const FullSet = "full"
+
+//Set Set is the access a permission set gives to each area. Missing
+//areas are None.
+type Set map[string]Access
+
+var allAreas = []string{Products, Orders, Shipments, Customers, Payments, Plugins, Shipping, Regions, Users}
+
+//sets are the built in permission sets. readonly leaves out Users since
+//that area holds other admins, their sessions and the audit log.
+var sets = map[string]Set{
+	FullSet:       allAccess(Write),
+	"catalog":     {Products: Write},
+	"orders":      {Orders: Write, Customers: Write, Shipments: Read, Products: Read},
+	"fulfillment": {Shipments: Write, Orders: Read, Products: Read},
+	"settings":    {Payments: Write, Plugins: Write, Shipping: Write, Regions: Write},
+	"readonly":    allAccess(Read, Users),
+}
+
+func allAccess(a Access, except ...string) Set {
+	var rtn = Set{}
+	for _, ar := range allAreas {
+		rtn[ar] = a
+	}
+	for _, ar := range except {
+		delete(rtn, ar)
+	}
+	return rtn
+}
+
+//SetNames SetNames lists the built in permission sets
+func SetNames() []string {
+	var rtn []string
+	for n := range sets {
+		rtn = append(rtn, n)
+	}
+	sort.Strings(rtn)
+	return rtn
+}
+
+//ValidSet ValidSet
+func ValidSet(name string) bool {
+	_, ok := sets[name]
+	return ok
+}
+
+//Service Service
+type Service interface {
+	Allowed(username string, area string, write bool) bool
+	SetsFor(username string) []string
+}
+
+//Six910PermissionService Six910PermissionService gives each admin in
+//Users the union of their sets. Admins not in Users get Default and
+//SuperAdmins get the full set.
+type Six910PermissionService struct {
+	Users       map[string][]string
+	Default     []string
+	SuperAdmins []string
+	Log         *lg.Logger
+}
+
+//GetNew GetNew
+func (s *Six910PermissionService) GetNew() Service {
+	return s
+}
+
+//Allowed Allowed
+func (s *Six910PermissionService) Allowed(username string, area string, write bool) bool {
+	var need = Read
+	if write {
+		need = Write
+	}
+	for _, sn := range s.SetsFor(username) {
+		if sets[sn][area] >= need {
+			return true
+		}
+	}
+	return false
+}
+
+//SetsFor SetsFor
+func (s *Six910PermissionService) SetsFor(username string) []string {
+	for _, sa := range s.SuperAdmins {
+		if username != "" && sa == username {
+			return []string{FullSet}
+		}
+	}
+	if us, ok := s.Users[username]; ok && username != "" {
+		return us
+	}
+	return s.Default
+}
+
+//ParseUsers ParseUsers reads "user:set,set;user2:set" into a map and
+//returns the names that are not built in sets
+func ParseUsers(val string) (map[string][]string, []string) {
+	var rtn = make(map[string][]string)
+	var bad []string
+	for _, ent := range strings.Split(val, ";") {
+		ent = strings.TrimSpace(ent)
+		if ent == "" {
+			continue
+		}
+		i := strings.LastIndex(ent, ":")
+		if i < 1 {
+			bad = append(bad, ent)
+			continue
+		}
+		user := strings.TrimSpace(ent[:i])
+		for _, sn := range strings.Split(ent[i+1:], ",") {
+			sn = strings.TrimSpace(sn)
+			if !ValidSet(sn) {
+				bad = append(bad, sn)
+				continue
+			}
+			rtn[user] = append(rtn[user], sn)
+		}
+	}
+	return rtn, bad
+}
@@ -0,0 +1,59 @@
+package permsrv
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSix910PermissionService_Allowed(t *testing.T) {
+	var ps Six910PermissionService
+	ps.Users = map[string][]string{"packer": {"fulfillment"}, "jane": {"catalog", "orders"}, "viewer": {"readonly"}}
+	ps.Default = []string{FullSet}
+	ps.SuperAdmins = []string{"packer2"}
+	s := ps.GetNew()
+	var tests = []struct {
+		user  string
+		area  string
+		write bool
+		want  bool
+	}{
+		{"packer", Shipments, true, true},
+		{"packer", Orders, false, true},
+		{"packer", Orders, true, false},
+		{"packer", Payments, false, false},
+		{"jane", Products, true, true},
+		{"jane", Customers, true, true},
+		{"jane", Plugins, false, false},
+		{"viewer", Regions, false, true},
+		{"viewer", Regions, true, false},
+		{"viewer", Users, false, false},
+		{"admin", Payments, true, true},
+		{"packer2", Users, true, true},
+		{"admin", "unknown", false, false},
+	}
+	for _, tt := range tests {
+		if s.Allowed(tt.user, tt.area, tt.write) != tt.want {
+			fmt.Println("wrong for: ", tt)
+			t.Fail()
+		}
+	}
+	ps.Default = nil
+	if s.Allowed("", Products, false) {
+		t.Fail()
+	}
+}
+
+func TestParseUsers(t *testing.T) {
+	us, bad := ParseUsers(" packer : fulfillment ; jane:catalog,orders;; ops@test.com:readonly")
+	fmt.Println("users: ", us, bad)
+	if len(bad) != 0 || len(us) != 3 || us["jane"][1] != "orders" || us["ops@test.com"][0] != "readonly" || us["packer"][0] != "fulfillment" {
+		t.Fail()
+	}
+	_, bad = ParseUsers("bob:cashier;nocolon")
+	if len(bad) != 2 || bad[0] != "cashier" || bad[1] != "nocolon" {
+		t.Fail()
+	}
+	if len(SetNames()) != 6 || !ValidSet("settings") || ValidSet("admin") {
+		t.Fail()
+	}
+}
@@ -0,0 +1,250 @@
+//Package resetsrv ...
+package resetsrv
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	lg "github.com/Ulbora/Level_Logger"
+	ds "github.com/Ulbora/json-datastore"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+const (
+	//ScopeAdmin ScopeAdmin
+	ScopeAdmin = "admin"
+
+	//ScopeCustomer ScopeCustomer
+	ScopeCustomer = "customer"
+
+	//DefaultTimeout DefaultTimeout
+	DefaultTimeout = time.Hour
+
+	//resendInterval stops the forgot password form from sending a flood
+	//of mail to one user
+	resendInterval = time.Minute
+)
+
+var (
+	//ErrInvalidToken ErrInvalidToken is returned for a token that is
+	//malformed or not signed with Key
+	ErrInvalidToken = errors.New("invalid password reset token")
+
+	//ErrExpiredToken ErrExpiredToken
+	ErrExpiredToken = errors.New("password reset token expired")
+
+	//ErrUsedToken ErrUsedToken is returned once a token has been used, a
+	//newer one has been sent or the password has changed
+	ErrUsedToken = errors.New("password reset token already used")
+
+	//ErrTooSoon ErrTooSoon
+	ErrTooSoon = errors.New("password reset requested too soon")
+)
+
+//Claims Claims is who a token resets the password for
+type Claims struct {
+	Scope    string
+	Username string
+	Expires  time.Time
+}
+
+//Record Record is the one open reset for a user. Only a hash of the
+//token ID is kept.
+type Record struct {
+	Scope     string    `json:"scope"`
+	Username  string    `json:"username"`
+	TokenHash string    `json:"tokenHash"`
+	Issued    time.Time `json:"issued"`
+	Expires   time.Time `json:"expires"`
+}
+
+//tokenPayload is the signed part of a token
+type tokenPayload struct {
+	Scope    string `json:"s"`
+	Username string `json:"u"`
+	ID       string `json:"id"`
+	Expires  int64  `json:"exp"`
+}
+
+//Service Service
+type Service interface {
+	NewToken(scope string, username string) (string, error)
+	Check(token string) (*Claims, error)
+	Redeem(token string) (*Claims, error)
+	PasswordChanged(scope string, username string) bool
+}
+
+//Six910ResetService Six910ResetService signs tokens with Key. A new
+//token replaces any open one for the same user.
+type Six910ResetService struct {
+	Store   ds.JSONDatastore
+	Key     []byte
+	Timeout time.Duration
+	Log     *lg.Logger
+	mu      sync.Mutex
+}
+
+//GetNew GetNew
+func (s *Six910ResetService) GetNew() Service {
+	if s.Timeout == 0 {
+		s.Timeout = DefaultTimeout
+	}
+	return s
+}
+
+//NewToken NewToken
+func (s *Six910ResetService) NewToken(scope string, username string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if rec := s.read(scope, username); rec != nil && now.Sub(rec.Issued) < resendInterval {
+		return "", ErrTooSoon
+	}
+	id := newTokenID()
+	var rec Record
+	rec.Scope = scope
+	rec.Username = username
+	rec.TokenHash = hashID(id)
+	rec.Issued = now
+	rec.Expires = now.Add(s.Timeout)
+	if !s.Store.Save(storeKey(scope, username), rec) {
+		return "", errors.New("password reset not saved for: " + username)
+	}
+	var tp tokenPayload
+	tp.Scope = scope
+	tp.Username = username
+	tp.ID = id
+	tp.Expires = rec.Expires.Unix()
+	pb, err := json.Marshal(tp)
+	if err != nil {
+		return "", err
+	}
+	return b64.EncodeToString(pb) + "." + b64.EncodeToString(s.sign(pb)), nil
+}
+
+//Check Check validates a token without using it up
+func (s *Six910ResetService) Check(token string) (*Claims, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.check(token)
+}
+
+//Redeem Redeem validates a token and uses it up, so it must be called
+//before the password is changed
+func (s *Six910ResetService) Redeem(token string) (*Claims, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, err := s.check(token)
+	if err != nil {
+		return nil, err
+	}
+	if !s.Store.Delete(storeKey(c.Scope, c.Username)) {
+		return nil, ErrUsedToken
+	}
+	s.Log.Info("password reset token used for: ", c.Scope, " ", c.Username)
+	return c, nil
+}
+
+//PasswordChanged PasswordChanged drops the open reset token for a user
+//whose password was changed some other way
+func (s *Six910ResetService) PasswordChanged(scope string, username string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.read(scope, username) == nil {
+		return false
+	}
+	return s.Store.Delete(storeKey(scope, username))
+}
+
+func (s *Six910ResetService) check(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return nil, ErrInvalidToken
+	}
+	pb, perr := b64.DecodeString(parts[0])
+	sig, serr := b64.DecodeString(parts[1])
+	if perr != nil || serr != nil || !hmac.Equal(sig, s.sign(pb)) {
+		return nil, ErrInvalidToken
+	}
+	var tp tokenPayload
+	if err := json.Unmarshal(pb, &tp); err != nil {
+		return nil, ErrInvalidToken
+	}
+	var c Claims
+	c.Scope = tp.Scope
+	c.Username = tp.Username
+	c.Expires = time.Unix(tp.Expires, 0)
+	if time.Now().After(c.Expires) {
+		return nil, ErrExpiredToken
+	}
+	rec := s.read(c.Scope, c.Username)
+	if rec == nil || subtle.ConstantTimeCompare([]byte(rec.TokenHash), []byte(hashID(tp.ID))) != 1 {
+		return nil, ErrUsedToken
+	}
+	return &c, nil
+}
+
+func (s *Six910ResetService) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, s.Key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+func (s *Six910ResetService) read(scope string, username string) *Record {
+	b := s.Store.Read(storeKey(scope, username))
+	if b == nil || len(*b) == 0 {
+		return nil
+	}
+	var rec Record
+	if err := json.Unmarshal(*b, &rec); err != nil {
+		s.Log.Error("bad password reset record for: ", username, err)
+		return nil
+	}
+	return &rec
+}
+
+var b64 = base64.RawURLEncoding
+
+//storeKey keeps any username safe to use as a file name
+func storeKey(scope string, username string) string {
+	return hex.EncodeToString([]byte(scope + ":" + username))
+}
+
+func newTokenID() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic("resetsrv: cannot read random bytes: " + err.Error())
+	}
+	return hex.EncodeToString(b)
+}
+
+func hashID(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:])
+}
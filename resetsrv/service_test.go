@@ -0,0 +1,119 @@
+package resetsrv
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	lg "github.com/Ulbora/Level_Logger"
+	ds "github.com/Ulbora/json-datastore"
+)
+
+func newTestService(t *testing.T) (*Six910ResetService, string) {
+	dir, err := ioutil.TempDir("", "six910reset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var l lg.Logger
+	var d ds.DataStore
+	d.Path = dir
+	var rs Six910ResetService
+	rs.Store = d.GetNew()
+	rs.Key = []byte("testkey")
+	rs.Log = &l
+	rs.GetNew()
+	return &rs, dir
+}
+
+func TestSix910ResetService_Redeem(t *testing.T) {
+	rs, dir := newTestService(t)
+	defer os.RemoveAll(dir)
+	s := rs.GetNew()
+	tkn, err := s.NewToken(ScopeAdmin, "admin|one")
+	fmt.Println("token: ", tkn, err)
+	if err != nil || strings.ContainsAny(tkn, "+/=") {
+		t.FailNow()
+	}
+	c, err := s.Check(tkn)
+	if err != nil || c.Scope != ScopeAdmin || c.Username != "admin|one" || c.Expires.Sub(time.Now()) > DefaultTimeout {
+		t.Fail()
+	}
+	if _, err := s.Check(tkn); err != nil {
+		t.Fail()
+	}
+	c2, err := s.Redeem(tkn)
+	if err != nil || c2.Username != "admin|one" {
+		t.Fail()
+	}
+	if _, err := s.Redeem(tkn); err != ErrUsedToken {
+		t.Fail()
+	}
+}
+
+func TestSix910ResetService_Invalid(t *testing.T) {
+	rs, dir := newTestService(t)
+	defer os.RemoveAll(dir)
+	tkn, _ := rs.NewToken(ScopeCustomer, "bob@test.com")
+	parts := strings.Split(tkn, ".")
+	forged := b64.EncodeToString([]byte(`{"s":"admin","u":"admin","id":"x","exp":9999999999}`)) + "." + parts[1]
+	for _, bad := range []string{"", "abc", tkn + "x", forged, parts[0] + ".!!"} {
+		if _, err := rs.Check(bad); err != ErrInvalidToken {
+			fmt.Println("accepted: ", bad, err)
+			t.Fail()
+		}
+	}
+	rs.Key = []byte("otherkey")
+	if _, err := rs.Check(tkn); err != ErrInvalidToken {
+		t.Fail()
+	}
+}
+
+func TestSix910ResetService_Expired(t *testing.T) {
+	rs, dir := newTestService(t)
+	defer os.RemoveAll(dir)
+	rs.Timeout = -time.Second
+	tkn, _ := rs.NewToken(ScopeCustomer, "bob@test.com")
+	if _, err := rs.Redeem(tkn); err != ErrExpiredToken {
+		t.Fail()
+	}
+}
+
+func TestSix910ResetService_PasswordChanged(t *testing.T) {
+	rs, dir := newTestService(t)
+	defer os.RemoveAll(dir)
+	tkn, _ := rs.NewToken(ScopeCustomer, "bob@test.com")
+	if rs.PasswordChanged(ScopeAdmin, "bob@test.com") {
+		t.Fail()
+	}
+	if !rs.PasswordChanged(ScopeCustomer, "bob@test.com") {
+		t.Fail()
+	}
+	if _, err := rs.Check(tkn); err != ErrUsedToken {
+		t.Fail()
+	}
+}
+
+func TestSix910ResetService_NewTokenReplaces(t *testing.T) {
+	rs, dir := newTestService(t)
+	defer os.RemoveAll(dir)
+	tkn, _ := rs.NewToken(ScopeAdmin, "admin")
+	if _, err := rs.NewToken(ScopeAdmin, "admin"); err != ErrTooSoon {
+		t.Fail()
+	}
+	rec := rs.read(ScopeAdmin, "admin")
+	rec.Issued = rec.Issued.Add(-2 * resendInterval)
+	rs.Store.Save(storeKey(ScopeAdmin, "admin"), rec)
+	tkn2, err := rs.NewToken(ScopeAdmin, "admin")
+	if err != nil {
+		t.FailNow()
+	}
+	if _, err := rs.Check(tkn); err != ErrUsedToken {
+		t.Fail()
+	}
+	if _, err := rs.Check(tkn2); err != nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,219 @@
+package sessrv
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	lg "github.com/Ulbora/Level_Logger"
+	ds "github.com/Ulbora/json-datastore"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+//touchInterval limits how often a session file is rewritten just to
+//move LastUsed
+const touchInterval = time.Minute
+
+//FileStore FileStore keeps one json file per session under Path so admins
+//stay logged in across restarts. The backend password and OAuth2 tokens
+//are sealed with Key, so a session file alone does not give them away;
+//changing Key logs every admin out. Path should only be readable by the server.
+type FileStore struct {
+	Expiry
+	Path  string
+	Key   []byte
+	Log   *lg.Logger
+	store ds.JSONDatastore
+	gcm   cipher.AEAD
+	mu    sync.Mutex
+}
+
+//fileSession is a session as written to disk, with the login in Sealed
+type fileSession struct {
+	AdminSession
+	Sealed string `json:"sealed"`
+}
+
+//sessionLogin is the part of a session that is sealed
+type sessionLogin struct {
+	Password     string `json:"password"`
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+}
+
+//GetNew GetNew
+func (f *FileStore) GetNew() Store {
+	var d ds.DataStore
+	d.Path = f.Path
+	f.store = d.GetNew()
+	key := sha256.Sum256([]byte("six910 admin session credential:" + string(f.Key)))
+	blk, err := aes.NewCipher(key[:])
+	if err == nil {
+		f.gcm, err = cipher.NewGCM(blk)
+	}
+	if err != nil {
+		f.Log.Error("admin session key not usable: ", err)
+	}
+	return f
+}
+
+//Create Create stores a copy of s under a new ID and returns it
+func (f *FileStore) Create(s *AdminSession) *AdminSession {
+	ns := newSession(s)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.save(ns) {
+		f.Log.Error("admin session not saved for: ", ns.Username)
+	}
+	return ns
+}
+
+//Get Get returns the session for id and marks it used
+func (f *FileStore) Get(id string) *AdminSession {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s := f.read(id)
+	if s == nil {
+		return nil
+	}
+	now := time.Now()
+	if f.expired(s, now) {
+		f.store.Delete(id)
+		return nil
+	}
+	if now.Sub(s.LastUsed) > touchInterval {
+		s.LastUsed = now
+		f.save(s)
+	}
+	return s
+}
+
+//Update Update replaces an existing session
+func (f *FileStore) Update(s *AdminSession) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if s.ID == "" || f.read(s.ID) == nil {
+		return false
+	}
+	return f.save(s)
+}
+
+//Delete Delete
+func (f *FileStore) Delete(id string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if id == "" || f.read(id) == nil {
+		return false
+	}
+	return f.store.Delete(id)
+}
+
+//List List returns the active sessions, newest first, and drops expired ones
+func (f *FileStore) List() *[]AdminSession {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var rtn = []AdminSession{}
+	now := time.Now()
+	for _, b := range *f.store.ReadAll() {
+		s, err := f.open(b)
+		if err != nil || s.ID == "" {
+			continue
+		}
+		if f.expired(s, now) {
+			f.store.Delete(s.ID)
+		} else {
+			rtn = append(rtn, *s)
+		}
+	}
+	sortSessions(rtn)
+	return &rtn
+}
+
+func (f *FileStore) read(id string) *AdminSession {
+	b := f.store.Read(id)
+	if b == nil || len(*b) == 0 {
+		return nil
+	}
+	s, err := f.open(*b)
+	if err != nil {
+		f.Log.Error("bad admin session file: ", err)
+		return nil
+	}
+	return s
+}
+
+//save writes s with its login sealed to the session ID
+func (f *FileStore) save(s *AdminSession) bool {
+	if f.gcm == nil {
+		return false
+	}
+	pt, err := json.Marshal(sessionLogin{s.Password, s.AccessToken, s.RefreshToken})
+	if err != nil {
+		return false
+	}
+	nonce := make([]byte, f.gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return false
+	}
+	var fs fileSession
+	fs.AdminSession = *s
+	fs.Password = ""
+	fs.AccessToken = ""
+	fs.RefreshToken = ""
+	fs.Sealed = base64.StdEncoding.EncodeToString(f.gcm.Seal(nonce, nonce, pt, []byte(s.ID)))
+	return f.store.Save(s.ID, &fs)
+}
+
+//open reads a session file; files that were not sealed with Key are refused
+func (f *FileStore) open(b []byte) (*AdminSession, error) {
+	if f.gcm == nil {
+		return nil, errors.New("no admin session key")
+	}
+	var fs fileSession
+	if err := json.Unmarshal(b, &fs); err != nil {
+		return nil, err
+	}
+	sb, err := base64.StdEncoding.DecodeString(fs.Sealed)
+	if err != nil {
+		return nil, err
+	}
+	if len(sb) < f.gcm.NonceSize() {
+		return nil, errors.New("admin session not sealed")
+	}
+	pt, err := f.gcm.Open(nil, sb[:f.gcm.NonceSize()], sb[f.gcm.NonceSize():], []byte(fs.ID))
+	if err != nil {
+		return nil, err
+	}
+	var lgn sessionLogin
+	if err := json.Unmarshal(pt, &lgn); err != nil {
+		return nil, err
+	}
+	s := fs.AdminSession
+	s.Password = lgn.Password
+	s.AccessToken = lgn.AccessToken
+	s.RefreshToken = lgn.RefreshToken
+	return &s, nil
+}
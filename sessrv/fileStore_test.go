@@ -0,0 +1,117 @@
+package sessrv
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	lg "github.com/Ulbora/Level_Logger"
+)
+
+func TestFileStore_CreateGetReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "six910sess")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var l lg.Logger
+	var fs FileStore
+	fs.Path = dir
+	fs.Log = &l
+	s := fs.GetNew()
+	var as AdminSession
+	as.Username = "admin"
+	as.Password = "admin"
+	ns := s.Create(&as)
+	s.Create(&as)
+
+	var fs2 FileStore
+	fs2.Path = dir
+	fs2.Log = &l
+	s2 := fs2.GetNew()
+	gs := s2.Get(ns.ID)
+	fmt.Println("gs: ", gs)
+	if gs == nil || gs.Username != "admin" || len(*s2.List()) != 2 {
+		t.Fail()
+	}
+	gs.Password = "newpw"
+	if !s2.Update(gs) || s2.Get(ns.ID).Password != "newpw" {
+		t.Fail()
+	}
+	if !s2.Delete(ns.ID) || s2.Get(ns.ID) != nil || len(*s2.List()) != 1 {
+		t.Fail()
+	}
+	var bad AdminSession
+	bad.ID = "notanid"
+	if s2.Update(&bad) || s2.Delete("notanid") {
+		t.Fail()
+	}
+}
+
+func TestFileStore_Sealed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "six910sess")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var l lg.Logger
+	var fs FileStore
+	fs.Path = dir
+	fs.Key = []byte("sessionkey")
+	fs.Log = &l
+	s := fs.GetNew()
+	var as AdminSession
+	as.Username = "admin"
+	as.Password = "secretpw"
+	as.RefreshToken = "refresh123"
+	ns := s.Create(&as)
+	b, err := ioutil.ReadFile(filepath.Join(dir, ns.ID+".json"))
+	fmt.Println("file: ", string(b))
+	if err != nil || strings.Contains(string(b), "secretpw") || strings.Contains(string(b), "refresh123") {
+		t.Fail()
+	}
+	gs := s.Get(ns.ID)
+	if gs == nil || gs.Password != "secretpw" || gs.RefreshToken != "refresh123" {
+		t.Fail()
+	}
+
+	var fs2 FileStore
+	fs2.Path = dir
+	fs2.Key = []byte("otherkey")
+	fs2.Log = &l
+	s2 := fs2.GetNew()
+	if s2.Get(ns.ID) != nil || len(*s2.List()) != 0 {
+		t.Fail()
+	}
+}
+
+func TestFileStore_Expiry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "six910sess")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var l lg.Logger
+	var fs FileStore
+	fs.Path = dir
+	fs.Log = &l
+	fs.IdleTimeout = time.Minute
+	s := fs.GetNew()
+	var as AdminSession
+	as.Username = "admin"
+	ns := s.Create(&as)
+	live := s.Create(&as)
+	ns.LastUsed = time.Now().Add(-2 * time.Minute)
+	s.Update(ns)
+	if s.Get(ns.ID) != nil || s.Get(live.ID) == nil {
+		t.Fail()
+	}
+	fls, _ := ioutil.ReadDir(dir)
+	if len(fls) != 1 {
+		t.Fail()
+	}
+}
@@ -0,0 +1,112 @@
+package sessrv
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+//MemoryStore MemoryStore keeps sessions in process; they are lost on restart
+type MemoryStore struct {
+	Expiry
+	sessions map[string]AdminSession
+	mu       sync.Mutex
+}
+
+//GetNew GetNew
+func (m *MemoryStore) GetNew() Store {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.sessions == nil {
+		m.sessions = make(map[string]AdminSession)
+	}
+	return m
+}
+
+//Create Create stores a copy of s under a new ID and returns it
+func (m *MemoryStore) Create(s *AdminSession) *AdminSession {
+	ns := newSession(s)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[ns.ID] = *ns
+	return ns
+}
+
+//Get Get returns the session for id and marks it used
+func (m *MemoryStore) Get(id string) *AdminSession {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return nil
+	}
+	now := time.Now()
+	if m.expired(&s, now) {
+		delete(m.sessions, id)
+		return nil
+	}
+	s.LastUsed = now
+	m.sessions[id] = s
+	return &s
+}
+
+//Update Update replaces an existing session
+func (m *MemoryStore) Update(s *AdminSession) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.sessions[s.ID]; !ok {
+		return false
+	}
+	m.sessions[s.ID] = *s
+	return true
+}
+
+//Delete Delete
+func (m *MemoryStore) Delete(id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.sessions[id]
+	delete(m.sessions, id)
+	return ok
+}
+
+//List List returns the active sessions, newest first, and drops expired ones
+func (m *MemoryStore) List() *[]AdminSession {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var rtn = []AdminSession{}
+	now := time.Now()
+	for id, s := range m.sessions {
+		if m.expired(&s, now) {
+			delete(m.sessions, id)
+		} else {
+			rtn = append(rtn, s)
+		}
+	}
+	sortSessions(rtn)
+	return &rtn
+}
+
+func sortSessions(sl []AdminSession) {
+	sort.Slice(sl, func(i, j int) bool {
+		return sl[i].Created.After(sl[j].Created)
+	})
+}
@@ -0,0 +1,76 @@
+package sessrv
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore_Create(t *testing.T) {
+	var ms MemoryStore
+	s := ms.GetNew()
+	var as AdminSession
+	as.Username = "admin"
+	as.Password = "admin"
+	ns := s.Create(&as)
+	ns2 := s.Create(&as)
+	fmt.Println("id: ", ns.ID)
+	if len(ns.ID) != 64 || ns.ID == ns2.ID || as.ID != "" || ns.Created.IsZero() {
+		t.Fail()
+	}
+	gs := s.Get(ns.ID)
+	if gs == nil || gs.Username != "admin" || gs.Password != "admin" {
+		t.Fail()
+	}
+	if s.Get("notanid") != nil {
+		t.Fail()
+	}
+}
+
+func TestMemoryStore_UpdateDelete(t *testing.T) {
+	var ms MemoryStore
+	s := ms.GetNew()
+	var as AdminSession
+	as.Username = "admin"
+	ns := s.Create(&as)
+	ns.Password = "newpw"
+	if !s.Update(ns) || s.Get(ns.ID).Password != "newpw" {
+		t.Fail()
+	}
+	if !s.Delete(ns.ID) || s.Get(ns.ID) != nil || s.Delete(ns.ID) {
+		t.Fail()
+	}
+	if s.Update(ns) {
+		t.Fail()
+	}
+}
+
+func TestMemoryStore_Expiry(t *testing.T) {
+	var ms MemoryStore
+	ms.IdleTimeout = time.Minute
+	ms.MaxAge = time.Hour
+	s := ms.GetNew()
+	var as AdminSession
+	as.Username = "admin"
+	idle := s.Create(&as)
+	old := s.Create(&as)
+	live := s.Create(&as)
+
+	ms.mu.Lock()
+	is := ms.sessions[idle.ID]
+	is.LastUsed = time.Now().Add(-2 * time.Minute)
+	ms.sessions[idle.ID] = is
+	ols := ms.sessions[old.ID]
+	ols.Created = time.Now().Add(-2 * time.Hour)
+	ms.sessions[old.ID] = ols
+	ms.mu.Unlock()
+
+	lst := s.List()
+	fmt.Println("list: ", len(*lst))
+	if len(*lst) != 1 || (*lst)[0].ID != live.ID {
+		t.Fail()
+	}
+	if s.Get(idle.ID) != nil || s.Get(old.ID) != nil || s.Get(live.ID) == nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,106 @@
+//Package sessrv ...
+package sessrv
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+const (
+	//DefaultIdleTimeout DefaultIdleTimeout
+	DefaultIdleTimeout = 30 * time.Minute
+
+	//DefaultMaxAge DefaultMaxAge
+	DefaultMaxAge = 12 * time.Hour
+
+	idLen = 32
+)
+
+//AdminSession AdminSession is the server side state of a logged in
+//store admin. Only ID goes in the cookie; the backend credential, the
+//Basic password or the OAuth2 tokens, stays here.
+type AdminSession struct {
+	ID           string    `json:"id"`
+	Username     string    `json:"username"`
+	Password     string    `json:"password"`
+	AccessToken  string    `json:"accessToken"`
+	RefreshToken string    `json:"refreshToken"`
+	TokenExpires time.Time `json:"tokenExpires"`
+	RemoteAddr   string    `json:"remoteAddr"`
+	UserAgent    string    `json:"userAgent"`
+	Created      time.Time `json:"created"`
+	LastUsed     time.Time `json:"lastUsed"`
+
+	TwoFactorPending  bool `json:"twoFactorPending"`
+	TwoFactorFailures int  `json:"twoFactorFailures"`
+}
+
+//Ref Ref identifies a session on admin pages without showing its ID
+func (s *AdminSession) Ref() string {
+	sum := sha256.Sum256([]byte(s.ID))
+	return hex.EncodeToString(sum[:8])
+}
+
+//Store Store keeps admin sessions by ID. Get and List never return
+//sessions that are past their idle or absolute expiry.
+type Store interface {
+	Create(s *AdminSession) *AdminSession
+	Get(id string) *AdminSession
+	Update(s *AdminSession) bool
+	Delete(id string) bool
+	List() *[]AdminSession
+}
+
+//Expiry Expiry holds the idle and absolute lifetimes shared by the stores
+type Expiry struct {
+	IdleTimeout time.Duration
+	MaxAge      time.Duration
+}
+
+func (e *Expiry) expired(s *AdminSession, now time.Time) bool {
+	idle := e.IdleTimeout
+	if idle <= 0 {
+		idle = DefaultIdleTimeout
+	}
+	max := e.MaxAge
+	if max <= 0 {
+		max = DefaultMaxAge
+	}
+	return now.Sub(s.LastUsed) > idle || now.Sub(s.Created) > max
+}
+
+func newID() string {
+	b := make([]byte, idLen)
+	if _, err := rand.Read(b); err != nil {
+		panic("sessrv: cannot read random bytes: " + err.Error())
+	}
+	return hex.EncodeToString(b)
+}
+
+func newSession(s *AdminSession) *AdminSession {
+	var ns = *s
+	ns.ID = newID()
+	ns.Created = time.Now()
+	ns.LastUsed = ns.Created
+	return &ns
+}
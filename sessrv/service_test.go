@@ -0,0 +1,36 @@
+package sessrv
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdminSession_Ref(t *testing.T) {
+	var s AdminSession
+	s.ID = newID()
+	var s2 AdminSession
+	s2.ID = newID()
+	if len(s.Ref()) != 16 || s.Ref() == s2.Ref() || s.Ref() != s.Ref() {
+		t.Fail()
+	}
+}
+
+func TestExpiry_expiredDefaults(t *testing.T) {
+	var e Expiry
+	now := time.Now()
+	var s AdminSession
+	s.Created = now.Add(-time.Hour)
+	s.LastUsed = now.Add(-time.Minute)
+	if e.expired(&s, now) {
+		t.Fail()
+	}
+	s.LastUsed = now.Add(-DefaultIdleTimeout - time.Second)
+	if !e.expired(&s, now) {
+		t.Fail()
+	}
+	s.LastUsed = now
+	s.Created = now.Add(-DefaultMaxAge - time.Second)
+	if !e.expired(&s, now) {
+		t.Fail()
+	}
+}
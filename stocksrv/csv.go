@@ -0,0 +1,43 @@
+package stocksrv
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+//WriteCSV WriteCSV writes one row per alert
+func WriteCSV(w io.Writer, alerts *[]Alert) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"distributor", "productId", "sku", "name", "stock", "stockAlert", "outOfStock", "backOrdered", "alerted"})
+	for _, a := range *alerts {
+		var alerted string
+		if !a.Alerted.IsZero() {
+			alerted = a.Alerted.Format(time.RFC3339)
+		}
+		cw.Write([]string{a.Distributor, strconv.FormatInt(a.ProductID, 10), a.Sku, a.Name,
+			strconv.FormatInt(a.Stock, 10), strconv.FormatInt(a.StockAlert, 10),
+			strconv.FormatBool(a.OutOfStock()), strconv.FormatInt(a.BackOrdered, 10), alerted})
+	}
+	cw.Flush()
+	return cw.Error()
+}
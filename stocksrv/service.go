@@ -0,0 +1,341 @@
+//Package stocksrv ...
+package stocksrv
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	lg "github.com/Ulbora/Level_Logger"
+	mails "github.com/Ulbora/Six910-ui/mailsrv"
+	api "github.com/Ulbora/Six910API-Go"
+	ml "github.com/Ulbora/go-mail-sender"
+	ds "github.com/Ulbora/json-datastore"
+	sdbi "github.com/Ulbora/six910-database-interface"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+const (
+	//DefaultInterval DefaultInterval
+	DefaultInterval = 24 * time.Hour
+
+	//DefaultOpenStatus DefaultOpenStatus is the order status whose back
+	//ordered items are listed
+	DefaultOpenStatus = "processing"
+
+	pageSize    = 100
+	maxProducts = 10000
+	maxOrders   = 500
+
+	//NoDistributor NoDistributor names the group of products without one
+	NoDistributor = "No distributor"
+)
+
+//Alert Alert is a product at or below its stock alert level, or with
+//order items on back order
+type Alert struct {
+	ProductID     int64     `json:"productId"`
+	Sku           string    `json:"sku"`
+	Name          string    `json:"name"`
+	Stock         int64     `json:"stock"`
+	StockAlert    int64     `json:"stockAlert"`
+	BackOrdered   int64     `json:"backOrdered"`
+	DistributorID int64     `json:"distributorId"`
+	Distributor   string    `json:"distributor"`
+	Alerted       time.Time `json:"alerted"`
+}
+
+//OutOfStock OutOfStock
+func (a *Alert) OutOfStock() bool {
+	return a.Stock <= 0
+}
+
+//Group Group is the alerts for one distributor
+type Group struct {
+	Distributor string
+	Alerts      []Alert
+}
+
+//Record Record is what was last mailed for a product. It is dropped once
+//the product is back above its alert level with nothing on back order.
+type Record struct {
+	ProductID   int64     `json:"productId"`
+	OutOfStock  bool      `json:"outOfStock"`
+	BackOrdered bool      `json:"backOrdered"`
+	Alerted     time.Time `json:"alerted"`
+}
+
+//Service Service
+type Service interface {
+	Scan(hd *api.Headers) *[]Alert
+	Notify(hd *api.Headers) (int, bool)
+}
+
+//Six910StockService Six910StockService scans the catalog through API and
+//mails new alerts to Recipients. A product is mailed again only when it
+//runs out of stock or gets a back order after an earlier alert.
+type Six910StockService struct {
+	API         api.API
+	Store       ds.JSONDatastore
+	MailService mails.MailService
+	MailFrom    string
+	Recipients  []string
+	StoreName   string
+	OpenStatus  string
+	Log         *lg.Logger
+	mu          sync.Mutex
+}
+
+//GetNew GetNew
+func (s *Six910StockService) GetNew() Service {
+	if s.OpenStatus == "" {
+		s.OpenStatus = DefaultOpenStatus
+	}
+	return s
+}
+
+//Scan Scan lists the current alerts grouped by distributor. Alerted is
+//set for products already mailed.
+func (s *Six910StockService) Scan(hd *api.Headers) *[]Alert {
+	rtn, _, _ := s.scan(hd)
+	for i := range rtn {
+		if rec := s.read(rtn[i].ProductID); rec != nil {
+			rtn[i].Alerted = rec.Alerted
+		}
+	}
+	return &rtn
+}
+
+//Notify Notify mails a digest of the alerts not mailed before and
+//remembers them. It returns how many products were in the digest.
+func (s *Six910StockService) Notify(hd *api.Headers) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	al, seen, complete := s.scan(hd)
+	var fresh []Alert
+	current := make(map[int64]bool)
+	for _, a := range al {
+		current[a.ProductID] = true
+		rec := s.read(a.ProductID)
+		//the stock of a back ordered product the scan did not reach is unknown
+		if rec == nil || (a.OutOfStock() && !rec.OutOfStock && seen[a.ProductID]) || (a.BackOrdered > 0 && !rec.BackOrdered) {
+			fresh = append(fresh, a)
+		}
+	}
+	if complete {
+		s.clear(current, seen)
+	} else {
+		s.Log.Info("stock alert scan did not finish; alert records kept")
+	}
+	if len(fresh) == 0 {
+		s.Log.Info("stock alert found nothing new in ", len(al), " alerts")
+		return 0, true
+	}
+	var mm ml.Mailer
+	mm.Subject = s.StoreName + " stock alert: " + strconv.Itoa(len(fresh)) + " products"
+	mm.Body = Digest(fresh)
+	mm.Recipients = s.Recipients
+	mm.SenderAddress = s.MailFrom
+	if !s.MailService.SendMail(&mm) {
+		s.Log.Error("stock alert mail not sent for ", len(fresh), " products")
+		return 0, false
+	}
+	now := time.Now()
+	for _, a := range fresh {
+		var rec Record
+		rec.ProductID = a.ProductID
+		rec.OutOfStock = a.OutOfStock()
+		rec.BackOrdered = a.BackOrdered > 0
+		rec.Alerted = now
+		if !s.Store.Save(storeKey(a.ProductID), rec) {
+			s.Log.Error("stock alert record not saved for product: ", a.ProductID)
+		}
+	}
+	s.Log.Info("stock alert mailed for ", len(fresh), " products")
+	return len(fresh), true
+}
+
+//GroupByDistributor GroupByDistributor keeps the order of alerts, which
+//Scan sorts by distributor
+func GroupByDistributor(alerts *[]Alert) []Group {
+	var rtn []Group
+	for _, a := range *alerts {
+		if len(rtn) == 0 || rtn[len(rtn)-1].Distributor != a.Distributor {
+			rtn = append(rtn, Group{Distributor: a.Distributor})
+		}
+		g := &rtn[len(rtn)-1]
+		g.Alerts = append(g.Alerts, a)
+	}
+	return rtn
+}
+
+//Digest Digest is the plain text mail body for alerts
+func Digest(alerts []Alert) string {
+	var b strings.Builder
+	for _, g := range GroupByDistributor(&alerts) {
+		b.WriteString(g.Distributor + "\n")
+		for _, a := range g.Alerts {
+			b.WriteString("  " + a.Sku + " " + a.Name + ": stock " + strconv.FormatInt(a.Stock, 10) +
+				", alert at " + strconv.FormatInt(a.StockAlert, 10))
+			if a.OutOfStock() {
+				b.WriteString(", OUT OF STOCK")
+			}
+			if a.BackOrdered > 0 {
+				b.WriteString(", " + strconv.FormatInt(a.BackOrdered, 10) + " on back order")
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+//scan pages through the products and the items of open orders. It also
+//returns the products it saw, which is not all of them past maxProducts,
+//and false when a backend call failed so the alerts may be missing some.
+func (s *Six910StockService) scan(hd *api.Headers) ([]Alert, map[int64]bool, bool) {
+	var rtn []Alert
+	var complete = true
+	idx := make(map[int64]int)
+	prods := make(map[int64]sdbi.Product)
+	seen := make(map[int64]bool)
+	for st := int64(0); st < maxProducts; st += pageSize {
+		pl := s.API.GetProductList(st, st+pageSize, hd)
+		if pl == nil {
+			s.Log.Error("stock alert product scan failed at ", st)
+			complete = false
+			break
+		}
+		for _, p := range *pl {
+			prods[p.ID] = p
+			seen[p.ID] = true
+			if p.Stock <= p.StockAlert {
+				idx[p.ID] = len(rtn)
+				rtn = append(rtn, newAlert(&p))
+			}
+		}
+		if len(*pl) < pageSize {
+			break
+		}
+	}
+	ol := s.API.GetStoreOrderListByStatus(s.OpenStatus, hd)
+	if ol == nil {
+		s.Log.Error("stock alert order scan failed")
+		complete = false
+	} else {
+		for i, o := range *ol {
+			if i >= maxOrders {
+				s.Log.Info("stock alert back order scan stopped at ", maxOrders, " orders")
+				break
+			}
+			il := s.API.GetOrderItemList(o.ID, hd)
+			if il == nil {
+				s.Log.Error("stock alert items not read for order: ", o.ID)
+				complete = false
+				continue
+			}
+			for _, oi := range *il {
+				if !oi.BackOrdered {
+					continue
+				}
+				ai, ok := idx[oi.ProductID]
+				if !ok {
+					p, found := prods[oi.ProductID]
+					if !found {
+						p.ID = oi.ProductID
+						p.Name = oi.ProductName
+					}
+					ai = len(rtn)
+					idx[oi.ProductID] = ai
+					rtn = append(rtn, newAlert(&p))
+				}
+				rtn[ai].BackOrdered += oi.Quantity
+			}
+		}
+	}
+	dists := make(map[int64]string)
+	if dl := s.API.GetDistributorList(hd); dl != nil {
+		for _, d := range *dl {
+			dists[d.ID] = d.Company
+		}
+	}
+	for i := range rtn {
+		rtn[i].Distributor = dists[rtn[i].DistributorID]
+		if rtn[i].Distributor == "" {
+			rtn[i].Distributor = NoDistributor
+		}
+	}
+	sort.SliceStable(rtn, func(i, j int) bool {
+		if rtn[i].Distributor != rtn[j].Distributor {
+			return rtn[i].Distributor < rtn[j].Distributor
+		}
+		return rtn[i].Name < rtn[j].Name
+	})
+	return rtn, seen, complete
+}
+
+//clear drops the records of seen products no longer alerted, so they are
+//mailed again if they run low later
+func (s *Six910StockService) clear(current map[int64]bool, seen map[int64]bool) {
+	all := s.Store.ReadAll()
+	if all == nil {
+		return
+	}
+	for _, b := range *all {
+		var rec Record
+		if err := json.Unmarshal(b, &rec); err != nil {
+			continue
+		}
+		if seen[rec.ProductID] && !current[rec.ProductID] {
+			s.Store.Delete(storeKey(rec.ProductID))
+		}
+	}
+}
+
+func (s *Six910StockService) read(productID int64) *Record {
+	b := s.Store.Read(storeKey(productID))
+	if b == nil || len(*b) == 0 {
+		return nil
+	}
+	var rec Record
+	if err := json.Unmarshal(*b, &rec); err != nil {
+		s.Log.Error("bad stock alert record for product: ", productID, err)
+		return nil
+	}
+	return &rec
+}
+
+func newAlert(p *sdbi.Product) Alert {
+	var a Alert
+	a.ProductID = p.ID
+	a.Sku = p.Sku
+	a.Name = p.Name
+	a.Stock = p.Stock
+	a.StockAlert = p.StockAlert
+	a.DistributorID = p.DistributorID
+	return a
+}
+
+func storeKey(productID int64) string {
+	return "product" + strconv.FormatInt(productID, 10)
+}
@@ -0,0 +1,150 @@
+package stocksrv
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	lg "github.com/Ulbora/Level_Logger"
+	mapi "github.com/Ulbora/Six910-ui/mockapi"
+	api "github.com/Ulbora/Six910API-Go"
+	ml "github.com/Ulbora/go-mail-sender"
+	ds "github.com/Ulbora/json-datastore"
+	sdbi "github.com/Ulbora/six910-database-interface"
+)
+
+type testMail struct {
+	sent []ml.Mailer
+}
+
+func (m *testMail) SendMail(mailer *ml.Mailer) bool {
+	m.sent = append(m.sent, *mailer)
+	return true
+}
+
+func testService(t *testing.T) (*Six910StockService, *mapi.MockAPI, *testMail, string) {
+	dir, err := ioutil.TempDir("", "six910stock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var l lg.Logger
+	var sapi mapi.MockAPI
+	pl := []sdbi.Product{
+		{ID: 1, Sku: "s1", Name: "bolt", Stock: 3, StockAlert: 5, DistributorID: 7},
+		{ID: 2, Sku: "s2", Name: "nut", Stock: 50, StockAlert: 5, DistributorID: 7},
+		{ID: 3, Sku: "s3", Name: "axle", Stock: 0, StockAlert: 2},
+	}
+	sapi.MockProductList = &pl
+	ol := []sdbi.Order{{ID: 10}}
+	sapi.MockOrderList = &ol
+	il := []sdbi.OrderItem{{ProductID: 2, Quantity: 4, BackOrdered: true}, {ProductID: 1, Quantity: 1}}
+	sapi.MockOrderItemList = &il
+	dl := []sdbi.Distributor{{ID: 7, Company: "Acme"}}
+	sapi.MockDistributorList = &dl
+
+	var sds ds.DataStore
+	sds.Path = dir
+	var m testMail
+	var ss Six910StockService
+	ss.API = sapi.GetNew()
+	ss.Store = sds.GetNew()
+	ss.MailService = &m
+	ss.MailFrom = "store@test.com"
+	ss.Recipients = []string{"buyer@test.com"}
+	ss.StoreName = "test"
+	ss.Log = &l
+	return &ss, &sapi, &m, dir
+}
+
+func TestSix910StockService_Scan(t *testing.T) {
+	ss, _, _, dir := testService(t)
+	defer os.RemoveAll(dir)
+	s := ss.GetNew()
+	var hd api.Headers
+	al := *s.Scan(&hd)
+	if len(al) != 3 || al[0].Distributor != "Acme" || al[0].Name != "bolt" || al[2].Distributor != NoDistributor {
+		t.Fail()
+	}
+	if al[1].ProductID != 2 || al[1].BackOrdered != 4 || al[1].OutOfStock() {
+		t.Fail()
+	}
+	if !al[2].OutOfStock() || !al[0].Alerted.IsZero() {
+		t.Fail()
+	}
+	gl := GroupByDistributor(&al)
+	if len(gl) != 2 || len(gl[0].Alerts) != 2 {
+		t.Fail()
+	}
+	var b bytes.Buffer
+	if err := WriteCSV(&b, &al); err != nil || strings.Count(b.String(), "\n") != 4 {
+		t.Fail()
+	}
+}
+
+func TestSix910StockService_Notify(t *testing.T) {
+	ss, sapi, m, dir := testService(t)
+	defer os.RemoveAll(dir)
+	s := ss.GetNew()
+	var hd api.Headers
+	if n, suc := s.Notify(&hd); !suc || n != 3 || len(m.sent) != 1 {
+		t.Fail()
+	}
+	body := m.sent[0].Body
+	if !strings.Contains(body, "Acme\n  s1 bolt") || !strings.Contains(body, "OUT OF STOCK") || !strings.Contains(body, "4 on back order") {
+		t.Fail()
+	}
+	if al := *s.Scan(&hd); al[0].Alerted.IsZero() {
+		t.Fail()
+	}
+
+	//nothing new the next day
+	if n, suc := s.Notify(&hd); !suc || n != 0 || len(m.sent) != 1 {
+		t.Fail()
+	}
+
+	//bolt runs out, axle is restocked
+	pl := []sdbi.Product{
+		{ID: 1, Sku: "s1", Name: "bolt", Stock: 0, StockAlert: 5, DistributorID: 7},
+		{ID: 3, Sku: "s3", Name: "axle", Stock: 20, StockAlert: 2},
+	}
+	sapi.MockProductList = &pl
+	sapi.MockOrderItemList = &[]sdbi.OrderItem{}
+	if n, _ := s.Notify(&hd); n != 1 || len(m.sent) != 2 || !strings.Contains(m.sent[1].Body, "bolt") {
+		t.Fail()
+	}
+
+	//axle runs low again and is mailed again
+	pl[1].Stock = 1
+	if n, _ := s.Notify(&hd); n != 1 || !strings.Contains(m.sent[2].Body, "axle") {
+		t.Fail()
+	}
+}
+
+func TestSix910StockService_NotifyScanFailed(t *testing.T) {
+	ss, sapi, m, dir := testService(t)
+	defer os.RemoveAll(dir)
+	s := ss.GetNew()
+	var hd api.Headers
+	if n, _ := s.Notify(&hd); n != 3 {
+		t.Fail()
+	}
+
+	//the backend fails mid scan, so nothing is forgotten
+	pl := sapi.MockProductList
+	sapi.MockProductList = nil
+	if n, suc := s.Notify(&hd); !suc || n != 0 {
+		t.Fail()
+	}
+	sapi.MockProductList = pl
+	ol := sapi.MockOrderList
+	sapi.MockOrderList = nil
+	if n, _ := s.Notify(&hd); n != 0 {
+		t.Fail()
+	}
+	sapi.MockOrderList = ol
+	if n, _ := s.Notify(&hd); n != 0 || len(m.sent) != 1 {
+		t.Fail()
+	}
+}
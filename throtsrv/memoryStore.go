@@ -0,0 +1,102 @@
+package throtsrv
+
+import (
+	"sync"
+	"time"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+const (
+	maxEvents  = 500
+	maxEntries = 10000
+	staleAfter = 24 * time.Hour
+)
+
+//MemoryStore MemoryStore keeps entries and the latest lockout events in
+//process
+type MemoryStore struct {
+	entries map[string]Entry
+	events  []LockoutEvent
+	mu      sync.Mutex
+}
+
+//GetNew GetNew
+func (m *MemoryStore) GetNew() Store {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.entries == nil {
+		m.entries = make(map[string]Entry)
+	}
+	return m
+}
+
+//Get Get
+func (m *MemoryStore) Get(key string) *Entry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	if !ok {
+		return nil
+	}
+	return &e
+}
+
+//Save Save drops entries untouched for a day once the map gets large
+func (m *MemoryStore) Save(e *Entry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[e.Key] = *e
+	if len(m.entries) > maxEntries {
+		now := time.Now()
+		for k, v := range m.entries {
+			if now.Sub(v.LastFailure) > staleAfter && now.After(v.LockedUntil) {
+				delete(m.entries, k)
+			}
+		}
+	}
+}
+
+//Delete Delete
+func (m *MemoryStore) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+}
+
+//AddEvent AddEvent
+func (m *MemoryStore) AddEvent(ev *LockoutEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, *ev)
+	if len(m.events) > maxEvents {
+		m.events = m.events[len(m.events)-maxEvents:]
+	}
+}
+
+//Events Events returns the lockout events, newest first
+func (m *MemoryStore) Events() *[]LockoutEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var rtn = make([]LockoutEvent, 0, len(m.events))
+	for i := len(m.events) - 1; i >= 0; i-- {
+		rtn = append(rtn, m.events[i])
+	}
+	return &rtn
+}
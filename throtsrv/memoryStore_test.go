@@ -0,0 +1,50 @@
+package throtsrv
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore_SaveGetDelete(t *testing.T) {
+	var ms MemoryStore
+	s := ms.GetNew()
+	if s.Get("ip:1.1.1.1") != nil {
+		t.Fail()
+	}
+	s.Save(&Entry{Key: "ip:1.1.1.1", Failures: 2})
+	e := s.Get("ip:1.1.1.1")
+	e.Failures = 5
+	if s.Get("ip:1.1.1.1").Failures != 2 {
+		t.Fail()
+	}
+	s.Delete("ip:1.1.1.1")
+	if s.Get("ip:1.1.1.1") != nil {
+		t.Fail()
+	}
+}
+
+func TestMemoryStore_Prune(t *testing.T) {
+	var ms MemoryStore
+	s := ms.GetNew()
+	old := time.Now().Add(-2 * staleAfter)
+	for i := 0; i < maxEntries; i++ {
+		s.Save(&Entry{Key: "ip:" + strconv.Itoa(i), LastFailure: old})
+	}
+	s.Save(&Entry{Key: "ip:new", LastFailure: time.Now()})
+	if len(ms.entries) != 1 || s.Get("ip:new") == nil {
+		t.Fail()
+	}
+}
+
+func TestMemoryStore_Events(t *testing.T) {
+	var ms MemoryStore
+	s := ms.GetNew()
+	for i := 0; i < maxEvents+5; i++ {
+		s.AddEvent(&LockoutEvent{Key: "k" + strconv.Itoa(i)})
+	}
+	evs := *s.Events()
+	if len(evs) != maxEvents || evs[0].Key != "k"+strconv.Itoa(maxEvents+4) {
+		t.Fail()
+	}
+}
@@ -0,0 +1,197 @@
+//Package throtsrv ...
+package throtsrv
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	lg "github.com/Ulbora/Level_Logger"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+//Entry Entry is the failure count for one username or client IP
+type Entry struct {
+	Key         string    `json:"key"`
+	Failures    int       `json:"failures"`
+	LastFailure time.Time `json:"lastFailure"`
+	LockedUntil time.Time `json:"lockedUntil"`
+}
+
+//LockoutEvent LockoutEvent is recorded each time a key gets locked out
+type LockoutEvent struct {
+	Key      string    `json:"key"`
+	Failures int       `json:"failures"`
+	Time     time.Time `json:"time"`
+	Until    time.Time `json:"until"`
+}
+
+//Store Store holds entries and lockout events. Implementations must be
+//safe for concurrent use; a shared store lets several UI instances
+//throttle together.
+type Store interface {
+	Get(key string) *Entry
+	Save(e *Entry)
+	Delete(key string)
+	AddEvent(ev *LockoutEvent)
+	Events() *[]LockoutEvent
+}
+
+//Policy Policy sets when failures start to slow down logins for a key
+type Policy struct {
+	FreeAttempts int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	LockoutAfter int
+	LockoutTime  time.Duration
+}
+
+//Throttle Throttle
+type Throttle interface {
+	Check(keys ...string) time.Duration
+	Fail(keys ...string)
+	Reset(keys ...string)
+	Events() *[]LockoutEvent
+}
+
+//Six910Throttle Six910Throttle uses IPPolicy for keys made by IPKey and
+//UserPolicy for the rest. A key with no failures for LockoutTime starts over.
+type Six910Throttle struct {
+	Store      Store
+	UserPolicy Policy
+	IPPolicy   Policy
+	Log        *lg.Logger
+	mu         sync.Mutex
+}
+
+//DefaultUserPolicy DefaultUserPolicy
+func DefaultUserPolicy() Policy {
+	return Policy{FreeAttempts: 3, BaseDelay: time.Second, MaxDelay: 5 * time.Minute,
+		LockoutAfter: 10, LockoutTime: 15 * time.Minute}
+}
+
+//DefaultIPPolicy DefaultIPPolicy allows more failures since many users
+//can share one address
+func DefaultIPPolicy() Policy {
+	return Policy{FreeAttempts: 15, BaseDelay: time.Second, MaxDelay: 5 * time.Minute,
+		LockoutAfter: 50, LockoutTime: 15 * time.Minute}
+}
+
+//GetNew GetNew
+func (t *Six910Throttle) GetNew() Throttle {
+	return t
+}
+
+//UserKey UserKey keeps admin and customer usernames apart
+func UserKey(scope string, username string) string {
+	return scope + ":user:" + strings.ToLower(strings.TrimSpace(username))
+}
+
+//IPKey IPKey
+func IPKey(ip string) string {
+	return "ip:" + ip
+}
+
+//Check Check returns how long to wait before the next try is allowed,
+//or 0 when every key may try now
+func (t *Six910Throttle) Check(keys ...string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var rtn time.Duration
+	now := time.Now()
+	for _, k := range keys {
+		e := t.Store.Get(k)
+		if e == nil {
+			continue
+		}
+		if w := t.blockedUntil(e).Sub(now); w > rtn {
+			rtn = w
+		}
+	}
+	return rtn
+}
+
+//Fail Fail counts a failed login for each key and locks a key out once
+//it reaches LockoutAfter failures
+func (t *Six910Throttle) Fail(keys ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	for _, k := range keys {
+		p := t.policy(k)
+		e := t.Store.Get(k)
+		if e == nil || now.Sub(e.LastFailure) > p.LockoutTime {
+			e = &Entry{Key: k}
+		}
+		e.Failures++
+		e.LastFailure = now
+		if p.LockoutAfter > 0 && e.Failures >= p.LockoutAfter && now.After(e.LockedUntil) {
+			e.LockedUntil = now.Add(p.LockoutTime)
+			var ev LockoutEvent
+			ev.Key = k
+			ev.Failures = e.Failures
+			ev.Time = now
+			ev.Until = e.LockedUntil
+			t.Store.AddEvent(&ev)
+			t.Log.Info("login locked out for: ", k, " until: ", e.LockedUntil.Format(time.RFC3339))
+		}
+		t.Store.Save(e)
+	}
+}
+
+//Reset Reset clears the keys after a good login
+func (t *Six910Throttle) Reset(keys ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, k := range keys {
+		t.Store.Delete(k)
+	}
+}
+
+//Events Events returns the recorded lockouts, newest first
+func (t *Six910Throttle) Events() *[]LockoutEvent {
+	return t.Store.Events()
+}
+
+func (t *Six910Throttle) policy(key string) Policy {
+	if strings.HasPrefix(key, "ip:") {
+		return t.IPPolicy
+	}
+	return t.UserPolicy
+}
+
+//blockedUntil is the later of the lockout end and the backoff, which
+//doubles with each failure past FreeAttempts
+func (t *Six910Throttle) blockedUntil(e *Entry) time.Time {
+	p := t.policy(e.Key)
+	rtn := e.LockedUntil
+	over := e.Failures - p.FreeAttempts
+	if over > 0 && p.BaseDelay > 0 {
+		d := time.Duration(float64(p.BaseDelay) * math.Pow(2, float64(over-1)))
+		if d > p.MaxDelay || d <= 0 {
+			d = p.MaxDelay
+		}
+		if bu := e.LastFailure.Add(d); bu.After(rtn) {
+			rtn = bu
+		}
+	}
+	return rtn
+}
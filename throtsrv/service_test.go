@@ -0,0 +1,107 @@
+package throtsrv
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	lg "github.com/Ulbora/Level_Logger"
+)
+
+func newTestThrottle() *Six910Throttle {
+	var ms MemoryStore
+	var t Six910Throttle
+	t.Store = ms.GetNew()
+	t.UserPolicy = DefaultUserPolicy()
+	t.IPPolicy = DefaultIPPolicy()
+	var l lg.Logger
+	l.LogLevel = lg.AllLevel
+	t.Log = &l
+	return &t
+}
+
+func TestSix910Throttle_Backoff(t *testing.T) {
+	th := newTestThrottle()
+	tr := th.GetNew()
+	k := UserKey("admin", " Tester ")
+	if k != "admin:user:tester" {
+		t.Fail()
+	}
+	for i := 0; i < 3; i++ {
+		tr.Fail(k)
+	}
+	if w := tr.Check(k); w != 0 {
+		fmt.Println("wait after free attempts: ", w)
+		t.Fail()
+	}
+	tr.Fail(k)
+	w1 := tr.Check(k)
+	tr.Fail(k)
+	w2 := tr.Check(k)
+	fmt.Println("waits: ", w1, w2)
+	if w1 <= 0 || w1 > time.Second || w2 <= time.Second || w2 > 2*time.Second {
+		t.Fail()
+	}
+	if tr.Check(UserKey("customer", "tester")) != 0 {
+		t.Fail()
+	}
+	tr.Reset(k)
+	if tr.Check(k) != 0 {
+		t.Fail()
+	}
+}
+
+func TestSix910Throttle_MaxDelay(t *testing.T) {
+	th := newTestThrottle()
+	th.UserPolicy.LockoutAfter = 0
+	for i := 0; i < 60; i++ {
+		th.Fail("admin:user:tester")
+	}
+	w := th.Check("admin:user:tester")
+	fmt.Println("wait: ", w)
+	if w <= 4*time.Minute || w > 5*time.Minute {
+		t.Fail()
+	}
+	if len(*th.Events()) != 0 {
+		t.Fail()
+	}
+}
+
+func TestSix910Throttle_Lockout(t *testing.T) {
+	th := newTestThrottle()
+	uk := UserKey("admin", "tester")
+	ik := IPKey("10.0.0.1")
+	for i := 0; i < 10; i++ {
+		th.Fail(uk, ik)
+	}
+	w := th.Check(ik, uk)
+	fmt.Println("wait: ", w)
+	if w <= 14*time.Minute {
+		t.Fail()
+	}
+	if th.Check(ik) > 2*time.Second {
+		t.Fail()
+	}
+	evs := *th.Events()
+	if len(evs) != 1 || evs[0].Key != uk || evs[0].Failures != 10 {
+		t.Fail()
+	}
+	th.Fail(uk)
+	if len(*th.Events()) != 1 {
+		t.Fail()
+	}
+}
+
+func TestSix910Throttle_FailuresExpire(t *testing.T) {
+	th := newTestThrottle()
+	uk := UserKey("admin", "tester")
+	th.Store.Save(&Entry{Key: uk, Failures: 9, LastFailure: time.Now().Add(-time.Hour)})
+	if th.Check(uk) != 0 {
+		t.Fail()
+	}
+	th.Fail(uk)
+	e := th.Store.Get(uk)
+	if e.Failures != 1 || !e.LockedUntil.IsZero() {
+		t.Fail()
+	}
+}
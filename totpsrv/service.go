@@ -0,0 +1,229 @@
+package totpsrv
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	lg "github.com/Ulbora/Level_Logger"
+	ds "github.com/Ulbora/json-datastore"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+const recoveryCodeCount = 10
+
+//Enrollment Enrollment is the two factor setup for one admin. Recovery
+//codes are kept as sha256 hashes and removed when used.
+type Enrollment struct {
+	Username      string    `json:"username"`
+	Secret        string    `json:"secret"`
+	Confirmed     bool      `json:"confirmed"`
+	RecoveryCodes []string  `json:"recoveryCodes"`
+	LastStep      int64     `json:"lastStep"`
+	Created       time.Time `json:"created"`
+	ConfirmedDate time.Time `json:"confirmedDate"`
+}
+
+//Service Service
+type Service interface {
+	Enrolled(username string) bool
+	StartEnrollment(username string) *Enrollment
+	ConfirmEnrollment(username string, code string) ([]string, bool)
+	Verify(username string, code string) bool
+	Reset(username string) bool
+	ListEnrolled() []string
+	ProvisioningURI(e *Enrollment) string
+}
+
+//Six910TotpService Six910TotpService
+type Six910TotpService struct {
+	Store  ds.JSONDatastore
+	Issuer string
+	Log    *lg.Logger
+	mu     sync.Mutex
+}
+
+//GetNew GetNew
+func (s *Six910TotpService) GetNew() Service {
+	return s
+}
+
+//Enrolled Enrolled is true once the admin has confirmed a code
+func (s *Six910TotpService) Enrolled(username string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.read(username)
+	return e != nil && e.Confirmed
+}
+
+//StartEnrollment StartEnrollment creates a new unconfirmed secret,
+//replacing any earlier unconfirmed one. A confirmed enrollment must be
+//reset first.
+func (s *Six910TotpService) StartEnrollment(username string) *Enrollment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e := s.read(username); e != nil && e.Confirmed {
+		return nil
+	}
+	var e Enrollment
+	e.Username = username
+	e.Secret = NewSecret()
+	e.Created = time.Now()
+	if !s.Store.Save(storeKey(username), e) {
+		s.Log.Error("two factor enrollment not saved for: ", username)
+		return nil
+	}
+	return &e
+}
+
+//ConfirmEnrollment ConfirmEnrollment turns two factor on when code matches
+//the new secret and returns the recovery codes. They are only shown now.
+func (s *Six910TotpService) ConfirmEnrollment(username string, code string) ([]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.read(username)
+	if e == nil || e.Confirmed {
+		return nil, false
+	}
+	st := validateStep(e.Secret, code, time.Now())
+	if st == 0 {
+		return nil, false
+	}
+	var codes []string
+	e.RecoveryCodes = nil
+	for i := 0; i < recoveryCodeCount; i++ {
+		rc := newRecoveryCode()
+		codes = append(codes, rc)
+		e.RecoveryCodes = append(e.RecoveryCodes, hashCode(rc))
+	}
+	e.Confirmed = true
+	e.ConfirmedDate = time.Now()
+	e.LastStep = st
+	suc := s.Store.Save(storeKey(username), e)
+	if !suc {
+		return nil, false
+	}
+	return codes, true
+}
+
+//Verify Verify checks a code from the app, which can not be used twice,
+//or a recovery code, which is then used up
+func (s *Six910TotpService) Verify(username string, code string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.read(username)
+	if e == nil || !e.Confirmed {
+		return false
+	}
+	if st := validateStep(e.Secret, code, time.Now()); st > e.LastStep {
+		e.LastStep = st
+		return s.Store.Save(storeKey(username), e)
+	}
+	nc := normalizeRecovery(code)
+	if nc == "" {
+		return false
+	}
+	hc := hashCode(nc)
+	for i, rc := range e.RecoveryCodes {
+		if subtle.ConstantTimeCompare([]byte(rc), []byte(hc)) == 1 {
+			e.RecoveryCodes = append(e.RecoveryCodes[:i], e.RecoveryCodes[i+1:]...)
+			s.Log.Info("two factor recovery code used by: ", username)
+			return s.Store.Save(storeKey(username), e)
+		}
+	}
+	return false
+}
+
+//Reset Reset removes the enrollment so the admin logs in with a password
+//only until they enroll again
+func (s *Six910TotpService) Reset(username string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.read(username) == nil {
+		return false
+	}
+	return s.Store.Delete(storeKey(username))
+}
+
+//ListEnrolled ListEnrolled returns the admins with two factor turned on
+func (s *Six910TotpService) ListEnrolled() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var rtn = []string{}
+	for _, b := range *s.Store.ReadAll() {
+		var e Enrollment
+		if err := json.Unmarshal(b, &e); err == nil && e.Confirmed {
+			rtn = append(rtn, e.Username)
+		}
+	}
+	sort.Strings(rtn)
+	return rtn
+}
+
+//ProvisioningURI ProvisioningURI
+func (s *Six910TotpService) ProvisioningURI(e *Enrollment) string {
+	return ProvisioningURI(s.Issuer, e.Username, e.Secret)
+}
+
+func (s *Six910TotpService) read(username string) *Enrollment {
+	b := s.Store.Read(storeKey(username))
+	if b == nil || len(*b) == 0 {
+		return nil
+	}
+	var e Enrollment
+	if err := json.Unmarshal(*b, &e); err != nil {
+		s.Log.Error("bad two factor enrollment for: ", username, err)
+		return nil
+	}
+	return &e
+}
+
+//storeKey keeps any username safe to use as a file name
+func storeKey(username string) string {
+	return hex.EncodeToString([]byte(username))
+}
+
+func newRecoveryCode() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		panic("totpsrv: cannot read random bytes: " + err.Error())
+	}
+	c := strings.ToLower(b32.EncodeToString(b))[:10]
+	return c[:5] + "-" + c[5:]
+}
+
+func normalizeRecovery(code string) string {
+	c := strings.ToLower(strings.Replace(strings.TrimSpace(code), "-", "", -1))
+	if len(c) != 10 {
+		return ""
+	}
+	return c[:5] + "-" + c[5:]
+}
+
+func hashCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
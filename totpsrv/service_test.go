@@ -0,0 +1,91 @@
+package totpsrv
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	lg "github.com/Ulbora/Level_Logger"
+	ds "github.com/Ulbora/json-datastore"
+)
+
+func newTestService(t *testing.T) (Service, string) {
+	dir, err := ioutil.TempDir("", "six910totp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var l lg.Logger
+	var d ds.DataStore
+	d.Path = dir
+	var ts Six910TotpService
+	ts.Store = d.GetNew()
+	ts.Issuer = "teststore"
+	ts.Log = &l
+	return ts.GetNew(), dir
+}
+
+func TestSix910TotpService_Enroll(t *testing.T) {
+	s, dir := newTestService(t)
+	defer os.RemoveAll(dir)
+	e := s.StartEnrollment("admin")
+	if e == nil || s.Enrolled("admin") || !strings.Contains(s.ProvisioningURI(e), "secret="+e.Secret) {
+		t.FailNow()
+	}
+	if _, suc := s.ConfirmEnrollment("admin", "000000x"); suc {
+		t.Fail()
+	}
+	code, _ := Code(e.Secret, time.Now())
+	codes, suc := s.ConfirmEnrollment("admin", code)
+	fmt.Println("recovery codes: ", codes)
+	if !suc || len(codes) != recoveryCodeCount || !s.Enrolled("admin") {
+		t.Fail()
+	}
+	if s.StartEnrollment("admin") != nil {
+		t.Fail()
+	}
+	if lst := s.ListEnrolled(); len(lst) != 1 || lst[0] != "admin" {
+		t.Fail()
+	}
+	//the confirm code can not be used again to log in
+	if s.Verify("admin", code) {
+		t.Fail()
+	}
+}
+
+func TestSix910TotpService_VerifyRecovery(t *testing.T) {
+	s, dir := newTestService(t)
+	defer os.RemoveAll(dir)
+	e := s.StartEnrollment("admin")
+	prev, _ := Code(e.Secret, time.Now().Add(-30*time.Second))
+	codes, suc := s.ConfirmEnrollment("admin", prev)
+	if !suc {
+		t.FailNow()
+	}
+	code, _ := Code(e.Secret, time.Now())
+	if !s.Verify("admin", code) || s.Verify("admin", code) {
+		t.Fail()
+	}
+	if !s.Verify("admin", strings.ToUpper(strings.Replace(codes[3], "-", "", 1))) || s.Verify("admin", codes[3]) {
+		t.Fail()
+	}
+	if s.Verify("admin", "") || s.Verify("nobody", code) {
+		t.Fail()
+	}
+}
+
+func TestSix910TotpService_Reset(t *testing.T) {
+	s, dir := newTestService(t)
+	defer os.RemoveAll(dir)
+	e := s.StartEnrollment("admin@test.com")
+	code, _ := Code(e.Secret, time.Now())
+	s.ConfirmEnrollment("admin@test.com", code)
+	if !s.Reset("admin@test.com") || s.Enrolled("admin@test.com") || s.Reset("admin@test.com") {
+		t.Fail()
+	}
+	if s.StartEnrollment("admin@test.com") == nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,101 @@
+//Package totpsrv ...
+package totpsrv
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+/*
+ Six910 is a shopping cart and E-commerce system.
+ Copyright (C) 2020 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+ Copyright (C) 2020 Ken Williamson
+ All rights reserved.
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+//RFC 6238 settings used by every authenticator app
+const (
+	period    = 30
+	digits    = 6
+	secretLen = 20
+)
+
+var b32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+//NewSecret NewSecret returns a random base32 secret for an authenticator app
+func NewSecret() string {
+	b := make([]byte, secretLen)
+	if _, err := rand.Read(b); err != nil {
+		panic("totpsrv: cannot read random bytes: " + err.Error())
+	}
+	return b32.EncodeToString(b)
+}
+
+//Code Code returns the code for secret at time t
+func Code(secret string, t time.Time) (string, error) {
+	return codeAt(secret, t.Unix()/period)
+}
+
+func codeAt(secret string, step int64) (string, error) {
+	key, err := b32.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return "", err
+	}
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	off := sum[len(sum)-1] & 0x0f
+	bin := binary.BigEndian.Uint32(sum[off:off+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", digits, bin%1000000), nil
+}
+
+//validateStep checks code against the step for t and one step either
+//side for clock drift. It returns the matching step or 0.
+func validateStep(secret string, code string, t time.Time) int64 {
+	code = strings.TrimSpace(code)
+	if len(code) != digits {
+		return 0
+	}
+	now := t.Unix() / period
+	for _, st := range []int64{now, now - 1, now + 1} {
+		c, err := codeAt(secret, st)
+		if err == nil && subtle.ConstantTimeCompare([]byte(c), []byte(code)) == 1 {
+			return st
+		}
+	}
+	return 0
+}
+
+//ProvisioningURI ProvisioningURI returns the otpauth:// URI that
+//authenticator apps read from a QR code
+func ProvisioningURI(issuer string, account string, secret string) string {
+	var v = url.Values{}
+	v.Set("secret", secret)
+	v.Set("issuer", issuer)
+	v.Set("algorithm", "SHA1")
+	v.Set("digits", fmt.Sprint(digits))
+	v.Set("period", fmt.Sprint(period))
+	label := url.PathEscape(issuer + ":" + account)
+	return "otpauth://totp/" + label + "?" + v.Encode()
+}
@@ -0,0 +1,54 @@
+package totpsrv
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+//RFC 6238 appendix B SHA1 vectors, last six digits
+func TestCode_RFC6238(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	var tests = []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		c, err := Code(secret, time.Unix(tt.unix, 0))
+		if err != nil || c != tt.code {
+			t.Error("bad code at ", tt.unix, c)
+		}
+	}
+	if _, err := Code("not base32!", time.Now()); err == nil {
+		t.Fail()
+	}
+}
+
+func TestValidateStep(t *testing.T) {
+	secret := NewSecret()
+	now := time.Now()
+	c, _ := Code(secret, now.Add(-30*time.Second))
+	if validateStep(secret, c, now) == 0 {
+		t.Fail()
+	}
+	old, _ := Code(secret, now.Add(-90*time.Second))
+	if validateStep(secret, old, now) != 0 || validateStep(secret, "12345", now) != 0 {
+		t.Fail()
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	u := ProvisioningURI("My Store", "admin", "ABCDEF")
+	if !strings.HasPrefix(u, "otpauth://totp/My%20Store:admin?") || !strings.Contains(u, "secret=ABCDEF") ||
+		!strings.Contains(u, "issuer=My+Store") || !strings.Contains(u, "period=30") {
+		t.Error(u)
+	}
+}
@@ -0,0 +1,95 @@
+package usersrv
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+/*
+ Copyright (C) 2019 Ulbora Labs LLC. (www.ulboralabs.com)
+ All rights reserved.
+
+ Copyright (C) 2019 Ken Williamson
+ All rights reserved.
+
+ This program is free software: you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation, either version 3 of the License, or
+ (at your option) any later version.
+ This program is distributed in the hope that it will be useful,
+ but WITHOUT ANY WARRANTY; without even the implied warranty of
+ MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ GNU General Public License for more details.
+ You should have received a copy of the GNU General Public License
+ along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+*/
+
+//Errors for user host status codes
+var (
+	ErrBadRequest   = errors.New("user host rejected the request")
+	ErrUnauthorized = errors.New("user host token missing or expired")
+	ErrForbidden    = errors.New("user host denied access")
+	ErrNotFound     = errors.New("user not found")
+	ErrConflict     = errors.New("user already exists")
+	ErrUnavailable  = errors.New("user host unavailable")
+	ErrRejected     = errors.New("user host did not make the change")
+)
+
+//UserError UserError is a failed call to the user host. errors.Is matches
+//it against the Err values for its code; a 2xx code whose response was
+//not a success is ErrRejected.
+type UserError struct {
+	Op      string
+	Code    int
+	Message string
+}
+
+func (e *UserError) Error() string {
+	var rtn = "usersrv " + e.Op + ": " + strconv.Itoa(e.Code)
+	if e.Message != "" {
+		rtn += " " + e.Message
+	}
+	return rtn
+}
+
+//Unwrap Unwrap
+func (e *UserError) Unwrap() error {
+	var rtn error
+	switch {
+	case e.Code >= 200 && e.Code < 300:
+		rtn = ErrRejected
+	case e.Code == http.StatusBadRequest:
+		rtn = ErrBadRequest
+	case e.Code == http.StatusUnauthorized:
+		rtn = ErrUnauthorized
+	case e.Code == http.StatusForbidden:
+		rtn = ErrForbidden
+	case e.Code == http.StatusNotFound:
+		rtn = ErrNotFound
+	case e.Code == http.StatusConflict:
+		rtn = ErrConflict
+	default:
+		rtn = ErrUnavailable
+	}
+	return rtn
+}
+
+//CodeError CodeError returns nil for a 2xx code and a *UserError for
+//anything else. Code 0 means the user host could not be reached.
+func CodeError(op string, code int, message string) error {
+	if code >= 200 && code < 300 {
+		return nil
+	}
+	return &UserError{Op: op, Code: code, Message: message}
+}
+
+//rejected rejected returns err, or ErrRejected with the real code when
+//the user host answered but res is not a success
+func rejected(op string, res *UserResponse, err error) error {
+	if err == nil && !res.Success {
+		err = &UserError{Op: op, Code: res.Code, Message: res.Message}
+	}
+	return err
+}
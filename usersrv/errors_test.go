@@ -0,0 +1,23 @@
+package usersrv
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCodeError(t *testing.T) {
+	if CodeError("get user", 200, "") != nil || CodeError("add user", 201, "") != nil {
+		t.Fail()
+	}
+	err := CodeError("get user", 403, "no access")
+	if err.Error() != "usersrv get user: 403 no access" || !errors.Is(err, ErrForbidden) || errors.Is(err, ErrNotFound) {
+		t.Fail()
+	}
+	if !errors.Is(CodeError("get user", 500, ""), ErrUnavailable) {
+		t.Fail()
+	}
+	var ue = UserError{Op: "add user", Code: 200, Message: "bad password"}
+	if !errors.Is(&ue, ErrRejected) || ue.Error() != "usersrv add user: 200 bad password" {
+		t.Fail()
+	}
+}
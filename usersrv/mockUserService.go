@@ -2,6 +2,9 @@
 package usersrv
 
 import (
+	"net/http"
+	"strings"
+
 	px "github.com/Ulbora/GoProxy"
 	lg "github.com/Ulbora/Level_Logger"
 )
@@ -41,21 +44,81 @@ type MockOauth2UserService struct {
 	MockUpdateUserResponse *UserResponse
 	MockUser               *User
 	MockUserCode           int
+
+	MockAddUserResponse    *UserResponse
+	MockAddUserErr         error
+	MockDeleteUserResponse *UserResponse
+	MockDeleteUserErr      error
+	MockUserList           *[]User
+	MockUserListErr        error
+	MockRoleList           *[]Role
+	MockRoleListErr        error
+}
+
+//UpdateUser UpdateUser fails like the user host when
+//MockUpdateUserResponse is nil or not a success
+func (u *MockOauth2UserService) UpdateUser(user UpdateUser) (*UserResponse, error) {
+	var err error
+	if u.MockUpdateUserResponse == nil {
+		return new(UserResponse), CodeError("update user", 0, "")
+	} else if !u.MockUpdateUserResponse.Success {
+		err = &UserError{Op: "update user", Code: http.StatusOK, Message: u.MockUpdateUserResponse.Message}
+	}
+	return u.MockUpdateUserResponse, err
+}
+
+//GetUser GetUser returns the CodeError for MockUserCode
+func (u *MockOauth2UserService) GetUser(username string, clientID string) (*User, error) {
+	return u.MockUser, CodeError("get user", u.MockUserCode, "")
+}
+
+//AddUser AddUser
+func (u *MockOauth2UserService) AddUser(user *User) (*UserResponse, error) {
+	return u.MockAddUserResponse, u.MockAddUserErr
+}
+
+//DeleteUser DeleteUser
+func (u *MockOauth2UserService) DeleteUser(username string, clientID string) (*UserResponse, error) {
+	return u.MockDeleteUserResponse, u.MockDeleteUserErr
+}
+
+//ListUsers ListUsers returns the part of MockUserList from start to end
+func (u *MockOauth2UserService) ListUsers(clientID string, start int64, end int64) (*[]User, error) {
+	var rtn = make([]User, 0)
+	if u.MockUserList != nil {
+		for i, usr := range *u.MockUserList {
+			if int64(i) >= start && int64(i) < end {
+				rtn = append(rtn, usr)
+			}
+		}
+	}
+	return &rtn, u.MockUserListErr
 }
 
-//UpdateUser UpdateUser
-func (u *MockOauth2UserService) UpdateUser(user UpdateUser) *UserResponse {
-	return u.MockUpdateUserResponse
+//SearchUsersByEmail SearchUsersByEmail returns the users in MockUserList
+//with email
+func (u *MockOauth2UserService) SearchUsersByEmail(email string, clientID string) (*[]User, error) {
+	var rtn = make([]User, 0)
+	if u.MockUserList != nil {
+		for _, usr := range *u.MockUserList {
+			if strings.EqualFold(usr.EmailAddress, email) {
+				rtn = append(rtn, usr)
+			}
+		}
+	}
+	return &rtn, u.MockUserListErr
 }
 
-//GetUser GetUser
-func (u *MockOauth2UserService) GetUser(username string, clientID string) (*User, int) {
-	return u.MockUser, u.MockUserCode
+//GetRoleList GetRoleList
+func (u *MockOauth2UserService) GetRoleList() (*[]Role, error) {
+	return u.MockRoleList, u.MockRoleListErr
 }
 
 //SetToken SetToken
-func (u *MockOauth2UserService) SetToken(token string) {
-	u.Token = token
+func (u *MockOauth2UserService) SetToken(token string) UserService {
+	var nu = *u
+	nu.Token = token
+	return &nu
 }
 
 //GetNew GetNew
@@ -2,6 +2,7 @@
 package usersrv
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -15,8 +16,12 @@ func TestMockOauth2UserService_UpdateUser(t *testing.T) {
 	s := c.GetNew()
 
 	var u UpdateUser
-	res := s.UpdateUser(u)
-	if !res.Success {
+	res, err := s.UpdateUser(u)
+	if err != nil || !res.Success {
+		t.Fail()
+	}
+	us.Success = false
+	if _, err := s.UpdateUser(u); !errors.Is(err, ErrRejected) {
 		t.Fail()
 	}
 }
@@ -28,8 +33,8 @@ func TestMockOauth2UserService_GetUser(t *testing.T) {
 	c.MockUserCode = 200
 
 	s := c.GetNew()
-	res, cd := s.GetUser("test", "344")
-	if res == nil && cd == 0 {
+	res, err := s.GetUser("test", "344")
+	if res == nil || err != nil {
 		t.Fail()
 	}
 }
@@ -37,5 +42,42 @@ func TestMockOauth2UserService_GetUser(t *testing.T) {
 func TestMockOauth2UserService_SetToken(t *testing.T) {
 	var c MockOauth2UserService
 	s := c.GetNew()
-	s.SetToken("123")
+	s2 := s.SetToken("123")
+	if s2.(*MockOauth2UserService).Token != "123" || c.Token != "" {
+		t.Fail()
+	}
+}
+
+func TestMockOauth2UserService_ListAndSearch(t *testing.T) {
+	var c MockOauth2UserService
+	c.MockUserList = &[]User{{Username: "bob", EmailAddress: "bob@test.com"}, {Username: "jane"}, {Username: "ann"}}
+	s := c.GetNew().SetToken("123")
+	lst, err := s.ListUsers("10", 1, 5)
+	if err != nil || len(*lst) != 2 || (*lst)[0].Username != "jane" {
+		t.Fail()
+	}
+	fl, _ := s.SearchUsersByEmail("BOB@test.com", "10")
+	if len(*fl) != 1 {
+		t.Fail()
+	}
+}
+
+func TestMockOauth2UserService_AddDelete(t *testing.T) {
+	var c MockOauth2UserService
+	var ur UserResponse
+	ur.Success = true
+	c.MockAddUserResponse = &ur
+	c.MockDeleteUserErr = ErrNotFound
+	c.MockRoleList = &[]Role{{ID: 1, Role: "admin"}}
+	s := c.GetNew()
+	res, err := s.AddUser(&User{Username: "bob"})
+	if err != nil || !res.Success {
+		t.Fail()
+	}
+	if _, err := s.DeleteUser("bob", "10"); err != ErrNotFound {
+		t.Fail()
+	}
+	if rl, _ := s.GetRoleList(); len(*rl) != 1 {
+		t.Fail()
+	}
 }
@@ -4,7 +4,10 @@ package usersrv
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	px "github.com/Ulbora/GoProxy"
 	lg "github.com/Ulbora/Level_Logger"
@@ -114,51 +117,108 @@ type Oauth2UserService struct {
 
 //UserService UserService
 type UserService interface {
-	UpdateUser(user UpdateUser) *UserResponse
-	GetUser(username string, clientID string) (*User, int)
-	SetToken(token string)
+	UpdateUser(user UpdateUser) (*UserResponse, error)
+	GetUser(username string, clientID string) (*User, error)
+	SetToken(token string) UserService
+
+	AddUser(user *User) (*UserResponse, error)
+	DeleteUser(username string, clientID string) (*UserResponse, error)
+	ListUsers(clientID string, start int64, end int64) (*[]User, error)
+	SearchUsersByEmail(email string, clientID string) (*[]User, error)
+	GetRoleList() (*[]Role, error)
 }
 
 //UpdateUser update
-func (u *Oauth2UserService) UpdateUser(user UpdateUser) *UserResponse {
+func (u *Oauth2UserService) UpdateUser(user UpdateUser) (*UserResponse, error) {
 	var rtn = new(UserResponse)
-	var upURL = u.UserHost + "/rs/user/update"
 	aJSON, err := json.Marshal(user)
 	u.Log.Debug("update user: ", err)
-	if err == nil {
-		req, rErr := http.NewRequest("PUT", upURL, bytes.NewBuffer(aJSON))
-		u.Log.Debug("update user req: ", rErr)
-		if rErr == nil {
-			req.Header.Set("Content-Type", "application/json")
-			req.Header.Set("Authorization", "Bearer "+u.Token)
-			req.Header.Set("clientId", u.ClientID)
-			//req.Header.Set("apiKey", u.APIKey)
-			_, code := u.Proxy.Do(req, &rtn)
-			rtn.Code = code
-		}
+	if err != nil {
+		return rtn, err
 	}
-	return rtn
+	code, err := u.do("update user", "PUT", u.UserHost+"/rs/user/update", bytes.NewBuffer(aJSON), &rtn)
+	rtn.Code = code
+	return rtn, rejected("update user", rtn, err)
 }
 
 // GetUser get
-func (u *Oauth2UserService) GetUser(username string, clientID string) (*User, int) {
+func (u *Oauth2UserService) GetUser(username string, clientID string) (*User, error) {
 	var rtn = new(User)
-	var code int
-	var gURL = u.UserHost + "/rs/user/get/" + username + "/" + clientID
-	req, rErr := http.NewRequest("GET", gURL, nil)
-	u.Log.Debug("get user req: ", rErr)
-	if rErr == nil {
-		req.Header.Set("clientId", u.ClientID)
-		req.Header.Set("Authorization", "Bearer "+u.Token)
-		//req.Header.Set("apiKey", u.APIKey)
-		_, code = u.Proxy.Do(req, &rtn)
+	var gURL = u.UserHost + "/rs/user/get/" + url.PathEscape(username) + "/" + url.PathEscape(clientID)
+	_, err := u.do("get user", "GET", gURL, nil, &rtn)
+	return rtn, err
+}
+
+//AddUser AddUser
+func (u *Oauth2UserService) AddUser(user *User) (*UserResponse, error) {
+	var rtn = new(UserResponse)
+	aJSON, err := json.Marshal(user)
+	if err != nil {
+		return rtn, err
 	}
-	return rtn, code
+	code, err := u.do("add user", "POST", u.UserHost+"/rs/user/add", bytes.NewBuffer(aJSON), &rtn)
+	rtn.Code = code
+	return rtn, rejected("add user", rtn, err)
 }
 
-//SetToken SetToken
-func (u *Oauth2UserService) SetToken(token string) {
-	u.Token = token
+//DeleteUser DeleteUser
+func (u *Oauth2UserService) DeleteUser(username string, clientID string) (*UserResponse, error) {
+	var rtn = new(UserResponse)
+	var dURL = u.UserHost + "/rs/user/delete/" + url.PathEscape(username) + "/" + url.PathEscape(clientID)
+	code, err := u.do("delete user", "DELETE", dURL, nil, &rtn)
+	rtn.Code = code
+	return rtn, rejected("delete user", rtn, err)
+}
+
+//ListUsers ListUsers returns the users of clientID from start up to end
+func (u *Oauth2UserService) ListUsers(clientID string, start int64, end int64) (*[]User, error) {
+	var rtn = make([]User, 0)
+	var lURL = u.UserHost + "/rs/user/list/" + url.PathEscape(clientID) + "/" +
+		strconv.FormatInt(start, 10) + "/" + strconv.FormatInt(end, 10)
+	_, err := u.do("list users", "GET", lURL, nil, &rtn)
+	return &rtn, err
+}
+
+//SearchUsersByEmail SearchUsersByEmail
+func (u *Oauth2UserService) SearchUsersByEmail(email string, clientID string) (*[]User, error) {
+	var rtn = make([]User, 0)
+	var sURL = u.UserHost + "/rs/user/search/email/" + url.PathEscape(email) + "/" + url.PathEscape(clientID)
+	_, err := u.do("search users", "GET", sURL, nil, &rtn)
+	return &rtn, err
+}
+
+//GetRoleList GetRoleList
+func (u *Oauth2UserService) GetRoleList() (*[]Role, error) {
+	var rtn = make([]Role, 0)
+	_, err := u.do("role list", "GET", u.UserHost+"/rs/role/list", nil, &rtn)
+	return &rtn, err
+}
+
+//do sends a request with the client ID and Bearer token and decodes the
+//response into obj
+func (u *Oauth2UserService) do(op string, method string, rURL string, body io.Reader, obj interface{}) (int, error) {
+	req, rErr := http.NewRequest(method, rURL, body)
+	u.Log.Debug(op, " req: ", rErr)
+	if rErr != nil {
+		return 0, rErr
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Authorization", "Bearer "+u.Token)
+	req.Header.Set("clientId", u.ClientID)
+	_, code := u.Proxy.Do(req, obj)
+	u.Log.Debug(op, " code: ", code)
+	return code, CodeError(op, code, "")
+}
+
+//SetToken SetToken returns a copy of the service that calls the user
+//host with token. The receiver is not changed, so concurrent requests
+//never share a token.
+func (u *Oauth2UserService) SetToken(token string) UserService {
+	var nu = *u
+	nu.Token = token
+	return &nu
 }
 
 //GetNew GetNew
@@ -3,6 +3,7 @@ package usersrv
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -56,10 +57,10 @@ func TestUserService_UpdateUserPassword(t *testing.T) {
 	user.ClientID = CLIDINT
 	user.Password = "bobbby"
 
-	res := c.UpdateUser(&user)
+	res, err := c.UpdateUser(&user)
 	fmt.Print("res: ")
 	fmt.Println(res)
-	if res.Success != true {
+	if err != nil || res.Success != true {
 		t.Fail()
 	}
 }
@@ -84,10 +85,10 @@ func TestUserService_UpdateUserDisable(t *testing.T) {
 	user.ClientID = CLIDINT
 	user.Enabled = false
 
-	res := c.UpdateUser(&user)
+	res, err := c.UpdateUser(&user)
 	fmt.Print("res: ")
 	fmt.Println(res)
-	if res.Success != true {
+	if err != nil || res.Success != true {
 		t.Fail()
 	}
 }
@@ -115,10 +116,10 @@ func TestUserService_UpdateUserInfo(t *testing.T) {
 	user.RoleID = 1
 	user.LastName = "williams"
 
-	res := c.UpdateUser(&user)
+	res, err := c.UpdateUser(&user)
 	fmt.Print("res: ")
 	fmt.Println(res)
-	if res.Success != true {
+	if err != nil || res.Success != true {
 		t.Fail()
 	}
 }
@@ -143,10 +144,10 @@ func TestUserService_UpdateUserDisable2(t *testing.T) {
 	user.ClientID = CLIDINT
 	user.Enabled = true
 
-	res := c.UpdateUser(&user)
+	res, err := c.UpdateUser(&user)
 	fmt.Print("res: ")
 	fmt.Println(res)
-	if res.Success != true {
+	if err != nil || res.Success != true {
 		t.Fail()
 	}
 }
@@ -168,10 +169,10 @@ func TestUserService_GetUser(t *testing.T) {
 	//c.Token = tempToken
 	s := c.GetNew()
 
-	res, code := s.GetUser(UID, CLID)
+	res, err := s.GetUser(UID, CLID)
 	fmt.Print("res: ")
 	fmt.Println(res)
-	if res.Username != UID || res.Enabled == false || code != 200 {
+	if res.Username != UID || res.Enabled == false || err != nil {
 		t.Fail()
 	}
 }
@@ -208,6 +209,104 @@ func TestUserService_GetUserInfoType(t *testing.T) {
 
 func TestOauth2UserService_SetToken(t *testing.T) {
 	var c Oauth2UserService
-	c.SetToken("rrr")
+	c.Token = "old"
+	s := c.SetToken("rrr")
+	if s.(*Oauth2UserService).Token != "rrr" || c.Token != "old" {
+		t.Fail()
+	}
+
+}
+
+func testUserService(body string, code int) *Oauth2UserService {
+	var c Oauth2UserService
+	var l lg.Logger
+	c.Log = &l
+	var p px.MockGoProxy
+	p.MockDoSuccess1 = true
+	var ress http.Response
+	ress.Body = ioutil.NopCloser(bytes.NewBufferString(body))
+	p.MockResp = &ress
+	p.MockRespCode = code
+	c.Proxy = p.GetNewProxy()
+	c.ClientID = "10"
+	c.UserHost = "http://localhost:3001"
+	return &c
+}
+
+func TestUserService_AddUser(t *testing.T) {
+	c := testUserService(`{"success":true}`, 200)
+	var user User
+	user.Username = UID
+	user.Password = "password1"
+	user.ClientID = CLIDINT
+	res, err := c.GetNew().AddUser(&user)
+	if err != nil || !res.Success || res.Code != 200 {
+		t.Fail()
+	}
+
+	c2 := testUserService(`{"success":false, "message":"exists"}`, 409)
+	_, err2 := c2.AddUser(&user)
+	fmt.Println("add err: ", err2)
+	if !errors.Is(err2, ErrConflict) {
+		t.Fail()
+	}
+
+	c3 := testUserService(`{"success":false, "message":"bad password"}`, 200)
+	_, err3 := c3.AddUser(&user)
+	var ue *UserError
+	if !errors.Is(err3, ErrRejected) || !errors.As(err3, &ue) || ue.Code != 200 || ue.Message != "bad password" {
+		t.Fail()
+	}
+}
 
+func TestUserService_DeleteUser(t *testing.T) {
+	c := testUserService(`{"success":true}`, 200)
+	res, err := c.DeleteUser(UID, CLID)
+	if err != nil || !res.Success {
+		t.Fail()
+	}
+	c2 := testUserService(`{"success":false}`, 401)
+	_, err2 := c2.DeleteUser(UID, CLID)
+	if !errors.Is(err2, ErrUnauthorized) {
+		t.Fail()
+	}
+	c3 := testUserService(`{"success":false}`, 200)
+	if _, err3 := c3.DeleteUser(UID, CLID); !errors.Is(err3, ErrRejected) || errors.Is(err3, ErrNotFound) {
+		t.Fail()
+	}
+}
+
+func TestUserService_ListUsers(t *testing.T) {
+	c := testUserService(`[{"username":"bob", "enabled": true}, {"username":"jane"}]`, 200)
+	res, err := c.ListUsers(CLID, 0, 100)
+	if err != nil || len(*res) != 2 || (*res)[1].Username != "jane" {
+		t.Fail()
+	}
+	c2 := testUserService(`{}`, 0)
+	res2, err2 := c2.ListUsers(CLID, 0, 100)
+	if !errors.Is(err2, ErrUnavailable) || len(*res2) != 0 {
+		t.Fail()
+	}
+}
+
+func TestUserService_SearchUsersByEmail(t *testing.T) {
+	c := testUserService(`[{"username":"bob", "emailAddress": "bob@test.com"}]`, 200)
+	res, err := c.SearchUsersByEmail("bob@test.com", CLID)
+	if err != nil || len(*res) != 1 {
+		t.Fail()
+	}
+	c2 := testUserService(`[]`, 404)
+	_, err2 := c2.SearchUsersByEmail("nobody@test.com", CLID)
+	var ue *UserError
+	if !errors.As(err2, &ue) || ue.Code != 404 || !errors.Is(err2, ErrNotFound) {
+		t.Fail()
+	}
+}
+
+func TestUserService_GetRoleList(t *testing.T) {
+	c := testUserService(`[{"id": 1, "role": "admin"}, {"id": 2, "role": "user"}]`, 200)
+	res, err := c.GetRoleList()
+	if err != nil || len(*res) != 2 || (*res)[0].Role != "admin" {
+		t.Fail()
+	}
 }